@@ -0,0 +1,19 @@
+#!/bin/sh
+# Generates a development CA and certificates for the gRPC hops.
+# Common names are checked by the servers, SANs must match the hosts the services are dialed by.
+set -e
+
+DIR=${1:-certs}
+mkdir -p "$DIR"
+cd "$DIR"
+
+openssl req -x509 -newkey rsa:4096 -nodes -days 365 -subj "/CN=voblako-ca" -keyout ca.key -out ca.crt
+
+for name in gateway auth file; do
+  openssl req -newkey rsa:2048 -nodes -subj "/CN=$name" -keyout "$name.key" -out "$name.csr"
+  printf "subjectAltName=DNS:%s,DNS:localhost,IP:127.0.0.1\nextendedKeyUsage=serverAuth,clientAuth\n" "$name" \
+    > "$name.ext"
+  openssl x509 -req -in "$name.csr" -CA ca.crt -CAkey ca.key -CAcreateserial -days 365 \
+    -extfile "$name.ext" -out "$name.crt"
+  rm "$name.csr" "$name.ext"
+done
@@ -1,8 +1,9 @@
 package main
 
 import (
-	"log"
+	"log/slog"
 
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
 	app "github.com/IlyaChgn/voblako/internal/pkg/server"
 
 	"github.com/joho/godotenv"
@@ -11,11 +12,11 @@ import (
 func main() {
 	err := godotenv.Load("local.env")
 	if err != nil {
-		log.Println(".env file not found, using OS environment")
+		slog.Warn(".env file not found, using OS environment")
 	}
 
 	srv := new(app.Server)
 	if err := srv.Run(); err != nil {
-		log.Fatal("Error occurred while starting server ", err)
+		logger.Fatal("Error occurred while starting server", "error", err)
 	}
 }
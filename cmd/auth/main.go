@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	mygrpc "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc"
 	authproto "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
@@ -14,58 +17,164 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	"github.com/IlyaChgn/voblako/internal/pkg/health"
+	"github.com/IlyaChgn/voblako/internal/pkg/interceptors"
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+	"github.com/IlyaChgn/voblako/internal/pkg/mailer"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+	"github.com/IlyaChgn/voblako/internal/pkg/migrations"
 	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+	"github.com/IlyaChgn/voblako/internal/pkg/shutdown"
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	err := godotenv.Load("local.env")
 	if err != nil {
-		log.Println(".env file not found, using OS environment")
+		slog.Warn(".env file not found, using OS environment")
 	}
 
 	cfgPath := os.Getenv("CONFIG_PATH")
 	generalCfg := config.ReadConfig(cfgPath)
 	if generalCfg == nil {
-		log.Fatalf("Something went wrong while opening config in auth service")
+		logger.Fatal("Something went wrong while opening config in auth service")
 	}
 	cfg := generalCfg.Auth
 
+	logger.Setup(generalCfg.Log, "auth")
+
+	ctx, stop := shutdown.NotifyContext(context.Background())
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), generalCfg.Tracing, "auth")
+	if err != nil {
+		logger.Fatal("Cannot set up tracing", "error", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	postgresURL := dbinit.NewConnectionString(cfg.Postgres.Username, cfg.Postgres.Password,
 		cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.DBName)
 	postgresPool, err := dbinit.NewPostgresPool(postgresURL)
 	if err != nil {
-		log.Fatal("Something went wrong while creating postgres pool", err)
+		logger.Fatal("Something went wrong while creating postgres pool", "error", err)
 	}
+	defer postgresPool.Close()
 
 	err = postgresPool.Ping(context.Background())
 	if err != nil {
-		log.Fatal("Cannot ping postgres database", err)
+		logger.Fatal("Cannot ping postgres database", "error", err)
+	}
+
+	// "auth migrate up|down [steps]|version" manages the schema without starting the service
+	schema, err := migrations.Auth()
+	if err != nil {
+		logger.Fatal("Cannot load migrations", "error", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = migrations.Run(ctx, postgresPool, schema, migrations.AuthLockID, os.Args[2:])
+		if err != nil {
+			logger.Fatal("Migration failed", "error", err)
+		}
+
+		return
+	}
+	if generalCfg.Migrations.OnStart {
+		err = migrations.Run(ctx, postgresPool, schema, migrations.AuthLockID, nil)
+		if err != nil {
+			logger.Fatal("Migration failed", "error", err)
+		}
 	}
 
 	redisClient := dbinit.NewRedisClient(cfg.Redis.Host, cfg.Redis.Port, cfg.Redis.Password, cfg.Redis.DB)
+	defer redisClient.Close()
 	err = redisClient.Ping(context.Background()).Err()
 	if err != nil {
-		log.Fatal("Cannot ping Redis", err)
+		logger.Fatal("Cannot ping Redis", "error", err)
 	}
 
 	authStorage := repository.NewAuthStorage(postgresPool)
+	orgStorage := repository.NewOrgStorage(postgresPool)
+	accessKeyStorage := repository.NewAccessKeyStorage(postgresPool)
 	sessionManager := repository.NewSessionManager(redisClient)
-	authManager := mygrpc.NewAuthManager(sessionManager, authStorage)
+	verificationManager := repository.NewVerificationManager(redisClient,
+		time.Second*time.Duration(cfg.Verification.TokenTTL))
+
+	var authMailer mailer.Mailer
+	if cfg.Mailer.Host != "" {
+		authMailer = mailer.NewSMTPMailer(cfg.Mailer.Host, cfg.Mailer.Port, cfg.Mailer.Username,
+			cfg.Mailer.Password, cfg.Mailer.From)
+	} else {
+		slog.Warn("SMTP host is not set, verification emails will be written to log")
+		authMailer = mailer.NewLogMailer()
+	}
+
+	authManager := mygrpc.NewAuthManager(sessionManager, authStorage, orgStorage, accessKeyStorage,
+		verificationManager, authMailer, cfg.Verification.VerifyURL)
+
+	checker := health.NewChecker(time.Second * time.Duration(generalCfg.Health.Timeout))
+	checker.Add("postgres", health.PostgresCheck(postgresPool))
+	checker.Add("redis", health.RedisCheck(redisClient))
+
+	var workers sync.WaitGroup
+	defer workers.Wait()
+
+	healthServer := health.NewServer(authproto.Auth_ServiceDesc.ServiceName)
+	workers.Go(func() {
+		health.Watch(ctx, checker, healthServer, time.Second*time.Duration(generalCfg.Health.Interval),
+			authproto.Auth_ServiceDesc.ServiceName)
+	})
+
+	if cfg.MetricsPort != "" {
+		metricsAddr := fmt.Sprintf("%s:%s", cfg.InternalHost, cfg.MetricsPort)
+		metricsServer := metrics.Serve(metricsAddr, map[string]http.Handler{
+			"/healthz": health.LivenessHandler(),
+			"/readyz":  health.ReadinessHandler(checker),
+		})
+		defer metricsServer.Shutdown(context.Background())
+	}
 
 	grpcAddr := fmt.Sprintf("%s:%s", cfg.InternalHost, cfg.Port)
 	listener, err := net.Listen("tcp", grpcAddr)
 	if err != nil {
-		log.Fatalf("Error occurred while listening gRPC service on %s: %v", grpcAddr, err)
+		logger.Fatal("Error occurred while listening gRPC service", "address", grpcAddr, "error", err)
 	}
 
-	srv := grpc.NewServer()
+	if generalCfg.ServiceAuth.TokenSecret == "" {
+		logger.Fatal("Service token secret is not set")
+	}
+	if !cfg.TLS.Enabled {
+		slog.Warn("TLS is disabled, gRPC traffic of the auth service is not encrypted")
+	}
+
+	serverCreds, err := serviceauth.ServerCredentials(cfg.TLS)
+	if err != nil {
+		logger.Fatal("Cannot load TLS credentials for auth service", "error", err)
+	}
+
+	tokenVerifier := serviceauth.NewTokenVerifier(generalCfg.ServiceAuth.TokenSecret, serviceauth.AuthServiceName,
+		serviceauth.GatewayName, serviceauth.FileServiceName)
+
+	// The token is checked after the common chain, so rejected calls are logged too
+	serverOpts := append(interceptors.ServerOptions(time.Second*time.Duration(cfg.Timeout)),
+		grpc.Creds(serverCreds),
+		grpc.ChainUnaryInterceptor(serviceauth.UnaryServerInterceptor(tokenVerifier, mygrpc.AccessPolicy)),
+		grpc.ChainStreamInterceptor(serviceauth.StreamServerInterceptor(tokenVerifier, mygrpc.AccessPolicy)),
+	)
+	srv := grpc.NewServer(serverOpts...)
 	authproto.RegisterAuthServer(srv, authManager)
+	healthpb.RegisterHealthServer(srv, healthServer)
 
-	log.Printf("Starting Auth gRPC service on %s", grpcAddr)
+	slog.Info("Starting Auth gRPC service", "address", grpcAddr)
 
-	if err = srv.Serve(listener); err != nil {
-		log.Fatalf("gRPC Server failed to serve: %v", err)
+	err = shutdown.ServeGRPC(ctx, srv, listener, generalCfg.Shutdown, func() {
+		checker.Drain()
+		healthServer.Shutdown()
+	})
+	if err != nil {
+		logger.Fatal("gRPC server failed to serve", "error", err)
 	}
 }
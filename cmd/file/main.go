@@ -2,15 +2,36 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"slices"
+	"sync"
+	"time"
 
+	authproto "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/compression"
 	mygrpc "github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc"
 	fileproto "github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/encryption"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/jobs"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/repository/membership"
 	metarepo "github.com/IlyaChgn/voblako/internal/pkg/file/repository/metadata"
 	objectrepo "github.com/IlyaChgn/voblako/internal/pkg/file/repository/object"
+	"github.com/IlyaChgn/voblako/internal/pkg/health"
+	"github.com/IlyaChgn/voblako/internal/pkg/interceptors"
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+	"github.com/IlyaChgn/voblako/internal/pkg/mailer"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+	"github.com/IlyaChgn/voblako/internal/pkg/migrations"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+	"github.com/IlyaChgn/voblako/internal/pkg/shutdown"
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
+	"github.com/minio/minio-go/v7"
 
 	"github.com/joho/godotenv"
 
@@ -18,55 +39,298 @@ import (
 	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
 	err := godotenv.Load("local.env")
 	if err != nil {
-		log.Println(".env file not found, using OS environment")
+		slog.Warn(".env file not found, using OS environment")
 	}
 
 	cfgPath := os.Getenv("CONFIG_PATH")
 	generalCfg := config.ReadConfig(cfgPath)
 	if generalCfg == nil {
-		log.Fatalf("Something went wrong while opening config in file service")
+		logger.Fatal("Something went wrong while opening config in file service")
 	}
 	cfg := generalCfg.File
 
+	logger.Setup(generalCfg.Log, "file")
+
+	ctx, stop := shutdown.NotifyContext(context.Background())
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), generalCfg.Tracing, "file")
+	if err != nil {
+		logger.Fatal("Cannot set up tracing", "error", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	postgresURL := dbinit.NewConnectionString(cfg.Postgres.Username, cfg.Postgres.Password,
 		cfg.Postgres.Host, cfg.Postgres.Port, cfg.Postgres.DBName)
 	postgresPool, err := dbinit.NewPostgresPool(postgresURL)
 	if err != nil {
-		log.Fatal("Something went wrong while creating postgres pool", err)
+		logger.Fatal("Something went wrong while creating postgres pool", "error", err)
 	}
+	defer postgresPool.Close()
 
 	err = postgresPool.Ping(context.Background())
 	if err != nil {
-		log.Fatal("Cannot ping postgres database", err)
+		logger.Fatal("Cannot ping postgres database", "error", err)
+	}
+
+	// "file migrate up|down [steps]|version" manages the schema without starting the service
+	schema, err := migrations.File()
+	if err != nil {
+		logger.Fatal("Cannot load migrations", "error", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = migrations.Run(ctx, postgresPool, schema, migrations.FileLockID, os.Args[2:])
+		if err != nil {
+			logger.Fatal("Migration failed", "error", err)
+		}
+
+		return
+	}
+	if generalCfg.Migrations.OnStart {
+		err = migrations.Run(ctx, postgresPool, schema, migrations.FileLockID, nil)
+		if err != nil {
+			logger.Fatal("Migration failed", "error", err)
+		}
+	}
+
+	objectStorage, storageCheck, err := newObjectStorage(cfg)
+	if err != nil {
+		logger.Fatal("Something went wrong while creating object storage", "error", err)
+	}
+
+	var fileMailer mailer.Mailer
+	if cfg.Mailer.Host != "" {
+		fileMailer = mailer.NewSMTPMailer(cfg.Mailer.Host, cfg.Mailer.Port, cfg.Mailer.Username,
+			cfg.Mailer.Password, cfg.Mailer.From)
+	} else {
+		slog.Warn("SMTP host is not set, export notifications will be written to log")
+		fileMailer = mailer.NewLogMailer()
+	}
+
+	keyring, err := encryption.Load(cfg.Encryption)
+	if err != nil {
+		logger.Fatal("Cannot load encryption keys", "error", err)
+	}
+	if keyring != nil && cfg.Presign.Enabled {
+		slog.Warn("Presigned uploads are disabled, as new files are encrypted")
 	}
 
-	minioURL := dbinit.NewMinioEndpoint(cfg.Minio.Host, cfg.Minio.Port)
-	minioClient, err := dbinit.NewMinioClient(minioURL, cfg.Minio.AccessKey, cfg.Minio.SecretKey, cfg.Minio.Bucket)
+	compressor, err := compression.New(cfg.Compression)
 	if err != nil {
-		log.Fatal("Something went wrong while creating minio client", err)
+		logger.Fatal("Cannot set up compression", "error", err)
 	}
 
 	metadataStorage := metarepo.NewMetadataStorage(postgresPool)
-	objectStorage := objectrepo.NewObjectStorage(minioClient, cfg.Minio.Bucket)
-	fileManager := mygrpc.NewFileManager(metadataStorage, objectStorage)
+	purgeJobStorage := metarepo.NewPurgeJobStorage(postgresPool)
+	exportJobStorage := metarepo.NewExportJobStorage(postgresPool)
+
+	// "file reconcile [--repair]" prints the report and exits, the config only decides the periodic repairs
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		reconcile(ctx, metadataStorage, objectStorage, cfg.Reconcile, slices.Contains(os.Args[2:], "--repair"))
+		return
+	}
+
+	// "file rotate-keys" rewraps data keys with the current master key, previous keys must still be configured
+	if len(os.Args) > 1 && os.Args[1] == "rotate-keys" {
+		if keyring == nil {
+			logger.Fatal("Encryption is disabled, there are no keys to rotate")
+		}
+
+		rotated, err := jobs.RotateDataKeys(ctx, metadataStorage, keyring)
+		if err != nil {
+			logger.Fatal("Key rotation failed", "error", err, "rotated", rotated)
+		}
+		fmt.Printf("%d data keys rotated to master key %s\n", rotated, keyring.CurrentKeyID())
+
+		return
+	}
+
+	// Workers are stopped together with the server, unfinished jobs are picked up by the next start
+	var workers sync.WaitGroup
+	defer workers.Wait()
+
+	if cfg.Reconcile.Interval > 0 {
+		reconciler := jobs.NewReconciler(metadataStorage, objectStorage, jobs.ReconcilerOptions{
+			Interval:    time.Duration(cfg.Reconcile.Interval) * time.Second,
+			GracePeriod: time.Duration(cfg.Reconcile.GracePeriod) * time.Second,
+			Repair:      cfg.Reconcile.Repair,
+		})
+		workers.Go(func() { reconciler.Run(ctx) })
+	}
+
+	if cfg.Multipart.CleanupInterval > 0 {
+		cleaner := jobs.NewMultipartCleaner(objectStorage, jobs.MultipartCleanerOptions{
+			Interval:  time.Duration(cfg.Multipart.CleanupInterval) * time.Second,
+			Retention: time.Duration(cfg.Multipart.Retention) * time.Second,
+		})
+		workers.Go(func() { cleaner.Run(ctx) })
+	}
+
+	exporter := jobs.NewExporter(exportJobStorage, metadataStorage, objectStorage, fileMailer, jobs.ExporterOptions{
+		Retention: time.Duration(cfg.Export.Retention) * time.Second,
+		NotifyURL: cfg.Export.NotifyURL,
+		Keyring:   keyring,
+	})
+	workers.Go(func() { exporter.Run(ctx) })
+
+	if generalCfg.ServiceAuth.TokenSecret == "" {
+		logger.Fatal("Service token secret is not set")
+	}
+
+	authServiceURL := fmt.Sprintf("%s:%s", generalCfg.Auth.ExternalHost, generalCfg.Auth.Port)
+	clientCreds, err := serviceauth.ClientCredentials(cfg.TLS)
+	if err != nil {
+		logger.Fatal("Cannot load TLS credentials for auth service client", "error", err)
+	}
+	// The file service calls the auth service on its own behalf, so its tokens are not bound to a user
+	authTokenSigner := serviceauth.NewTokenSigner(generalCfg.ServiceAuth.TokenSecret, serviceauth.FileServiceName,
+		serviceauth.AuthServiceName, time.Second*time.Duration(generalCfg.ServiceAuth.TokenTTL))
+	clientOpts := append(interceptors.ClientOptions(time.Second*time.Duration(generalCfg.Auth.Timeout)),
+		grpc.WithTransportCredentials(clientCreds),
+		grpc.WithChainUnaryInterceptor(serviceauth.UnaryClientInterceptor(authTokenSigner, "")))
+	authConn, err := grpc.NewClient(authServiceURL, clientOpts...)
+	if err != nil {
+		logger.Fatal("Cannot create client for auth service", "error", err)
+	}
+	defer authConn.Close()
+
+	authClient := authproto.NewAuthClient(authConn)
+	membershipChecker := membership.NewMembershipChecker(authClient)
+
+	purger := jobs.NewPurger(purgeJobStorage, metadataStorage, objectStorage, membership.NewUserChecker(authClient))
+	workers.Go(func() { purger.Run(ctx) })
+
+	fileManager := mygrpc.NewFileManager(metadataStorage, objectStorage, purgeJobStorage, purger,
+		exportJobStorage, exporter, membershipChecker, mygrpc.FileManagerOptions{
+			DownloadURLTTL:  time.Duration(cfg.Export.DownloadURLTTL) * time.Second,
+			DefaultQuota:    cfg.DefaultQuota,
+			DefaultOrgQuota: cfg.DefaultOrgQuota,
+
+			PresignEnabled:     cfg.Presign.Enabled,
+			PresignUploadTTL:   time.Duration(cfg.Presign.UploadURLTTL) * time.Second,
+			PresignDownloadTTL: time.Duration(cfg.Presign.DownloadURLTTL) * time.Second,
+
+			Compressor: compressor,
+			Keyring:    keyring,
+		})
+
+	checker := health.NewChecker(time.Second * time.Duration(generalCfg.Health.Timeout))
+	checker.Add("postgres", health.PostgresCheck(postgresPool))
+	if storageCheck != nil {
+		checker.Add("storage", storageCheck)
+	}
+
+	healthServer := health.NewServer(fileproto.File_ServiceDesc.ServiceName)
+	workers.Go(func() {
+		health.Watch(ctx, checker, healthServer, time.Second*time.Duration(generalCfg.Health.Interval),
+			fileproto.File_ServiceDesc.ServiceName)
+	})
+
+	metrics.RegisterStorageStats(metadataStorage.GetStorageStats)
+	if cfg.MetricsPort != "" {
+		metricsAddr := fmt.Sprintf("%s:%s", cfg.InternalHost, cfg.MetricsPort)
+		metricsServer := metrics.Serve(metricsAddr, map[string]http.Handler{
+			"/healthz": health.LivenessHandler(),
+			"/readyz":  health.ReadinessHandler(checker),
+		})
+		defer metricsServer.Shutdown(context.Background())
+	}
 
 	grpcAddr := fmt.Sprintf("%s:%s", cfg.InternalHost, cfg.Port)
 	listener, err := net.Listen("tcp", grpcAddr)
 	if err != nil {
-		log.Fatalf("Error occurred while listening gRPC service on %s: %v", grpcAddr, err)
+		logger.Fatal("Error occurred while listening gRPC service", "address", grpcAddr, "error", err)
+	}
+
+	if !cfg.TLS.Enabled {
+		slog.Warn("TLS is disabled, gRPC traffic of the file service is not encrypted")
+	}
+
+	serverCreds, err := serviceauth.ServerCredentials(cfg.TLS)
+	if err != nil {
+		logger.Fatal("Cannot load TLS credentials for file service", "error", err)
 	}
+	tokenVerifier := serviceauth.NewTokenVerifier(generalCfg.ServiceAuth.TokenSecret, serviceauth.FileServiceName,
+		serviceauth.GatewayName)
 
-	srv := grpc.NewServer()
+	// The token is checked after the common chain, so rejected calls are logged too
+	serverOpts := append(interceptors.ServerOptions(time.Second*time.Duration(cfg.Timeout)),
+		grpc.Creds(serverCreds),
+		grpc.ChainUnaryInterceptor(serviceauth.UnaryServerInterceptor(tokenVerifier, mygrpc.AccessPolicy)),
+		grpc.ChainStreamInterceptor(serviceauth.StreamServerInterceptor(tokenVerifier, mygrpc.AccessPolicy)),
+	)
+	srv := grpc.NewServer(serverOpts...)
 	fileproto.RegisterFileServer(srv, fileManager)
+	healthpb.RegisterHealthServer(srv, healthServer)
+
+	slog.Info("Starting File gRPC service", "address", grpcAddr)
+
+	err = shutdown.ServeGRPC(ctx, srv, listener, generalCfg.Shutdown, func() {
+		checker.Drain()
+		healthServer.Shutdown()
+	})
+	if err != nil {
+		logger.Fatal("gRPC server failed to serve", "error", err)
+	}
+}
+
+// newObjectStorage creates the backend selected in the config together with the check of its availability
+func newObjectStorage(cfg config.FileServiceConfig) (fileinterfaces.ObjectStorage, health.Check, error) {
+	switch cfg.Storage.Backend {
+	case "", "minio":
+		minioURL := dbinit.NewMinioEndpoint(cfg.Minio.Host, cfg.Minio.Port)
+		minioClient, err := dbinit.NewMinioClient(minioURL, cfg.Minio.AccessKey, cfg.Minio.SecretKey,
+			cfg.Minio.Bucket)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	log.Printf("Starting File gRPC service on %s", grpcAddr)
+		var presignClient *minio.Client
+		if cfg.Minio.PublicEndpoint != "" {
+			presignClient, err = dbinit.NewMinioPresignClient(cfg.Minio.PublicEndpoint, cfg.Minio.AccessKey,
+				cfg.Minio.SecretKey, cfg.Minio.Region, cfg.Minio.PublicSecure)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return objectrepo.NewObjectStorage(minioClient, presignClient, cfg.Minio.Bucket),
+			health.MinioCheck(minioClient, cfg.Minio.Bucket), nil
+	case "fs":
+		storage, err := objectrepo.NewFSStorage(cfg.Storage.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return storage, health.DirCheck(cfg.Storage.Path), nil
+	case "memory":
+		slog.Warn("Files are kept in memory and will be lost on restart")
+
+		return objectrepo.NewMemoryStorage(), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+func reconcile(ctx context.Context, metadataStorage fileinterfaces.MetadataStorage,
+	objectStorage fileinterfaces.ObjectStorage, cfg config.ReconcileConfig, repair bool) {
+	report, err := jobs.NewReconciler(metadataStorage, objectStorage, jobs.ReconcilerOptions{
+		GracePeriod: time.Duration(cfg.GracePeriod) * time.Second,
+		Repair:      repair,
+	}).Reconcile(ctx)
+	if err != nil {
+		logger.Fatal("Reconciliation failed", "error", err)
+	}
 
-	if err = srv.Serve(listener); err != nil {
-		log.Fatalf("gRPC Server failed to serve: %v", err)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Fatal("Cannot print reconciliation report", "error", err)
 	}
 }
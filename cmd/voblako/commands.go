@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IlyaChgn/voblako/pkg/client"
+	"github.com/google/uuid"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func newFlagSet(name, arguments string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: voblako %s [flags] %s\n", name, arguments)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+func orgFlag(fs *flag.FlagSet) *uint {
+	return fs.Uint("org", 0, "ID of the organization, the personal space is used by default")
+}
+
+// parseFlags checks the number of positional arguments, maxArgs is negative if they are not limited
+func parseFlags(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fs.Usage()
+		return errUsage
+	}
+
+	return nil
+}
+
+func readLine(prompt string) (string, error) {
+	if prompt != "" {
+		fmt.Fprint(os.Stderr, prompt)
+	}
+
+	line, err := stdin.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// resolveFile finds the file by its ID or by its name, names must be unique to be used
+func resolveFile(ctx context.Context, c *client.Client, ref string, orgID uint) (*client.File, error) {
+	if _, err := uuid.Parse(ref); err == nil {
+		file, err := c.GetMetadata(ctx, ref)
+		if !errors.Is(err, client.ErrFileNotFound) {
+			return file, err
+		}
+	}
+
+	files, err := c.ListFiles(ctx, client.ListOptions{Limit: 2, OrgID: orgID, Filename: ref})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(files) {
+	case 0:
+		return nil, fmt.Errorf("%s: %w", ref, client.ErrFileNotFound)
+	case 1:
+		return files[0], nil
+	default:
+		return nil, fmt.Errorf("%s: several files have this name, use the ID of the file", ref)
+	}
+}
+
+func runLogin(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("login", "")
+	server := fs.String("server", cfg.Server, "URL of the gateway")
+	email := fs.String("email", cfg.Email, "email of the account")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var err error
+	if *email == "" {
+		if *email, err = readLine("Email: "); err != nil {
+			return err
+		}
+	}
+
+	var password string
+	if *passwordStdin {
+		password, err = readLine("")
+	} else {
+		password, err = readPassword("Password: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg.Server = strings.TrimSuffix(*server, "/")
+	cfg.SessionID = ""
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	user, err := c.Login(ctx, *email, password)
+	if err != nil {
+		return err
+	}
+
+	cfg.Email = user.Email
+	cfg.SessionID = c.SessionID()
+	if err := cfg.save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in to %s as %s\n", cfg.Server, user.Email)
+
+	return nil
+}
+
+func runLogout(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("logout", "")
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Logout(ctx); err != nil && !errors.Is(err, client.ErrNotAuthorized) {
+		return err
+	}
+
+	cfg.SessionID = ""
+
+	return cfg.save()
+}
+
+func runList(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("ls", "[PREFIX]")
+	orgID := orgFlag(fs)
+	withDeleted := fs.Bool("deleted", false, "list deleted files too")
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+	prefix := fs.Arg(0)
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSIZE\tUPDATED\tNAME")
+
+	for file, err := range c.Files(ctx, client.ListOptions{OrgID: *orgID, WithDeleted: *withDeleted}) {
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(file.Filename, prefix) {
+			continue
+		}
+
+		name := file.Filename
+		if file.IsDeleted {
+			name += " (deleted)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", file.UUID, formatSize(file.Size),
+			file.UpdateTime.Local().Format(time.DateTime), name)
+	}
+
+	return w.Flush()
+}
+
+func runPut(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("put", "LOCAL [NAME]")
+	orgID := orgFlag(fs)
+	contentType := fs.String("type", "", "content type of the file, detected from the content by default")
+	if err := parseFlags(fs, args, 1, 2); err != nil {
+		return err
+	}
+
+	local, name := fs.Arg(0), fs.Arg(1)
+	if name == "" {
+		if local == "-" {
+			fmt.Fprintln(os.Stderr, "voblako: the name is required to upload stdin")
+			return errUsage
+		}
+		name = filepath.Base(local)
+	}
+
+	var content io.Reader = os.Stdin
+	size := int64(-1)
+	if local != "-" {
+		file, err := os.Open(local)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		content, size = file, info.Size()
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	bar := newProgressBar(name, size)
+	file, err := c.UploadFile(ctx, name, content, client.UploadOptions{
+		OrgID:       *orgID,
+		ContentType: *contentType,
+		Progress:    bar.update,
+	})
+	bar.done()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(file.UUID)
+
+	return nil
+}
+
+func runGet(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("get", "FILE [LOCAL]")
+	orgID := orgFlag(fs)
+	if err := parseFlags(fs, args, 1, 2); err != nil {
+		return err
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	file, err := resolveFile(ctx, c, fs.Arg(0), *orgID)
+	if err != nil {
+		return err
+	}
+
+	local := fs.Arg(1)
+	if local == "-" {
+		_, err = c.DownloadFile(ctx, file.UUID, os.Stdout, nil)
+		return err
+	}
+
+	if local == "" {
+		local = path.Base(file.Filename)
+	} else if info, err := os.Stat(local); err == nil && info.IsDir() {
+		local = filepath.Join(local, path.Base(file.Filename))
+	}
+
+	return downloadTo(ctx, c, file, local)
+}
+
+// downloadTo writes the file next to the target and renames it, so a failed download keeps the old content
+func downloadTo(ctx context.Context, c *client.Client, file *client.File, local string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(local), ".voblako-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	bar := newProgressBar(file.Filename, file.Size)
+	_, err = c.DownloadFile(ctx, file.UUID, tmp, bar.update)
+	bar.done()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), local)
+}
+
+func runRemove(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("rm", "FILE...")
+	orgID := orgFlag(fs)
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range fs.Args() {
+		file, err := resolveFile(ctx, c, ref, *orgID)
+		if err != nil {
+			return err
+		}
+
+		if err := c.DeleteFile(ctx, file.UUID); err != nil {
+			return fmt.Errorf("%s: %w", ref, err)
+		}
+		fmt.Printf("removed %s\n", file.Filename)
+	}
+
+	return nil
+}
+
+func runMove(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("mv", "FILE NAME")
+	orgID := orgFlag(fs)
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	file, err := resolveFile(ctx, c, fs.Arg(0), *orgID)
+	if err != nil {
+		return err
+	}
+
+	return c.RenameFile(ctx, file.UUID, fs.Arg(1))
+}
+
+func runShare(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("share", "FILE")
+	orgID := orgFlag(fs)
+	if err := parseFlags(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	file, err := resolveFile(ctx, c, fs.Arg(0), *orgID)
+	if err != nil {
+		return err
+	}
+
+	downloadURL, err := c.GetDownloadURL(ctx, file.UUID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(downloadURL.URL)
+	fmt.Fprintf(os.Stderr, "The link expires at %s\n", downloadURL.ExpireTime.Local().Format(time.DateTime))
+
+	return nil
+}
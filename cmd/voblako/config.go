@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/IlyaChgn/voblako/pkg/client"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// config keeps the session of the logged in user, so the file is readable only by its owner
+type config struct {
+	Server    string `json:"server"`
+	Email     string `json:"email,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+
+	path string
+}
+
+func configPath() (string, error) {
+	if path := os.Getenv("VOBLAKO_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "voblako", "config.json"), nil
+}
+
+func loadConfig() (*config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config{Server: defaultServer, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (cfg *config) save() error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.path), 0o700); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(cfg.path, data, 0o600)
+}
+
+func (cfg *config) client() (*client.Client, error) {
+	return client.New(cfg.Server, client.Options{SessionID: cfg.SessionID, UserAgent: "voblako-cli"})
+}
+
+// writeFileAtomic replaces the file with a renamed temporary file, so it is never left half written
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
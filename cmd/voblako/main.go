@@ -0,0 +1,85 @@
+// Command voblako is the command-line client of the voblako REST API
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/IlyaChgn/voblako/pkg/client"
+)
+
+const usage = `Usage: voblako <command> [flags] [arguments]
+
+Commands:
+  login   [-server URL] [-email EMAIL] [-password-stdin]
+  logout
+  ls      [-org ID] [-deleted] [PREFIX]
+  put     [-org ID] [-type CONTENT_TYPE] LOCAL [NAME]
+  get     [-org ID] FILE [LOCAL]
+  rm      [-org ID] FILE...
+  mv      [-org ID] FILE NAME
+  share   [-org ID] FILE
+  sync    [-org ID] [-delete] [-dry-run] DIR FOLDER
+
+FILE is the ID of the file or its name. The configuration is kept in the file
+set by VOBLAKO_CONFIG, by default in the user configuration directory.
+Run "voblako <command> -h" for the flags of the command.
+`
+
+type command func(ctx context.Context, cfg *config, args []string) error
+
+var commands = map[string]command{
+	"login":  runLogin,
+	"logout": runLogout,
+	"ls":     runList,
+	"put":    runPut,
+	"get":    runGet,
+	"rm":     runRemove,
+	"mv":     runMove,
+	"share":  runShare,
+	"sync":   runSync,
+}
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "voblako: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "voblako: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = run(ctx, cfg, os.Args[2:])
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		stop()
+		os.Exit(2)
+	case errors.Is(err, client.ErrNotAuthorized):
+		fmt.Fprintln(os.Stderr, `voblako: not logged in, run "voblako login"`)
+	default:
+		fmt.Fprintf(os.Stderr, "voblako: %v\n", err)
+	}
+
+	stop()
+	os.Exit(1)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	barWidth       = 30
+	nameWidth      = 24
+	redrawInterval = 100 * time.Millisecond
+)
+
+// progressBar draws the transfer on stderr, it is silent if stderr is not a terminal
+type progressBar struct {
+	out     io.Writer
+	name    string
+	total   int64
+	current int64
+	drawn   time.Time
+}
+
+// newProgressBar returns the bar of a transfer with unknown size if total is negative
+func newProgressBar(name string, total int64) *progressBar {
+	bar := &progressBar{name: name, total: total}
+	if isTerminal(os.Stderr) {
+		bar.out = os.Stderr
+	}
+
+	return bar
+}
+
+func (b *progressBar) update(current int64) {
+	b.current = current
+	if b.out == nil || time.Since(b.drawn) < redrawInterval {
+		return
+	}
+
+	b.draw()
+}
+
+func (b *progressBar) done() {
+	if b.out == nil {
+		return
+	}
+
+	b.draw()
+	fmt.Fprintln(b.out)
+}
+
+func (b *progressBar) draw() {
+	b.drawn = time.Now()
+
+	name := b.name
+	if utf8.RuneCountInString(name) > nameWidth {
+		runes := []rune(name)
+		name = "…" + string(runes[len(runes)-nameWidth+1:])
+	}
+
+	if b.total < 0 {
+		fmt.Fprintf(b.out, "\r%-*s %s", nameWidth, name, formatSize(b.current))
+		return
+	}
+
+	percent := int64(100)
+	if b.total > 0 {
+		percent = min(b.current*100/b.total, 100)
+	}
+	filled := int(percent * barWidth / 100)
+
+	fmt.Fprintf(b.out, "\r%-*s [%s%s] %3d%% %s/%s", nameWidth, name, strings.Repeat("=", filled),
+		strings.Repeat(" ", barWidth-filled), percent, formatSize(b.current), formatSize(b.total))
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
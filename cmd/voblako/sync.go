@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/IlyaChgn/voblako/pkg/client"
+)
+
+// maxFilenameLen is the limit of the gateway, names of synced files include the remote folder
+const maxFilenameLen = 50
+
+// syncEntry is the state of a file after it was last synced. The file is not read again while its size
+// and modification time are the same, and it is not uploaded again while its checksum is the same.
+type syncEntry struct {
+	ID           string    `json:"id"`
+	Size         int64     `json:"size"`
+	ModTime      time.Time `json:"mod_time"`
+	Checksum     string    `json:"checksum"`
+	RemoteUpdate time.Time `json:"remote_update_time"`
+}
+
+type syncState struct {
+	Files map[string]*syncEntry `json:"files"`
+
+	path string
+}
+
+type localFile struct {
+	path    string
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+type syncStats struct {
+	uploaded, updated, deleted, unchanged, failed int
+}
+
+type syncer struct {
+	client *client.Client
+	orgID  uint
+	dir    string
+	folder string
+	delete bool
+	dryRun bool
+	state  *syncState
+	out    io.Writer
+}
+
+func runSync(ctx context.Context, cfg *config, args []string) error {
+	fs := newFlagSet("sync", "DIR FOLDER")
+	orgID := orgFlag(fs)
+	deleteRemote := fs.Bool("delete", false, "delete remote files that do not exist in DIR")
+	dryRun := fs.Bool("dry-run", false, "print the changes without making them")
+	if err := parseFlags(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	folder := strings.Trim(path.Clean("/"+fs.Arg(1)), "/")
+
+	c, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	statePath, err := syncStatePath(cfg, *orgID, dir, folder)
+	if err != nil {
+		return err
+	}
+	state, err := loadSyncState(statePath)
+	if err != nil {
+		return err
+	}
+
+	s := &syncer{
+		client: c,
+		orgID:  *orgID,
+		dir:    dir,
+		folder: folder,
+		delete: *deleteRemote,
+		dryRun: *dryRun,
+		state:  state,
+		out:    os.Stdout,
+	}
+
+	stats, err := s.run(ctx)
+	if !s.dryRun {
+		if saveErr := state.save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+
+	fmt.Printf("%d uploaded, %d updated, %d deleted, %d unchanged\n",
+		stats.uploaded, stats.updated, stats.deleted, stats.unchanged)
+	if err == nil && stats.failed > 0 {
+		err = fmt.Errorf("%d files failed to sync", stats.failed)
+	}
+
+	return err
+}
+
+// syncStatePath keeps the state of every pair of directory and folder separately in the cache directory
+func syncStatePath(cfg *config, orgID uint, dir, folder string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	key := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%d\x00%s\x00%s", cfg.Server, cfg.Email, orgID, dir, folder))
+
+	return filepath.Join(cacheDir, "voblako", "sync", hex.EncodeToString(key[:16])+".json"), nil
+}
+
+func loadSyncState(path string) (*syncState, error) {
+	state := &syncState{Files: make(map[string]*syncEntry), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid sync state %s: %w", path, err)
+	}
+	if state.Files == nil {
+		state.Files = make(map[string]*syncEntry)
+	}
+
+	return state, nil
+}
+
+func (state *syncState) save() error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(state.path), 0o700); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(state.path, data, 0o600)
+}
+
+// run uploads new and changed files of the directory, remote files missing locally are deleted only
+// with the delete option. Failed files are reported and skipped, so one file does not stop the sync.
+func (s *syncer) run(ctx context.Context) (syncStats, error) {
+	var stats syncStats
+
+	locals, err := s.localFiles()
+	if err != nil {
+		return stats, err
+	}
+	remotes, err := s.remoteFiles(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, local := range locals {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		uploaded, err := s.syncFile(ctx, local, remotes[local.name])
+		switch {
+		case err != nil:
+			stats.failed++
+			fmt.Fprintf(os.Stderr, "voblako: %s: %v\n", local.name, err)
+		case !uploaded:
+			stats.unchanged++
+		case remotes[local.name] == nil:
+			stats.uploaded++
+		default:
+			stats.updated++
+		}
+	}
+
+	synced := make(map[string]bool, len(locals))
+	for _, local := range locals {
+		synced[local.name] = true
+	}
+
+	for name := range s.state.Files {
+		if !synced[name] {
+			delete(s.state.Files, name)
+		}
+	}
+
+	if !s.delete {
+		return stats, nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(remotes)) {
+		if synced[name] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		fmt.Fprintf(s.out, "delete %s\n", name)
+		if s.dryRun {
+			stats.deleted++
+			continue
+		}
+
+		if err := s.client.DeleteFile(ctx, remotes[name].UUID); err != nil {
+			stats.failed++
+			fmt.Fprintf(os.Stderr, "voblako: %s: %v\n", name, err)
+			continue
+		}
+		stats.deleted++
+	}
+
+	return stats, nil
+}
+
+// syncFile uploads the file if it differs from the remote one and returns whether it was uploaded
+func (s *syncer) syncFile(ctx context.Context, local *localFile, remote *client.File) (bool, error) {
+	if utf8.RuneCountInString(local.name) > maxFilenameLen {
+		return false, fmt.Errorf("the name is longer than %d characters", maxFilenameLen)
+	}
+
+	entry := s.state.Files[local.name]
+	remoteKnown := entry != nil && remote != nil && entry.ID == remote.UUID &&
+		entry.RemoteUpdate.Equal(remote.UpdateTime) && entry.Size == remote.Size
+	if remoteKnown && entry.Size == local.size && entry.ModTime.Equal(local.modTime) {
+		return false, nil
+	}
+
+	checksum, err := fileChecksum(local.path)
+	if err != nil {
+		return false, err
+	}
+	if remoteKnown && entry.Checksum == checksum {
+		if !s.dryRun {
+			entry.ModTime = local.modTime
+		}
+		return false, nil
+	}
+
+	if remote == nil {
+		fmt.Fprintf(s.out, "upload %s\n", local.name)
+	} else {
+		fmt.Fprintf(s.out, "update %s\n", local.name)
+	}
+	if s.dryRun {
+		return true, nil
+	}
+
+	uploaded, err := s.upload(ctx, local, remote)
+	if err != nil {
+		return false, err
+	}
+
+	s.state.Files[local.name] = &syncEntry{
+		ID:           uploaded.UUID,
+		Size:         local.size,
+		ModTime:      local.modTime,
+		Checksum:     checksum,
+		RemoteUpdate: uploaded.UpdateTime,
+	}
+
+	return true, nil
+}
+
+// upload replaces the content of the remote file if it exists, its metadata is requested again for the
+// new update time
+func (s *syncer) upload(ctx context.Context, local *localFile, remote *client.File) (*client.File, error) {
+	file, err := os.Open(local.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	bar := newProgressBar(local.name, local.size)
+	defer bar.done()
+
+	options := client.UploadOptions{OrgID: s.orgID, Progress: bar.update}
+	if remote == nil {
+		return s.client.UploadFile(ctx, local.name, file, options)
+	}
+
+	if err := s.client.UpdateFile(ctx, remote.UUID, file, options); err != nil {
+		return nil, err
+	}
+
+	return s.client.GetMetadata(ctx, remote.UUID)
+}
+
+// localFiles returns the regular files of the directory sorted by name, symbolic links are not followed
+func (s *syncer) localFiles() ([]*localFile, error) {
+	var files []*localFile
+
+	err := filepath.WalkDir(s.dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(s.dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, &localFile{
+			path:    filePath,
+			name:    path.Join(s.folder, filepath.ToSlash(rel)),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(files, func(a, b *localFile) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return files, nil
+}
+
+// remoteFiles returns the files in the folder by name. Names are not unique, so the most recently updated
+// file is synced and the others are left as they are.
+func (s *syncer) remoteFiles(ctx context.Context) (map[string]*client.File, error) {
+	prefix := ""
+	if s.folder != "" {
+		prefix = s.folder + "/"
+	}
+
+	files := make(map[string]*client.File)
+	for file, err := range s.client.Files(ctx, client.ListOptions{OrgID: s.orgID}) {
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(file.Filename, prefix) {
+			continue
+		}
+
+		if other, ok := files[file.Filename]; ok && other.UpdateTime.After(file.UpdateTime) {
+			continue
+		}
+		files[file.Filename] = file
+	}
+
+	return files, nil
+}
+
+func fileChecksum(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
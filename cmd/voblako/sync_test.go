@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/IlyaChgn/voblako/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI keeps files in memory and counts the uploads
+type fakeAPI struct {
+	mu      sync.Mutex
+	files   []*client.File
+	content map[string]string
+	uploads int
+}
+
+func (api *fakeAPI) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/files", func(w http.ResponseWriter, r *http.Request) {
+		data, header := readForm(t, r)
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		file := &client.File{
+			UUID:       strconv.Itoa(len(api.files) + 1),
+			Filename:   header,
+			Size:       int64(len(data)),
+			UpdateTime: time.Now(),
+		}
+		api.files = append(api.files, file)
+		api.content[file.UUID] = data
+		api.uploads++
+		responses.SendOkResponse(w, file)
+	})
+	mux.HandleFunc("POST /api/files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := readForm(t, r)
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		file := api.find(r.PathValue("id"))
+		file.Size = int64(len(data))
+		file.UpdateTime = time.Now()
+		api.content[file.UUID] = data
+		api.uploads++
+		responses.SendOkResponse(w, nil)
+	})
+	mux.HandleFunc("POST /api/files/{id}/name", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.find(r.PathValue("id")).Filename = body["filename"]
+		responses.SendOkResponse(w, nil)
+	})
+	mux.HandleFunc("GET /api/files/{id}/meta", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		responses.SendOkResponse(w, api.find(r.PathValue("id")))
+	})
+	mux.HandleFunc("DELETE /api/files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.find(r.PathValue("id")).IsDeleted = true
+		responses.SendOkResponse(w, nil)
+	})
+	mux.HandleFunc("POST /api/files/list", func(w http.ResponseWriter, r *http.Request) {
+		var options client.ListOptions
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&options))
+
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		files := []*client.File{}
+		for _, file := range api.files {
+			if !file.IsDeleted {
+				files = append(files, file)
+			}
+		}
+		files = files[min(int(options.Offset), len(files)):]
+		responses.SendOkResponse(w, files[:min(int(options.Limit), len(files))])
+	})
+
+	return mux
+}
+
+func (api *fakeAPI) find(id string) *client.File {
+	for _, file := range api.files {
+		if file.UUID == id {
+			return file
+		}
+	}
+
+	return nil
+}
+
+func readForm(t *testing.T, r *http.Request) (string, string) {
+	file, header, err := r.FormFile("file")
+	require.NoError(t, err)
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+
+	return string(data), header.Filename
+}
+
+func TestSync(t *testing.T) {
+	api := &fakeAPI{content: make(map[string]string)}
+	server := httptest.NewServer(api.handler(t))
+	defer server.Close()
+
+	c, err := client.New(server.URL, client.Options{})
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("b"), 0o644))
+
+	api.files = append(api.files, &client.File{UUID: "old", Filename: "docs/old.txt", UpdateTime: time.Now()})
+	api.files = append(api.files, &client.File{UUID: "other", Filename: "other.txt", UpdateTime: time.Now()})
+
+	state, err := loadSyncState(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+
+	run := func(deleteRemote bool) syncStats {
+		s := &syncer{client: c, dir: dir, folder: "docs", delete: deleteRemote, state: state, out: io.Discard}
+		stats, err := s.run(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, state.save())
+
+		state, err = loadSyncState(state.path)
+		require.NoError(t, err)
+
+		return stats
+	}
+
+	assert.Equal(t, syncStats{uploaded: 2}, run(false))
+	assert.Equal(t, "b", api.content[state.Files["docs/sub/b.txt"].ID])
+	assert.Equal(t, 2, api.uploads)
+
+	assert.Equal(t, syncStats{unchanged: 2}, run(false))
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "a.txt"), later, later))
+	assert.Equal(t, syncStats{unchanged: 2}, run(false))
+	assert.Equal(t, 2, api.uploads)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "sub", "b.txt")))
+	assert.Equal(t, syncStats{updated: 1, deleted: 2}, run(true))
+	assert.Equal(t, "changed", api.content[state.Files["docs/a.txt"].ID])
+	assert.Len(t, state.Files, 1)
+
+	assert.True(t, api.find("old").IsDeleted)
+	assert.False(t, api.find("other").IsDeleted)
+}
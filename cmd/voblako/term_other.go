@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// readPassword cannot turn off the echo on this platform, use "login -password-stdin" to avoid it
+func readPassword(prompt string) (string, error) {
+	return readLine(prompt)
+}
@@ -1,9 +1,23 @@
 package models
 
+import "time"
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID           uint   `json:"id"`
 	Email        string `json:"email"`
 	PasswordHash string `json:"-"`
+	Verified     bool   `json:"verified"`
+	Role         string `json:"role"`
+	Disabled     bool   `json:"disabled"`
+
+	// AuthTime and AuthProvider describe the login of the session, the provider is empty for passwords
+	AuthTime     time.Time `json:"auth_time,omitzero"`
+	AuthProvider string    `json:"auth_provider,omitempty"`
 }
 
 type FullUserData struct {
@@ -26,3 +40,74 @@ type SignupData struct {
 	Password       string `json:"password"`
 	PasswordRepeat string `json:"password_repeat"`
 }
+
+type VerificationData struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationData struct {
+	Email string `json:"email"`
+}
+
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type OIDCState struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+}
+
+// AccessKey authenticates S3 clients. The secret is returned only when the key is created.
+type AccessKey struct {
+	ID         string    `json:"access_key_id"`
+	Secret     string    `json:"secret_access_key,omitempty"`
+	UserID     uint      `json:"-"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type DeleteAccountData struct {
+	Password string `json:"password"`
+}
+
+type UsersListOptions struct {
+	Query  string `json:"query"`
+	Limit  uint   `json:"limit"`
+	Offset uint   `json:"offset"`
+}
+
+type SetRoleData struct {
+	Role string `json:"role"`
+}
+
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// Organization.Role is the role of the user who requested the organization
+type Organization struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type OrgMember struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+}
+
+type CreateOrganizationData struct {
+	Name string `json:"name"`
+}
+
+type SetMemberData struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
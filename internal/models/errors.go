@@ -13,8 +13,60 @@ var (
 
 	UserNotExists     = errors.New("user does not exist")
 	UserAlreadyExists = errors.New("user already exists")
+	UserDisabledError = errors.New("user account is disabled")
+	InvalidRoleError  = errors.New("invalid role")
+
+	InvalidEmailError        = errors.New("invalid email")
+	UserNotVerified          = errors.New("user email is not verified")
+	UserAlreadyVerified      = errors.New("user email is already verified")
+	InvalidVerificationToken = errors.New("invalid or expired verification token")
+	SendingVerificationError = errors.New("error occurred while sending verification email")
+
+	UnknownProviderError  = errors.New("unknown identity provider")
+	InvalidOIDCStateError = errors.New("invalid or expired OIDC state")
+	OIDCExchangeError     = errors.New("error occurred while exchanging OIDC authorization code")
+	IdentityConflictError = errors.New("user with this email exists and cannot be linked")
+
+	OrgNotExists           = errors.New("organization does not exist")
+	InvalidOrgNameError    = errors.New("invalid organization name")
+	InvalidOrgRoleError    = errors.New("invalid organization role")
+	OrgOwnerImmutableError = errors.New("organization owner cannot be changed or removed")
+
+	InvalidServiceTokenError = errors.New("invalid or expired service token")
+
+	AccessKeyNotExists  = errors.New("access key does not exist")
+	AccessKeyLimitError = errors.New("too many access keys")
+
+	MalformedSignatureError = errors.New("request authorization is malformed")
+	SignatureMismatchError  = errors.New("request signature does not match")
+	RequestExpiredError     = errors.New("request is expired or its time is too skewed")
+	PayloadMismatchError    = errors.New("payload does not match its signed hash")
+
+	BucketNotExists          = errors.New("bucket does not exist")
+	MultipartUploadNotExists = errors.New("multipart upload does not exist")
+	InvalidPartError         = errors.New("part is not uploaded or its ETag does not match")
+	InvalidPartOrderError    = errors.New("parts are not in ascending order")
+	ObjectTooLargeError      = errors.New("object is too large")
+	TooManyUploadsError      = errors.New("too many multipart uploads are in progress")
+	MalformedXMLError        = errors.New("request XML is not well-formed")
+
+	PurgeJobNotExists  = errors.New("purge job does not exist")
+	ExportJobNotExists = errors.New("export job does not exist")
 
 	PermissionDeniedError = errors.New("permission denied")
 	InvalidInputError     = errors.New("invalid input")
 	InvalidFilenameError  = errors.New("invalid filename")
+	FileNotExists         = errors.New("file does not exist")
+	QuotaExceededError    = errors.New("storage quota exceeded")
+	UpdateConflictError   = errors.New("file is being updated by another request")
+	FileChangedError      = errors.New("file has been changed while it was read")
+
+	UploadNotFinishedError = errors.New("file is not uploaded to storage yet")
+	UploadMismatchError    = errors.New("uploaded file does not match the declared size or checksum")
+
+	PresignNotSupportedError = errors.New("storage backend cannot sign URLs")
+	PresignDisabledError     = errors.New("presigned URLs are disabled")
+
+	MasterKeyMissingError = errors.New("master key of the file is not configured")
+	CorruptedObjectError  = errors.New("stored object is corrupted or cannot be decrypted")
 )
@@ -1,16 +1,25 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
+// FilesListOptions.OrgID selects the organization space, zero means the personal space of the user
 type FilesListOptions struct {
 	Limit       uint `json:"limit"`
 	Offset      uint `json:"offset"`
 	WithDeleted bool `json:"with_deleted"`
+	OrgID       uint `json:"org_id"`
+	// Filename lists only files with exactly this name
+	Filename string `json:"filename"`
 }
 
+// FileMetadata.OwnerID is the user who uploaded the file. Files with non-zero OrgID belong to the organization.
 type FileMetadata struct {
 	UUID    string `json:"uuid"`
 	OwnerID uint   `json:"owner_id"`
+	OrgID   uint   `json:"org_id,omitempty"`
 
 	Filename    string `json:"filename"`
 	ContentType string `json:"content_type"`
@@ -18,18 +27,162 @@ type FileMetadata struct {
 	StorageKey  string `json:"-"`
 	IsDeleted   bool   `json:"is_deleted"`
 
+	// DataKey is nil for files stored in plaintext
+	DataKey *DataKey      `json:"-"`
+	Stored  StoredContent `json:"-"`
+
 	UploadTime  time.Time  `json:"upload_time"`
 	UpdateTime  time.Time  `json:"update_time"`
 	DeletedTime *time.Time `json:"deleted_time"`
 }
 
+// StoredContent describes the object of the file. Size is the size of the object, which differs from the size
+// of the file if it is compressed or encrypted. Encoding is empty for content stored uncompressed.
+// Checksum is the hex MD5 of the content before it is encoded, it is empty for files stored before it was kept.
+type StoredContent struct {
+	Encoding string
+	Size     int64
+	Checksum string
+}
+
+// DataKey is the key of the file content wrapped with the master key KeyID
+type DataKey struct {
+	FileID string
+	Key    []byte
+	KeyID  string
+}
+
 type GeneralFileData struct {
+	OrgID       uint
 	Filename    string
 	ContentType string
 	File        []byte
 	Size        int64
 }
 
+// StartUploadRequest declares the file uploaded directly to the storage, the object must match Size
+type StartUploadRequest struct {
+	OrgID       uint   `json:"org_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// PresignedUpload.UploadURL accepts a single PUT request with the file content until ExpireTime
+type PresignedUpload struct {
+	File       *FileMetadata `json:"file"`
+	UploadURL  string        `json:"upload_url"`
+	ExpireTime time.Time     `json:"expire_time"`
+}
+
+// FinishUploadRequest.Checksum is an optional hex MD5 of the content
+type FinishUploadRequest struct {
+	Checksum string `json:"checksum"`
+}
+
+type DownloadURL struct {
+	URL        string    `json:"url"`
+	ExpireTime time.Time `json:"expire_time"`
+}
+
+// ObjectInfo.Checksum is a hex MD5 of the object content
+type ObjectInfo struct {
+	Size     int64
+	Checksum string
+}
+
 type UpdateFilenameRequest struct {
 	Filename string `json:"filename"`
 }
+
+// StorageUsage counts only files that are not deleted. Zero quota means that the storage is unlimited
+type StorageUsage struct {
+	OwnerID    uint  `json:"owner_id,omitempty"`
+	OrgID      uint  `json:"org_id,omitempty"`
+	UsedBytes  int64 `json:"used_bytes"`
+	FilesCount int64 `json:"files_count"`
+	QuotaBytes int64 `json:"quota_bytes"`
+}
+
+type SetQuotaData struct {
+	QuotaBytes int64 `json:"quota_bytes"`
+}
+
+const (
+	JobStatusWaiting   = "waiting"
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusDone      = "done"
+	JobStatusFailed    = "failed"
+	JobStatusExpired   = "expired"
+	JobStatusCancelled = "cancelled"
+)
+
+type PurgeJob struct {
+	ID             string    `json:"id"`
+	OwnerID        uint      `json:"owner_id"`
+	Status         string    `json:"status"`
+	DeletedObjects int64     `json:"deleted_objects"`
+	Attempts       int       `json:"-"`
+	Error          string    `json:"error,omitempty"`
+	CreateTime     time.Time `json:"create_time"`
+	UpdateTime     time.Time `json:"update_time"`
+}
+
+type ExportJob struct {
+	ID          string     `json:"id"`
+	OwnerID     uint       `json:"owner_id"`
+	Status      string     `json:"status"`
+	Email       string     `json:"-"`
+	Account     []byte     `json:"-"`
+	ObjectKey   string     `json:"-"`
+	Size        int64      `json:"size"`
+	Attempts    int        `json:"-"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreateTime  time.Time  `json:"create_time"`
+	UpdateTime  time.Time  `json:"update_time"`
+	ExpireTime  *time.Time `json:"expire_time"`
+}
+
+type ExportManifest struct {
+	Account    json.RawMessage       `json:"account"`
+	Files      []*ExportManifestFile `json:"files"`
+	CreateTime time.Time             `json:"create_time"`
+}
+
+type ExportManifestFile struct {
+	FileMetadata
+	Path string `json:"path"`
+}
+
+const (
+	UploadStatePending   = "pending"
+	UploadStateCommitted = "committed"
+)
+
+// ObjectRef is a reference from a metadata row to the object storage, it is used by the reconciler
+type ObjectRef struct {
+	FileID      string
+	StorageKey  string
+	PendingKey  string
+	UploadState string
+	UpdateTime  time.Time
+}
+
+// ReconcileReport lists inconsistencies between metadata and objects. MissingObjects and StaleUploads hold
+// file IDs, StaleReplacements and OrphanObjects hold object keys.
+type ReconcileReport struct {
+	CheckedFiles      int      `json:"checked_files"`
+	CheckedObjects    int      `json:"checked_objects"`
+	MissingObjects    []string `json:"missing_objects"`
+	StaleUploads      []string `json:"stale_uploads"`
+	StaleReplacements []string `json:"stale_replacements"`
+	OrphanObjects     []string `json:"orphan_objects"`
+	Repaired          bool     `json:"repaired"`
+}
+
+func (r *ReconcileReport) Consistent() bool {
+	return len(r.MissingObjects) == 0 && len(r.StaleUploads) == 0 && len(r.StaleReplacements) == 0 &&
+		len(r.OrphanObjects) == 0
+}
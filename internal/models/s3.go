@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Bucket is the personal space of the user or the space of the organization
+type Bucket struct {
+	Name  string
+	OrgID uint
+}
+
+// Object is the file seen through the S3 API, the key is the filename
+type Object struct {
+	Key          string
+	UUID         string
+	ContentType  string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+type ListObjectsOptions struct {
+	Prefix     string
+	Delimiter  string
+	StartAfter string
+	MaxKeys    int
+}
+
+// ObjectsList.NextStartAfter is the last key or common prefix returned, if the list is truncated
+type ObjectsList struct {
+	Objects        []*Object
+	CommonPrefixes []string
+	IsTruncated    bool
+	NextStartAfter string
+}
+
+// MultipartUpload is kept until it is completed or aborted, the parts expire together with it
+type MultipartUpload struct {
+	ID          string    `json:"id"`
+	UserID      uint      `json:"user_id"`
+	Bucket      string    `json:"bucket"`
+	OrgID       uint      `json:"org_id"`
+	Key         string    `json:"key"`
+	ContentType string    `json:"content_type"`
+	CreateTime  time.Time `json:"create_time"`
+}
+
+type CompletedPart struct {
+	Number int
+	ETag   string
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxAccessKeys = 10
+
+	accessKeyIDPrefix = "VB"
+	// The sizes give IDs and secrets of the same length as AWS ones, some clients validate it
+	accessKeyIDSize     = 11
+	accessKeySecretSize = 30
+)
+
+func (m *AuthManager) CreateAccessKey(ctx context.Context, r *protobuf.UserIDData) (*protobuf.AccessKey, error) {
+	id := make([]byte, accessKeyIDSize)
+	secret := make([]byte, accessKeySecretSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	key, err := m.accessKeyStorage.CreateAccessKey(ctx, &models.AccessKey{
+		ID:     accessKeyIDPrefix + base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(id),
+		Secret: base64.StdEncoding.EncodeToString(secret),
+		UserID: uint(r.ID),
+	}, maxAccessKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAccessKey(key), nil
+}
+
+func (m *AuthManager) ListAccessKeys(ctx context.Context, r *protobuf.UserIDData) (*protobuf.AccessKeysList, error) {
+	keys, err := m.accessKeyStorage.ListAccessKeys(ctx, uint(r.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*protobuf.AccessKey, len(keys))
+	for k, v := range keys {
+		list[k] = convertAccessKey(v)
+	}
+
+	return &protobuf.AccessKeysList{AccessKeys: list}, nil
+}
+
+func (m *AuthManager) DeleteAccessKey(ctx context.Context, r *protobuf.AccessKeyRequest) (*emptypb.Empty, error) {
+	if err := m.accessKeyStorage.DeleteAccessKey(ctx, uint(r.UserID), r.AccessKeyID); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// GetAccessKey returns the key with the secret and its owner, it is used by the gateway to check signatures
+func (m *AuthManager) GetAccessKey(ctx context.Context, r *protobuf.AccessKeyRequest) (*protobuf.AccessKey, error) {
+	key, err := m.accessKeyStorage.GetAccessKey(ctx, r.AccessKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := m.authStorage.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, models.AccessKeyNotExists
+	}
+
+	resp := convertAccessKey(key)
+	resp.User = convertUser(user)
+	resp.User.PasswordHash = ""
+
+	return resp, nil
+}
+
+func convertAccessKey(key *models.AccessKey) *protobuf.AccessKey {
+	return &protobuf.AccessKey{
+		ID:         key.ID,
+		Secret:     key.Secret,
+		UserID:     uint32(key.UserID),
+		CreateTime: timestamppb.New(key.CreateTime),
+	}
+}
@@ -3,11 +3,17 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	authinterfaces "github.com/IlyaChgn/voblako/internal/pkg/auth"
 	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/mailer"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -16,16 +22,29 @@ import (
 type AuthManager struct {
 	protobuf.UnimplementedAuthServer
 
-	sessionManager authinterfaces.SessionManager
-	authStorage    authinterfaces.AuthRepository
+	sessionManager      authinterfaces.SessionManager
+	authStorage         authinterfaces.AuthRepository
+	orgStorage          authinterfaces.OrgRepository
+	accessKeyStorage    authinterfaces.AccessKeyRepository
+	verificationManager authinterfaces.VerificationManager
+	mailer              mailer.Mailer
+
+	verifyURL string
 }
 
 func NewAuthManager(
 	manager authinterfaces.SessionManager, storage authinterfaces.AuthRepository,
+	orgStorage authinterfaces.OrgRepository, accessKeyStorage authinterfaces.AccessKeyRepository,
+	verificationManager authinterfaces.VerificationManager, sender mailer.Mailer, verifyURL string,
 ) *AuthManager {
 	return &AuthManager{
-		sessionManager: manager,
-		authStorage:    storage,
+		sessionManager:      manager,
+		authStorage:         storage,
+		orgStorage:          orgStorage,
+		accessKeyStorage:    accessKeyStorage,
+		verificationManager: verificationManager,
+		mailer:              sender,
+		verifyURL:           verifyURL,
 	}
 }
 
@@ -44,8 +63,12 @@ func (m *AuthManager) CreateUser(ctx context.Context, newUser *protobuf.NewUser)
 
 func (m *AuthManager) CreateSession(ctx context.Context, user *protobuf.FullUserData) (*emptypb.Empty, error) {
 	return nil, m.sessionManager.CreateSession(ctx, user.SessionID, &models.User{
-		ID:    uint(user.User.ID),
-		Email: user.User.Email,
+		ID:           uint(user.User.ID),
+		Email:        user.User.Email,
+		Verified:     user.User.Verified,
+		Role:         user.User.Role,
+		AuthTime:     time.Now(),
+		AuthProvider: user.Provider,
 	})
 }
 
@@ -75,9 +98,221 @@ func (m *AuthManager) GetCurrentUser(ctx context.Context, session *protobuf.Sess
 
 	currUser := convertUser(user)
 	currUser.IsAuth = true
+	currUser.AuthProvider = user.AuthProvider
+	if !user.AuthTime.IsZero() {
+		currUser.AuthTime = user.AuthTime.Unix()
+	}
 	return currUser, nil
 }
 
+func (m *AuthManager) SendVerification(ctx context.Context, email *protobuf.EmailData) (*emptypb.Empty, error) {
+	user, err := m.authStorage.GetUserByEmail(ctx, email.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.UserNotExists.Error())
+	}
+	if user.Verified {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s", models.UserAlreadyVerified.Error())
+	}
+
+	token := uuid.NewString()
+	if err := m.verificationManager.CreateToken(ctx, token, user.ID); err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s?token=%s", m.verifyURL, url.QueryEscape(token))
+	body := fmt.Sprintf("To confirm your email address, follow the link:\n%s\n\nVerification code: %s", link, token)
+	if err := m.mailer.Send(ctx, user.Email, "Voblako email verification", body); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%s", models.SendingVerificationError.Error())
+	}
+
+	return nil, nil
+}
+
+func (m *AuthManager) VerifyEmail(ctx context.Context, data *protobuf.VerificationData) (*protobuf.User, error) {
+	userID, err := m.verificationManager.PopToken(ctx, data.Token)
+	if err != nil {
+		if errors.Is(err, models.InvalidVerificationToken) {
+			return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+		}
+
+		return nil, err
+	}
+
+	if err := m.authStorage.SetVerified(ctx, userID); err != nil {
+		if errors.Is(err, models.UserNotExists) {
+			return nil, status.Errorf(codes.NotFound, "%s", models.InvalidVerificationToken.Error())
+		}
+
+		return nil, err
+	}
+
+	// The email is already verified, sessions left with the old flag only lose access to verified routes
+	if err := m.sessionManager.SetUserVerified(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while updating sessions of the user", "error", err)
+	}
+
+	user, err := m.authStorage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertUser(user), nil
+}
+
+func (m *AuthManager) GetOrCreateExternalUser(
+	ctx context.Context, identity *protobuf.ExternalIdentity,
+) (*protobuf.User, error) {
+	user, err := m.authStorage.GetUserByIdentity(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		return convertUser(user), nil
+	}
+
+	existing, err := m.authStorage.GetUserByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		// Linking to a local account is safe only if the provider has confirmed the email
+		if !identity.EmailVerified {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", models.IdentityConflictError.Error())
+		}
+
+		if existing.Verified {
+			err = m.authStorage.LinkIdentity(ctx, existing.ID, identity.Provider, identity.Subject)
+			if err != nil {
+				return nil, err
+			}
+
+			return convertUser(existing), nil
+		}
+
+		// Anyone could have signed up with this email, so the password and sessions of the account are dropped
+		// before it is given to the owner of the email
+		err = m.authStorage.ClaimUnverifiedUser(ctx, existing.ID, identity.Provider, identity.Subject)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.sessionManager.RemoveUserSessions(ctx, existing.ID); err != nil {
+			return nil, err
+		}
+
+		user, err = m.authStorage.GetUserByID(ctx, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertUser(user), nil
+	}
+
+	user, err = m.authStorage.CreateExternalUser(ctx, &models.ExternalIdentity{
+		Provider:      identity.Provider,
+		Subject:       identity.Subject,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+	})
+	if err != nil {
+		if errors.Is(err, models.UserAlreadyExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "%s", models.IdentityConflictError.Error())
+		}
+
+		return nil, err
+	}
+
+	return convertUser(user), nil
+}
+
+func (m *AuthManager) DeleteUser(ctx context.Context, data *protobuf.UserIDData) (*emptypb.Empty, error) {
+	// Sessions are revoked first, so the user cannot act while the row is being removed
+	err := m.sessionManager.RemoveUserSessions(ctx, uint(data.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.authStorage.DeleteUser(ctx, uint(data.ID))
+	if err != nil {
+		if errors.Is(err, models.UserNotExists) {
+			return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+		}
+
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (m *AuthManager) GetUserByID(ctx context.Context, data *protobuf.UserIDData) (*protobuf.User, error) {
+	user, err := m.authStorage.GetUserByID(ctx, uint(data.ID))
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.UserNotExists.Error())
+	}
+
+	return convertUser(user), nil
+}
+
+func (m *AuthManager) ListUsers(ctx context.Context, r *protobuf.ListUsersRequest) (*protobuf.UsersList, error) {
+	users, err := m.authStorage.ListUsers(ctx, models.UsersListOptions{
+		Query:  r.Query,
+		Limit:  uint(r.Limit),
+		Offset: uint(r.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*protobuf.User, len(users))
+	for k, v := range users {
+		list[k] = convertUser(v)
+	}
+
+	return &protobuf.UsersList{Users: list}, nil
+}
+
+func (m *AuthManager) SetUserDisabled(ctx context.Context, r *protobuf.SetUserDisabledRequest) (*protobuf.User, error) {
+	err := m.authStorage.SetDisabled(ctx, uint(r.ID), r.Disabled)
+	if err != nil {
+		if errors.Is(err, models.UserNotExists) {
+			return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+		}
+
+		return nil, err
+	}
+
+	if r.Disabled {
+		if err := m.sessionManager.RemoveUserSessions(ctx, uint(r.ID)); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.GetUserByID(ctx, &protobuf.UserIDData{ID: r.ID})
+}
+
+func (m *AuthManager) SetUserRole(ctx context.Context, r *protobuf.SetUserRoleRequest) (*protobuf.User, error) {
+	err := m.authStorage.SetRole(ctx, uint(r.ID), r.Role)
+	if err != nil {
+		if errors.Is(err, models.UserNotExists) {
+			return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+		}
+
+		return nil, err
+	}
+
+	// The role is cached in sessions, so the user has to log in again to get the new one
+	if err := m.sessionManager.RemoveUserSessions(ctx, uint(r.ID)); err != nil {
+		return nil, err
+	}
+
+	return m.GetUserByID(ctx, &protobuf.UserIDData{ID: r.ID})
+}
+
 func convertUser(user *models.User) *protobuf.User {
 	if user == nil {
 		return nil
@@ -87,5 +322,8 @@ func convertUser(user *models.User) *protobuf.User {
 		ID:           uint32(user.ID),
 		Email:        user.Email,
 		PasswordHash: user.PasswordHash,
+		Verified:     user.Verified,
+		Role:         user.Role,
+		Disabled:     user.Disabled,
 	}
 }
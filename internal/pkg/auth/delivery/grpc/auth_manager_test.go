@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterfaces "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthStorage struct {
+	authinterfaces.AuthRepository
+	users   map[uint]*models.User
+	links   map[string]uint
+	claimed []uint
+}
+
+func (s *fakeAuthStorage) GetUserByIdentity(_ context.Context, provider, subject string) (*models.User, error) {
+	if id, ok := s.links[provider+":"+subject]; ok {
+		return s.users[id], nil
+	}
+
+	return nil, nil
+}
+
+func (s *fakeAuthStorage) GetUserByEmail(_ context.Context, email string) (*models.User, error) {
+	for _, user := range s.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *fakeAuthStorage) GetUserByID(_ context.Context, id uint) (*models.User, error) {
+	copied := *s.users[id]
+	return &copied, nil
+}
+
+func (s *fakeAuthStorage) LinkIdentity(_ context.Context, userID uint, provider, subject string) error {
+	s.links[provider+":"+subject] = userID
+	return nil
+}
+
+func (s *fakeAuthStorage) ClaimUnverifiedUser(_ context.Context, userID uint, provider, subject string) error {
+	s.claimed = append(s.claimed, userID)
+	s.users[userID].PasswordHash = utils.HashPassword("random")
+	s.users[userID].Verified = true
+	s.links[provider+":"+subject] = userID
+
+	return nil
+}
+
+type fakeSessionManager struct {
+	authinterfaces.SessionManager
+	removed []uint
+}
+
+func (m *fakeSessionManager) RemoveUserSessions(_ context.Context, userID uint) error {
+	m.removed = append(m.removed, userID)
+	return nil
+}
+
+func TestAuthManager_GetOrCreateExternalUser_UnverifiedAccount(t *testing.T) {
+	storage := &fakeAuthStorage{
+		users: map[uint]*models.User{
+			1: {ID: 1, Email: "victim@example.com", PasswordHash: utils.HashPassword("attacker-password")},
+		},
+		links: make(map[string]uint),
+	}
+	sessions := &fakeSessionManager{}
+	m := NewAuthManager(sessions, storage, nil, nil, nil, nil, "")
+
+	user, err := m.GetOrCreateExternalUser(context.Background(), &protobuf.ExternalIdentity{
+		Provider:      "google",
+		Subject:       "subject",
+		Email:         "victim@example.com",
+		EmailVerified: true,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, user.Verified)
+	assert.Equal(t, []uint{1}, storage.claimed)
+	assert.Equal(t, []uint{1}, sessions.removed)
+	// The password of whoever has signed up with the email must not work anymore
+	assert.False(t, utils.CheckPasswordHash("attacker-password", storage.users[1].PasswordHash))
+}
+
+func TestAuthManager_GetOrCreateExternalUser_VerifiedAccount(t *testing.T) {
+	storage := &fakeAuthStorage{
+		users: map[uint]*models.User{
+			1: {ID: 1, Email: "user@example.com", PasswordHash: utils.HashPassword("password"), Verified: true},
+		},
+		links: make(map[string]uint),
+	}
+	sessions := &fakeSessionManager{}
+	m := NewAuthManager(sessions, storage, nil, nil, nil, nil, "")
+
+	user, err := m.GetOrCreateExternalUser(context.Background(), &protobuf.ExternalIdentity{
+		Provider:      "google",
+		Subject:       "subject",
+		Email:         "user@example.com",
+		EmailVerified: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(1), user.ID)
+	assert.Empty(t, storage.claimed)
+	assert.Empty(t, sessions.removed)
+	assert.True(t, utils.CheckPasswordHash("password", storage.users[1].PasswordHash))
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (m *AuthManager) CreateOrganization(
+	ctx context.Context, r *protobuf.CreateOrganizationRequest,
+) (*protobuf.Organization, error) {
+	org, err := m.orgStorage.CreateOrganization(ctx, r.Name, uint(r.OwnerID))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertOrganization(org), nil
+}
+
+func (m *AuthManager) ListOrganizations(
+	ctx context.Context, r *protobuf.UserIDData,
+) (*protobuf.OrganizationsList, error) {
+	orgs, err := m.orgStorage.GetUserOrganizations(ctx, uint(r.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*protobuf.Organization, len(orgs))
+	for k, v := range orgs {
+		list[k] = convertOrganization(v)
+	}
+
+	return &protobuf.OrganizationsList{Organizations: list}, nil
+}
+
+// GetMembership is also used by the file service to authorize access to the organization space
+func (m *AuthManager) GetMembership(
+	ctx context.Context, r *protobuf.MembershipRequest,
+) (*protobuf.Organization, error) {
+	org, err := m.orgStorage.GetMembership(ctx, uint(r.OrgID), uint(r.UserID))
+	if err != nil {
+		return nil, err
+	} else if org == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.OrgNotExists.Error())
+	}
+
+	return convertOrganization(org), nil
+}
+
+func (m *AuthManager) ListMembers(ctx context.Context, r *protobuf.MembershipRequest) (*protobuf.MembersList, error) {
+	if _, err := m.GetMembership(ctx, r); err != nil {
+		return nil, err
+	}
+
+	members, err := m.orgStorage.GetMembers(ctx, uint(r.OrgID))
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*protobuf.Member, len(members))
+	for k, v := range members {
+		list[k] = convertMember(v)
+	}
+
+	return &protobuf.MembersList{Members: list}, nil
+}
+
+func (m *AuthManager) SetMember(ctx context.Context, r *protobuf.SetMemberRequest) (*protobuf.Member, error) {
+	if r.Role != models.OrgRoleAdmin && r.Role != models.OrgRoleMember {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", models.InvalidOrgRoleError.Error())
+	}
+
+	actor, err := m.orgStorage.GetMembership(ctx, uint(r.OrgID), uint(r.ActorID))
+	if err != nil {
+		return nil, err
+	} else if actor == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.OrgNotExists.Error())
+	}
+
+	user, err := m.authStorage.GetUserByEmail(ctx, r.Email)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.UserNotExists.Error())
+	}
+
+	target, err := m.orgStorage.GetMembership(ctx, uint(r.OrgID), user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetRole string
+	if target != nil {
+		targetRole = target.Role
+	}
+	if targetRole == models.OrgRoleOwner {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s", models.OrgOwnerImmutableError.Error())
+	}
+	if !canManage(actor.Role, targetRole) || !canManage(actor.Role, r.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	err = m.orgStorage.SetMember(ctx, uint(r.OrgID), user.ID, r.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Member{
+		UserID: uint32(user.ID),
+		Email:  user.Email,
+		Role:   r.Role,
+	}, nil
+}
+
+func (m *AuthManager) RemoveMember(ctx context.Context, r *protobuf.RemoveMemberRequest) (*emptypb.Empty, error) {
+	actor, err := m.orgStorage.GetMembership(ctx, uint(r.OrgID), uint(r.ActorID))
+	if err != nil {
+		return nil, err
+	} else if actor == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.OrgNotExists.Error())
+	}
+
+	target, err := m.orgStorage.GetMembership(ctx, uint(r.OrgID), uint(r.UserID))
+	if err != nil {
+		return nil, err
+	} else if target == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.UserNotExists.Error())
+	}
+
+	if target.Role == models.OrgRoleOwner {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s", models.OrgOwnerImmutableError.Error())
+	}
+	// Any member can leave the organization
+	if r.ActorID != r.UserID && !canManage(actor.Role, target.Role) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	err = m.orgStorage.RemoveMember(ctx, uint(r.OrgID), uint(r.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// canManage reports whether a member with actorRole may grant or revoke targetRole.
+// Owners manage everyone, admins manage only plain members.
+func canManage(actorRole, targetRole string) bool {
+	switch actorRole {
+	case models.OrgRoleOwner:
+		return true
+	case models.OrgRoleAdmin:
+		return targetRole == "" || targetRole == models.OrgRoleMember
+	default:
+		return false
+	}
+}
+
+func convertOrganization(org *models.Organization) *protobuf.Organization {
+	return &protobuf.Organization{
+		ID:   uint32(org.ID),
+		Name: org.Name,
+		Role: org.Role,
+	}
+}
+
+func convertMember(member *models.OrgMember) *protobuf.Member {
+	return &protobuf.Member{
+		UserID: uint32(member.UserID),
+		Email:  member.Email,
+		Role:   member.Role,
+	}
+}
@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// AccessPolicy binds every RPC to its caller. Sign up, login and verification happen before the gateway knows
+// the user, and access keys are looked up to authenticate S3 requests. The file service checks memberships and
+// deleted owners on its own behalf. Health checks come from the gateway readiness probe.
+var AccessPolicy = serviceauth.Policy{
+	Public: []string{
+		protobuf.Auth_CreateUser_FullMethodName,
+		protobuf.Auth_CreateSession_FullMethodName,
+		protobuf.Auth_Logout_FullMethodName,
+		protobuf.Auth_GetUserByEmail_FullMethodName,
+		protobuf.Auth_GetCurrentUser_FullMethodName,
+		protobuf.Auth_SendVerification_FullMethodName,
+		protobuf.Auth_VerifyEmail_FullMethodName,
+		protobuf.Auth_GetOrCreateExternalUser_FullMethodName,
+		protobuf.Auth_GetAccessKey_FullMethodName,
+		healthpb.Health_Check_FullMethodName,
+	},
+	Internal: []string{
+		protobuf.Auth_GetMembership_FullMethodName,
+		protobuf.Auth_GetUserByID_FullMethodName,
+	},
+	Admin: []string{
+		protobuf.Auth_ListUsers_FullMethodName,
+		protobuf.Auth_SetUserDisabled_FullMethodName,
+		protobuf.Auth_SetUserRole_FullMethodName,
+	},
+	Subjects: map[string]serviceauth.Subject{
+		protobuf.Auth_DeleteUser_FullMethodName:  serviceauth.SubjectOf((*protobuf.UserIDData).GetID),
+		protobuf.Auth_GetUserByID_FullMethodName: serviceauth.SubjectOf((*protobuf.UserIDData).GetID),
+		protobuf.Auth_CreateOrganization_FullMethodName: serviceauth.SubjectOf(
+			(*protobuf.CreateOrganizationRequest).GetOwnerID),
+		protobuf.Auth_ListOrganizations_FullMethodName: serviceauth.SubjectOf((*protobuf.UserIDData).GetID),
+		protobuf.Auth_GetMembership_FullMethodName:     serviceauth.SubjectOf((*protobuf.MembershipRequest).GetUserID),
+		protobuf.Auth_ListMembers_FullMethodName:       serviceauth.SubjectOf((*protobuf.MembershipRequest).GetUserID),
+		protobuf.Auth_SetMember_FullMethodName:         serviceauth.SubjectOf((*protobuf.SetMemberRequest).GetActorID),
+		protobuf.Auth_RemoveMember_FullMethodName:      serviceauth.SubjectOf((*protobuf.RemoveMemberRequest).GetActorID),
+		protobuf.Auth_CreateAccessKey_FullMethodName:   serviceauth.SubjectOf((*protobuf.UserIDData).GetID),
+		protobuf.Auth_ListAccessKeys_FullMethodName:    serviceauth.SubjectOf((*protobuf.UserIDData).GetID),
+		protobuf.Auth_DeleteAccessKey_FullMethodName:   serviceauth.SubjectOf((*protobuf.AccessKeyRequest).GetUserID),
+	},
+}
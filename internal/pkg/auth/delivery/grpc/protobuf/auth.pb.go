@@ -12,6 +12,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -77,9 +78,11 @@ func (x *NewUser) GetPassword() string {
 }
 
 type FullUserData struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	SessionID     string                 `protobuf:"bytes,2,opt,name=SessionID,proto3" json:"SessionID,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	User      *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	SessionID string                 `protobuf:"bytes,2,opt,name=SessionID,proto3" json:"SessionID,omitempty"`
+	// Provider is the identity provider of the login, it is empty for passwords
+	Provider      string `protobuf:"bytes,3,opt,name=Provider,proto3" json:"Provider,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -128,12 +131,25 @@ func (x *FullUserData) GetSessionID() string {
 	return ""
 }
 
+func (x *FullUserData) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
 type User struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            uint32                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=Email,proto3" json:"Email,omitempty"`
-	PasswordHash  string                 `protobuf:"bytes,3,opt,name=PasswordHash,proto3" json:"PasswordHash,omitempty"`
-	IsAuth        bool                   `protobuf:"varint,4,opt,name=IsAuth,proto3" json:"IsAuth,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ID           uint32                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Email        string                 `protobuf:"bytes,2,opt,name=Email,proto3" json:"Email,omitempty"`
+	PasswordHash string                 `protobuf:"bytes,3,opt,name=PasswordHash,proto3" json:"PasswordHash,omitempty"`
+	IsAuth       bool                   `protobuf:"varint,4,opt,name=IsAuth,proto3" json:"IsAuth,omitempty"`
+	Verified     bool                   `protobuf:"varint,5,opt,name=Verified,proto3" json:"Verified,omitempty"`
+	Role         string                 `protobuf:"bytes,6,opt,name=Role,proto3" json:"Role,omitempty"`
+	Disabled     bool                   `protobuf:"varint,7,opt,name=Disabled,proto3" json:"Disabled,omitempty"`
+	// AuthTime (unix seconds) and AuthProvider describe the login of the session
+	AuthTime      int64  `protobuf:"varint,8,opt,name=AuthTime,proto3" json:"AuthTime,omitempty"`
+	AuthProvider  string `protobuf:"bytes,9,opt,name=AuthProvider,proto3" json:"AuthProvider,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -196,6 +212,41 @@ func (x *User) GetIsAuth() bool {
 	return false
 }
 
+func (x *User) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *User) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *User) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *User) GetAuthTime() int64 {
+	if x != nil {
+		return x.AuthTime
+	}
+	return 0
+}
+
+func (x *User) GetAuthProvider() string {
+	if x != nil {
+		return x.AuthProvider
+	}
+	return ""
+}
+
 type SessionData struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SessionID     string                 `protobuf:"bytes,1,opt,name=SessionID,proto3" json:"SessionID,omitempty"`
@@ -284,34 +335,1099 @@ func (x *EmailData) GetEmail() string {
 	return ""
 }
 
-var File_auth_proto protoreflect.FileDescriptor
+type VerificationData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_auth_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"auth.proto\x12\bprotobuf\x1a\x1bgoogle/protobuf/empty.proto\";\n" +
-	"\aNewUser\x12\x14\n" +
-	"\x05Email\x18\x01 \x01(\tR\x05Email\x12\x1a\n" +
-	"\bPassword\x18\x02 \x01(\tR\bPassword\"P\n" +
-	"\fFullUserData\x12\"\n" +
-	"\x04user\x18\x01 \x01(\v2\x0e.protobuf.UserR\x04user\x12\x1c\n" +
-	"\tSessionID\x18\x02 \x01(\tR\tSessionID\"h\n" +
-	"\x04User\x12\x0e\n" +
-	"\x02ID\x18\x01 \x01(\rR\x02ID\x12\x14\n" +
-	"\x05Email\x18\x02 \x01(\tR\x05Email\x12\"\n" +
-	"\fPasswordHash\x18\x03 \x01(\tR\fPasswordHash\x12\x16\n" +
-	"\x06IsAuth\x18\x04 \x01(\bR\x06IsAuth\"+\n" +
-	"\vSessionData\x12\x1c\n" +
-	"\tSessionID\x18\x01 \x01(\tR\tSessionID\"!\n" +
-	"\tEmailData\x12\x14\n" +
-	"\x05Email\x18\x01 \x01(\tR\x05Email2\xa1\x02\n" +
-	"\x04Auth\x12/\n" +
-	"\n" +
-	"CreateUser\x12\x11.protobuf.NewUser\x1a\x0e.protobuf.User\x12?\n" +
-	"\rCreateSession\x12\x16.protobuf.FullUserData\x1a\x16.google.protobuf.Empty\x127\n" +
-	"\x06Logout\x12\x15.protobuf.SessionData\x1a\x16.google.protobuf.Empty\x125\n" +
-	"\x0eGetUserByEmail\x12\x13.protobuf.EmailData\x1a\x0e.protobuf.User\x127\n" +
-	"\x0eGetCurrentUser\x12\x15.protobuf.SessionData\x1a\x0e.protobuf.UserB@Z>auth_service/internal/pkg/auth/delivery/grpc/protobuf;protobufb\x06proto3"
+func (x *VerificationData) Reset() {
+	*x = VerificationData{}
+	mi := &file_auth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerificationData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerificationData) ProtoMessage() {}
+
+func (x *VerificationData) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerificationData.ProtoReflect.Descriptor instead.
+func (*VerificationData) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerificationData) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ExternalIdentity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=Provider,proto3" json:"Provider,omitempty"`
+	Subject       string                 `protobuf:"bytes,2,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=Email,proto3" json:"Email,omitempty"`
+	EmailVerified bool                   `protobuf:"varint,4,opt,name=EmailVerified,proto3" json:"EmailVerified,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExternalIdentity) Reset() {
+	*x = ExternalIdentity{}
+	mi := &file_auth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExternalIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExternalIdentity) ProtoMessage() {}
+
+func (x *ExternalIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExternalIdentity.ProtoReflect.Descriptor instead.
+func (*ExternalIdentity) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ExternalIdentity) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ExternalIdentity) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ExternalIdentity) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ExternalIdentity) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+type UserIDData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            uint32                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserIDData) Reset() {
+	*x = UserIDData{}
+	mi := &file_auth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserIDData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserIDData) ProtoMessage() {}
+
+func (x *UserIDData) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserIDData.ProtoReflect.Descriptor instead.
+func (*UserIDData) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UserIDData) GetID() uint32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+type ListUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	Limit         uint32                 `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	Offset        uint32                 `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_auth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetOffset() uint32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type UsersList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=Users,proto3" json:"Users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsersList) Reset() {
+	*x = UsersList{}
+	mi := &file_auth_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsersList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsersList) ProtoMessage() {}
+
+func (x *UsersList) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsersList.ProtoReflect.Descriptor instead.
+func (*UsersList) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UsersList) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type SetUserDisabledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            uint32                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Disabled      bool                   `protobuf:"varint,2,opt,name=Disabled,proto3" json:"Disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserDisabledRequest) Reset() {
+	*x = SetUserDisabledRequest{}
+	mi := &file_auth_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserDisabledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserDisabledRequest) ProtoMessage() {}
+
+func (x *SetUserDisabledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserDisabledRequest.ProtoReflect.Descriptor instead.
+func (*SetUserDisabledRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SetUserDisabledRequest) GetID() uint32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *SetUserDisabledRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type SetUserRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            uint32                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=Role,proto3" json:"Role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetUserRoleRequest) Reset() {
+	*x = SetUserRoleRequest{}
+	mi := &file_auth_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetUserRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserRoleRequest) ProtoMessage() {}
+
+func (x *SetUserRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetUserRoleRequest.ProtoReflect.Descriptor instead.
+func (*SetUserRoleRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetUserRoleRequest) GetID() uint32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *SetUserRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type Organization struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            uint32                 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=Role,proto3" json:"Role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Organization) Reset() {
+	*x = Organization{}
+	mi := &file_auth_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Organization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Organization) ProtoMessage() {}
+
+func (x *Organization) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Organization.ProtoReflect.Descriptor instead.
+func (*Organization) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Organization) GetID() uint32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Organization) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Organization) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type CreateOrganizationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrganizationRequest) Reset() {
+	*x = CreateOrganizationRequest{}
+	mi := &file_auth_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrganizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrganizationRequest) ProtoMessage() {}
+
+func (x *CreateOrganizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrganizationRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrganizationRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateOrganizationRequest) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *CreateOrganizationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type OrganizationsList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Organizations []*Organization        `protobuf:"bytes,1,rep,name=Organizations,proto3" json:"Organizations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrganizationsList) Reset() {
+	*x = OrganizationsList{}
+	mi := &file_auth_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrganizationsList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrganizationsList) ProtoMessage() {}
+
+func (x *OrganizationsList) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrganizationsList.ProtoReflect.Descriptor instead.
+func (*OrganizationsList) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OrganizationsList) GetOrganizations() []*Organization {
+	if x != nil {
+		return x.Organizations
+	}
+	return nil
+}
+
+type MembershipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrgID         uint32                 `protobuf:"varint,1,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	UserID        uint32                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MembershipRequest) Reset() {
+	*x = MembershipRequest{}
+	mi := &file_auth_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MembershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MembershipRequest) ProtoMessage() {}
+
+func (x *MembershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MembershipRequest.ProtoReflect.Descriptor instead.
+func (*MembershipRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MembershipRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *MembershipRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type Member struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        uint32                 `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=Email,proto3" json:"Email,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=Role,proto3" json:"Role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Member) Reset() {
+	*x = Member{}
+	mi := &file_auth_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Member) ProtoMessage() {}
+
+func (x *Member) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Member.ProtoReflect.Descriptor instead.
+func (*Member) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Member) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *Member) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Member) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type MembersList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*Member              `protobuf:"bytes,1,rep,name=Members,proto3" json:"Members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MembersList) Reset() {
+	*x = MembersList{}
+	mi := &file_auth_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MembersList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MembersList) ProtoMessage() {}
+
+func (x *MembersList) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MembersList.ProtoReflect.Descriptor instead.
+func (*MembersList) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MembersList) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type SetMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorID       uint32                 `protobuf:"varint,1,opt,name=ActorID,proto3" json:"ActorID,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,2,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=Email,proto3" json:"Email,omitempty"`
+	Role          string                 `protobuf:"bytes,4,opt,name=Role,proto3" json:"Role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMemberRequest) Reset() {
+	*x = SetMemberRequest{}
+	mi := &file_auth_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMemberRequest) ProtoMessage() {}
+
+func (x *SetMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMemberRequest.ProtoReflect.Descriptor instead.
+func (*SetMemberRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetMemberRequest) GetActorID() uint32 {
+	if x != nil {
+		return x.ActorID
+	}
+	return 0
+}
+
+func (x *SetMemberRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *SetMemberRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SetMemberRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type RemoveMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorID       uint32                 `protobuf:"varint,1,opt,name=ActorID,proto3" json:"ActorID,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,2,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	UserID        uint32                 `protobuf:"varint,3,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_auth_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RemoveMemberRequest) GetActorID() uint32 {
+	if x != nil {
+		return x.ActorID
+	}
+	return 0
+}
+
+func (x *RemoveMemberRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *RemoveMemberRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type AccessKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	UserID        uint32                 `protobuf:"varint,3,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=CreateTime,proto3" json:"CreateTime,omitempty"`
+	User          *User                  `protobuf:"bytes,5,opt,name=User,proto3" json:"User,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessKey) Reset() {
+	*x = AccessKey{}
+	mi := &file_auth_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessKey) ProtoMessage() {}
+
+func (x *AccessKey) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessKey.ProtoReflect.Descriptor instead.
+func (*AccessKey) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AccessKey) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *AccessKey) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *AccessKey) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *AccessKey) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *AccessKey) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type AccessKeysList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessKeys    []*AccessKey           `protobuf:"bytes,1,rep,name=AccessKeys,proto3" json:"AccessKeys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessKeysList) Reset() {
+	*x = AccessKeysList{}
+	mi := &file_auth_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessKeysList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessKeysList) ProtoMessage() {}
+
+func (x *AccessKeysList) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessKeysList.ProtoReflect.Descriptor instead.
+func (*AccessKeysList) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AccessKeysList) GetAccessKeys() []*AccessKey {
+	if x != nil {
+		return x.AccessKeys
+	}
+	return nil
+}
+
+type AccessKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        uint32                 `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	AccessKeyID   string                 `protobuf:"bytes,2,opt,name=AccessKeyID,proto3" json:"AccessKeyID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessKeyRequest) Reset() {
+	*x = AccessKeyRequest{}
+	mi := &file_auth_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessKeyRequest) ProtoMessage() {}
+
+func (x *AccessKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_auth_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessKeyRequest.ProtoReflect.Descriptor instead.
+func (*AccessKeyRequest) Descriptor() ([]byte, []int) {
+	return file_auth_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AccessKeyRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *AccessKeyRequest) GetAccessKeyID() string {
+	if x != nil {
+		return x.AccessKeyID
+	}
+	return ""
+}
+
+var File_auth_proto protoreflect.FileDescriptor
+
+const file_auth_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"auth.proto\x12\bprotobuf\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\";\n" +
+	"\aNewUser\x12\x14\n" +
+	"\x05Email\x18\x01 \x01(\tR\x05Email\x12\x1a\n" +
+	"\bPassword\x18\x02 \x01(\tR\bPassword\"l\n" +
+	"\fFullUserData\x12\"\n" +
+	"\x04user\x18\x01 \x01(\v2\x0e.protobuf.UserR\x04user\x12\x1c\n" +
+	"\tSessionID\x18\x02 \x01(\tR\tSessionID\x12\x1a\n" +
+	"\bProvider\x18\x03 \x01(\tR\bProvider\"\xf4\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\rR\x02ID\x12\x14\n" +
+	"\x05Email\x18\x02 \x01(\tR\x05Email\x12\"\n" +
+	"\fPasswordHash\x18\x03 \x01(\tR\fPasswordHash\x12\x16\n" +
+	"\x06IsAuth\x18\x04 \x01(\bR\x06IsAuth\x12\x1a\n" +
+	"\bVerified\x18\x05 \x01(\bR\bVerified\x12\x12\n" +
+	"\x04Role\x18\x06 \x01(\tR\x04Role\x12\x1a\n" +
+	"\bDisabled\x18\a \x01(\bR\bDisabled\x12\x1a\n" +
+	"\bAuthTime\x18\b \x01(\x03R\bAuthTime\x12\"\n" +
+	"\fAuthProvider\x18\t \x01(\tR\fAuthProvider\"+\n" +
+	"\vSessionData\x12\x1c\n" +
+	"\tSessionID\x18\x01 \x01(\tR\tSessionID\"!\n" +
+	"\tEmailData\x12\x14\n" +
+	"\x05Email\x18\x01 \x01(\tR\x05Email\"(\n" +
+	"\x10VerificationData\x12\x14\n" +
+	"\x05Token\x18\x01 \x01(\tR\x05Token\"\x84\x01\n" +
+	"\x10ExternalIdentity\x12\x1a\n" +
+	"\bProvider\x18\x01 \x01(\tR\bProvider\x12\x18\n" +
+	"\aSubject\x18\x02 \x01(\tR\aSubject\x12\x14\n" +
+	"\x05Email\x18\x03 \x01(\tR\x05Email\x12$\n" +
+	"\rEmailVerified\x18\x04 \x01(\bR\rEmailVerified\"\x1c\n" +
+	"\n" +
+	"UserIDData\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\rR\x02ID\"V\n" +
+	"\x10ListUsersRequest\x12\x14\n" +
+	"\x05Query\x18\x01 \x01(\tR\x05Query\x12\x14\n" +
+	"\x05Limit\x18\x02 \x01(\rR\x05Limit\x12\x16\n" +
+	"\x06Offset\x18\x03 \x01(\rR\x06Offset\"1\n" +
+	"\tUsersList\x12$\n" +
+	"\x05Users\x18\x01 \x03(\v2\x0e.protobuf.UserR\x05Users\"D\n" +
+	"\x16SetUserDisabledRequest\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\rR\x02ID\x12\x1a\n" +
+	"\bDisabled\x18\x02 \x01(\bR\bDisabled\"8\n" +
+	"\x12SetUserRoleRequest\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\rR\x02ID\x12\x12\n" +
+	"\x04Role\x18\x02 \x01(\tR\x04Role\"F\n" +
+	"\fOrganization\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\rR\x02ID\x12\x12\n" +
+	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x12\n" +
+	"\x04Role\x18\x03 \x01(\tR\x04Role\"I\n" +
+	"\x19CreateOrganizationRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x12\n" +
+	"\x04Name\x18\x02 \x01(\tR\x04Name\"Q\n" +
+	"\x11OrganizationsList\x12<\n" +
+	"\rOrganizations\x18\x01 \x03(\v2\x16.protobuf.OrganizationR\rOrganizations\"A\n" +
+	"\x11MembershipRequest\x12\x14\n" +
+	"\x05OrgID\x18\x01 \x01(\rR\x05OrgID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"J\n" +
+	"\x06Member\x12\x16\n" +
+	"\x06UserID\x18\x01 \x01(\rR\x06UserID\x12\x14\n" +
+	"\x05Email\x18\x02 \x01(\tR\x05Email\x12\x12\n" +
+	"\x04Role\x18\x03 \x01(\tR\x04Role\"9\n" +
+	"\vMembersList\x12*\n" +
+	"\aMembers\x18\x01 \x03(\v2\x10.protobuf.MemberR\aMembers\"l\n" +
+	"\x10SetMemberRequest\x12\x18\n" +
+	"\aActorID\x18\x01 \x01(\rR\aActorID\x12\x14\n" +
+	"\x05OrgID\x18\x02 \x01(\rR\x05OrgID\x12\x14\n" +
+	"\x05Email\x18\x03 \x01(\tR\x05Email\x12\x12\n" +
+	"\x04Role\x18\x04 \x01(\tR\x04Role\"]\n" +
+	"\x13RemoveMemberRequest\x12\x18\n" +
+	"\aActorID\x18\x01 \x01(\rR\aActorID\x12\x14\n" +
+	"\x05OrgID\x18\x02 \x01(\rR\x05OrgID\x12\x16\n" +
+	"\x06UserID\x18\x03 \x01(\rR\x06UserID\"\xab\x01\n" +
+	"\tAccessKey\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x16\n" +
+	"\x06Secret\x18\x02 \x01(\tR\x06Secret\x12\x16\n" +
+	"\x06UserID\x18\x03 \x01(\rR\x06UserID\x12:\n" +
+	"\n" +
+	"CreateTime\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"CreateTime\x12\"\n" +
+	"\x04User\x18\x05 \x01(\v2\x0e.protobuf.UserR\x04User\"E\n" +
+	"\x0eAccessKeysList\x123\n" +
+	"\n" +
+	"AccessKeys\x18\x01 \x03(\v2\x13.protobuf.AccessKeyR\n" +
+	"AccessKeys\"L\n" +
+	"\x10AccessKeyRequest\x12\x16\n" +
+	"\x06UserID\x18\x01 \x01(\rR\x06UserID\x12 \n" +
+	"\vAccessKeyID\x18\x02 \x01(\tR\vAccessKeyID2\xc3\v\n" +
+	"\x04Auth\x12/\n" +
+	"\n" +
+	"CreateUser\x12\x11.protobuf.NewUser\x1a\x0e.protobuf.User\x12?\n" +
+	"\rCreateSession\x12\x16.protobuf.FullUserData\x1a\x16.google.protobuf.Empty\x127\n" +
+	"\x06Logout\x12\x15.protobuf.SessionData\x1a\x16.google.protobuf.Empty\x125\n" +
+	"\x0eGetUserByEmail\x12\x13.protobuf.EmailData\x1a\x0e.protobuf.User\x127\n" +
+	"\x0eGetCurrentUser\x12\x15.protobuf.SessionData\x1a\x0e.protobuf.User\x12?\n" +
+	"\x10SendVerification\x12\x13.protobuf.EmailData\x1a\x16.google.protobuf.Empty\x129\n" +
+	"\vVerifyEmail\x12\x1a.protobuf.VerificationData\x1a\x0e.protobuf.User\x12E\n" +
+	"\x17GetOrCreateExternalUser\x12\x1a.protobuf.ExternalIdentity\x1a\x0e.protobuf.User\x12:\n" +
+	"\n" +
+	"DeleteUser\x12\x14.protobuf.UserIDData\x1a\x16.google.protobuf.Empty\x123\n" +
+	"\vGetUserByID\x12\x14.protobuf.UserIDData\x1a\x0e.protobuf.User\x12<\n" +
+	"\tListUsers\x12\x1a.protobuf.ListUsersRequest\x1a\x13.protobuf.UsersList\x12C\n" +
+	"\x0fSetUserDisabled\x12 .protobuf.SetUserDisabledRequest\x1a\x0e.protobuf.User\x12;\n" +
+	"\vSetUserRole\x12\x1c.protobuf.SetUserRoleRequest\x1a\x0e.protobuf.User\x12Q\n" +
+	"\x12CreateOrganization\x12#.protobuf.CreateOrganizationRequest\x1a\x16.protobuf.Organization\x12F\n" +
+	"\x11ListOrganizations\x12\x14.protobuf.UserIDData\x1a\x1b.protobuf.OrganizationsList\x12D\n" +
+	"\rGetMembership\x12\x1b.protobuf.MembershipRequest\x1a\x16.protobuf.Organization\x12A\n" +
+	"\vListMembers\x12\x1b.protobuf.MembershipRequest\x1a\x15.protobuf.MembersList\x129\n" +
+	"\tSetMember\x12\x1a.protobuf.SetMemberRequest\x1a\x10.protobuf.Member\x12E\n" +
+	"\fRemoveMember\x12\x1d.protobuf.RemoveMemberRequest\x1a\x16.google.protobuf.Empty\x12<\n" +
+	"\x0fCreateAccessKey\x12\x14.protobuf.UserIDData\x1a\x13.protobuf.AccessKey\x12@\n" +
+	"\x0eListAccessKeys\x12\x14.protobuf.UserIDData\x1a\x18.protobuf.AccessKeysList\x12E\n" +
+	"\x0fDeleteAccessKey\x12\x1a.protobuf.AccessKeyRequest\x1a\x16.google.protobuf.Empty\x12?\n" +
+	"\fGetAccessKey\x12\x1a.protobuf.AccessKeyRequest\x1a\x13.protobuf.AccessKeyBOZMgithub.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf;protobufb\x06proto3"
 
 var (
 	file_auth_proto_rawDescOnce sync.Once
@@ -325,32 +1441,93 @@ func file_auth_proto_rawDescGZIP() []byte {
 	return file_auth_proto_rawDescData
 }
 
-var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_auth_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_auth_proto_goTypes = []any{
-	(*NewUser)(nil),       // 0: protobuf.NewUser
-	(*FullUserData)(nil),  // 1: protobuf.FullUserData
-	(*User)(nil),          // 2: protobuf.User
-	(*SessionData)(nil),   // 3: protobuf.SessionData
-	(*EmailData)(nil),     // 4: protobuf.EmailData
-	(*emptypb.Empty)(nil), // 5: google.protobuf.Empty
+	(*NewUser)(nil),                   // 0: protobuf.NewUser
+	(*FullUserData)(nil),              // 1: protobuf.FullUserData
+	(*User)(nil),                      // 2: protobuf.User
+	(*SessionData)(nil),               // 3: protobuf.SessionData
+	(*EmailData)(nil),                 // 4: protobuf.EmailData
+	(*VerificationData)(nil),          // 5: protobuf.VerificationData
+	(*ExternalIdentity)(nil),          // 6: protobuf.ExternalIdentity
+	(*UserIDData)(nil),                // 7: protobuf.UserIDData
+	(*ListUsersRequest)(nil),          // 8: protobuf.ListUsersRequest
+	(*UsersList)(nil),                 // 9: protobuf.UsersList
+	(*SetUserDisabledRequest)(nil),    // 10: protobuf.SetUserDisabledRequest
+	(*SetUserRoleRequest)(nil),        // 11: protobuf.SetUserRoleRequest
+	(*Organization)(nil),              // 12: protobuf.Organization
+	(*CreateOrganizationRequest)(nil), // 13: protobuf.CreateOrganizationRequest
+	(*OrganizationsList)(nil),         // 14: protobuf.OrganizationsList
+	(*MembershipRequest)(nil),         // 15: protobuf.MembershipRequest
+	(*Member)(nil),                    // 16: protobuf.Member
+	(*MembersList)(nil),               // 17: protobuf.MembersList
+	(*SetMemberRequest)(nil),          // 18: protobuf.SetMemberRequest
+	(*RemoveMemberRequest)(nil),       // 19: protobuf.RemoveMemberRequest
+	(*AccessKey)(nil),                 // 20: protobuf.AccessKey
+	(*AccessKeysList)(nil),            // 21: protobuf.AccessKeysList
+	(*AccessKeyRequest)(nil),          // 22: protobuf.AccessKeyRequest
+	(*timestamppb.Timestamp)(nil),     // 23: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),             // 24: google.protobuf.Empty
 }
 var file_auth_proto_depIdxs = []int32{
-	2, // 0: protobuf.FullUserData.user:type_name -> protobuf.User
-	0, // 1: protobuf.Auth.CreateUser:input_type -> protobuf.NewUser
-	1, // 2: protobuf.Auth.CreateSession:input_type -> protobuf.FullUserData
-	3, // 3: protobuf.Auth.Logout:input_type -> protobuf.SessionData
-	4, // 4: protobuf.Auth.GetUserByEmail:input_type -> protobuf.EmailData
-	3, // 5: protobuf.Auth.GetCurrentUser:input_type -> protobuf.SessionData
-	2, // 6: protobuf.Auth.CreateUser:output_type -> protobuf.User
-	5, // 7: protobuf.Auth.CreateSession:output_type -> google.protobuf.Empty
-	5, // 8: protobuf.Auth.Logout:output_type -> google.protobuf.Empty
-	2, // 9: protobuf.Auth.GetUserByEmail:output_type -> protobuf.User
-	2, // 10: protobuf.Auth.GetCurrentUser:output_type -> protobuf.User
-	6, // [6:11] is the sub-list for method output_type
-	1, // [1:6] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2,  // 0: protobuf.FullUserData.user:type_name -> protobuf.User
+	2,  // 1: protobuf.UsersList.Users:type_name -> protobuf.User
+	12, // 2: protobuf.OrganizationsList.Organizations:type_name -> protobuf.Organization
+	16, // 3: protobuf.MembersList.Members:type_name -> protobuf.Member
+	23, // 4: protobuf.AccessKey.CreateTime:type_name -> google.protobuf.Timestamp
+	2,  // 5: protobuf.AccessKey.User:type_name -> protobuf.User
+	20, // 6: protobuf.AccessKeysList.AccessKeys:type_name -> protobuf.AccessKey
+	0,  // 7: protobuf.Auth.CreateUser:input_type -> protobuf.NewUser
+	1,  // 8: protobuf.Auth.CreateSession:input_type -> protobuf.FullUserData
+	3,  // 9: protobuf.Auth.Logout:input_type -> protobuf.SessionData
+	4,  // 10: protobuf.Auth.GetUserByEmail:input_type -> protobuf.EmailData
+	3,  // 11: protobuf.Auth.GetCurrentUser:input_type -> protobuf.SessionData
+	4,  // 12: protobuf.Auth.SendVerification:input_type -> protobuf.EmailData
+	5,  // 13: protobuf.Auth.VerifyEmail:input_type -> protobuf.VerificationData
+	6,  // 14: protobuf.Auth.GetOrCreateExternalUser:input_type -> protobuf.ExternalIdentity
+	7,  // 15: protobuf.Auth.DeleteUser:input_type -> protobuf.UserIDData
+	7,  // 16: protobuf.Auth.GetUserByID:input_type -> protobuf.UserIDData
+	8,  // 17: protobuf.Auth.ListUsers:input_type -> protobuf.ListUsersRequest
+	10, // 18: protobuf.Auth.SetUserDisabled:input_type -> protobuf.SetUserDisabledRequest
+	11, // 19: protobuf.Auth.SetUserRole:input_type -> protobuf.SetUserRoleRequest
+	13, // 20: protobuf.Auth.CreateOrganization:input_type -> protobuf.CreateOrganizationRequest
+	7,  // 21: protobuf.Auth.ListOrganizations:input_type -> protobuf.UserIDData
+	15, // 22: protobuf.Auth.GetMembership:input_type -> protobuf.MembershipRequest
+	15, // 23: protobuf.Auth.ListMembers:input_type -> protobuf.MembershipRequest
+	18, // 24: protobuf.Auth.SetMember:input_type -> protobuf.SetMemberRequest
+	19, // 25: protobuf.Auth.RemoveMember:input_type -> protobuf.RemoveMemberRequest
+	7,  // 26: protobuf.Auth.CreateAccessKey:input_type -> protobuf.UserIDData
+	7,  // 27: protobuf.Auth.ListAccessKeys:input_type -> protobuf.UserIDData
+	22, // 28: protobuf.Auth.DeleteAccessKey:input_type -> protobuf.AccessKeyRequest
+	22, // 29: protobuf.Auth.GetAccessKey:input_type -> protobuf.AccessKeyRequest
+	2,  // 30: protobuf.Auth.CreateUser:output_type -> protobuf.User
+	24, // 31: protobuf.Auth.CreateSession:output_type -> google.protobuf.Empty
+	24, // 32: protobuf.Auth.Logout:output_type -> google.protobuf.Empty
+	2,  // 33: protobuf.Auth.GetUserByEmail:output_type -> protobuf.User
+	2,  // 34: protobuf.Auth.GetCurrentUser:output_type -> protobuf.User
+	24, // 35: protobuf.Auth.SendVerification:output_type -> google.protobuf.Empty
+	2,  // 36: protobuf.Auth.VerifyEmail:output_type -> protobuf.User
+	2,  // 37: protobuf.Auth.GetOrCreateExternalUser:output_type -> protobuf.User
+	24, // 38: protobuf.Auth.DeleteUser:output_type -> google.protobuf.Empty
+	2,  // 39: protobuf.Auth.GetUserByID:output_type -> protobuf.User
+	9,  // 40: protobuf.Auth.ListUsers:output_type -> protobuf.UsersList
+	2,  // 41: protobuf.Auth.SetUserDisabled:output_type -> protobuf.User
+	2,  // 42: protobuf.Auth.SetUserRole:output_type -> protobuf.User
+	12, // 43: protobuf.Auth.CreateOrganization:output_type -> protobuf.Organization
+	14, // 44: protobuf.Auth.ListOrganizations:output_type -> protobuf.OrganizationsList
+	12, // 45: protobuf.Auth.GetMembership:output_type -> protobuf.Organization
+	17, // 46: protobuf.Auth.ListMembers:output_type -> protobuf.MembersList
+	16, // 47: protobuf.Auth.SetMember:output_type -> protobuf.Member
+	24, // 48: protobuf.Auth.RemoveMember:output_type -> google.protobuf.Empty
+	20, // 49: protobuf.Auth.CreateAccessKey:output_type -> protobuf.AccessKey
+	21, // 50: protobuf.Auth.ListAccessKeys:output_type -> protobuf.AccessKeysList
+	24, // 51: protobuf.Auth.DeleteAccessKey:output_type -> google.protobuf.Empty
+	20, // 52: protobuf.Auth.GetAccessKey:output_type -> protobuf.AccessKey
+	30, // [30:53] is the sub-list for method output_type
+	7,  // [7:30] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_auth_proto_init() }
@@ -364,7 +1541,7 @@ func file_auth_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_auth_proto_rawDesc), len(file_auth_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
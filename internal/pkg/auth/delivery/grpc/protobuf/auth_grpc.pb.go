@@ -22,11 +22,29 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Auth_CreateUser_FullMethodName     = "/protobuf.Auth/CreateUser"
-	Auth_CreateSession_FullMethodName  = "/protobuf.Auth/CreateSession"
-	Auth_Logout_FullMethodName         = "/protobuf.Auth/Logout"
-	Auth_GetUserByEmail_FullMethodName = "/protobuf.Auth/GetUserByEmail"
-	Auth_GetCurrentUser_FullMethodName = "/protobuf.Auth/GetCurrentUser"
+	Auth_CreateUser_FullMethodName              = "/protobuf.Auth/CreateUser"
+	Auth_CreateSession_FullMethodName           = "/protobuf.Auth/CreateSession"
+	Auth_Logout_FullMethodName                  = "/protobuf.Auth/Logout"
+	Auth_GetUserByEmail_FullMethodName          = "/protobuf.Auth/GetUserByEmail"
+	Auth_GetCurrentUser_FullMethodName          = "/protobuf.Auth/GetCurrentUser"
+	Auth_SendVerification_FullMethodName        = "/protobuf.Auth/SendVerification"
+	Auth_VerifyEmail_FullMethodName             = "/protobuf.Auth/VerifyEmail"
+	Auth_GetOrCreateExternalUser_FullMethodName = "/protobuf.Auth/GetOrCreateExternalUser"
+	Auth_DeleteUser_FullMethodName              = "/protobuf.Auth/DeleteUser"
+	Auth_GetUserByID_FullMethodName             = "/protobuf.Auth/GetUserByID"
+	Auth_ListUsers_FullMethodName               = "/protobuf.Auth/ListUsers"
+	Auth_SetUserDisabled_FullMethodName         = "/protobuf.Auth/SetUserDisabled"
+	Auth_SetUserRole_FullMethodName             = "/protobuf.Auth/SetUserRole"
+	Auth_CreateOrganization_FullMethodName      = "/protobuf.Auth/CreateOrganization"
+	Auth_ListOrganizations_FullMethodName       = "/protobuf.Auth/ListOrganizations"
+	Auth_GetMembership_FullMethodName           = "/protobuf.Auth/GetMembership"
+	Auth_ListMembers_FullMethodName             = "/protobuf.Auth/ListMembers"
+	Auth_SetMember_FullMethodName               = "/protobuf.Auth/SetMember"
+	Auth_RemoveMember_FullMethodName            = "/protobuf.Auth/RemoveMember"
+	Auth_CreateAccessKey_FullMethodName         = "/protobuf.Auth/CreateAccessKey"
+	Auth_ListAccessKeys_FullMethodName          = "/protobuf.Auth/ListAccessKeys"
+	Auth_DeleteAccessKey_FullMethodName         = "/protobuf.Auth/DeleteAccessKey"
+	Auth_GetAccessKey_FullMethodName            = "/protobuf.Auth/GetAccessKey"
 )
 
 // AuthClient is the client API for Auth service.
@@ -38,6 +56,24 @@ type AuthClient interface {
 	Logout(ctx context.Context, in *SessionData, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetUserByEmail(ctx context.Context, in *EmailData, opts ...grpc.CallOption) (*User, error)
 	GetCurrentUser(ctx context.Context, in *SessionData, opts ...grpc.CallOption) (*User, error)
+	SendVerification(ctx context.Context, in *EmailData, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	VerifyEmail(ctx context.Context, in *VerificationData, opts ...grpc.CallOption) (*User, error)
+	GetOrCreateExternalUser(ctx context.Context, in *ExternalIdentity, opts ...grpc.CallOption) (*User, error)
+	DeleteUser(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetUserByID(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*User, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*UsersList, error)
+	SetUserDisabled(ctx context.Context, in *SetUserDisabledRequest, opts ...grpc.CallOption) (*User, error)
+	SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*User, error)
+	CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*Organization, error)
+	ListOrganizations(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*OrganizationsList, error)
+	GetMembership(ctx context.Context, in *MembershipRequest, opts ...grpc.CallOption) (*Organization, error)
+	ListMembers(ctx context.Context, in *MembershipRequest, opts ...grpc.CallOption) (*MembersList, error)
+	SetMember(ctx context.Context, in *SetMemberRequest, opts ...grpc.CallOption) (*Member, error)
+	RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CreateAccessKey(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*AccessKey, error)
+	ListAccessKeys(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*AccessKeysList, error)
+	DeleteAccessKey(ctx context.Context, in *AccessKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetAccessKey(ctx context.Context, in *AccessKeyRequest, opts ...grpc.CallOption) (*AccessKey, error)
 }
 
 type authClient struct {
@@ -98,6 +134,186 @@ func (c *authClient) GetCurrentUser(ctx context.Context, in *SessionData, opts .
 	return out, nil
 }
 
+func (c *authClient) SendVerification(ctx context.Context, in *EmailData, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Auth_SendVerification_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) VerifyEmail(ctx context.Context, in *VerificationData, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Auth_VerifyEmail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetOrCreateExternalUser(ctx context.Context, in *ExternalIdentity, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Auth_GetOrCreateExternalUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DeleteUser(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Auth_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetUserByID(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Auth_GetUserByID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*UsersList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsersList)
+	err := c.cc.Invoke(ctx, Auth_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) SetUserDisabled(ctx context.Context, in *SetUserDisabledRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Auth_SetUserDisabled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, Auth_SetUserRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CreateOrganization(ctx context.Context, in *CreateOrganizationRequest, opts ...grpc.CallOption) (*Organization, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, Auth_CreateOrganization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListOrganizations(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*OrganizationsList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrganizationsList)
+	err := c.cc.Invoke(ctx, Auth_ListOrganizations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetMembership(ctx context.Context, in *MembershipRequest, opts ...grpc.CallOption) (*Organization, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, Auth_GetMembership_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListMembers(ctx context.Context, in *MembershipRequest, opts ...grpc.CallOption) (*MembersList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MembersList)
+	err := c.cc.Invoke(ctx, Auth_ListMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) SetMember(ctx context.Context, in *SetMemberRequest, opts ...grpc.CallOption) (*Member, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Member)
+	err := c.cc.Invoke(ctx, Auth_SetMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RemoveMember(ctx context.Context, in *RemoveMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Auth_RemoveMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CreateAccessKey(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*AccessKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessKey)
+	err := c.cc.Invoke(ctx, Auth_CreateAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListAccessKeys(ctx context.Context, in *UserIDData, opts ...grpc.CallOption) (*AccessKeysList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessKeysList)
+	err := c.cc.Invoke(ctx, Auth_ListAccessKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DeleteAccessKey(ctx context.Context, in *AccessKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Auth_DeleteAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetAccessKey(ctx context.Context, in *AccessKeyRequest, opts ...grpc.CallOption) (*AccessKey, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccessKey)
+	err := c.cc.Invoke(ctx, Auth_GetAccessKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServer is the server API for Auth service.
 // All implementations must embed UnimplementedAuthServer
 // for forward compatibility.
@@ -107,6 +323,24 @@ type AuthServer interface {
 	Logout(context.Context, *SessionData) (*emptypb.Empty, error)
 	GetUserByEmail(context.Context, *EmailData) (*User, error)
 	GetCurrentUser(context.Context, *SessionData) (*User, error)
+	SendVerification(context.Context, *EmailData) (*emptypb.Empty, error)
+	VerifyEmail(context.Context, *VerificationData) (*User, error)
+	GetOrCreateExternalUser(context.Context, *ExternalIdentity) (*User, error)
+	DeleteUser(context.Context, *UserIDData) (*emptypb.Empty, error)
+	GetUserByID(context.Context, *UserIDData) (*User, error)
+	ListUsers(context.Context, *ListUsersRequest) (*UsersList, error)
+	SetUserDisabled(context.Context, *SetUserDisabledRequest) (*User, error)
+	SetUserRole(context.Context, *SetUserRoleRequest) (*User, error)
+	CreateOrganization(context.Context, *CreateOrganizationRequest) (*Organization, error)
+	ListOrganizations(context.Context, *UserIDData) (*OrganizationsList, error)
+	GetMembership(context.Context, *MembershipRequest) (*Organization, error)
+	ListMembers(context.Context, *MembershipRequest) (*MembersList, error)
+	SetMember(context.Context, *SetMemberRequest) (*Member, error)
+	RemoveMember(context.Context, *RemoveMemberRequest) (*emptypb.Empty, error)
+	CreateAccessKey(context.Context, *UserIDData) (*AccessKey, error)
+	ListAccessKeys(context.Context, *UserIDData) (*AccessKeysList, error)
+	DeleteAccessKey(context.Context, *AccessKeyRequest) (*emptypb.Empty, error)
+	GetAccessKey(context.Context, *AccessKeyRequest) (*AccessKey, error)
 	mustEmbedUnimplementedAuthServer()
 }
 
@@ -132,6 +366,60 @@ func (UnimplementedAuthServer) GetUserByEmail(context.Context, *EmailData) (*Use
 func (UnimplementedAuthServer) GetCurrentUser(context.Context, *SessionData) (*User, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentUser not implemented")
 }
+func (UnimplementedAuthServer) SendVerification(context.Context, *EmailData) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendVerification not implemented")
+}
+func (UnimplementedAuthServer) VerifyEmail(context.Context, *VerificationData) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedAuthServer) GetOrCreateExternalUser(context.Context, *ExternalIdentity) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrCreateExternalUser not implemented")
+}
+func (UnimplementedAuthServer) DeleteUser(context.Context, *UserIDData) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedAuthServer) GetUserByID(context.Context, *UserIDData) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserByID not implemented")
+}
+func (UnimplementedAuthServer) ListUsers(context.Context, *ListUsersRequest) (*UsersList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedAuthServer) SetUserDisabled(context.Context, *SetUserDisabledRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserDisabled not implemented")
+}
+func (UnimplementedAuthServer) SetUserRole(context.Context, *SetUserRoleRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserRole not implemented")
+}
+func (UnimplementedAuthServer) CreateOrganization(context.Context, *CreateOrganizationRequest) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedAuthServer) ListOrganizations(context.Context, *UserIDData) (*OrganizationsList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrganizations not implemented")
+}
+func (UnimplementedAuthServer) GetMembership(context.Context, *MembershipRequest) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMembership not implemented")
+}
+func (UnimplementedAuthServer) ListMembers(context.Context, *MembershipRequest) (*MembersList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMembers not implemented")
+}
+func (UnimplementedAuthServer) SetMember(context.Context, *SetMemberRequest) (*Member, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMember not implemented")
+}
+func (UnimplementedAuthServer) RemoveMember(context.Context, *RemoveMemberRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedAuthServer) CreateAccessKey(context.Context, *UserIDData) (*AccessKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccessKey not implemented")
+}
+func (UnimplementedAuthServer) ListAccessKeys(context.Context, *UserIDData) (*AccessKeysList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccessKeys not implemented")
+}
+func (UnimplementedAuthServer) DeleteAccessKey(context.Context, *AccessKeyRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccessKey not implemented")
+}
+func (UnimplementedAuthServer) GetAccessKey(context.Context, *AccessKeyRequest) (*AccessKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccessKey not implemented")
+}
 func (UnimplementedAuthServer) mustEmbedUnimplementedAuthServer() {}
 func (UnimplementedAuthServer) testEmbeddedByValue()              {}
 
@@ -243,6 +531,330 @@ func _Auth_GetCurrentUser_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_SendVerification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).SendVerification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_SendVerification_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).SendVerification(ctx, req.(*EmailData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerificationData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_VerifyEmail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).VerifyEmail(ctx, req.(*VerificationData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetOrCreateExternalUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIdentity)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetOrCreateExternalUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetOrCreateExternalUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetOrCreateExternalUser(ctx, req.(*ExternalIdentity))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeleteUser(ctx, req.(*UserIDData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetUserByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetUserByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetUserByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetUserByID(ctx, req.(*UserIDData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_SetUserDisabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserDisabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).SetUserDisabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_SetUserDisabled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).SetUserDisabled(ctx, req.(*SetUserDisabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_SetUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).SetUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_SetUserRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).SetUserRole(ctx, req.(*SetUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrganizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_CreateOrganization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CreateOrganization(ctx, req.(*CreateOrganizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListOrganizations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListOrganizations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListOrganizations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListOrganizations(ctx, req.(*UserIDData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetMembership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MembershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetMembership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetMembership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetMembership(ctx, req.(*MembershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MembershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListMembers(ctx, req.(*MembershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_SetMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).SetMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_SetMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).SetMember(ctx, req.(*SetMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RemoveMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RemoveMember(ctx, req.(*RemoveMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_CreateAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CreateAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_CreateAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CreateAccessKey(ctx, req.(*UserIDData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListAccessKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserIDData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListAccessKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListAccessKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListAccessKeys(ctx, req.(*UserIDData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_DeleteAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DeleteAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_DeleteAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DeleteAccessKey(ctx, req.(*AccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetAccessKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetAccessKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetAccessKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetAccessKey(ctx, req.(*AccessKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Auth_ServiceDesc is the grpc.ServiceDesc for Auth service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -270,6 +882,78 @@ var Auth_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCurrentUser",
 			Handler:    _Auth_GetCurrentUser_Handler,
 		},
+		{
+			MethodName: "SendVerification",
+			Handler:    _Auth_SendVerification_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _Auth_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "GetOrCreateExternalUser",
+			Handler:    _Auth_GetOrCreateExternalUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _Auth_DeleteUser_Handler,
+		},
+		{
+			MethodName: "GetUserByID",
+			Handler:    _Auth_GetUserByID_Handler,
+		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _Auth_ListUsers_Handler,
+		},
+		{
+			MethodName: "SetUserDisabled",
+			Handler:    _Auth_SetUserDisabled_Handler,
+		},
+		{
+			MethodName: "SetUserRole",
+			Handler:    _Auth_SetUserRole_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _Auth_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "ListOrganizations",
+			Handler:    _Auth_ListOrganizations_Handler,
+		},
+		{
+			MethodName: "GetMembership",
+			Handler:    _Auth_GetMembership_Handler,
+		},
+		{
+			MethodName: "ListMembers",
+			Handler:    _Auth_ListMembers_Handler,
+		},
+		{
+			MethodName: "SetMember",
+			Handler:    _Auth_SetMember_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _Auth_RemoveMember_Handler,
+		},
+		{
+			MethodName: "CreateAccessKey",
+			Handler:    _Auth_CreateAccessKey_Handler,
+		},
+		{
+			MethodName: "ListAccessKeys",
+			Handler:    _Auth_ListAccessKeys_Handler,
+		},
+		{
+			MethodName: "DeleteAccessKey",
+			Handler:    _Auth_DeleteAccessKey_Handler,
+		},
+		{
+			MethodName: "GetAccessKey",
+			Handler:    _Auth_GetAccessKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth.proto",
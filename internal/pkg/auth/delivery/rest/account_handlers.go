@@ -0,0 +1,178 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterfaces "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/gorilla/mux"
+)
+
+// reauthTimeout is how recent a login through an identity provider must be to replace the password
+const reauthTimeout = 10 * time.Minute
+
+type AccountHandler struct {
+	authUsecases authinterfaces.AuthUsecases
+	fileUsecases fileinterfaces.FileUsecases
+	ctxUserKey   string
+}
+
+func NewAccountHandler(authUsecases authinterfaces.AuthUsecases, fileUsecases fileinterfaces.FileUsecases,
+	ctxUserKey string) *AccountHandler {
+	return &AccountHandler{
+		authUsecases: authUsecases,
+		fileUsecases: fileUsecases,
+		ctxUserKey:   ctxUserKey,
+	}
+}
+
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var deleteData *models.DeleteAccountData
+	err := json.NewDecoder(r.Body).Decode(&deleteData)
+	if err != nil || deleteData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	// Accounts created through identity providers have no password they know, so a recent login
+	// through a provider confirms the deletion instead
+	if deleteData.Password == "" {
+		if user.AuthProvider == "" || time.Since(user.AuthTime) > reauthTimeout {
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrReauthRequired)
+			return
+		}
+	} else {
+		err = h.authUsecases.CheckPassword(ctx, user.Email, deleteData.Password)
+		if err != nil {
+			if errors.Is(err, models.PasswordsNotMatch) || errors.Is(err, models.UserNotExists) {
+				responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongCredentials)
+				return
+			}
+
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+			return
+		}
+	}
+
+	// The purge job is recorded before the user is deleted, so the files are found even if the deletion
+	// is interrupted. The job waits until the file service confirms that the user no longer exists.
+	job, err := h.fileUsecases.StartPurge(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	err = h.authUsecases.DeleteUser(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	if session, _ := r.Cookie("session_id"); session != nil {
+		session.Expires = time.Now().AddDate(0, 0, -1)
+		session.Path = "/"
+		http.SetCookie(w, session)
+	}
+
+	// Starting the job again only saves waiting for the next rescan of the purger, so errors are not fatal
+	if started, err := h.fileUsecases.StartPurge(context.WithoutCancel(ctx), user.ID); err != nil {
+		slog.WarnContext(ctx, "Purge of deleted user will start on the next rescan", "user_id", user.ID,
+			"error", err)
+	} else {
+		job = started
+	}
+
+	responses.SendOkResponse(w, job)
+}
+
+func (h *AccountHandler) GetDeletionStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	job, err := h.fileUsecases.GetPurgeJob(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		case errors.Is(err, models.PurgeJobNotExists):
+			responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrJobNotFound)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, job)
+}
+
+// CreateAccessKey responds with the secret, it cannot be retrieved later
+func (h *AccountHandler) CreateAccessKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	key, err := h.authUsecases.CreateAccessKey(ctx, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.AccessKeyLimitError):
+			responses.SendErrResponse(w, responses.StatusConflict, responses.ErrAccessKeyLimit)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, key)
+}
+
+func (h *AccountHandler) ListAccessKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	keys, err := h.authUsecases.ListAccessKeys(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, keys)
+}
+
+func (h *AccountHandler) DeleteAccessKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+	id := mux.Vars(r)["id"]
+
+	err := h.authUsecases.DeleteAccessKey(ctx, user.ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.AccessKeyNotExists):
+			responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrAccessKeyNotFound)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, nil)
+}
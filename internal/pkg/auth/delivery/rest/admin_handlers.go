@@ -0,0 +1,296 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterfaces "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/gorilla/mux"
+)
+
+type AdminHandler struct {
+	authUsecases authinterfaces.AuthUsecases
+	fileUsecases fileinterfaces.FileUsecases
+	ctxUserKey   string
+}
+
+func NewAdminHandler(authUsecases authinterfaces.AuthUsecases, fileUsecases fileinterfaces.FileUsecases,
+	ctxUserKey string) *AdminHandler {
+	return &AdminHandler{
+		authUsecases: authUsecases,
+		fileUsecases: fileUsecases,
+		ctxUserKey:   ctxUserKey,
+	}
+}
+
+func (h *AdminHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var options models.UsersListOptions
+	err := json.NewDecoder(r.Body).Decode(&options)
+	if err != nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	users, err := h.authUsecases.ListUsers(ctx, options)
+	if err != nil {
+		if errors.Is(err, models.InvalidInputError) {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidURLParams)
+			return
+		}
+
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, users)
+}
+
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := parseUserID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	user, err := h.authUsecases.GetUser(ctx, userID)
+	if err != nil {
+		sendUserErr(ctx, w, err)
+		return
+	}
+
+	responses.SendOkResponse(w, user)
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+func (h *AdminHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	ctx := r.Context()
+
+	userID, ok := parseUserID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	admin := ctx.Value(h.ctxUserKey).(*models.User)
+	if admin.ID == userID {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrSelfModification)
+		return
+	}
+
+	user, err := h.authUsecases.SetUserDisabled(ctx, userID, disabled)
+	if err != nil {
+		sendUserErr(ctx, w, err)
+		return
+	}
+
+	responses.SendOkResponse(w, user)
+}
+
+func (h *AdminHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := parseUserID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	var roleData *models.SetRoleData
+	err := json.NewDecoder(r.Body).Decode(&roleData)
+	if err != nil || roleData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	// Otherwise the last administrator could lock everyone out of the admin API
+	admin := ctx.Value(h.ctxUserKey).(*models.User)
+	if admin.ID == userID {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrSelfModification)
+		return
+	}
+
+	user, err := h.authUsecases.SetUserRole(ctx, userID, roleData.Role)
+	if err != nil {
+		if errors.Is(err, models.InvalidRoleError) {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidRole)
+			return
+		}
+
+		sendUserErr(ctx, w, err)
+		return
+	}
+
+	responses.SendOkResponse(w, user)
+}
+
+func (h *AdminHandler) GetStorageUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := parseUserID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	usage, err := h.fileUsecases.GetStorageUsage(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, usage)
+}
+
+func (h *AdminHandler) SetQuota(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := parseUserID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	var quotaData *models.SetQuotaData
+	err := json.NewDecoder(r.Body).Decode(&quotaData)
+	if err != nil || quotaData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	usage, err := h.fileUsecases.SetQuota(ctx, userID, quotaData.QuotaBytes)
+	if err != nil {
+		if errors.Is(err, models.InvalidInputError) {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidQuota)
+			return
+		}
+
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, usage)
+}
+
+func (h *AdminHandler) ForceDeleteFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+
+	metadata, err := h.fileUsecases.ForceDeleteFile(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		case errors.Is(err, models.FileNotExists):
+			responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrFileNotFound)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	admin := ctx.Value(h.ctxUserKey).(*models.User)
+	slog.InfoContext(ctx, "File was deleted by administrator", "file_id", metadata.UUID, "owner_id", metadata.OwnerID,
+		"admin_id", admin.ID)
+
+	responses.SendOkResponse(w, metadata)
+}
+
+func (h *AdminHandler) GetOrgStorageUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, ok := parseOrgID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	usage, err := h.fileUsecases.GetOrgStorageUsage(ctx, orgID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, usage)
+}
+
+func (h *AdminHandler) SetOrgQuota(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, ok := parseOrgID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	var quotaData *models.SetQuotaData
+	err := json.NewDecoder(r.Body).Decode(&quotaData)
+	if err != nil || quotaData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	usage, err := h.fileUsecases.SetOrgQuota(ctx, orgID, quotaData.QuotaBytes)
+	if err != nil {
+		if errors.Is(err, models.InvalidInputError) {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidQuota)
+			return
+		}
+
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, usage)
+}
+
+func parseUserID(r *http.Request) (uint, bool) {
+	return parseUintVar(r, "id")
+}
+
+func parseOrgID(r *http.Request) (uint, bool) {
+	return parseUintVar(r, "id")
+}
+
+func parseUintVar(r *http.Request, name string) (uint, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+func sendUserErr(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, models.UserNotExists) {
+		responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrUserNotFound)
+		return
+	}
+
+	slog.ErrorContext(ctx, "Internal error", "error", err)
+	responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+}
@@ -3,7 +3,7 @@ package rest
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -43,11 +43,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	user, err := h.usecases.Login(ctx, loginData)
 	if err != nil {
 		if errors.Is(err, models.PasswordsNotMatch) || errors.Is(err, models.UserNotExists) {
-			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongCredentials)
+			responses.SendErrResponse(w, responses.StatusUnauthorized, responses.ErrWrongCredentials)
+			return
+		}
+		if errors.Is(err, models.UserNotVerified) {
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrNotVerified)
+			return
+		}
+		if errors.Is(err, models.UserDisabledError) {
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrUserDisabled)
 			return
 		}
 
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal error", "error", err)
 		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		return
 	}
@@ -56,8 +64,10 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	http.SetCookie(w, newSession)
 	responses.SendOkResponse(w, &models.AuthData{
 		User: models.User{
-			ID:    user.ID,
-			Email: user.Email,
+			ID:       user.ID,
+			Email:    user.Email,
+			Verified: user.Verified,
+			Role:     user.Role,
 		},
 		IsAuth: true,
 	})
@@ -85,6 +95,8 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	user, err := h.usecases.Signup(ctx, signupData)
 	if err != nil {
 		switch {
+		case errors.Is(err, models.InvalidEmailError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongEmailFormat)
 		case errors.Is(err, models.PasswordsNotMatch):
 			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrDoNotMatch)
 		case errors.Is(err, models.IncorrectPasswordLen):
@@ -92,21 +104,29 @@ func (h *AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, models.UserAlreadyExists):
 			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrAlreadyExists)
 		default:
-			log.Println(err)
+			slog.ErrorContext(ctx, "Internal error", "error", err)
 			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 		return
 	}
 
-	newSession := createSession(user.SessionID)
-	http.SetCookie(w, newSession)
-	responses.SendOkResponse(w, &models.AuthData{
+	authData := &models.AuthData{
 		User: models.User{
-			ID:    user.ID,
-			Email: user.Email,
+			ID:       user.ID,
+			Email:    user.Email,
+			Verified: user.Verified,
+			Role:     user.Role,
 		},
-		IsAuth: true,
-	})
+	}
+
+	// Unverified users may be forbidden to log in, in which case there is no session yet
+	if user.SessionID != "" {
+		newSession := createSession(user.SessionID)
+		http.SetCookie(w, newSession)
+		authData.IsAuth = true
+	}
+
+	responses.SendOkResponse(w, authData)
 }
 
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
@@ -120,7 +140,7 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal error", "error", err)
 		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		return
 	}
@@ -149,6 +169,51 @@ func (h *AuthHandler) CheckAuth(w http.ResponseWriter, r *http.Request) {
 	responses.SendOkResponse(w, &models.AuthData{IsAuth: true, User: *user})
 }
 
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token := r.URL.Query().Get("token")
+
+	user, err := h.usecases.VerifyEmail(ctx, token)
+	if err != nil {
+		if errors.Is(err, models.InvalidVerificationToken) {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidToken)
+			return
+		}
+
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, user)
+}
+
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var resendData *models.ResendVerificationData
+	err := json.NewDecoder(r.Body).Decode(&resendData)
+	if err != nil || resendData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	err = h.usecases.ResendVerification(ctx, resendData.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidEmailError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongEmailFormat)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+		return
+	}
+
+	responses.SendOkResponse(w, nil)
+}
+
 func createSession(sessionID string) *http.Cookie {
 	return &http.Cookie{
 		Name:     "session_id",
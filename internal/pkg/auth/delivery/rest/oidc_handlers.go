@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterfaces "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/gorilla/mux"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	oidcCookiePath  = "/api/auth/oidc"
+)
+
+type OIDCHandler struct {
+	usecases        authinterfaces.OIDCUsecases
+	stateTTL        time.Duration
+	successRedirect string
+}
+
+func NewOIDCHandler(usecases authinterfaces.OIDCUsecases, stateTTL time.Duration,
+	successRedirect string) *OIDCHandler {
+	return &OIDCHandler{
+		usecases:        usecases,
+		stateTTL:        stateTTL,
+		successRedirect: successRedirect,
+	}
+}
+
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	provider := mux.Vars(r)["provider"]
+
+	url, state, err := h.usecases.AuthURL(ctx, provider)
+	if err != nil {
+		if errors.Is(err, models.UnknownProviderError) {
+			responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrUnknownProvider)
+			return
+		}
+
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	// The cookie binds the state to the browser which started the login to prevent login CSRF
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcCookiePath,
+		Expires:  time.Now().Add(h.stateTTL),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	provider := mux.Vars(r)["provider"]
+	query := r.URL.Query()
+
+	if query.Get("error") != "" {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrOIDCFailed)
+		return
+	}
+
+	state := query.Get("state")
+	stateCookie, _ := r.Cookie(oidcStateCookie)
+	if stateCookie == nil || state == "" || stateCookie.Value != state {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrOIDCFailed)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     oidcCookiePath,
+		Expires:  time.Now().AddDate(0, 0, -1),
+		HttpOnly: true,
+	})
+
+	user, err := h.usecases.Callback(ctx, provider, state, query.Get("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.UnknownProviderError):
+			responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrUnknownProvider)
+		case errors.Is(err, models.IdentityConflictError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrIdentityConflict)
+		case errors.Is(err, models.UserDisabledError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrUserDisabled)
+		case errors.Is(err, models.UserNotVerified):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrNotVerified)
+		case errors.Is(err, models.InvalidOIDCStateError), errors.Is(err, models.OIDCExchangeError),
+			errors.Is(err, models.InvalidEmailError):
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrOIDCFailed)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+		return
+	}
+
+	http.SetCookie(w, createSession(user.SessionID))
+	http.Redirect(w, r, h.successRedirect, http.StatusFound)
+}
@@ -0,0 +1,190 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterfaces "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+)
+
+type OrgHandler struct {
+	orgUsecases  authinterfaces.OrgUsecases
+	fileUsecases fileinterfaces.FileUsecases
+	ctxUserKey   string
+}
+
+func NewOrgHandler(orgUsecases authinterfaces.OrgUsecases, fileUsecases fileinterfaces.FileUsecases,
+	ctxUserKey string) *OrgHandler {
+	return &OrgHandler{
+		orgUsecases:  orgUsecases,
+		fileUsecases: fileUsecases,
+		ctxUserKey:   ctxUserKey,
+	}
+}
+
+func (h *OrgHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var orgData *models.CreateOrganizationData
+	err := json.NewDecoder(r.Body).Decode(&orgData)
+	if err != nil || orgData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	org, err := h.orgUsecases.CreateOrganization(ctx, user.ID, orgData.Name)
+	if err != nil {
+		if errors.Is(err, models.InvalidOrgNameError) {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidOrgName)
+			return
+		}
+
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, org)
+}
+
+func (h *OrgHandler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	orgs, err := h.orgUsecases.ListOrganizations(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, orgs)
+}
+
+func (h *OrgHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, ok := parseOrgID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	members, err := h.orgUsecases.ListMembers(ctx, user.ID, orgID)
+	if err != nil {
+		sendOrgErr(ctx, w, err)
+		return
+	}
+
+	responses.SendOkResponse(w, members)
+}
+
+func (h *OrgHandler) SetMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, ok := parseOrgID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	var memberData *models.SetMemberData
+	err := json.NewDecoder(r.Body).Decode(&memberData)
+	if err != nil || memberData == nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	member, err := h.orgUsecases.SetMember(ctx, user.ID, orgID, memberData)
+	if err != nil {
+		sendOrgErr(ctx, w, err)
+		return
+	}
+
+	responses.SendOkResponse(w, member)
+}
+
+func (h *OrgHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, ok := parseOrgID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	memberID, ok := parseUintVar(r, "userID")
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	err := h.orgUsecases.RemoveMember(ctx, user.ID, orgID, memberID)
+	if err != nil {
+		sendOrgErr(ctx, w, err)
+		return
+	}
+
+	responses.SendOkResponse(w, nil)
+}
+
+func (h *OrgHandler) GetStorageUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, ok := parseOrgID(r)
+	if !ok {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	_, err := h.orgUsecases.GetOrganization(ctx, user.ID, orgID)
+	if err != nil {
+		sendOrgErr(ctx, w, err)
+		return
+	}
+
+	usage, err := h.fileUsecases.GetOrgStorageUsage(ctx, orgID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		return
+	}
+
+	responses.SendOkResponse(w, usage)
+}
+
+func sendOrgErr(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.OrgNotExists):
+		responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrOrgNotFound)
+	case errors.Is(err, models.UserNotExists):
+		responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrUserNotFound)
+	case errors.Is(err, models.PermissionDeniedError):
+		responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+	case errors.Is(err, models.OrgOwnerImmutableError):
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrOrgOwnerImmutable)
+	case errors.Is(err, models.InvalidOrgRoleError):
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidOrgRole)
+	case errors.Is(err, models.InvalidEmailError):
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongEmailFormat)
+	default:
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+	}
+}
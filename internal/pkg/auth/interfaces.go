@@ -9,17 +9,89 @@ import (
 type SessionManager interface {
 	CreateSession(ctx context.Context, sessionID string, user *models.User) error
 	RemoveSession(ctx context.Context, sessionID string) error
+	RemoveUserSessions(ctx context.Context, userID uint) error
+	SetUserVerified(ctx context.Context, userID uint) error
 	GetSession(ctx context.Context, sessionID string) (*models.User, bool)
 }
 
+type VerificationManager interface {
+	CreateToken(ctx context.Context, token string, userID uint) error
+	PopToken(ctx context.Context, token string) (uint, error)
+}
+
+type OIDCStateStorage interface {
+	SaveState(ctx context.Context, state string, data *models.OIDCState) error
+	PopState(ctx context.Context, state string) (*models.OIDCState, error)
+}
+
 type AuthRepository interface {
 	CreateUser(ctx context.Context, email, password string) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUserByID(ctx context.Context, id uint) (*models.User, error)
+	SetVerified(ctx context.Context, id uint) error
+
+	GetUserByIdentity(ctx context.Context, provider, subject string) (*models.User, error)
+	CreateExternalUser(ctx context.Context, identity *models.ExternalIdentity) (*models.User, error)
+	LinkIdentity(ctx context.Context, userID uint, provider, subject string) error
+	ClaimUnverifiedUser(ctx context.Context, userID uint, provider, subject string) error
+
+	ListUsers(ctx context.Context, options models.UsersListOptions) ([]*models.User, error)
+	SetDisabled(ctx context.Context, id uint, disabled bool) error
+	SetRole(ctx context.Context, id uint, role string) error
+
+	DeleteUser(ctx context.Context, id uint) error
+}
+
+type OrgRepository interface {
+	CreateOrganization(ctx context.Context, name string, ownerID uint) (*models.Organization, error)
+	GetUserOrganizations(ctx context.Context, userID uint) ([]*models.Organization, error)
+	GetMembership(ctx context.Context, orgID, userID uint) (*models.Organization, error)
+	GetMembers(ctx context.Context, orgID uint) ([]*models.OrgMember, error)
+	SetMember(ctx context.Context, orgID, userID uint, role string) error
+	RemoveMember(ctx context.Context, orgID, userID uint) error
+}
+
+type AccessKeyRepository interface {
+	// CreateAccessKey returns models.AccessKeyLimitError if the user already has the maximum number of keys
+	CreateAccessKey(ctx context.Context, key *models.AccessKey, maxKeys int) (*models.AccessKey, error)
+	GetAccessKey(ctx context.Context, id string) (*models.AccessKey, error)
+	ListAccessKeys(ctx context.Context, userID uint) ([]*models.AccessKey, error)
+	DeleteAccessKey(ctx context.Context, userID uint, id string) error
 }
 
 type AuthUsecases interface {
 	Login(ctx context.Context, data *models.LoginData) (*models.FullUserData, error)
+	Authenticate(ctx context.Context, email, password string) (*models.User, error)
 	Signup(ctx context.Context, data *models.SignupData) (*models.FullUserData, error)
 	Logout(ctx context.Context, sessionID string) error
 	CheckAuth(ctx context.Context, sessionID string) (*models.User, bool)
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
+	ResendVerification(ctx context.Context, email string) error
+	CheckPassword(ctx context.Context, email, password string) error
+
+	CreateAccessKey(ctx context.Context, userID uint) (*models.AccessKey, error)
+	ListAccessKeys(ctx context.Context, userID uint) ([]*models.AccessKey, error)
+	DeleteAccessKey(ctx context.Context, userID uint, id string) error
+	// GetAccessKey returns the key with the secret and its owner, keys of disabled users are rejected
+	GetAccessKey(ctx context.Context, id string) (*models.AccessKey, *models.User, error)
+	DeleteUser(ctx context.Context, id uint) error
+
+	GetUser(ctx context.Context, id uint) (*models.User, error)
+	ListUsers(ctx context.Context, options models.UsersListOptions) ([]*models.User, error)
+	SetUserDisabled(ctx context.Context, id uint, disabled bool) (*models.User, error)
+	SetUserRole(ctx context.Context, id uint, role string) (*models.User, error)
+}
+
+type OIDCUsecases interface {
+	AuthURL(ctx context.Context, provider string) (string, string, error)
+	Callback(ctx context.Context, provider, state, code string) (*models.FullUserData, error)
+}
+
+type OrgUsecases interface {
+	CreateOrganization(ctx context.Context, ownerID uint, name string) (*models.Organization, error)
+	ListOrganizations(ctx context.Context, userID uint) ([]*models.Organization, error)
+	GetOrganization(ctx context.Context, userID, orgID uint) (*models.Organization, error)
+	ListMembers(ctx context.Context, userID, orgID uint) ([]*models.OrgMember, error)
+	SetMember(ctx context.Context, actorID, orgID uint, data *models.SetMemberData) (*models.OrgMember, error)
+	RemoveMember(ctx context.Context, actorID, orgID, userID uint) error
 }
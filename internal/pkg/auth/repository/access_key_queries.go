@@ -0,0 +1,35 @@
+package repository
+
+const (
+	// LockAccessKeysQuery serializes creation of access keys of a user until the transaction ends
+	LockAccessKeysQuery = `
+		SELECT pg_advisory_xact_lock($1, $2);
+	`
+
+	CreateAccessKeyQuery = `
+		INSERT
+		INTO public.access_key (id, user_id, secret)
+		SELECT $1, $2, $3
+		WHERE (SELECT COUNT(*) FROM public.access_key WHERE user_id = $2) < $4
+		RETURNING id, user_id, created_time;
+	`
+
+	GetAccessKeyQuery = `
+		SELECT k.id, k.user_id, k.secret, k.created_time
+		FROM public.access_key k
+		WHERE k.id = $1;
+	`
+
+	ListAccessKeysQuery = `
+		SELECT k.id, k.user_id, k.created_time
+		FROM public.access_key k
+		WHERE k.user_id = $1
+		ORDER BY k.created_time;
+	`
+
+	DeleteAccessKeyQuery = `
+		DELETE
+		FROM public.access_key
+		WHERE id = $1 AND user_id = $2;
+	`
+)
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type accessKeyStorage struct {
+	pool dbinit.PostgresPool
+}
+
+func NewAccessKeyStorage(pool dbinit.PostgresPool) authinterface.AccessKeyRepository {
+	return &accessKeyStorage{pool: pool}
+}
+
+// accessKeysLock is the class of the advisory lock, the user ID is the key within it
+const accessKeysLock = 1
+
+func (s *accessKeyStorage) CreateAccessKey(ctx context.Context, key *models.AccessKey,
+	maxKeys int) (*models.AccessKey, error) {
+	created := models.AccessKey{Secret: key.Secret}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Keys are counted under the lock, so concurrent requests cannot exceed the limit together
+	if _, err := tx.Exec(ctx, LockAccessKeysQuery, accessKeysLock, key.UserID); err != nil {
+		return nil, err
+	}
+
+	line := tx.QueryRow(ctx, CreateAccessKeyQuery, key.ID, key.UserID, key.Secret, maxKeys)
+	if err := line.Scan(&created.ID, &created.UserID, &created.CreateTime); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.AccessKeyLimitError
+		}
+
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (s *accessKeyStorage) GetAccessKey(ctx context.Context, id string) (*models.AccessKey, error) {
+	var key models.AccessKey
+
+	line := s.pool.QueryRow(ctx, GetAccessKeyQuery, id)
+	if err := line.Scan(&key.ID, &key.UserID, &key.Secret, &key.CreateTime); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.AccessKeyNotExists
+		}
+
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (s *accessKeyStorage) ListAccessKeys(ctx context.Context, userID uint) ([]*models.AccessKey, error) {
+	rows, err := s.pool.Query(ctx, ListAccessKeysQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*models.AccessKey, 0)
+	for rows.Next() {
+		var key models.AccessKey
+		if err := rows.Scan(&key.ID, &key.UserID, &key.CreateTime); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *accessKeyStorage) DeleteAccessKey(ctx context.Context, userID uint, id string) error {
+	tag, err := s.pool.Exec(ctx, DeleteAccessKeyQuery, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.AccessKeyNotExists
+	}
+
+	return nil
+}
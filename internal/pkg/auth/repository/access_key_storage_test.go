@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessKeyStorage_CreateAccessKey(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAccessKeyStorage(mock)
+
+	now := time.Now()
+	rows := pgxmock.NewRows([]string{"id", "user_id", "created_time"}).AddRow("VBKEY", uint(1), now)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(accessKeysLock, uint(1)).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery("INSERT\\s+INTO public.access_key").WithArgs("VBKEY", uint(1), "secret", 10).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	key, err := s.CreateAccessKey(context.Background(),
+		&models.AccessKey{ID: "VBKEY", UserID: 1, Secret: "secret"}, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.AccessKey{ID: "VBKEY", UserID: 1, Secret: "secret", CreateTime: now}, key)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAccessKeyStorage_CreateAccessKey_Limit(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAccessKeyStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(accessKeysLock, uint(1)).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery("INSERT\\s+INTO public.access_key").WithArgs("VBKEY", uint(1), "secret", 10).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+
+	key, err := s.CreateAccessKey(context.Background(),
+		&models.AccessKey{ID: "VBKEY", UserID: 1, Secret: "secret"}, 10)
+
+	assert.Nil(t, key)
+	assert.Equal(t, models.AccessKeyLimitError, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAccessKeyStorage_DeleteAccessKey_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAccessKeyStorage(mock)
+
+	mock.ExpectExec("DELETE\\s+FROM public.access_key").WithArgs("VBKEY", uint(2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	err = s.DeleteAccessKey(context.Background(), 2, "VBKEY")
+
+	assert.Equal(t, models.AccessKeyNotExists, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
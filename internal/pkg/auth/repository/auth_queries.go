@@ -2,15 +2,85 @@ package repository
 
 const (
 	GetUserByEmailQuery = `
-		SELECT u.id, u.email, u.password_hash
+		SELECT u.id, u.email, u.password_hash, u.verified, u.role, u.disabled
 		FROM public.user u
 		WHERE u.email = $1;
 	`
 
+	GetUserByIDQuery = `
+		SELECT u.id, u.email, u.password_hash, u.verified, u.role, u.disabled
+		FROM public.user u
+		WHERE u.id = $1;
+	`
+
 	CreateUserQuery = `
 		INSERT
 		INTO public.user (email, password_hash)
 		VALUES ($1, $2)
-		RETURNING id, email;
+		RETURNING id, email, verified, role, disabled;
+	`
+
+	SetVerifiedQuery = `
+		UPDATE public.user
+		SET verified = TRUE
+		WHERE id = $1;
+	`
+
+	GetUserByIdentityQuery = `
+		SELECT u.id, u.email, u.password_hash, u.verified, u.role, u.disabled
+		FROM public.user u
+		JOIN public.user_identity i ON i.user_id = u.id
+		WHERE i.provider = $1 AND i.subject = $2;
+	`
+
+	CreateExternalUserQuery = `
+		INSERT
+		INTO public.user (email, password_hash, verified)
+		VALUES ($1, $2, $3)
+		RETURNING id, email, verified, role, disabled;
+	`
+
+	LinkIdentityQuery = `
+		INSERT
+		INTO public.user_identity (user_id, provider, subject)
+		VALUES ($1, $2, $3);
+	`
+
+	ClaimUserQuery = `
+		UPDATE public.user
+		SET password_hash = $2, verified = TRUE
+		WHERE id = $1;
+	`
+
+	DeleteUserAccessKeysQuery = `
+		DELETE
+		FROM public.access_key
+		WHERE user_id = $1;
+	`
+
+	ListUsersQuery = `
+		SELECT u.id, u.email, u.verified, u.role, u.disabled
+		FROM public.user u
+		WHERE u.email ILIKE '%' || $1 || '%'
+		ORDER BY u.id
+		LIMIT $2 OFFSET $3;
+	`
+
+	SetDisabledQuery = `
+		UPDATE public.user
+		SET disabled = $2
+		WHERE id = $1;
+	`
+
+	SetRoleQuery = `
+		UPDATE public.user
+		SET role = $2
+		WHERE id = $1;
+	`
+
+	DeleteUserQuery = `
+		DELETE
+		FROM public.user
+		WHERE id = $1;
 	`
 )
@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
 	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
 	"github.com/IlyaChgn/voblako/internal/pkg/utils"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
@@ -31,7 +33,7 @@ func (s *authStorage) CreateUser(ctx context.Context, email, password string) (*
 	defer tx.Rollback(ctx)
 
 	line := tx.QueryRow(ctx, CreateUserQuery, email, utils.HashPassword(password))
-	if err := line.Scan(&user.ID, &user.Email); err != nil {
+	if err := line.Scan(&user.ID, &user.Email, &user.Verified, &user.Role, &user.Disabled); err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
 			return nil, models.UserAlreadyExists
@@ -51,7 +53,8 @@ func (s *authStorage) GetUserByEmail(ctx context.Context, email string) (*models
 	var user models.User
 
 	line := s.pool.QueryRow(ctx, GetUserByEmailQuery, email)
-	if err := line.Scan(&user.ID, &user.Email, &user.PasswordHash); err != nil {
+	if err := line.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Verified, &user.Role,
+		&user.Disabled); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
@@ -60,3 +63,218 @@ func (s *authStorage) GetUserByEmail(ctx context.Context, email string) (*models
 
 	return &user, nil
 }
+
+func (s *authStorage) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+
+	line := s.pool.QueryRow(ctx, GetUserByIDQuery, id)
+	if err := line.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Verified, &user.Role,
+		&user.Disabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *authStorage) SetVerified(ctx context.Context, id uint) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, SetVerifiedQuery, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.UserNotExists
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authStorage) GetUserByIdentity(ctx context.Context, provider, subject string) (*models.User, error) {
+	var user models.User
+
+	line := s.pool.QueryRow(ctx, GetUserByIdentityQuery, provider, subject)
+	if err := line.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.Verified, &user.Role,
+		&user.Disabled); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// CreateExternalUser creates a user together with the linked identity. Such users get a random password,
+// so they can sign in only through the identity provider.
+func (s *authStorage) CreateExternalUser(ctx context.Context, identity *models.ExternalIdentity) (*models.User, error) {
+	var user models.User
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	line := tx.QueryRow(ctx, CreateExternalUserQuery, identity.Email, utils.HashPassword(uuid.NewString()),
+		identity.EmailVerified)
+	if err := line.Scan(&user.ID, &user.Email, &user.Verified, &user.Role, &user.Disabled); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return nil, models.UserAlreadyExists
+		}
+
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, LinkIdentityQuery, user.ID, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (s *authStorage) LinkIdentity(ctx context.Context, userID uint, provider, subject string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, LinkIdentityQuery, userID, provider, subject)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimUnverifiedUser links the identity to an account whose email was not verified. The password and access keys
+// are replaced, so whoever has created the account cannot use it after the owner of the email has signed in.
+func (s *authStorage) ClaimUnverifiedUser(ctx context.Context, userID uint, provider, subject string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, ClaimUserQuery, userID, utils.HashPassword(uuid.NewString()))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.UserNotExists
+	}
+
+	_, err = tx.Exec(ctx, DeleteUserAccessKeysQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, LinkIdentityQuery, userID, provider, subject)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authStorage) ListUsers(ctx context.Context, options models.UsersListOptions) ([]*models.User, error) {
+	rows, err := s.pool.Query(ctx, ListUsersQuery, escapeLike(options.Query), options.Limit, options.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Verified, &user.Role, &user.Disabled); err != nil {
+			return nil, err
+		}
+
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+func (s *authStorage) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	return s.updateUser(ctx, SetDisabledQuery, id, disabled)
+}
+
+func (s *authStorage) SetRole(ctx context.Context, id uint, role string) error {
+	return s.updateUser(ctx, SetRoleQuery, id, role)
+}
+
+func (s *authStorage) updateUser(ctx context.Context, query string, id uint, value any) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, id, value)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.UserNotExists
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *authStorage) DeleteUser(ctx context.Context, id uint) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, DeleteUserQuery, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.UserNotExists
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
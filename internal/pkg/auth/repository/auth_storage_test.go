@@ -23,7 +23,8 @@ func TestAuthStorage_CreateUser_Success(t *testing.T) {
 	email := "test@example.com"
 	password := "password"
 
-	rows := pgxmock.NewRows([]string{"id", "email"}).AddRow(uint(1), email)
+	rows := pgxmock.NewRows([]string{"id", "email", "verified", "role", "disabled"}).
+		AddRow(uint(1), email, false, models.RoleUser, false)
 
 	mock.ExpectBegin()
 	mock.ExpectQuery("INSERT INTO public.user").WithArgs(email, pgxmock.AnyArg()).WillReturnRows(rows)
@@ -84,8 +85,8 @@ func TestAuthStorage_GetUserByEmail(t *testing.T) {
 
 	s := NewAuthStorage(mock)
 	email := "test@example.com"
-	rows := pgxmock.NewRows([]string{"id", "email", "password_hash"}).
-		AddRow(uint(1), email, "hashed_password")
+	rows := pgxmock.NewRows([]string{"id", "email", "password_hash", "verified", "role", "disabled"}).
+		AddRow(uint(1), email, "hashed_password", true, models.RoleAdmin, false)
 
 	mock.ExpectQuery("SELECT u.id, u.email, u.password_hash").WithArgs(email).WillReturnRows(rows)
 
@@ -95,6 +96,8 @@ func TestAuthStorage_GetUserByEmail(t *testing.T) {
 	assert.NotNil(t, user)
 	if user != nil {
 		assert.Equal(t, email, user.Email)
+		assert.True(t, user.Verified)
+		assert.Equal(t, models.RoleAdmin, user.Role)
 	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -123,3 +126,123 @@ func TestAuthStorage_GetUserByEmail_NotFound(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestAuthStorage_SetVerified(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAuthStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE public.user").WithArgs(uint(1)).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	err = s.SetVerified(context.Background(), 1)
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuthStorage_SetVerified_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAuthStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE public.user").WithArgs(uint(1)).WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectRollback()
+
+	err = s.SetVerified(context.Background(), 1)
+
+	assert.Equal(t, models.UserNotExists, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuthStorage_ClaimUnverifiedUser(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAuthStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE public.user SET password_hash").WithArgs(uint(1), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("DELETE FROM public.access_key").WithArgs(uint(1)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectExec("INSERT INTO public.user_identity").WithArgs(uint(1), "google", "subject").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	err = s.ClaimUnverifiedUser(context.Background(), 1, "google", "subject")
+
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuthStorage_ListUsers(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAuthStorage(mock)
+
+	rows := pgxmock.NewRows([]string{"id", "email", "verified", "role", "disabled"}).
+		AddRow(uint(1), "first_user@example.com", true, models.RoleUser, false).
+		AddRow(uint(2), "second_user@example.com", false, models.RoleUser, true)
+
+	mock.ExpectQuery("SELECT u.id, u.email, u.verified").
+		WithArgs(`first\_user`, uint(10), uint(0)).WillReturnRows(rows)
+
+	users, err := s.ListUsers(context.Background(), models.UsersListOptions{Query: "first_user", Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.True(t, users[1].Disabled)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuthStorage_SetDisabled_NotFound(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewAuthStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE public.user").WithArgs(uint(1), true).WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectRollback()
+
+	err = s.SetDisabled(context.Background(), 1, true)
+
+	assert.Equal(t, models.UserNotExists, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
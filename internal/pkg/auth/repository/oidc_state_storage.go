@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const oidcStateKeyPrefix = "oidc:state:"
+
+type oidcStateStorage struct {
+	client   *redis.Client
+	stateTTL time.Duration
+}
+
+func NewOIDCStateStorage(client *redis.Client, stateTTL time.Duration) authinterface.OIDCStateStorage {
+	return &oidcStateStorage{
+		client:   client,
+		stateTTL: stateTTL,
+	}
+}
+
+func (s *oidcStateStorage) SaveState(ctx context.Context, state string, data *models.OIDCState) error {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return models.MarshallingSessionError
+	}
+
+	err = s.client.Set(ctx, oidcStateKeyPrefix+state, rawData, s.stateTTL).Err()
+	if err != nil {
+		return models.AddToRedisError
+	}
+
+	return nil
+}
+
+func (s *oidcStateStorage) PopState(ctx context.Context, state string) (*models.OIDCState, error) {
+	rawData, err := s.client.GetDel(ctx, oidcStateKeyPrefix+state).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, models.InvalidOIDCStateError
+		}
+
+		return nil, err
+	}
+
+	var data models.OIDCState
+	if err := json.Unmarshal([]byte(rawData), &data); err != nil {
+		return nil, models.InvalidOIDCStateError
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+const (
+	CreateOrganizationQuery = `
+		INSERT
+		INTO public.organization (name)
+		VALUES ($1)
+		RETURNING id, name;
+	`
+
+	AddOwnerQuery = `
+		INSERT
+		INTO public.organization_member (org_id, user_id, role)
+		VALUES ($1, $2, 'owner');
+	`
+
+	GetUserOrganizationsQuery = `
+		SELECT o.id, o.name, m.role
+		FROM public.organization o
+		JOIN public.organization_member m ON m.org_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.id;
+	`
+
+	GetMembershipQuery = `
+		SELECT o.id, o.name, m.role
+		FROM public.organization o
+		JOIN public.organization_member m ON m.org_id = o.id
+		WHERE o.id = $1 AND m.user_id = $2;
+	`
+
+	GetMembersQuery = `
+		SELECT u.id, u.email, m.role
+		FROM public.organization_member m
+		JOIN public.user u ON u.id = m.user_id
+		WHERE m.org_id = $1
+		ORDER BY u.id;
+	`
+
+	SetMemberQuery = `
+		INSERT
+		INTO public.organization_member (org_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (org_id, user_id)
+		DO UPDATE SET role = EXCLUDED.role;
+	`
+
+	RemoveMemberQuery = `
+		DELETE
+		FROM public.organization_member
+		WHERE org_id = $1 AND user_id = $2;
+	`
+)
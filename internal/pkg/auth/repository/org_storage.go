@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type orgStorage struct {
+	pool dbinit.PostgresPool
+}
+
+func NewOrgStorage(pool dbinit.PostgresPool) authinterface.OrgRepository {
+	return &orgStorage{pool: pool}
+}
+
+func (s *orgStorage) CreateOrganization(ctx context.Context, name string, ownerID uint) (*models.Organization, error) {
+	org := models.Organization{Role: models.OrgRoleOwner}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	line := tx.QueryRow(ctx, CreateOrganizationQuery, name)
+	if err := line.Scan(&org.ID, &org.Name); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, AddOwnerQuery, org.ID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+func (s *orgStorage) GetUserOrganizations(ctx context.Context, userID uint) ([]*models.Organization, error) {
+	rows, err := s.pool.Query(ctx, GetUserOrganizationsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := make([]*models.Organization, 0)
+	for rows.Next() {
+		var org models.Organization
+		if err := rows.Scan(&org.ID, &org.Name, &org.Role); err != nil {
+			return nil, err
+		}
+
+		orgs = append(orgs, &org)
+	}
+
+	return orgs, rows.Err()
+}
+
+// GetMembership returns nil if the user is not a member of the organization
+func (s *orgStorage) GetMembership(ctx context.Context, orgID, userID uint) (*models.Organization, error) {
+	var org models.Organization
+
+	line := s.pool.QueryRow(ctx, GetMembershipQuery, orgID, userID)
+	if err := line.Scan(&org.ID, &org.Name, &org.Role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &org, nil
+}
+
+func (s *orgStorage) GetMembers(ctx context.Context, orgID uint) ([]*models.OrgMember, error) {
+	rows, err := s.pool.Query(ctx, GetMembersQuery, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]*models.OrgMember, 0)
+	for rows.Next() {
+		var member models.OrgMember
+		if err := rows.Scan(&member.UserID, &member.Email, &member.Role); err != nil {
+			return nil, err
+		}
+
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+func (s *orgStorage) SetMember(ctx context.Context, orgID, userID uint, role string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, SetMemberQuery, orgID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *orgStorage) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, RemoveMemberQuery, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
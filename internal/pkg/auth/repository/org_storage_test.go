@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgStorage_CreateOrganization(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewOrgStorage(mock)
+
+	rows := pgxmock.NewRows([]string{"id", "name"}).AddRow(uint(3), "Team")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT\\s+INTO public.organization ").WithArgs("Team").WillReturnRows(rows)
+	mock.ExpectExec("INSERT\\s+INTO public.organization_member").WithArgs(uint(3), uint(1)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	org, err := s.CreateOrganization(context.Background(), "Team", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Organization{ID: 3, Name: "Team", Role: models.OrgRoleOwner}, org)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOrgStorage_GetMembership_NotMember(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewOrgStorage(mock)
+
+	mock.ExpectQuery("SELECT o.id, o.name, m.role").WithArgs(uint(3), uint(2)).WillReturnError(pgx.ErrNoRows)
+
+	org, err := s.GetMembership(context.Background(), 3, 2)
+
+	assert.NoError(t, err)
+	assert.Nil(t, org)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
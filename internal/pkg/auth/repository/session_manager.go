@@ -3,16 +3,24 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
 
 	"github.com/redis/go-redis/v9"
 )
 
 const sessionDuration = 30 * 24 * time.Hour
 
+// userSessionsKey stores IDs of all sessions of the user, so they can be revoked at once
+func userSessionsKey(userID uint) string {
+	return fmt.Sprintf("user_sessions:%d", userID)
+}
+
 type sessionManager struct {
 	client *redis.Client
 }
@@ -34,11 +42,22 @@ func (manager *sessionManager) CreateSession(ctx context.Context, sessionID stri
 		return models.AddToRedisError
 	}
 
+	err = manager.client.SAdd(ctx, userSessionsKey(user.ID), sessionID).Err()
+	if err != nil {
+		return models.AddToRedisError
+	}
+
+	err = manager.client.Expire(ctx, userSessionsKey(user.ID), sessionDuration).Err()
+	if err != nil {
+		return models.AddToRedisError
+	}
+
 	return nil
 }
 
 func (manager *sessionManager) RemoveSession(ctx context.Context, sessionID string) error {
-	if _, exists := manager.GetSession(ctx, sessionID); !exists {
+	user, exists := manager.GetSession(ctx, sessionID)
+	if !exists {
 		return models.SessionNotExistsError
 	}
 
@@ -47,16 +66,79 @@ func (manager *sessionManager) RemoveSession(ctx context.Context, sessionID stri
 		return models.DeleteFromRedisError
 	}
 
+	_, err = manager.client.SRem(ctx, userSessionsKey(user.ID), sessionID).Result()
+	if err != nil {
+		return models.DeleteFromRedisError
+	}
+
+	return nil
+}
+
+func (manager *sessionManager) RemoveUserSessions(ctx context.Context, userID uint) error {
+	sessions, err := manager.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return models.DeleteFromRedisError
+	}
+
+	keys := append(sessions, userSessionsKey(userID))
+
+	_, err = manager.client.Del(ctx, keys...).Result()
+	if err != nil {
+		return models.DeleteFromRedisError
+	}
+
+	return nil
+}
+
+// SetUserVerified marks all sessions of the user as verified, so they do not have to be refreshed on every request
+func (manager *sessionManager) SetUserVerified(ctx context.Context, userID uint) error {
+	sessions, err := manager.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return models.AddToRedisError
+	}
+
+	for _, sessionID := range sessions {
+		user, exists := manager.GetSession(ctx, sessionID)
+		if !exists || user.Verified {
+			continue
+		}
+
+		user.Verified = true
+
+		rawUser, err := json.Marshal(user)
+		if err != nil {
+			return models.MarshallingSessionError
+		}
+
+		// The session may expire between the reads, XX keeps it from being recreated
+		err = manager.client.SetArgs(ctx, sessionID, rawUser, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return models.AddToRedisError
+		}
+	}
+
 	return nil
 }
 
 func (manager *sessionManager) GetSession(ctx context.Context, sessionID string) (*models.User, bool) {
-	rawUser, _ := manager.client.Get(ctx, sessionID).Result()
+	rawUser, err := manager.client.Get(ctx, sessionID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			metrics.SessionLookups.WithLabelValues("miss").Inc()
+		} else {
+			metrics.SessionLookups.WithLabelValues("error").Inc()
+		}
+
+		return nil, false
+	}
 
 	var user *models.User
 	if err := json.Unmarshal([]byte(rawUser), &user); err != nil {
+		metrics.SessionLookups.WithLabelValues("error").Inc()
 		return nil, false
 	}
 
+	metrics.SessionLookups.WithLabelValues("hit").Inc()
+
 	return user, user != nil
 }
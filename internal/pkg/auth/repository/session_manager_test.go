@@ -7,6 +7,7 @@ import (
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,6 +20,8 @@ func TestSessionManager_CreateSession(t *testing.T) {
 
 	rawUser, _ := json.Marshal(user)
 	mock.ExpectSet(sessionID, rawUser, sessionDuration).SetVal("")
+	mock.ExpectSAdd(userSessionsKey(user.ID), sessionID).SetVal(1)
+	mock.ExpectExpire(userSessionsKey(user.ID), sessionDuration).SetVal(true)
 
 	err := sm.CreateSession(context.Background(), sessionID, user)
 
@@ -36,6 +39,7 @@ func TestSessionManager_RemoveSession(t *testing.T) {
 	rawUser, _ := json.Marshal(user)
 	mock.ExpectGet(sessionID).SetVal(string(rawUser))
 	mock.ExpectDel(sessionID).SetVal(1)
+	mock.ExpectSRem(userSessionsKey(user.ID), sessionID).SetVal(1)
 
 	err := sm.RemoveSession(context.Background(), sessionID)
 
@@ -74,3 +78,35 @@ func TestSessionManager_GetSession_NotFound(t *testing.T) {
 	assert.Nil(t, retrievedUser)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestSessionManager_RemoveUserSessions(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	sm := NewSessionManager(client)
+
+	mock.ExpectSMembers(userSessionsKey(1)).SetVal([]string{"session1", "session2"})
+	mock.ExpectDel("session1", "session2", userSessionsKey(1)).SetVal(3)
+
+	err := sm.RemoveUserSessions(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionManager_SetUserVerified(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	sm := NewSessionManager(client)
+
+	user := &models.User{ID: 1, Email: "test@example.com"}
+	rawUser, _ := json.Marshal(user)
+	verifiedUser, _ := json.Marshal(&models.User{ID: 1, Email: "test@example.com", Verified: true})
+
+	mock.ExpectSMembers(userSessionsKey(user.ID)).SetVal([]string{"session123", "expired"})
+	mock.ExpectGet("session123").SetVal(string(rawUser))
+	mock.ExpectSetArgs("session123", verifiedUser, redis.SetArgs{Mode: "XX", KeepTTL: true}).SetVal("OK")
+	mock.ExpectGet("expired").RedisNil()
+
+	err := sm.SetUserVerified(context.Background(), user.ID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const verificationKeyPrefix = "verification:"
+
+type verificationManager struct {
+	client   *redis.Client
+	tokenTTL time.Duration
+}
+
+func NewVerificationManager(client *redis.Client, tokenTTL time.Duration) authinterface.VerificationManager {
+	return &verificationManager{
+		client:   client,
+		tokenTTL: tokenTTL,
+	}
+}
+
+func (manager *verificationManager) CreateToken(ctx context.Context, token string, userID uint) error {
+	err := manager.client.Set(ctx, verificationKeyPrefix+token, userID, manager.tokenTTL).Err()
+	if err != nil {
+		return models.AddToRedisError
+	}
+
+	return nil
+}
+
+func (manager *verificationManager) PopToken(ctx context.Context, token string) (uint, error) {
+	rawID, err := manager.client.GetDel(ctx, verificationKeyPrefix+token).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, models.InvalidVerificationToken
+		}
+
+		return 0, err
+	}
+
+	userID, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return 0, models.InvalidVerificationToken
+	}
+
+	return uint(userID), nil
+}
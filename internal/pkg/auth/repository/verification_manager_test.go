@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationManager_CreateToken(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	vm := NewVerificationManager(client, time.Hour)
+
+	mock.ExpectSet(verificationKeyPrefix+"token123", uint(1), time.Hour).SetVal("OK")
+
+	err := vm.CreateToken(context.Background(), "token123", 1)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerificationManager_PopToken(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	vm := NewVerificationManager(client, time.Hour)
+
+	mock.ExpectGetDel(verificationKeyPrefix + "token123").SetVal("1")
+
+	userID, err := vm.PopToken(context.Background(), "token123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), userID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerificationManager_PopToken_NotFound(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	vm := NewVerificationManager(client, time.Hour)
+
+	mock.ExpectGetDel(verificationKeyPrefix + "token123").RedisNil()
+
+	userID, err := vm.PopToken(context.Background(), "token123")
+
+	assert.Equal(t, models.InvalidVerificationToken, err)
+	assert.Zero(t, userID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
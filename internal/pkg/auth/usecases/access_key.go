@@ -0,0 +1,84 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (uc *authUsecases) CreateAccessKey(ctx context.Context, userID uint) (*models.AccessKey, error) {
+	key, err := uc.client.CreateAccessKey(ctx, &protobuf.UserIDData{ID: uint32(userID)})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.ResourceExhausted {
+			return nil, models.AccessKeyLimitError
+		}
+
+		return nil, err
+	}
+
+	return convertAccessKey(key), nil
+}
+
+func (uc *authUsecases) ListAccessKeys(ctx context.Context, userID uint) ([]*models.AccessKey, error) {
+	resp, err := uc.client.ListAccessKeys(ctx, &protobuf.UserIDData{ID: uint32(userID)})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*models.AccessKey, len(resp.AccessKeys))
+	for k, v := range resp.AccessKeys {
+		list[k] = convertAccessKey(v)
+	}
+
+	return list, nil
+}
+
+func (uc *authUsecases) DeleteAccessKey(ctx context.Context, userID uint, id string) error {
+	_, err := uc.client.DeleteAccessKey(ctx, &protobuf.AccessKeyRequest{
+		UserID:      uint32(userID),
+		AccessKeyID: id,
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return models.AccessKeyNotExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (uc *authUsecases) GetAccessKey(ctx context.Context, id string) (*models.AccessKey, *models.User, error) {
+	key, err := uc.client.GetAccessKey(ctx, &protobuf.AccessKeyRequest{AccessKeyID: id})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, nil, models.AccessKeyNotExists
+		}
+
+		return nil, nil, err
+	}
+
+	user := convertUser(key.User)
+	if user.Disabled {
+		return nil, nil, models.UserDisabledError
+	}
+
+	return convertAccessKey(key), user, nil
+}
+
+func convertAccessKey(key *protobuf.AccessKey) *models.AccessKey {
+	return &models.AccessKey{
+		ID:         key.ID,
+		Secret:     key.Secret,
+		UserID:     uint(key.UserID),
+		CreateTime: key.CreateTime.AsTime(),
+	}
+}
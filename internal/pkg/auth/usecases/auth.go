@@ -2,6 +2,9 @@ package usecases
 
 import (
 	"context"
+	"log/slog"
+	"net/mail"
+	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
@@ -14,25 +17,22 @@ import (
 )
 
 type authUsecases struct {
-	client protobuf.AuthClient
+	client               protobuf.AuthClient
+	allowUnverifiedLogin bool
 }
 
-func NewAuthUsecases(client protobuf.AuthClient) authinterface.AuthUsecases {
-	return &authUsecases{client: client}
+func NewAuthUsecases(client protobuf.AuthClient, allowUnverifiedLogin bool) authinterface.AuthUsecases {
+	return &authUsecases{
+		client:               client,
+		allowUnverifiedLogin: allowUnverifiedLogin,
+	}
 }
 
 func (uc *authUsecases) Login(ctx context.Context, data *models.LoginData) (*models.FullUserData, error) {
-	user, err := uc.client.GetUserByEmail(ctx, &protobuf.EmailData{Email: data.Email})
+	user, err := uc.checkCredentials(ctx, data.Email, data.Password)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
-		return nil, models.UserNotExists
-	}
-
-	if !utils.CheckPasswordHash(data.Password, user.PasswordHash) {
-		return nil, models.PasswordsNotMatch
-	}
 
 	sessionID := uuid.NewString()
 	_, err = uc.client.CreateSession(ctx, &protobuf.FullUserData{
@@ -45,14 +45,51 @@ func (uc *authUsecases) Login(ctx context.Context, data *models.LoginData) (*mod
 
 	return &models.FullUserData{
 		User: models.User{
-			ID:    uint(user.ID),
-			Email: user.Email,
+			ID:       uint(user.ID),
+			Email:    user.Email,
+			Verified: user.Verified,
+			Role:     user.Role,
 		},
 		SessionID: sessionID,
 	}, nil
 }
 
+// Authenticate checks the credentials the same way as Login, but does not create a session
+func (uc *authUsecases) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := uc.checkCredentials(ctx, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertUser(user), nil
+}
+
+func (uc *authUsecases) checkCredentials(ctx context.Context, email, password string) (*protobuf.User, error) {
+	user, err := uc.client.GetUserByEmail(ctx, &protobuf.EmailData{Email: email})
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, models.UserNotExists
+	}
+
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return nil, models.PasswordsNotMatch
+	}
+	if !user.Verified && !uc.allowUnverifiedLogin {
+		return nil, models.UserNotVerified
+	}
+	if user.Disabled {
+		return nil, models.UserDisabledError
+	}
+
+	return user, nil
+}
+
 func (uc *authUsecases) Signup(ctx context.Context, data *models.SignupData) (*models.FullUserData, error) {
+	if !isValidEmail(data.Email) {
+		return nil, models.InvalidEmailError
+	}
 	if data.Password != data.PasswordRepeat {
 		return nil, models.PasswordsNotMatch
 	}
@@ -73,6 +110,24 @@ func (uc *authUsecases) Signup(ctx context.Context, data *models.SignupData) (*m
 		return nil, err
 	}
 
+	// The account is already created, so the user can request the email again if sending fails
+	_, err = uc.client.SendVerification(ctx, &protobuf.EmailData{Email: newUser.Email})
+	if err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while sending verification email", "error", err)
+	}
+
+	fullUser := &models.FullUserData{
+		User: models.User{
+			ID:       uint(newUser.ID),
+			Email:    newUser.Email,
+			Verified: newUser.Verified,
+			Role:     newUser.Role,
+		},
+	}
+	if !newUser.Verified && !uc.allowUnverifiedLogin {
+		return fullUser, nil
+	}
+
 	sessionID := uuid.NewString()
 	_, err = uc.client.CreateSession(ctx, &protobuf.FullUserData{
 		User:      newUser,
@@ -82,13 +137,9 @@ func (uc *authUsecases) Signup(ctx context.Context, data *models.SignupData) (*m
 		return nil, err
 	}
 
-	return &models.FullUserData{
-		User: models.User{
-			ID:    uint(newUser.ID),
-			Email: newUser.Email,
-		},
-		SessionID: sessionID,
-	}, nil
+	fullUser.SessionID = sessionID
+
+	return fullUser, nil
 }
 
 func (uc *authUsecases) Logout(ctx context.Context, sessionID string) error {
@@ -111,8 +162,181 @@ func (uc *authUsecases) CheckAuth(ctx context.Context, sessionID string) (*model
 		return nil, false
 	}
 
+	authUser := &models.User{
+		ID:           uint(user.ID),
+		Email:        user.Email,
+		Verified:     user.Verified,
+		Role:         user.Role,
+		AuthProvider: user.AuthProvider,
+	}
+	if user.AuthTime != 0 {
+		authUser.AuthTime = time.Unix(user.AuthTime, 0)
+	}
+
+	return authUser, true
+}
+
+func (uc *authUsecases) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, models.InvalidVerificationToken
+	}
+
+	user, err := uc.client.VerifyEmail(ctx, &protobuf.VerificationData{Token: token})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.InvalidVerificationToken
+		}
+
+		return nil, err
+	}
+
 	return &models.User{
-		ID:    uint(user.ID),
-		Email: user.Email,
-	}, true
+		ID:       uint(user.ID),
+		Email:    user.Email,
+		Verified: user.Verified,
+	}, nil
+}
+
+func (uc *authUsecases) ResendVerification(ctx context.Context, email string) error {
+	if !isValidEmail(email) {
+		return models.InvalidEmailError
+	}
+
+	_, err := uc.client.SendVerification(ctx, &protobuf.EmailData{Email: email})
+	if err != nil {
+		st, _ := status.FromError(err)
+		// Do not disclose whether the email is registered or already verified
+		if st.Code() == codes.NotFound || st.Code() == codes.FailedPrecondition {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (uc *authUsecases) CheckPassword(ctx context.Context, email, password string) error {
+	user, err := uc.client.GetUserByEmail(ctx, &protobuf.EmailData{Email: email})
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return models.UserNotExists
+	}
+
+	if !utils.CheckPasswordHash(password, user.PasswordHash) {
+		return models.PasswordsNotMatch
+	}
+
+	return nil
+}
+
+func (uc *authUsecases) DeleteUser(ctx context.Context, id uint) error {
+	_, err := uc.client.DeleteUser(ctx, &protobuf.UserIDData{ID: uint32(id)})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return models.UserNotExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (uc *authUsecases) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	user, err := uc.client.GetUserByID(ctx, &protobuf.UserIDData{ID: uint32(id)})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.UserNotExists
+		}
+
+		return nil, err
+	}
+
+	return convertUser(user), nil
+}
+
+func (uc *authUsecases) ListUsers(ctx context.Context, options models.UsersListOptions) ([]*models.User, error) {
+	if int(options.Limit) < 0 || int(options.Offset) < 0 || len(options.Query) > 100 {
+		return nil, models.InvalidInputError
+	}
+
+	resp, err := uc.client.ListUsers(ctx, &protobuf.ListUsersRequest{
+		Query:  options.Query,
+		Limit:  uint32(options.Limit),
+		Offset: uint32(options.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*models.User, len(resp.Users))
+	for k, v := range resp.Users {
+		list[k] = convertUser(v)
+	}
+
+	return list, nil
+}
+
+func (uc *authUsecases) SetUserDisabled(ctx context.Context, id uint, disabled bool) (*models.User, error) {
+	user, err := uc.client.SetUserDisabled(ctx, &protobuf.SetUserDisabledRequest{
+		ID:       uint32(id),
+		Disabled: disabled,
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.UserNotExists
+		}
+
+		return nil, err
+	}
+
+	return convertUser(user), nil
+}
+
+func (uc *authUsecases) SetUserRole(ctx context.Context, id uint, role string) (*models.User, error) {
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return nil, models.InvalidRoleError
+	}
+
+	user, err := uc.client.SetUserRole(ctx, &protobuf.SetUserRoleRequest{
+		ID:   uint32(id),
+		Role: role,
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.UserNotExists
+		}
+
+		return nil, err
+	}
+
+	return convertUser(user), nil
+}
+
+func convertUser(user *protobuf.User) *models.User {
+	return &models.User{
+		ID:       uint(user.ID),
+		Email:    user.Email,
+		Verified: user.Verified,
+		Role:     user.Role,
+		Disabled: user.Disabled,
+	}
+}
+
+func isValidEmail(email string) bool {
+	if len(email) == 0 || len(email) > 100 {
+		return false
+	}
+
+	addr, err := mail.ParseAddress(email)
+
+	return err == nil && addr.Address == email
 }
@@ -3,6 +3,7 @@ package usecases
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
@@ -20,7 +21,7 @@ func TestAuthUsecases_Login(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	loginData := &models.LoginData{
 		Email:    "test@example.com",
@@ -48,7 +49,7 @@ func TestAuthUsecases_Signup(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	signupData := &models.SignupData{
 		Email:          "test@example.com",
@@ -62,6 +63,7 @@ func TestAuthUsecases_Signup(t *testing.T) {
 	}
 
 	mockAuthClient.EXPECT().CreateUser(gomock.Any(), &protobuf.NewUser{Email: signupData.Email, Password: signupData.Password}).Return(newUser, nil)
+	mockAuthClient.EXPECT().SendVerification(gomock.Any(), &protobuf.EmailData{Email: signupData.Email}).Return(&emptypb.Empty{}, nil)
 	mockAuthClient.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(&emptypb.Empty{}, nil)
 
 	fullUserData, err := au.Signup(context.Background(), signupData)
@@ -76,7 +78,7 @@ func TestAuthUsecases_Logout(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	sessionID := "session123"
 
@@ -92,10 +94,11 @@ func TestAuthUsecases_CheckAuth(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	sessionID := "session123"
-	user := &protobuf.User{ID: 1, Email: "test@example.com", IsAuth: true}
+	user := &protobuf.User{ID: 1, Email: "test@example.com", IsAuth: true, AuthTime: 1700000000,
+		AuthProvider: "company"}
 
 	mockAuthClient.EXPECT().GetCurrentUser(gomock.Any(), &protobuf.SessionData{SessionID: sessionID}).Return(user, nil)
 
@@ -104,6 +107,8 @@ func TestAuthUsecases_CheckAuth(t *testing.T) {
 	assert.True(t, isAuth)
 	assert.NotNil(t, retrievedUser)
 	assert.Equal(t, user.Email, retrievedUser.Email)
+	assert.Equal(t, time.Unix(1700000000, 0), retrievedUser.AuthTime)
+	assert.Equal(t, "company", retrievedUser.AuthProvider)
 }
 
 func TestAuthUsecases_CheckAuth_NotAuth(t *testing.T) {
@@ -111,7 +116,7 @@ func TestAuthUsecases_CheckAuth_NotAuth(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	sessionID := "session123"
 	user := &protobuf.User{IsAuth: false}
@@ -129,7 +134,7 @@ func TestAuthUsecases_Signup_PasswordMismatch(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	signupData := &models.SignupData{
 		Email:          "test@example.com",
@@ -149,7 +154,7 @@ func TestAuthUsecases_Signup_UserAlreadyExists(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAuthClient := mocks.NewMockAuthClient(ctrl)
-	au := NewAuthUsecases(mockAuthClient)
+	au := NewAuthUsecases(mockAuthClient, true)
 
 	signupData := &models.SignupData{
 		Email:          "test@example.com",
@@ -166,3 +171,245 @@ func TestAuthUsecases_Signup_UserAlreadyExists(t *testing.T) {
 	assert.Nil(t, fullUserData)
 	assert.Equal(t, models.UserAlreadyExists, err)
 }
+
+func TestAuthUsecases_Signup_InvalidEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	for _, email := range []string{"", "test", "test@", "Test <test@example.com>"} {
+		fullUserData, err := au.Signup(context.Background(), &models.SignupData{
+			Email:          email,
+			Password:       "password",
+			PasswordRepeat: "password",
+		})
+
+		assert.Nil(t, fullUserData)
+		assert.Equal(t, models.InvalidEmailError, err)
+	}
+}
+
+func TestAuthUsecases_Signup_UnverifiedLoginForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, false)
+
+	signupData := &models.SignupData{
+		Email:          "test@example.com",
+		Password:       "password",
+		PasswordRepeat: "password",
+	}
+
+	newUser := &protobuf.User{ID: 1, Email: signupData.Email}
+
+	mockAuthClient.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(newUser, nil)
+	mockAuthClient.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(&emptypb.Empty{}, nil)
+
+	fullUserData, err := au.Signup(context.Background(), signupData)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, fullUserData)
+	assert.Empty(t, fullUserData.SessionID)
+}
+
+func TestAuthUsecases_Login_NotVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, false)
+
+	loginData := &models.LoginData{
+		Email:    "test@example.com",
+		Password: "password",
+	}
+
+	user := &protobuf.User{
+		ID:           1,
+		Email:        loginData.Email,
+		PasswordHash: utils.HashPassword(loginData.Password),
+	}
+
+	mockAuthClient.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(user, nil)
+
+	fullUserData, err := au.Login(context.Background(), loginData)
+
+	assert.Nil(t, fullUserData)
+	assert.Equal(t, models.UserNotVerified, err)
+}
+
+func TestAuthUsecases_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	token := "token123"
+	user := &protobuf.User{ID: 1, Email: "test@example.com", Verified: true}
+
+	mockAuthClient.EXPECT().VerifyEmail(gomock.Any(), &protobuf.VerificationData{Token: token}).Return(user, nil)
+
+	verifiedUser, err := au.VerifyEmail(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.True(t, verifiedUser.Verified)
+}
+
+func TestAuthUsecases_VerifyEmail_InvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	st := status.New(codes.NotFound, "invalid token")
+	mockAuthClient.EXPECT().VerifyEmail(gomock.Any(), gomock.Any()).Return(nil, st.Err())
+
+	user, err := au.VerifyEmail(context.Background(), "token123")
+
+	assert.Nil(t, user)
+	assert.Equal(t, models.InvalidVerificationToken, err)
+}
+
+func TestAuthUsecases_ResendVerification_UnknownEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	st := status.New(codes.NotFound, "user does not exist")
+	mockAuthClient.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil, st.Err())
+
+	err := au.ResendVerification(context.Background(), "test@example.com")
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUsecases_ResendVerification_AlreadyVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	st := status.New(codes.FailedPrecondition, "user email is already verified")
+	mockAuthClient.EXPECT().SendVerification(gomock.Any(), gomock.Any()).Return(nil, st.Err())
+
+	err := au.ResendVerification(context.Background(), "test@example.com")
+
+	assert.NoError(t, err)
+}
+
+func TestAuthUsecases_Login_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	loginData := &models.LoginData{
+		Email:    "test@example.com",
+		Password: "password",
+	}
+
+	user := &protobuf.User{
+		ID:           1,
+		Email:        loginData.Email,
+		PasswordHash: utils.HashPassword(loginData.Password),
+		Disabled:     true,
+	}
+
+	mockAuthClient.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(user, nil)
+
+	fullUserData, err := au.Login(context.Background(), loginData)
+
+	assert.Nil(t, fullUserData)
+	assert.Equal(t, models.UserDisabledError, err)
+}
+
+func TestAuthUsecases_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	user := &protobuf.User{
+		ID:           1,
+		Email:        "test@example.com",
+		PasswordHash: utils.HashPassword("password"),
+	}
+
+	mockAuthClient.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(user, nil).Times(2)
+
+	authUser, err := au.Authenticate(context.Background(), user.Email, "password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), authUser.ID)
+
+	authUser, err = au.Authenticate(context.Background(), user.Email, "wrong password")
+
+	assert.Nil(t, authUser)
+	assert.Equal(t, models.PasswordsNotMatch, err)
+}
+
+func TestAuthUsecases_GetAccessKey_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	key := &protobuf.AccessKey{
+		ID:     "VBKEY",
+		Secret: "secret",
+		UserID: 1,
+		User:   &protobuf.User{ID: 1, Disabled: true},
+	}
+
+	mockAuthClient.EXPECT().GetAccessKey(gomock.Any(), &protobuf.AccessKeyRequest{AccessKeyID: "VBKEY"}).
+		Return(key, nil)
+
+	accessKey, user, err := au.GetAccessKey(context.Background(), "VBKEY")
+
+	assert.Nil(t, accessKey)
+	assert.Nil(t, user)
+	assert.Equal(t, models.UserDisabledError, err)
+}
+
+func TestAuthUsecases_SetUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	user := &protobuf.User{ID: 2, Email: "test@example.com", Role: models.RoleAdmin}
+
+	mockAuthClient.EXPECT().SetUserRole(gomock.Any(), &protobuf.SetUserRoleRequest{ID: 2, Role: models.RoleAdmin}).
+		Return(user, nil)
+
+	updatedUser, err := au.SetUserRole(context.Background(), 2, models.RoleAdmin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, updatedUser.Role)
+}
+
+func TestAuthUsecases_SetUserRole_InvalidRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	au := NewAuthUsecases(mockAuthClient, true)
+
+	user, err := au.SetUserRole(context.Background(), 2, "superuser")
+
+	assert.Nil(t, user)
+	assert.Equal(t, models.InvalidRoleError, err)
+}
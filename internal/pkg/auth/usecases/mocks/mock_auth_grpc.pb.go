@@ -43,6 +43,46 @@ func (m *MockAuthClient) EXPECT() *MockAuthClientMockRecorder {
 	return m.recorder
 }
 
+// CreateAccessKey mocks base method.
+func (m *MockAuthClient) CreateAccessKey(ctx context.Context, in *protobuf.UserIDData, opts ...grpc.CallOption) (*protobuf.AccessKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAccessKey", varargs...)
+	ret0, _ := ret[0].(*protobuf.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessKey indicates an expected call of CreateAccessKey.
+func (mr *MockAuthClientMockRecorder) CreateAccessKey(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessKey", reflect.TypeOf((*MockAuthClient)(nil).CreateAccessKey), varargs...)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockAuthClient) CreateOrganization(ctx context.Context, in *protobuf.CreateOrganizationRequest, opts ...grpc.CallOption) (*protobuf.Organization, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateOrganization", varargs...)
+	ret0, _ := ret[0].(*protobuf.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockAuthClientMockRecorder) CreateOrganization(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockAuthClient)(nil).CreateOrganization), varargs...)
+}
+
 // CreateSession mocks base method.
 func (m *MockAuthClient) CreateSession(ctx context.Context, in *protobuf.FullUserData, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -83,6 +123,66 @@ func (mr *MockAuthClientMockRecorder) CreateUser(ctx, in any, opts ...any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthClient)(nil).CreateUser), varargs...)
 }
 
+// DeleteAccessKey mocks base method.
+func (m *MockAuthClient) DeleteAccessKey(ctx context.Context, in *protobuf.AccessKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAccessKey", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccessKey indicates an expected call of DeleteAccessKey.
+func (mr *MockAuthClientMockRecorder) DeleteAccessKey(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessKey", reflect.TypeOf((*MockAuthClient)(nil).DeleteAccessKey), varargs...)
+}
+
+// DeleteUser mocks base method.
+func (m *MockAuthClient) DeleteUser(ctx context.Context, in *protobuf.UserIDData, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteUser", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockAuthClientMockRecorder) DeleteUser(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAuthClient)(nil).DeleteUser), varargs...)
+}
+
+// GetAccessKey mocks base method.
+func (m *MockAuthClient) GetAccessKey(ctx context.Context, in *protobuf.AccessKeyRequest, opts ...grpc.CallOption) (*protobuf.AccessKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAccessKey", varargs...)
+	ret0, _ := ret[0].(*protobuf.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessKey indicates an expected call of GetAccessKey.
+func (mr *MockAuthClientMockRecorder) GetAccessKey(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessKey", reflect.TypeOf((*MockAuthClient)(nil).GetAccessKey), varargs...)
+}
+
 // GetCurrentUser mocks base method.
 func (m *MockAuthClient) GetCurrentUser(ctx context.Context, in *protobuf.SessionData, opts ...grpc.CallOption) (*protobuf.User, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +203,46 @@ func (mr *MockAuthClientMockRecorder) GetCurrentUser(ctx, in any, opts ...any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockAuthClient)(nil).GetCurrentUser), varargs...)
 }
 
+// GetMembership mocks base method.
+func (m *MockAuthClient) GetMembership(ctx context.Context, in *protobuf.MembershipRequest, opts ...grpc.CallOption) (*protobuf.Organization, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetMembership", varargs...)
+	ret0, _ := ret[0].(*protobuf.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembership indicates an expected call of GetMembership.
+func (mr *MockAuthClientMockRecorder) GetMembership(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembership", reflect.TypeOf((*MockAuthClient)(nil).GetMembership), varargs...)
+}
+
+// GetOrCreateExternalUser mocks base method.
+func (m *MockAuthClient) GetOrCreateExternalUser(ctx context.Context, in *protobuf.ExternalIdentity, opts ...grpc.CallOption) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetOrCreateExternalUser", varargs...)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateExternalUser indicates an expected call of GetOrCreateExternalUser.
+func (mr *MockAuthClientMockRecorder) GetOrCreateExternalUser(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateExternalUser", reflect.TypeOf((*MockAuthClient)(nil).GetOrCreateExternalUser), varargs...)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockAuthClient) GetUserByEmail(ctx context.Context, in *protobuf.EmailData, opts ...grpc.CallOption) (*protobuf.User, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +263,106 @@ func (mr *MockAuthClientMockRecorder) GetUserByEmail(ctx, in any, opts ...any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockAuthClient)(nil).GetUserByEmail), varargs...)
 }
 
+// GetUserByID mocks base method.
+func (m *MockAuthClient) GetUserByID(ctx context.Context, in *protobuf.UserIDData, opts ...grpc.CallOption) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUserByID", varargs...)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthClientMockRecorder) GetUserByID(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthClient)(nil).GetUserByID), varargs...)
+}
+
+// ListAccessKeys mocks base method.
+func (m *MockAuthClient) ListAccessKeys(ctx context.Context, in *protobuf.UserIDData, opts ...grpc.CallOption) (*protobuf.AccessKeysList, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAccessKeys", varargs...)
+	ret0, _ := ret[0].(*protobuf.AccessKeysList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessKeys indicates an expected call of ListAccessKeys.
+func (mr *MockAuthClientMockRecorder) ListAccessKeys(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessKeys", reflect.TypeOf((*MockAuthClient)(nil).ListAccessKeys), varargs...)
+}
+
+// ListMembers mocks base method.
+func (m *MockAuthClient) ListMembers(ctx context.Context, in *protobuf.MembershipRequest, opts ...grpc.CallOption) (*protobuf.MembersList, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListMembers", varargs...)
+	ret0, _ := ret[0].(*protobuf.MembersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockAuthClientMockRecorder) ListMembers(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockAuthClient)(nil).ListMembers), varargs...)
+}
+
+// ListOrganizations mocks base method.
+func (m *MockAuthClient) ListOrganizations(ctx context.Context, in *protobuf.UserIDData, opts ...grpc.CallOption) (*protobuf.OrganizationsList, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListOrganizations", varargs...)
+	ret0, _ := ret[0].(*protobuf.OrganizationsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockAuthClientMockRecorder) ListOrganizations(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockAuthClient)(nil).ListOrganizations), varargs...)
+}
+
+// ListUsers mocks base method.
+func (m *MockAuthClient) ListUsers(ctx context.Context, in *protobuf.ListUsersRequest, opts ...grpc.CallOption) (*protobuf.UsersList, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListUsers", varargs...)
+	ret0, _ := ret[0].(*protobuf.UsersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockAuthClientMockRecorder) ListUsers(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAuthClient)(nil).ListUsers), varargs...)
+}
+
 // Logout mocks base method.
 func (m *MockAuthClient) Logout(ctx context.Context, in *protobuf.SessionData, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -143,6 +383,126 @@ func (mr *MockAuthClientMockRecorder) Logout(ctx, in any, opts ...any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthClient)(nil).Logout), varargs...)
 }
 
+// RemoveMember mocks base method.
+func (m *MockAuthClient) RemoveMember(ctx context.Context, in *protobuf.RemoveMemberRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveMember", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockAuthClientMockRecorder) RemoveMember(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockAuthClient)(nil).RemoveMember), varargs...)
+}
+
+// SendVerification mocks base method.
+func (m *MockAuthClient) SendVerification(ctx context.Context, in *protobuf.EmailData, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendVerification", varargs...)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockAuthClientMockRecorder) SendVerification(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockAuthClient)(nil).SendVerification), varargs...)
+}
+
+// SetMember mocks base method.
+func (m *MockAuthClient) SetMember(ctx context.Context, in *protobuf.SetMemberRequest, opts ...grpc.CallOption) (*protobuf.Member, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetMember", varargs...)
+	ret0, _ := ret[0].(*protobuf.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMember indicates an expected call of SetMember.
+func (mr *MockAuthClientMockRecorder) SetMember(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMember", reflect.TypeOf((*MockAuthClient)(nil).SetMember), varargs...)
+}
+
+// SetUserDisabled mocks base method.
+func (m *MockAuthClient) SetUserDisabled(ctx context.Context, in *protobuf.SetUserDisabledRequest, opts ...grpc.CallOption) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetUserDisabled", varargs...)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserDisabled indicates an expected call of SetUserDisabled.
+func (mr *MockAuthClientMockRecorder) SetUserDisabled(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDisabled", reflect.TypeOf((*MockAuthClient)(nil).SetUserDisabled), varargs...)
+}
+
+// SetUserRole mocks base method.
+func (m *MockAuthClient) SetUserRole(ctx context.Context, in *protobuf.SetUserRoleRequest, opts ...grpc.CallOption) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetUserRole", varargs...)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockAuthClientMockRecorder) SetUserRole(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockAuthClient)(nil).SetUserRole), varargs...)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthClient) VerifyEmail(ctx context.Context, in *protobuf.VerificationData, opts ...grpc.CallOption) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "VerifyEmail", varargs...)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthClientMockRecorder) VerifyEmail(ctx, in any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthClient)(nil).VerifyEmail), varargs...)
+}
+
 // MockAuthServer is a mock of AuthServer interface.
 type MockAuthServer struct {
 	ctrl     *gomock.Controller
@@ -167,6 +527,36 @@ func (m *MockAuthServer) EXPECT() *MockAuthServerMockRecorder {
 	return m.recorder
 }
 
+// CreateAccessKey mocks base method.
+func (m *MockAuthServer) CreateAccessKey(arg0 context.Context, arg1 *protobuf.UserIDData) (*protobuf.AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessKey", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessKey indicates an expected call of CreateAccessKey.
+func (mr *MockAuthServerMockRecorder) CreateAccessKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessKey", reflect.TypeOf((*MockAuthServer)(nil).CreateAccessKey), arg0, arg1)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockAuthServer) CreateOrganization(arg0 context.Context, arg1 *protobuf.CreateOrganizationRequest) (*protobuf.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockAuthServerMockRecorder) CreateOrganization(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockAuthServer)(nil).CreateOrganization), arg0, arg1)
+}
+
 // CreateSession mocks base method.
 func (m *MockAuthServer) CreateSession(arg0 context.Context, arg1 *protobuf.FullUserData) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +587,51 @@ func (mr *MockAuthServerMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthServer)(nil).CreateUser), arg0, arg1)
 }
 
+// DeleteAccessKey mocks base method.
+func (m *MockAuthServer) DeleteAccessKey(arg0 context.Context, arg1 *protobuf.AccessKeyRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessKey", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccessKey indicates an expected call of DeleteAccessKey.
+func (mr *MockAuthServerMockRecorder) DeleteAccessKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessKey", reflect.TypeOf((*MockAuthServer)(nil).DeleteAccessKey), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockAuthServer) DeleteUser(arg0 context.Context, arg1 *protobuf.UserIDData) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockAuthServerMockRecorder) DeleteUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockAuthServer)(nil).DeleteUser), arg0, arg1)
+}
+
+// GetAccessKey mocks base method.
+func (m *MockAuthServer) GetAccessKey(arg0 context.Context, arg1 *protobuf.AccessKeyRequest) (*protobuf.AccessKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessKey", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.AccessKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessKey indicates an expected call of GetAccessKey.
+func (mr *MockAuthServerMockRecorder) GetAccessKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessKey", reflect.TypeOf((*MockAuthServer)(nil).GetAccessKey), arg0, arg1)
+}
+
 // GetCurrentUser mocks base method.
 func (m *MockAuthServer) GetCurrentUser(arg0 context.Context, arg1 *protobuf.SessionData) (*protobuf.User, error) {
 	m.ctrl.T.Helper()
@@ -212,6 +647,36 @@ func (mr *MockAuthServerMockRecorder) GetCurrentUser(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockAuthServer)(nil).GetCurrentUser), arg0, arg1)
 }
 
+// GetMembership mocks base method.
+func (m *MockAuthServer) GetMembership(arg0 context.Context, arg1 *protobuf.MembershipRequest) (*protobuf.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembership", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembership indicates an expected call of GetMembership.
+func (mr *MockAuthServerMockRecorder) GetMembership(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembership", reflect.TypeOf((*MockAuthServer)(nil).GetMembership), arg0, arg1)
+}
+
+// GetOrCreateExternalUser mocks base method.
+func (m *MockAuthServer) GetOrCreateExternalUser(arg0 context.Context, arg1 *protobuf.ExternalIdentity) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateExternalUser", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateExternalUser indicates an expected call of GetOrCreateExternalUser.
+func (mr *MockAuthServerMockRecorder) GetOrCreateExternalUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateExternalUser", reflect.TypeOf((*MockAuthServer)(nil).GetOrCreateExternalUser), arg0, arg1)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockAuthServer) GetUserByEmail(arg0 context.Context, arg1 *protobuf.EmailData) (*protobuf.User, error) {
 	m.ctrl.T.Helper()
@@ -227,6 +692,81 @@ func (mr *MockAuthServerMockRecorder) GetUserByEmail(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockAuthServer)(nil).GetUserByEmail), arg0, arg1)
 }
 
+// GetUserByID mocks base method.
+func (m *MockAuthServer) GetUserByID(arg0 context.Context, arg1 *protobuf.UserIDData) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthServerMockRecorder) GetUserByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthServer)(nil).GetUserByID), arg0, arg1)
+}
+
+// ListAccessKeys mocks base method.
+func (m *MockAuthServer) ListAccessKeys(arg0 context.Context, arg1 *protobuf.UserIDData) (*protobuf.AccessKeysList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccessKeys", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.AccessKeysList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessKeys indicates an expected call of ListAccessKeys.
+func (mr *MockAuthServerMockRecorder) ListAccessKeys(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessKeys", reflect.TypeOf((*MockAuthServer)(nil).ListAccessKeys), arg0, arg1)
+}
+
+// ListMembers mocks base method.
+func (m *MockAuthServer) ListMembers(arg0 context.Context, arg1 *protobuf.MembershipRequest) (*protobuf.MembersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.MembersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockAuthServerMockRecorder) ListMembers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockAuthServer)(nil).ListMembers), arg0, arg1)
+}
+
+// ListOrganizations mocks base method.
+func (m *MockAuthServer) ListOrganizations(arg0 context.Context, arg1 *protobuf.UserIDData) (*protobuf.OrganizationsList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizations", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.OrganizationsList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockAuthServerMockRecorder) ListOrganizations(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockAuthServer)(nil).ListOrganizations), arg0, arg1)
+}
+
+// ListUsers mocks base method.
+func (m *MockAuthServer) ListUsers(arg0 context.Context, arg1 *protobuf.ListUsersRequest) (*protobuf.UsersList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.UsersList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockAuthServerMockRecorder) ListUsers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAuthServer)(nil).ListUsers), arg0, arg1)
+}
+
 // Logout mocks base method.
 func (m *MockAuthServer) Logout(arg0 context.Context, arg1 *protobuf.SessionData) (*emptypb.Empty, error) {
 	m.ctrl.T.Helper()
@@ -242,6 +782,96 @@ func (mr *MockAuthServerMockRecorder) Logout(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthServer)(nil).Logout), arg0, arg1)
 }
 
+// RemoveMember mocks base method.
+func (m *MockAuthServer) RemoveMember(arg0 context.Context, arg1 *protobuf.RemoveMemberRequest) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockAuthServerMockRecorder) RemoveMember(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockAuthServer)(nil).RemoveMember), arg0, arg1)
+}
+
+// SendVerification mocks base method.
+func (m *MockAuthServer) SendVerification(arg0 context.Context, arg1 *protobuf.EmailData) (*emptypb.Empty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendVerification", arg0, arg1)
+	ret0, _ := ret[0].(*emptypb.Empty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendVerification indicates an expected call of SendVerification.
+func (mr *MockAuthServerMockRecorder) SendVerification(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendVerification", reflect.TypeOf((*MockAuthServer)(nil).SendVerification), arg0, arg1)
+}
+
+// SetMember mocks base method.
+func (m *MockAuthServer) SetMember(arg0 context.Context, arg1 *protobuf.SetMemberRequest) (*protobuf.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMember", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMember indicates an expected call of SetMember.
+func (mr *MockAuthServerMockRecorder) SetMember(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMember", reflect.TypeOf((*MockAuthServer)(nil).SetMember), arg0, arg1)
+}
+
+// SetUserDisabled mocks base method.
+func (m *MockAuthServer) SetUserDisabled(arg0 context.Context, arg1 *protobuf.SetUserDisabledRequest) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserDisabled", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserDisabled indicates an expected call of SetUserDisabled.
+func (mr *MockAuthServerMockRecorder) SetUserDisabled(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDisabled", reflect.TypeOf((*MockAuthServer)(nil).SetUserDisabled), arg0, arg1)
+}
+
+// SetUserRole mocks base method.
+func (m *MockAuthServer) SetUserRole(arg0 context.Context, arg1 *protobuf.SetUserRoleRequest) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockAuthServerMockRecorder) SetUserRole(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockAuthServer)(nil).SetUserRole), arg0, arg1)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthServer) VerifyEmail(arg0 context.Context, arg1 *protobuf.VerificationData) (*protobuf.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(*protobuf.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthServerMockRecorder) VerifyEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthServer)(nil).VerifyEmail), arg0, arg1)
+}
+
 // mustEmbedUnimplementedAuthServer mocks base method.
 func (m *MockAuthServer) mustEmbedUnimplementedAuthServer() {
 	m.ctrl.T.Helper()
@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type OIDCProviderOptions struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type oidcProvider struct {
+	options OIDCProviderOptions
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// init runs the discovery lazily, so an unavailable provider does not prevent the gateway from starting
+func (p *oidcProvider) init(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.verifier != nil {
+		return nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.options.Issuer)
+	if err != nil {
+		return fmt.Errorf("discovery of %s failed: %w", p.options.Issuer, err)
+	}
+
+	scopes := append([]string{oidc.ScopeOpenID}, p.options.Scopes...)
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.options.ClientID,
+		ClientSecret: p.options.ClientSecret,
+		RedirectURL:  p.options.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.options.ClientID})
+
+	return nil
+}
+
+type oidcUsecases struct {
+	client       protobuf.AuthClient
+	stateStorage authinterface.OIDCStateStorage
+	providers    map[string]*oidcProvider
+
+	allowUnverifiedLogin bool
+}
+
+func NewOIDCUsecases(client protobuf.AuthClient, stateStorage authinterface.OIDCStateStorage,
+	providers []OIDCProviderOptions, allowUnverifiedLogin bool) authinterface.OIDCUsecases {
+	uc := &oidcUsecases{
+		client:               client,
+		stateStorage:         stateStorage,
+		providers:            make(map[string]*oidcProvider, len(providers)),
+		allowUnverifiedLogin: allowUnverifiedLogin,
+	}
+
+	for _, options := range providers {
+		uc.providers[options.Name] = &oidcProvider{options: options}
+	}
+
+	return uc
+}
+
+func (uc *oidcUsecases) AuthURL(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return "", "", models.UnknownProviderError
+	}
+
+	if err := provider.init(ctx); err != nil {
+		return "", "", err
+	}
+
+	state := rand.Text()
+	data := &models.OIDCState{
+		Provider: providerName,
+		Verifier: oauth2.GenerateVerifier(),
+		Nonce:    rand.Text(),
+	}
+
+	if err := uc.stateStorage.SaveState(ctx, state, data); err != nil {
+		return "", "", err
+	}
+
+	url := provider.oauth.AuthCodeURL(state, oauth2.S256ChallengeOption(data.Verifier), oidc.Nonce(data.Nonce))
+
+	return url, state, nil
+}
+
+func (uc *oidcUsecases) Callback(ctx context.Context, providerName, state, code string,
+) (*models.FullUserData, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return nil, models.UnknownProviderError
+	}
+
+	data, err := uc.stateStorage.PopState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if data.Provider != providerName {
+		return nil, models.InvalidOIDCStateError
+	}
+
+	if err := provider.init(ctx); err != nil {
+		return nil, err
+	}
+
+	token, err := provider.oauth.Exchange(ctx, code, oauth2.VerifierOption(data.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.OIDCExchangeError, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: no id_token in response", models.OIDCExchangeError)
+	}
+
+	idToken, err := provider.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.OIDCExchangeError, err)
+	}
+	if idToken.Nonce != data.Nonce {
+		return nil, models.InvalidOIDCStateError
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.OIDCExchangeError, err)
+	}
+	if !isValidEmail(claims.Email) {
+		return nil, models.InvalidEmailError
+	}
+	// Provider emails must be verified unless unverified login is allowed. Even then an unverified email
+	// only creates a new account, the auth service refuses to link it to an existing one.
+	if !claims.EmailVerified && !uc.allowUnverifiedLogin {
+		return nil, models.UserNotVerified
+	}
+
+	user, err := uc.client.GetOrCreateExternalUser(ctx, &protobuf.ExternalIdentity{
+		Provider:      providerName,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.AlreadyExists {
+			return nil, models.IdentityConflictError
+		}
+
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, models.UserDisabledError
+	}
+
+	sessionID := uuid.NewString()
+	_, err = uc.client.CreateSession(ctx, &protobuf.FullUserData{
+		User:      user,
+		SessionID: sessionID,
+		Provider:  providerName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.FullUserData{
+		User: models.User{
+			ID:       uint(user.ID),
+			Email:    user.Email,
+			Verified: user.Verified,
+			Role:     user.Role,
+		},
+		SessionID: sessionID,
+	}, nil
+}
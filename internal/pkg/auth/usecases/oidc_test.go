@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/usecases/mocks"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	testClientID = "voblako"
+	testSubject  = "external-user-1"
+	testEmail    = "sso@example.com"
+)
+
+type memoryStateStorage struct {
+	states map[string]*models.OIDCState
+}
+
+func (s *memoryStateStorage) SaveState(_ context.Context, state string, data *models.OIDCState) error {
+	s.states[state] = data
+	return nil
+}
+
+func (s *memoryStateStorage) PopState(_ context.Context, state string) (*models.OIDCState, error) {
+	data, ok := s.states[state]
+	if !ok {
+		return nil, models.InvalidOIDCStateError
+	}
+	delete(s.states, state)
+
+	return data, nil
+}
+
+// mockOIDCServer is a minimal provider supporting discovery, JWKS and the token endpoint with PKCE
+type mockOIDCServer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	codeChallenge string
+	nonce         string
+	emailVerified bool
+}
+
+func newMockOIDCServer(t *testing.T) *mockOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	m := &mockOIDCServer{key: key, emailVerified: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                m.server.URL,
+			"authorization_endpoint":                m.server.URL + "/authorize",
+			"token_endpoint":                        m.server.URL + "/token",
+			"jwks_uri":                              m.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key:       &m.key.PublicKey,
+			KeyID:     "test-key",
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "valid-code" ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != m.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     m.idToken(t),
+		})
+	})
+
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+func (m *mockOIDCServer) idToken(t *testing.T) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: m.key},
+		(&jose.SignerOptions{}).WithHeader("kid", "test-key"))
+	require.NoError(t, err)
+
+	now := time.Now()
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Issuer:   m.server.URL,
+		Subject:  testSubject,
+		Audience: jwt.Audience{testClientID},
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}).Claims(map[string]any{
+		"nonce":          m.nonce,
+		"email":          testEmail,
+		"email_verified": m.emailVerified,
+	}).Serialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func newTestOIDCUsecases(t *testing.T, client protobuf.AuthClient) (*mockOIDCServer, *oidcUsecases) {
+	server := newMockOIDCServer(t)
+	uc := NewOIDCUsecases(client, &memoryStateStorage{states: make(map[string]*models.OIDCState)},
+		[]OIDCProviderOptions{{
+			Name:        "company",
+			Issuer:      server.server.URL,
+			ClientID:    testClientID,
+			RedirectURL: "http://localhost/api/auth/oidc/company/callback",
+			Scopes:      []string{"email"},
+		}}, false)
+
+	return server, uc.(*oidcUsecases)
+}
+
+func startLogin(t *testing.T, server *mockOIDCServer, uc *oidcUsecases) string {
+	authURL, state, err := uc.AuthURL(context.Background(), "company")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	query := parsed.Query()
+	assert.Equal(t, state, query.Get("state"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+
+	server.codeChallenge = query.Get("code_challenge")
+	server.nonce = query.Get("nonce")
+
+	return state
+}
+
+func TestOIDCUsecases_Callback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	server, uc := newTestOIDCUsecases(t, mockAuthClient)
+
+	state := startLogin(t, server, uc)
+
+	user := &protobuf.User{ID: 1, Email: testEmail, Verified: true}
+	mockAuthClient.EXPECT().GetOrCreateExternalUser(gomock.Any(), &protobuf.ExternalIdentity{
+		Provider:      "company",
+		Subject:       testSubject,
+		Email:         testEmail,
+		EmailVerified: true,
+	}).Return(user, nil)
+	mockAuthClient.EXPECT().CreateSession(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, data *protobuf.FullUserData, _ ...grpc.CallOption) (*emptypb.Empty, error) {
+			assert.Equal(t, "company", data.Provider)
+			return &emptypb.Empty{}, nil
+		})
+
+	fullUserData, err := uc.Callback(context.Background(), "company", state, "valid-code")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, fullUserData.SessionID)
+	assert.Equal(t, testEmail, fullUserData.User.Email)
+}
+
+func TestOIDCUsecases_Callback_StateReused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	server, uc := newTestOIDCUsecases(t, mockAuthClient)
+
+	state := startLogin(t, server, uc)
+
+	mockAuthClient.EXPECT().GetOrCreateExternalUser(gomock.Any(), gomock.Any()).
+		Return(&protobuf.User{ID: 1, Email: testEmail}, nil)
+	mockAuthClient.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(&emptypb.Empty{}, nil)
+
+	_, err := uc.Callback(context.Background(), "company", state, "valid-code")
+	assert.NoError(t, err)
+
+	_, err = uc.Callback(context.Background(), "company", state, "valid-code")
+	assert.ErrorIs(t, err, models.InvalidOIDCStateError)
+}
+
+func TestOIDCUsecases_Callback_InvalidCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	server, uc := newTestOIDCUsecases(t, mockAuthClient)
+
+	state := startLogin(t, server, uc)
+
+	_, err := uc.Callback(context.Background(), "company", state, "wrong-code")
+
+	assert.ErrorIs(t, err, models.OIDCExchangeError)
+}
+
+func TestOIDCUsecases_Callback_IdentityConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	server, uc := newTestOIDCUsecases(t, mockAuthClient)
+
+	state := startLogin(t, server, uc)
+
+	st := status.New(codes.AlreadyExists, "conflict")
+	mockAuthClient.EXPECT().GetOrCreateExternalUser(gomock.Any(), gomock.Any()).Return(nil, st.Err())
+
+	_, err := uc.Callback(context.Background(), "company", state, "valid-code")
+
+	assert.ErrorIs(t, err, models.IdentityConflictError)
+}
+
+func TestOIDCUsecases_Callback_UnverifiedEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	server, uc := newTestOIDCUsecases(t, mockAuthClient)
+	server.emailVerified = false
+
+	state := startLogin(t, server, uc)
+
+	_, err := uc.Callback(context.Background(), "company", state, "valid-code")
+	assert.ErrorIs(t, err, models.UserNotVerified)
+
+	uc.allowUnverifiedLogin = true
+	state = startLogin(t, server, uc)
+
+	mockAuthClient.EXPECT().GetOrCreateExternalUser(gomock.Any(), &protobuf.ExternalIdentity{
+		Provider:      "company",
+		Subject:       testSubject,
+		Email:         testEmail,
+		EmailVerified: false,
+	}).Return(&protobuf.User{ID: 1, Email: testEmail}, nil)
+	mockAuthClient.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(&emptypb.Empty{}, nil)
+
+	fullUserData, err := uc.Callback(context.Background(), "company", state, "valid-code")
+	assert.NoError(t, err)
+	assert.False(t, fullUserData.User.Verified)
+}
+
+func TestOIDCUsecases_UnknownProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, uc := newTestOIDCUsecases(t, mocks.NewMockAuthClient(ctrl))
+
+	_, _, err := uc.AuthURL(context.Background(), "unknown")
+
+	assert.ErrorIs(t, err, models.UnknownProviderError)
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type orgUsecases struct {
+	client protobuf.AuthClient
+}
+
+func NewOrgUsecases(client protobuf.AuthClient) authinterface.OrgUsecases {
+	return &orgUsecases{client: client}
+}
+
+func (uc *orgUsecases) CreateOrganization(ctx context.Context, ownerID uint, name string) (*models.Organization, error) {
+	if utf8.RuneCountInString(name) < 1 || utf8.RuneCountInString(name) > 50 {
+		return nil, models.InvalidOrgNameError
+	}
+
+	org, err := uc.client.CreateOrganization(ctx, &protobuf.CreateOrganizationRequest{
+		OwnerID: uint32(ownerID),
+		Name:    name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertOrganization(org), nil
+}
+
+func (uc *orgUsecases) ListOrganizations(ctx context.Context, userID uint) ([]*models.Organization, error) {
+	resp, err := uc.client.ListOrganizations(ctx, &protobuf.UserIDData{ID: uint32(userID)})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*models.Organization, len(resp.Organizations))
+	for k, v := range resp.Organizations {
+		list[k] = convertOrganization(v)
+	}
+
+	return list, nil
+}
+
+func (uc *orgUsecases) GetOrganization(ctx context.Context, userID, orgID uint) (*models.Organization, error) {
+	org, err := uc.client.GetMembership(ctx, &protobuf.MembershipRequest{
+		OrgID:  uint32(orgID),
+		UserID: uint32(userID),
+	})
+	if err != nil {
+		return nil, convertOrgErr(err)
+	}
+
+	return convertOrganization(org), nil
+}
+
+func (uc *orgUsecases) ListMembers(ctx context.Context, userID, orgID uint) ([]*models.OrgMember, error) {
+	resp, err := uc.client.ListMembers(ctx, &protobuf.MembershipRequest{
+		OrgID:  uint32(orgID),
+		UserID: uint32(userID),
+	})
+	if err != nil {
+		return nil, convertOrgErr(err)
+	}
+
+	list := make([]*models.OrgMember, len(resp.Members))
+	for k, v := range resp.Members {
+		list[k] = convertMember(v)
+	}
+
+	return list, nil
+}
+
+func (uc *orgUsecases) SetMember(
+	ctx context.Context, actorID, orgID uint, data *models.SetMemberData,
+) (*models.OrgMember, error) {
+	if data.Role != models.OrgRoleAdmin && data.Role != models.OrgRoleMember {
+		return nil, models.InvalidOrgRoleError
+	}
+	if !isValidEmail(data.Email) {
+		return nil, models.InvalidEmailError
+	}
+
+	member, err := uc.client.SetMember(ctx, &protobuf.SetMemberRequest{
+		ActorID: uint32(actorID),
+		OrgID:   uint32(orgID),
+		Email:   data.Email,
+		Role:    data.Role,
+	})
+	if err != nil {
+		return nil, convertOrgErr(err)
+	}
+
+	return convertMember(member), nil
+}
+
+func (uc *orgUsecases) RemoveMember(ctx context.Context, actorID, orgID, userID uint) error {
+	_, err := uc.client.RemoveMember(ctx, &protobuf.RemoveMemberRequest{
+		ActorID: uint32(actorID),
+		OrgID:   uint32(orgID),
+		UserID:  uint32(userID),
+	})
+	if err != nil {
+		return convertOrgErr(err)
+	}
+
+	return nil
+}
+
+// convertOrgErr maps status codes of the organization RPCs. Organizations the user is not a member of
+// are reported as missing, so their existence is not disclosed.
+func convertOrgErr(err error) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.NotFound:
+		if st.Message() == models.UserNotExists.Error() {
+			return models.UserNotExists
+		}
+		return models.OrgNotExists
+	case codes.PermissionDenied:
+		return models.PermissionDeniedError
+	case codes.FailedPrecondition:
+		return models.OrgOwnerImmutableError
+	case codes.InvalidArgument:
+		return models.InvalidOrgRoleError
+	}
+
+	return err
+}
+
+func convertOrganization(org *protobuf.Organization) *models.Organization {
+	return &models.Organization{
+		ID:   uint(org.ID),
+		Name: org.Name,
+		Role: org.Role,
+	}
+}
+
+func convertMember(member *protobuf.Member) *models.OrgMember {
+	return &models.OrgMember{
+		UserID: uint(member.UserID),
+		Email:  member.Email,
+		Role:   member.Role,
+	}
+}
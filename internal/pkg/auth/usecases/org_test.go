@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/auth/usecases/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOrgUsecases_CreateOrganization_InvalidName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	ou := NewOrgUsecases(mockAuthClient)
+
+	org, err := ou.CreateOrganization(context.Background(), 1, "")
+
+	assert.Nil(t, org)
+	assert.Equal(t, models.InvalidOrgNameError, err)
+}
+
+func TestOrgUsecases_SetMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	ou := NewOrgUsecases(mockAuthClient)
+
+	mockAuthClient.EXPECT().SetMember(gomock.Any(), &protobuf.SetMemberRequest{
+		ActorID: 1,
+		OrgID:   3,
+		Email:   "test@example.com",
+		Role:    models.OrgRoleMember,
+	}).Return(&protobuf.Member{UserID: 2, Email: "test@example.com", Role: models.OrgRoleMember}, nil)
+
+	member, err := ou.SetMember(context.Background(), 1, 3, &models.SetMemberData{
+		Email: "test@example.com",
+		Role:  models.OrgRoleMember,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.OrgMember{UserID: 2, Email: "test@example.com", Role: models.OrgRoleMember}, member)
+}
+
+func TestOrgUsecases_SetMember_InvalidRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	ou := NewOrgUsecases(mockAuthClient)
+
+	member, err := ou.SetMember(context.Background(), 1, 3, &models.SetMemberData{
+		Email: "test@example.com",
+		Role:  models.OrgRoleOwner,
+	})
+
+	assert.Nil(t, member)
+	assert.Equal(t, models.InvalidOrgRoleError, err)
+}
+
+func TestOrgUsecases_ListMembers_NotMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthClient := mocks.NewMockAuthClient(ctrl)
+	ou := NewOrgUsecases(mockAuthClient)
+
+	mockAuthClient.EXPECT().ListMembers(gomock.Any(), gomock.Any()).
+		Return(nil, status.Errorf(codes.NotFound, "%s", models.OrgNotExists.Error()))
+
+	members, err := ou.ListMembers(context.Background(), 2, 3)
+
+	assert.Nil(t, members)
+	assert.Equal(t, models.OrgNotExists, err)
+}
@@ -1,20 +1,90 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"gopkg.in/yaml.v3"
 )
 
+type RateLimitConfig struct {
+	Window      int  `yaml:"window"`
+	IPLimit     int  `yaml:"ip_limit"`
+	EmailLimit  int  `yaml:"email_limit"`
+	MaxFailures int  `yaml:"max_failures"`
+	LockoutBase int  `yaml:"lockout_base"`
+	LockoutMax  int  `yaml:"lockout_max"`
+	TrustProxy  bool `yaml:"trust_proxy"`
+}
+
+// DAVConfig.CredentialsTTL is how long a successful Basic auth check is reused, in seconds
+type DAVConfig struct {
+	Enabled        bool `yaml:"enabled" env:"DAV_ENABLED"`
+	CredentialsTTL int  `yaml:"credentials_ttl"`
+}
+
+// S3Config.Port is served by a separate listener, because S3 clients address buckets from the root path.
+// MultipartTTL is in seconds, MaxObjectSize is in bytes. Zero MaxObjectSize and MaxOpenUploads disable the limits.
+type S3Config struct {
+	Enabled        bool   `yaml:"enabled" env:"S3_ENABLED"`
+	Port           string `yaml:"port" env:"S3_PORT"`
+	Region         string `yaml:"region"`
+	MultipartTTL   int    `yaml:"multipart_ttl"`
+	MaxObjectSize  int64  `yaml:"max_object_size"`
+	MaxOpenUploads int    `yaml:"max_open_uploads"`
+}
+
+// OpenAPIConfig.Docs serves the document and its UI, Validate rejects requests that do not match the document
+type OpenAPIConfig struct {
+	Docs     bool `yaml:"docs" env:"OPENAPI_DOCS"`
+	Validate bool `yaml:"validate"`
+}
+
+type OIDCProviderConfig struct {
+	Name            string   `yaml:"name"`
+	Issuer          string   `yaml:"issuer"`
+	ClientID        string   `yaml:"client_id"`
+	ClientSecretEnv string   `yaml:"client_secret_env"`
+	RedirectURL     string   `yaml:"redirect_url"`
+	Scopes          []string `yaml:"scopes"`
+}
+
+type OIDCConfig struct {
+	Providers       []OIDCProviderConfig `yaml:"providers"`
+	StateTTL        int                  `yaml:"state_ttl"`
+	SuccessRedirect string               `yaml:"success_redirect"`
+}
+
+// TLSConfig configures mTLS of the gRPC hops. AllowedClients are common names of client certificates
+// accepted by a server, an empty list accepts any certificate signed by the CA.
+type TLSConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	CAFile         string   `yaml:"ca_file"`
+	CertFile       string   `yaml:"cert_file"`
+	KeyFile        string   `yaml:"key_file"`
+	AllowedClients []string `yaml:"allowed_clients"`
+}
+
+type ServiceAuthConfig struct {
+	TokenSecret string `env:"SERVICE_TOKEN_SECRET"`
+	TokenTTL    int    `yaml:"token_ttl"`
+}
+
 type ServerConfig struct {
-	Host    string   `yaml:"host"`
-	Port    string   `env:"APP_PORT"`
-	Timeout int      `yaml:"timeout"`
-	Origins []string `yaml:"origins"`
-	Headers []string `yaml:"headers"`
-	Methods []string `yaml:"methods"`
+	Host        string          `yaml:"host"`
+	Port        string          `env:"APP_PORT"`
+	MetricsPort string          `env:"APP_METRICS_PORT"`
+	Timeout     int             `yaml:"timeout"`
+	Origins     []string        `yaml:"origins"`
+	Headers     []string        `yaml:"headers"`
+	Methods     []string        `yaml:"methods"`
+	RateLimit   RateLimitConfig `yaml:"rate_limit"`
+	OIDC        OIDCConfig      `yaml:"oidc"`
+	TLS         TLSConfig       `yaml:"tls"`
+	DAV         DAVConfig       `yaml:"dav"`
+	S3          S3Config        `yaml:"s3"`
+	OpenAPI     OpenAPIConfig   `yaml:"openapi"`
 }
 
 type PostgresConfig struct {
@@ -47,24 +117,150 @@ type MinioConfig struct {
 	AccessKey string `env:"MINIO_ROOT_USER"`
 	SecretKey string `env:"MINIO_ROOT_PASSWORD"`
 	Bucket    string `env:"MINIO_BUCKET"`
+
+	PublicEndpoint string `env:"MINIO_PUBLIC_ENDPOINT"`
+	PublicSecure   bool   `env:"MINIO_PUBLIC_SECURE"`
+	// Region must match the server, presigned URLs are signed for it
+	Region string `env:"MINIO_REGION" env-default:"us-east-1"`
+}
+
+// StorageConfig.Backend is minio, fs or memory. Path is the root directory of the fs backend.
+type StorageConfig struct {
+	Backend string `yaml:"backend" env:"STORAGE_BACKEND"`
+	Path    string `yaml:"path" env:"STORAGE_PATH"`
+}
+
+// ReconcileConfig values are in seconds, zero interval disables the periodic check
+type ReconcileConfig struct {
+	Interval    int  `yaml:"interval"`
+	GracePeriod int  `yaml:"grace_period"`
+	Repair      bool `yaml:"repair"`
+}
+
+// MultipartConfig values are in seconds. Retention must be longer than S3Config.MultipartTTL of the gateway,
+// zero interval disables the removal of abandoned upload parts.
+type MultipartConfig struct {
+	CleanupInterval int `yaml:"cleanup_interval"`
+	Retention       int `yaml:"retention"`
+}
+
+// PresignConfig TTLs are in seconds. Uploads not finished in time are removed by the reconciler, so its grace
+// period must be longer than UploadURLTTL.
+type PresignConfig struct {
+	Enabled        bool `yaml:"enabled" env:"PRESIGN_ENABLED"`
+	UploadURLTTL   int  `yaml:"upload_url_ttl"`
+	DownloadURLTTL int  `yaml:"download_url_ttl"`
+}
+
+// EncryptionConfig keys are base64 encoded 32 byte keys, MasterKey takes precedence over KeyFile. Previous keys
+// only unwrap data keys until they are rewrapped with "file rotate-keys".
+type EncryptionConfig struct {
+	Enabled          bool     `yaml:"enabled" env:"ENCRYPTION_ENABLED"`
+	MasterKey        string   `env:"ENCRYPTION_MASTER_KEY"`
+	KeyFile          string   `yaml:"key_file" env:"ENCRYPTION_KEY_FILE"`
+	PreviousKeyFiles []string `yaml:"previous_key_files" env:"ENCRYPTION_PREVIOUS_KEY_FILES" env-separator:","`
+}
+
+// CompressionConfig.Algorithm is zstd or gzip. Content smaller than MinSize bytes is stored as is.
+type CompressionConfig struct {
+	Enabled   bool   `yaml:"enabled" env:"COMPRESSION_ENABLED"`
+	Algorithm string `yaml:"algorithm"`
+	MinSize   int    `yaml:"min_size"`
+}
+
+type MailerConfig struct {
+	Host     string `env:"SMTP_HOST"`
+	Port     string `env:"SMTP_PORT"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+	From     string `env:"SMTP_FROM"`
+}
+
+type VerificationConfig struct {
+	VerifyURL             string `yaml:"verify_url"`
+	TokenTTL              int    `yaml:"token_ttl"`
+	AllowUnverifiedLogin  bool   `yaml:"allow_unverified_login"`
+	AllowUnverifiedUpload bool   `yaml:"allow_unverified_upload"`
 }
 
 type AuthServiceConfig struct {
-	Postgres PostgresAuthConfig
-	Redis    RedisConfig
+	Postgres     PostgresAuthConfig
+	Redis        RedisConfig
+	Mailer       MailerConfig
+	Verification VerificationConfig `yaml:"verification"`
+	TLS          TLSConfig          `yaml:"tls"`
+
+	// Timeout is the default deadline of calls to the service, in seconds
+	Timeout int `yaml:"timeout"`
 
 	InternalHost string `yaml:"host"`
 	ExternalHost string `env:"AUTH_HOST"`
 	Port         string `env:"AUTH_PORT"`
+	MetricsPort  string `env:"AUTH_METRICS_PORT"`
+}
+
+type ExportConfig struct {
+	DownloadURLTTL int    `yaml:"download_url_ttl"`
+	Retention      int    `yaml:"retention"`
+	NotifyURL      string `yaml:"notify_url"`
 }
 
 type FileServiceConfig struct {
-	Postgres PostgresFileConfig
-	Minio    MinioConfig
+	Postgres    PostgresFileConfig
+	Minio       MinioConfig
+	Storage     StorageConfig     `yaml:"storage"`
+	Presign     PresignConfig     `yaml:"presign"`
+	Encryption  EncryptionConfig  `yaml:"encryption"`
+	Compression CompressionConfig `yaml:"compression"`
+	Mailer      MailerConfig
+	Export      ExportConfig    `yaml:"export"`
+	Reconcile   ReconcileConfig `yaml:"reconcile"`
+	Multipart   MultipartConfig `yaml:"multipart"`
+	TLS         TLSConfig       `yaml:"tls"`
+
+	DefaultQuota    int64 `yaml:"default_quota"`
+	DefaultOrgQuota int64 `yaml:"default_org_quota"`
+
+	// Timeout is the default deadline of calls to the service, in seconds
+	Timeout int `yaml:"timeout"`
 
 	InternalHost string `yaml:"host"`
 	ExternalHost string `env:"FILE_HOST"`
 	Port         string `env:"FILE_PORT"`
+	MetricsPort  string `env:"FILE_METRICS_PORT"`
+}
+
+// LogConfig.Level is one of debug, info, warn and error, LogConfig.Format is text or json
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// TracingConfig.SampleRatio applies to traces started in the service, the others follow the caller's decision
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled" env:"TRACING_ENABLED"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// HealthConfig.Interval is the period of dependency checks in the gRPC services, both values are in seconds
+type HealthConfig struct {
+	Interval int `yaml:"interval"`
+	Timeout  int `yaml:"timeout"`
+}
+
+// ShutdownConfig.ReadinessDelay is the time between failing readiness and closing listeners, so balancers
+// notice the service is going away. Both values are in seconds.
+type ShutdownConfig struct {
+	DrainTimeout   int `yaml:"drain_timeout"`
+	ReadinessDelay int `yaml:"readiness_delay"`
+}
+
+// MigrationsConfig.OnStart applies pending migrations when a service starts, otherwise the migrate
+// subcommand has to be run before deploys
+type MigrationsConfig struct {
+	OnStart bool `yaml:"on_start" env:"MIGRATE_ON_START"`
 }
 
 type CtxKeys struct {
@@ -76,6 +272,13 @@ type Config struct {
 	Auth   AuthServiceConfig `yaml:"auth_service"`
 	File   FileServiceConfig `yaml:"file_service"`
 
+	ServiceAuth ServiceAuthConfig `yaml:"service_auth"`
+	Log         LogConfig         `yaml:"log"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Health      HealthConfig      `yaml:"health"`
+	Shutdown    ShutdownConfig    `yaml:"shutdown"`
+	Migrations  MigrationsConfig  `yaml:"migrations"`
+
 	Keys CtxKeys `yaml:"ctx_keys"`
 }
 
@@ -84,7 +287,7 @@ func ReadConfig(cfgPath string) *Config {
 
 	file, err := os.Open(cfgPath)
 	if err != nil {
-		log.Println("Something went wrong while opening config file ", err)
+		slog.Error("Something went wrong while opening config file", "error", err)
 
 		return nil
 	}
@@ -92,18 +295,18 @@ func ReadConfig(cfgPath string) *Config {
 
 	decoder := yaml.NewDecoder(file)
 	if err := decoder.Decode(cfg); err != nil {
-		log.Println("Something went wrong while reading config from yaml file ", err)
+		slog.Error("Something went wrong while reading config from yaml file", "error", err)
 
 		return nil
 	}
 
 	if err := cleanenv.ReadEnv(cfg); err != nil {
-		log.Println("Something went wrong while reading config from env file ", err)
+		slog.Error("Something went wrong while reading config from env file", "error", err)
 
 		return nil
 	}
 
-	log.Println("Successfully opened config")
+	slog.Info("Successfully opened config")
 
 	return cfg
 }
@@ -0,0 +1,223 @@
+// Package compression compresses the content of files before it is stored. Only content types that are known
+// to compress well are compressed, so images, archives and media are stored as is.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	EncodingIdentity = ""
+	EncodingGzip     = "gzip"
+	EncodingZstd     = "zstd"
+)
+
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/x-ndjson":     true,
+	"application/xml":          true,
+	"application/javascript":   true,
+	"application/x-javascript": true,
+	"application/x-yaml":       true,
+	"application/yaml":         true,
+	"application/sql":          true,
+	"application/x-sh":         true,
+	"application/rtf":          true,
+	"application/x-tex":        true,
+	"application/wasm":         true,
+	"image/svg+xml":            true,
+	"image/bmp":                true,
+	"image/x-ms-bmp":           true,
+	"image/tiff":               true,
+}
+
+// Compressible reports whether the content type is worth compressing. Text types are compressible, except for
+// the ones with a structured suffix like +zip.
+func Compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+
+	return strings.HasPrefix(mediaType, "text/") || compressibleTypes[mediaType]
+}
+
+// Compressor keeps content smaller than MinSize and content that does not shrink uncompressed
+type Compressor struct {
+	encoding string
+	minSize  int
+	encoder  *zstd.Encoder
+}
+
+// New returns nil if compression is disabled
+func New(cfg config.CompressionConfig) (*Compressor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	c := &Compressor{encoding: cfg.Algorithm, minSize: cfg.MinSize}
+
+	switch cfg.Algorithm {
+	case EncodingZstd:
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		c.encoder = encoder
+	case EncodingGzip:
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %q", cfg.Algorithm)
+	}
+
+	return c, nil
+}
+
+// Compress returns the content to store and its encoding. A nil compressor stores everything as is.
+func (c *Compressor) Compress(contentType string, data []byte) ([]byte, string, error) {
+	if c == nil || len(data) < c.minSize || !Compressible(contentType) {
+		return data, EncodingIdentity, nil
+	}
+
+	var compressed []byte
+	switch c.encoding {
+	case EncodingZstd:
+		compressed = c.encoder.EncodeAll(data, make([]byte, 0, len(data)/2))
+	case EncodingGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, "", err
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		compressed = buf.Bytes()
+	}
+
+	if len(compressed) >= len(data) {
+		return data, EncodingIdentity, nil
+	}
+
+	return compressed, c.encoding, nil
+}
+
+// NewReader decompresses the content stored with the encoding as it is read. Compressed content cannot be read
+// by ranges, so readers of a range skip the content before it.
+func NewReader(encoding string, r io.Reader) (io.ReadCloser, error) {
+	source := &sourceReader{r: r}
+
+	switch encoding {
+	case EncodingIdentity:
+		return io.NopCloser(r), nil
+	case EncodingZstd:
+		decoder, err := zstd.NewReader(source, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		return &decodingReader{decoder: decoder, source: source, close: decoder.Close}, nil
+	case EncodingGzip:
+		decoder, err := gzip.NewReader(source)
+		if err != nil {
+			return nil, source.wrap(err)
+		}
+
+		return &decodingReader{decoder: decoder, source: source, close: func() { _ = decoder.Close() }}, nil
+	default:
+		return nil, fmt.Errorf("unknown content encoding %q", encoding)
+	}
+}
+
+// sourceReader remembers the error of the stored object, so it is not reported as a corrupted one
+type sourceReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *sourceReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = err
+	}
+
+	return n, err
+}
+
+func (r *sourceReader) wrap(err error) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	return fmt.Errorf("%w: %w", models.CorruptedObjectError, err)
+}
+
+type decodingReader struct {
+	decoder io.Reader
+	source  *sourceReader
+	close   func()
+}
+
+func (r *decodingReader) Read(p []byte) (int, error) {
+	n, err := r.decoder.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = r.source.wrap(err)
+	}
+
+	return n, err
+}
+
+func (r *decodingReader) Close() error {
+	r.close()
+	return nil
+}
+
+// Decompress restores the content stored with the encoding, it does not need the compressor, so files stay
+// readable after compression is disabled or the algorithm is changed
+func Decompress(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case EncodingIdentity:
+		return data, nil
+	case EncodingZstd:
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+
+		content, err := decoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.CorruptedObjectError, err)
+		}
+
+		return content, nil
+	case EncodingGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.CorruptedObjectError, err)
+		}
+		defer r.Close()
+
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.CorruptedObjectError, err)
+		}
+
+		return content, nil
+	default:
+		return nil, fmt.Errorf("unknown content encoding %q", encoding)
+	}
+}
@@ -0,0 +1,165 @@
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressible(t *testing.T) {
+	tests := []struct {
+		contentType  string
+		compressible bool
+	}{
+		{"text/plain", true},
+		{"text/csv; charset=utf-8", true},
+		{"application/json", true},
+		{"application/vnd.api+json", true},
+		{"image/svg+xml", true},
+		{"image/jpeg", false},
+		{"image/png", false},
+		{"application/zip", false},
+		{"application/gzip", false},
+		{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", false},
+		{"video/mp4", false},
+		{"application/octet-stream", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.compressible, Compressible(tt.contentType), tt.contentType)
+	}
+}
+
+func TestCompressor(t *testing.T) {
+	data := bytes.Repeat([]byte("2025-01-01T00:00:00Z INFO request served path=/api/files status=200\n"), 1000)
+
+	for _, algorithm := range []string{EncodingZstd, EncodingGzip} {
+		t.Run(algorithm, func(t *testing.T) {
+			compressor, err := New(config.CompressionConfig{Enabled: true, Algorithm: algorithm, MinSize: 1024})
+			require.NoError(t, err)
+
+			compressed, encoding, err := compressor.Compress("text/plain", data)
+			require.NoError(t, err)
+			assert.Equal(t, algorithm, encoding)
+			assert.Less(t, len(compressed), len(data)/10)
+
+			decompressed, err := Decompress(encoding, compressed)
+			require.NoError(t, err)
+			assert.Equal(t, data, decompressed)
+		})
+	}
+}
+
+func TestCompressor_StoredAsIs(t *testing.T) {
+	compressor, err := New(config.CompressionConfig{Enabled: true, Algorithm: EncodingZstd, MinSize: 1024})
+	require.NoError(t, err)
+
+	random := make([]byte, 4096)
+	_, err = rand.Read(random)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		compressor  *Compressor
+		contentType string
+		data        []byte
+	}{
+		{"Disabled", nil, "text/plain", bytes.Repeat([]byte("a"), 4096)},
+		{"Small", compressor, "text/plain", []byte("short text")},
+		{"CompressedType", compressor, "image/png", bytes.Repeat([]byte("a"), 4096)},
+		{"Incompressible", compressor, "text/plain", random},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, encoding, err := tt.compressor.Compress(tt.contentType, tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, EncodingIdentity, encoding)
+			assert.Equal(t, tt.data, stored)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	compressor, err := New(config.CompressionConfig{Algorithm: EncodingZstd})
+	require.NoError(t, err)
+	assert.Nil(t, compressor)
+
+	_, err = New(config.CompressionConfig{Enabled: true, Algorithm: "brotli"})
+	assert.Error(t, err)
+}
+
+func TestDecompress_Corrupted(t *testing.T) {
+	for _, encoding := range []string{EncodingZstd, EncodingGzip} {
+		_, err := Decompress(encoding, []byte("not compressed"))
+		assert.ErrorIs(t, err, models.CorruptedObjectError, encoding)
+	}
+
+	_, err := Decompress("brotli", []byte("data"))
+	assert.Error(t, err)
+}
+
+func TestNewReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	stored := []struct {
+		encoding string
+		object   []byte
+	}{
+		{EncodingIdentity, data},
+	}
+	for _, algorithm := range []string{EncodingZstd, EncodingGzip} {
+		compressor, err := New(config.CompressionConfig{Enabled: true, Algorithm: algorithm, MinSize: 1024})
+		require.NoError(t, err)
+		compressed, encoding, err := compressor.Compress("text/plain", data)
+		require.NoError(t, err)
+
+		stored = append(stored, struct {
+			encoding string
+			object   []byte
+		}{encoding, compressed})
+	}
+
+	for _, tt := range stored {
+		reader, err := NewReader(tt.encoding, bytes.NewReader(tt.object))
+		require.NoError(t, err)
+
+		// A range is read by skipping the content before it
+		_, err = io.CopyN(io.Discard, reader, 5005)
+		require.NoError(t, err)
+		rest, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, data[5005:], rest, tt.encoding)
+		assert.NoError(t, reader.Close())
+	}
+}
+
+func TestNewReader_Errors(t *testing.T) {
+	for _, encoding := range []string{EncodingZstd, EncodingGzip} {
+		reader, err := NewReader(encoding, strings.NewReader("not compressed"))
+		if err == nil {
+			_, err = io.ReadAll(reader)
+		}
+		assert.ErrorIs(t, err, models.CorruptedObjectError, encoding)
+
+		// Errors of the stored object are not reported as corrupted content
+		reader, err = NewReader(encoding, iotest.ErrReader(assert.AnError))
+		if err == nil {
+			_, err = io.ReadAll(reader)
+		}
+		assert.ErrorIs(t, err, assert.AnError, encoding)
+		assert.NotErrorIs(t, err, models.CorruptedObjectError, encoding)
+	}
+
+	_, err := NewReader("brotli", strings.NewReader("data"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,342 @@
+package dav
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+
+	"golang.org/x/net/webdav"
+)
+
+const (
+	// Files have no folders, so folders are emulated with slashes in filenames. Empty folders are kept
+	// as empty marker files named after the folder with a trailing slash.
+	folderContentType = "httpd/unix-directory"
+	listPageSize      = 1000
+)
+
+// fileSystem exposes the personal space of a user. It is created per request and keeps the list of
+// files loaded by the first call until the request changes something.
+type fileSystem struct {
+	usecases fileinterfaces.FileUsecases
+	userID   uint
+
+	files map[string]*models.FileMetadata
+	// folders contains markers of created folders and nil for folders implied by filenames
+	folders map[string]*models.FileMetadata
+}
+
+func newFileSystem(usecases fileinterfaces.FileUsecases, userID uint) *fileSystem {
+	return &fileSystem{
+		usecases: usecases,
+		userID:   userID,
+	}
+}
+
+func (fs *fileSystem) Mkdir(ctx context.Context, name string, _ os.FileMode) error {
+	name, err := fs.resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if fs.exists(name) {
+		return os.ErrExist
+	}
+	if !fs.isFolder(path.Dir(name)) {
+		return os.ErrNotExist
+	}
+
+	_, err = fs.usecases.UploadFile(ctx, fs.userID, &models.GeneralFileData{
+		Filename:    name + "/",
+		ContentType: folderContentType,
+		File:        []byte{},
+	})
+	fs.invalidate()
+
+	return convertError(err)
+}
+
+func (fs *fileSystem) OpenFile(ctx context.Context, name string, flag int, _ os.FileMode) (webdav.File, error) {
+	name, err := fs.resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs.isFolder(name) {
+		if flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			return nil, os.ErrPermission
+		}
+
+		return &folderFile{fs: fs, name: name}, nil
+	}
+
+	meta := fs.files[name]
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		if meta == nil {
+			return nil, os.ErrNotExist
+		}
+
+		return &readFile{ctx: ctx, fs: fs, name: name, meta: meta}, nil
+	}
+
+	if meta == nil && flag&os.O_CREATE == 0 {
+		return nil, os.ErrNotExist
+	}
+	if meta != nil && flag&os.O_EXCL != 0 {
+		return nil, os.ErrExist
+	}
+	if !fs.isFolder(path.Dir(name)) {
+		return nil, os.ErrNotExist
+	}
+
+	return &writeFile{ctx: ctx, fs: fs, name: name, meta: meta}, nil
+}
+
+func (fs *fileSystem) RemoveAll(ctx context.Context, name string) error {
+	name, err := fs.resolve(ctx, name)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return os.ErrPermission
+	}
+
+	if meta, ok := fs.files[name]; ok {
+		err = fs.usecases.DeleteFile(ctx, fs.userID, meta.UUID)
+		fs.invalidate()
+
+		return convertError(err)
+	}
+	if !fs.isFolder(name) {
+		return os.ErrNotExist
+	}
+
+	defer fs.invalidate()
+
+	for _, meta := range fs.folderContent(name) {
+		if err := fs.usecases.DeleteFile(ctx, fs.userID, meta.UUID); err != nil {
+			return convertError(err)
+		}
+	}
+
+	return nil
+}
+
+func (fs *fileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	oldName, err := fs.resolve(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	newName = strings.TrimPrefix(path.Clean("/"+newName), "/")
+	if oldName == "" || newName == "" || strings.HasPrefix(newName+"/", oldName+"/") {
+		return os.ErrPermission
+	}
+	if fs.exists(newName) {
+		return os.ErrExist
+	}
+	if !fs.isFolder(path.Dir(newName)) {
+		return os.ErrNotExist
+	}
+
+	if meta, ok := fs.files[oldName]; ok {
+		err = fs.usecases.UpdateFilename(ctx, fs.userID, meta.UUID, newName)
+		fs.invalidate()
+
+		return convertError(err)
+	}
+	if !fs.isFolder(oldName) {
+		return os.ErrNotExist
+	}
+
+	defer fs.invalidate()
+
+	for filename, meta := range fs.folderContent(oldName) {
+		filename = newName + strings.TrimPrefix(filename, oldName)
+		if err := fs.usecases.UpdateFilename(ctx, fs.userID, meta.UUID, filename); err != nil {
+			return convertError(err)
+		}
+	}
+
+	return nil
+}
+
+func (fs *fileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	name, err := fs.resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if meta, ok := fs.files[name]; ok {
+		return &fileInfo{meta: meta, name: path.Base(name)}, nil
+	}
+	if fs.isFolder(name) {
+		return fs.folderInfo(name), nil
+	}
+
+	return nil, os.ErrNotExist
+}
+
+// resolve loads the list of files if needed and converts the WebDAV name to a filename
+func (fs *fileSystem) resolve(ctx context.Context, name string) (string, error) {
+	if fs.files == nil {
+		if err := fs.load(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimPrefix(path.Clean("/"+name), "/"), nil
+}
+
+func (fs *fileSystem) load(ctx context.Context) error {
+	files := make(map[string]*models.FileMetadata)
+	folders := make(map[string]*models.FileMetadata)
+
+	for offset := uint(0); ; offset += listPageSize {
+		list, err := fs.usecases.GetFilesList(ctx, fs.userID, models.FilesListOptions{
+			Limit:  listPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return convertError(err)
+		}
+
+		for _, meta := range list {
+			name := strings.TrimPrefix(path.Clean("/"+meta.Filename), "/")
+			if name == "" {
+				continue
+			}
+
+			if strings.HasSuffix(meta.Filename, "/") {
+				folders[name] = meta
+			} else if _, ok := files[name]; !ok {
+				// Files with the same name cannot be told apart by path, the oldest one is shown
+				files[name] = meta
+			}
+
+			for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+				if _, ok := folders[dir]; !ok {
+					folders[dir] = nil
+				}
+			}
+		}
+
+		if len(list) < listPageSize {
+			break
+		}
+	}
+
+	fs.files = files
+	fs.folders = folders
+
+	return nil
+}
+
+func (fs *fileSystem) invalidate() {
+	fs.files = nil
+	fs.folders = nil
+}
+
+func (fs *fileSystem) exists(name string) bool {
+	_, ok := fs.files[name]
+
+	return ok || fs.isFolder(name)
+}
+
+func (fs *fileSystem) isFolder(name string) bool {
+	if name == "" || name == "." {
+		return true
+	}
+	_, ok := fs.folders[name]
+
+	return ok
+}
+
+func (fs *fileSystem) folderInfo(name string) *fileInfo {
+	info := &fileInfo{name: path.Base(name), isDir: true}
+	if name == "" {
+		info.name = "/"
+	}
+	if marker := fs.folders[name]; marker != nil {
+		info.meta = marker
+	}
+
+	return info
+}
+
+// folderContent returns all files in the folder and its subfolders by filename, markers included
+func (fs *fileSystem) folderContent(name string) map[string]*models.FileMetadata {
+	prefix := name + "/"
+
+	content := make(map[string]*models.FileMetadata)
+	for filename, meta := range fs.files {
+		if strings.HasPrefix(filename, prefix) {
+			content[filename] = meta
+		}
+	}
+	for folder, meta := range fs.folders {
+		if meta != nil && (folder == name || strings.HasPrefix(folder, prefix)) {
+			content[folder+"/"] = meta
+		}
+	}
+
+	return content
+}
+
+// children returns entries of the folder sorted by name
+func (fs *fileSystem) children(name string) []os.FileInfo {
+	prefix := name + "/"
+	if name == "" {
+		prefix = ""
+	}
+
+	var children []os.FileInfo
+	for filename, meta := range fs.files {
+		if isChild(filename, prefix) {
+			children = append(children, &fileInfo{meta: meta, name: path.Base(filename)})
+		}
+	}
+	for folder := range fs.folders {
+		if isChild(folder, prefix) {
+			children = append(children, fs.folderInfo(folder))
+		}
+	}
+
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name() < children[j].Name()
+	})
+
+	return children
+}
+
+func isChild(name, prefix string) bool {
+	return strings.HasPrefix(name, prefix) && !strings.Contains(name[len(prefix):], "/")
+}
+
+func detectContentType(name string, data []byte) string {
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType
+	}
+
+	return http.DetectContentType(data)
+}
+
+func convertError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, models.FileNotExists):
+		return os.ErrNotExist
+	case errors.Is(err, models.PermissionDeniedError):
+		return os.ErrPermission
+	}
+
+	return err
+}
@@ -0,0 +1,234 @@
+package dav
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+)
+
+// fileInfo describes a file or a folder, implicit folders have no metadata
+type fileInfo struct {
+	meta  *models.FileMetadata
+	name  string
+	isDir bool
+}
+
+func (fi *fileInfo) Name() string {
+	return fi.name
+}
+
+func (fi *fileInfo) IsDir() bool {
+	return fi.isDir
+}
+
+func (fi *fileInfo) Sys() any {
+	return nil
+}
+
+func (fi *fileInfo) Size() int64 {
+	if fi.isDir || fi.meta == nil {
+		return 0
+	}
+
+	return fi.meta.Size
+}
+
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.isDir {
+		return os.ModeDir | 0o755
+	}
+
+	return 0o644
+}
+
+func (fi *fileInfo) ModTime() time.Time {
+	if fi.meta == nil {
+		return time.Time{}
+	}
+	if fi.meta.UpdateTime.IsZero() {
+		return fi.meta.UploadTime
+	}
+
+	return fi.meta.UpdateTime
+}
+
+// ContentType implements webdav.ContentTyper, otherwise the content is downloaded to sniff the type
+func (fi *fileInfo) ContentType(_ context.Context) (string, error) {
+	if fi.isDir || fi.meta == nil {
+		return folderContentType, nil
+	}
+
+	return fi.meta.ContentType, nil
+}
+
+type folderFile struct {
+	fs   *fileSystem
+	name string
+
+	children []os.FileInfo
+	read     bool
+}
+
+func (f *folderFile) Close() error {
+	return nil
+}
+
+func (f *folderFile) Read(_ []byte) (int, error) {
+	return 0, os.ErrInvalid
+}
+
+func (f *folderFile) Write(_ []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *folderFile) Seek(_ int64, _ int) (int64, error) {
+	return 0, os.ErrInvalid
+}
+
+func (f *folderFile) Stat() (os.FileInfo, error) {
+	return f.fs.folderInfo(f.name), nil
+}
+
+func (f *folderFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.read {
+		f.children = f.fs.children(f.name)
+		f.read = true
+	}
+
+	if count <= 0 {
+		children := f.children
+		f.children = nil
+
+		return children, nil
+	}
+	if len(f.children) == 0 {
+		return nil, io.EOF
+	}
+
+	count = min(count, len(f.children))
+	children := f.children[:count]
+	f.children = f.children[count:]
+
+	return children, nil
+}
+
+// readFile opens the content on the first read, so that HEAD and PROPFIND requests do not fetch it. Only
+// the parts that are read are fetched, so range requests do not download the whole file.
+type readFile struct {
+	ctx  context.Context
+	fs   *fileSystem
+	name string
+	meta *models.FileMetadata
+
+	content io.ReadSeeker
+	offset  int64
+}
+
+func (f *readFile) Close() error {
+	return nil
+}
+
+func (f *readFile) Write(_ []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *readFile) Readdir(_ int) ([]os.FileInfo, error) {
+	return nil, os.ErrInvalid
+}
+
+func (f *readFile) Stat() (os.FileInfo, error) {
+	return &fileInfo{meta: f.meta, name: path.Base(f.name)}, nil
+}
+
+func (f *readFile) Read(p []byte) (int, error) {
+	if f.content == nil {
+		_, content, err := f.fs.usecases.OpenFile(f.ctx, f.fs.userID, f.meta.UUID)
+		if err != nil {
+			return 0, convertError(err)
+		}
+		f.content = content
+	}
+
+	if _, err := f.content.Seek(f.offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := f.content.Read(p)
+	f.offset += int64(n)
+
+	return n, err
+}
+
+func (f *readFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.meta.Size
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+
+	f.offset = offset
+
+	return offset, nil
+}
+
+// writeFile buffers the content and uploads it on close, the same way the REST API uploads files
+type writeFile struct {
+	ctx  context.Context
+	fs   *fileSystem
+	name string
+	meta *models.FileMetadata
+
+	buf bytes.Buffer
+}
+
+func (f *writeFile) Read(_ []byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+func (f *writeFile) Seek(_ int64, _ int) (int64, error) {
+	return 0, os.ErrInvalid
+}
+
+func (f *writeFile) Readdir(_ int) ([]os.FileInfo, error) {
+	return nil, os.ErrInvalid
+}
+
+func (f *writeFile) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+func (f *writeFile) Stat() (os.FileInfo, error) {
+	meta := &models.FileMetadata{
+		Filename:   f.name,
+		Size:       int64(f.buf.Len()),
+		UpdateTime: time.Now(),
+	}
+
+	return &fileInfo{meta: meta, name: path.Base(f.name)}, nil
+}
+
+func (f *writeFile) Close() error {
+	defer f.fs.invalidate()
+
+	data := f.buf.Bytes()
+	if f.meta != nil {
+		return convertError(f.fs.usecases.UpdateFile(f.ctx, f.fs.userID, f.meta.UUID, data, int64(len(data))))
+	}
+
+	_, err := f.fs.usecases.UploadFile(f.ctx, f.fs.userID, &models.GeneralFileData{
+		Filename:    f.name,
+		ContentType: detectContentType(f.name, data),
+		File:        data,
+		Size:        int64(len(data)),
+	})
+
+	return convertError(err)
+}
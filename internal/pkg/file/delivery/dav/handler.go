@@ -0,0 +1,95 @@
+package dav
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+
+	"golang.org/x/net/webdav"
+)
+
+// lockIdleTimeout is how long the locks of a user are kept after the last request of the user. Clients
+// refresh their locks well within it, so only locks of clients that are gone are dropped.
+const lockIdleTimeout = time.Hour
+
+// Handler serves the personal space of the user from the context over WebDAV. Locks are kept in memory,
+// so they are not shared between gateway instances.
+type Handler struct {
+	usecases   fileinterfaces.FileUsecases
+	prefix     string
+	ctxUserKey string
+
+	mu        sync.Mutex
+	locks     map[uint]*userLocks
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type userLocks struct {
+	locks    webdav.LockSystem
+	lastUsed time.Time
+}
+
+func NewHandler(usecases fileinterfaces.FileUsecases, prefix, ctxUserKey string) *Handler {
+	return &Handler{
+		usecases:   usecases,
+		prefix:     prefix,
+		ctxUserKey: ctxUserKey,
+		locks:      make(map[uint]*userLocks),
+		now:        time.Now,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value(h.ctxUserKey).(*models.User)
+	if !ok || user == nil {
+		responses.SendErrResponse(w, responses.StatusUnauthorized, responses.ErrNotAuthorized)
+
+		return
+	}
+
+	handler := &webdav.Handler{
+		Prefix:     h.prefix,
+		FileSystem: newFileSystem(h.usecases, user.ID),
+		LockSystem: h.lockSystem(user.ID),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				slog.WarnContext(r.Context(), "WebDAV request failed", "method", r.Method, "path", r.URL.Path,
+					"error", err)
+			}
+		},
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// lockSystem returns the locks of the user. Every user has own locks, because all users see the same paths
+// and lock tokens are sequential. Locks of idle users are dropped, at most once a while not to walk all users
+// on every request.
+func (h *Handler) lockSystem(userID uint) webdav.LockSystem {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	if now.Sub(h.lastSweep) >= lockIdleTimeout/4 {
+		for id, locks := range h.locks {
+			if now.Sub(locks.lastUsed) >= lockIdleTimeout {
+				delete(h.locks, id)
+			}
+		}
+		h.lastSweep = now
+	}
+
+	locks, ok := h.locks[userID]
+	if !ok {
+		locks = &userLocks{locks: webdav.NewMemLS()}
+		h.locks[userID] = locks
+	}
+	locks.lastUsed = now
+
+	return locks.locks
+}
@@ -0,0 +1,186 @@
+package dav
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+const ctxUserKey = "user"
+
+type fakeFileUsecases struct {
+	fileinterfaces.FileUsecases
+	files map[string]*models.GeneralFileData
+	next  int
+}
+
+func newFakeFileUsecases() *fakeFileUsecases {
+	return &fakeFileUsecases{files: make(map[string]*models.GeneralFileData)}
+}
+
+func (uc *fakeFileUsecases) UploadFile(_ context.Context, _ uint,
+	data *models.GeneralFileData) (*models.FileMetadata, error) {
+	uc.next++
+	id := fmt.Sprintf("%08d-0000-0000-0000-000000000000", uc.next)
+	uc.files[id] = data
+
+	return &models.FileMetadata{UUID: id, Filename: data.Filename}, nil
+}
+
+func (uc *fakeFileUsecases) GetFilesList(_ context.Context, _ uint,
+	options models.FilesListOptions) ([]*models.FileMetadata, error) {
+	ids := make([]string, 0, len(uc.files))
+	for id := range uc.files {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var list []*models.FileMetadata
+	for _, id := range ids[min(int(options.Offset), len(ids)):] {
+		file := uc.files[id]
+		list = append(list, &models.FileMetadata{
+			UUID:        id,
+			Filename:    file.Filename,
+			ContentType: file.ContentType,
+			Size:        int64(len(file.File)),
+		})
+	}
+
+	return list, nil
+}
+
+func (uc *fakeFileUsecases) OpenFile(_ context.Context, _ uint,
+	id string) (*models.GeneralFileData, io.ReadSeeker, error) {
+	file, ok := uc.files[id]
+	if !ok {
+		return nil, nil, models.FileNotExists
+	}
+
+	return file, bytes.NewReader(file.File), nil
+}
+
+func (uc *fakeFileUsecases) UpdateFile(_ context.Context, _ uint, id string, file []byte, _ int64) error {
+	uc.files[id].File = file
+
+	return nil
+}
+
+func (uc *fakeFileUsecases) UpdateFilename(_ context.Context, _ uint, id string, filename string) error {
+	uc.files[id].Filename = filename
+
+	return nil
+}
+
+func (uc *fakeFileUsecases) DeleteFile(_ context.Context, _ uint, id string) error {
+	delete(uc.files, id)
+
+	return nil
+}
+
+func (uc *fakeFileUsecases) filenames() []string {
+	var names []string
+	for _, file := range uc.files {
+		names = append(names, file.Filename)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func serve(h http.Handler, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	r = r.WithContext(context.WithValue(r.Context(), ctxUserKey, &models.User{ID: 1}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandler_FoldersAreEmulatedWithFilenames(t *testing.T) {
+	uc := newFakeFileUsecases()
+	h := NewHandler(uc, "/dav", ctxUserKey)
+
+	assert.Equal(t, http.StatusCreated, serve(h, "MKCOL", "/dav/docs", "", nil).Code)
+	assert.Equal(t, http.StatusCreated, serve(h, "PUT", "/dav/docs/a.txt", "hello", nil).Code)
+	assert.Equal(t, http.StatusConflict, serve(h, "PUT", "/dav/missing/a.txt", "hello", nil).Code)
+	assert.Equal(t, []string{"docs/", "docs/a.txt"}, uc.filenames())
+
+	w := serve(h, "PROPFIND", "/dav/docs/", "", map[string]string{"Depth": "1"})
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.Contains(t, w.Body.String(), "/dav/docs/a.txt")
+
+	w = serve(h, "GET", "/dav/docs/a.txt", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	body, _ := io.ReadAll(w.Body)
+	assert.Equal(t, "hello", string(body))
+}
+
+func TestHandler_MoveCopyDelete(t *testing.T) {
+	uc := newFakeFileUsecases()
+	h := NewHandler(uc, "/dav", ctxUserKey)
+
+	serve(h, "MKCOL", "/dav/docs", "", nil)
+	serve(h, "PUT", "/dav/docs/a.txt", "hello", nil)
+
+	w := serve(h, "MOVE", "/dav/docs", "", map[string]string{"Destination": "http://example.com/dav/notes"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []string{"notes/", "notes/a.txt"}, uc.filenames())
+
+	w = serve(h, "COPY", "/dav/notes/a.txt", "", map[string]string{"Destination": "http://example.com/dav/b.txt"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []string{"b.txt", "notes/", "notes/a.txt"}, uc.filenames())
+
+	assert.Equal(t, http.StatusNoContent, serve(h, "DELETE", "/dav/notes", "", nil).Code)
+	assert.Equal(t, []string{"b.txt"}, uc.filenames())
+}
+
+func TestHandler_LocksAreSeparatedByUser(t *testing.T) {
+	uc := newFakeFileUsecases()
+	h := NewHandler(uc, "/dav", ctxUserKey)
+
+	lockBody := `<?xml version="1.0"?><D:lockinfo xmlns:D="DAV:"><D:lockscope><D:exclusive/></D:lockscope>` +
+		`<D:locktype><D:write/></D:locktype></D:lockinfo>`
+	w := serve(h, "LOCK", "/dav/a.txt", lockBody, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	token := w.Header().Get("Lock-Token")
+	assert.NotEmpty(t, token)
+	assert.Equal(t, http.StatusLocked, serve(h, "PUT", "/dav/a.txt", "hello", nil).Code)
+
+	assert.Equal(t, http.StatusNoContent, serve(h, "UNLOCK", "/dav/a.txt", "",
+		map[string]string{"Lock-Token": token}).Code)
+	assert.Equal(t, http.StatusCreated, serve(h, "PUT", "/dav/a.txt", "hello", nil).Code)
+	assert.NotSame(t, h.lockSystem(1), h.lockSystem(2))
+}
+
+func TestHandler_IdleLocksAreEvicted(t *testing.T) {
+	h := NewHandler(newFakeFileUsecases(), "/dav", ctxUserKey)
+	now := time.Now()
+	h.now = func() time.Time { return now }
+
+	idle := h.lockSystem(1)
+	active := h.lockSystem(2)
+
+	now = now.Add(lockIdleTimeout / 2)
+	assert.Same(t, active, h.lockSystem(2))
+
+	now = now.Add(lockIdleTimeout / 2)
+	assert.Same(t, active, h.lockSystem(2))
+	assert.Len(t, h.locks, 1)
+	assert.NotSame(t, idle, h.lockSystem(1))
+}
@@ -1,55 +1,243 @@
 package grpc
 
 import (
+	"bufio"
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/compression"
 	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/encryption"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/jobs"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const (
+	// fileReadChunkSize is the size of messages of ReadFile, it stays well below the gRPC message limit
+	fileReadChunkSize = 1 << 20
+	// objectReadBlockSize is how much of the object is read at once, it is a multiple of the encryption chunk
+	objectReadBlockSize = 16 * encryption.ChunkSize
+)
+
 type FileManager struct {
 	protobuf.UnimplementedFileServer
 
 	metadataStorage fileinterfaces.MetadataStorage
 	objectStorage   fileinterfaces.ObjectStorage
+	purgeJobStorage fileinterfaces.PurgeJobStorage
+	purgeScheduler  fileinterfaces.JobScheduler
+
+	exportJobStorage fileinterfaces.ExportJobStorage
+	exportScheduler  fileinterfaces.JobScheduler
+
+	membershipChecker fileinterfaces.MembershipChecker
+
+	options FileManagerOptions
+}
+
+// FileManagerOptions holds the limits of the file service. Zero quota means that the storage is unlimited.
+// DownloadURLTTL is used for exports, the presign options for direct uploads and downloads of files.
+// Content is compressed if Compressor is set and new files are encrypted if Keyring is set.
+type FileManagerOptions struct {
+	DownloadURLTTL  time.Duration
+	DefaultQuota    int64
+	DefaultOrgQuota int64
+
+	PresignEnabled     bool
+	PresignUploadTTL   time.Duration
+	PresignDownloadTTL time.Duration
+
+	Compressor *compression.Compressor
+	Keyring    *encryption.Keyring
 }
 
 func NewFileManager(
 	metadataStorage fileinterfaces.MetadataStorage,
 	objectStorage fileinterfaces.ObjectStorage,
+	purgeJobStorage fileinterfaces.PurgeJobStorage,
+	purgeScheduler fileinterfaces.JobScheduler,
+	exportJobStorage fileinterfaces.ExportJobStorage,
+	exportScheduler fileinterfaces.JobScheduler,
+	membershipChecker fileinterfaces.MembershipChecker,
+	options FileManagerOptions,
 ) *FileManager {
 	return &FileManager{
-		metadataStorage: metadataStorage,
-		objectStorage:   objectStorage,
+		metadataStorage:   metadataStorage,
+		objectStorage:     objectStorage,
+		purgeJobStorage:   purgeJobStorage,
+		purgeScheduler:    purgeScheduler,
+		exportJobStorage:  exportJobStorage,
+		exportScheduler:   exportScheduler,
+		membershipChecker: membershipChecker,
+		options:           options,
 	}
 }
 
 func (m *FileManager) UploadFile(ctx context.Context, r *protobuf.UploadFileRequest) (*protobuf.FileMetadata, error) {
-	metadata, err := m.metadataStorage.UploadMetadata(ctx, uint(r.OwnerID), r.Filename, r.ContentType, r.Size)
+	if r.OrgID != 0 {
+		if err := m.checkMembership(ctx, uint(r.OrgID), uint(r.OwnerID)); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata, err := m.metadataStorage.UploadMetadata(ctx, uint(r.OwnerID), uint(r.OrgID), r.Filename,
+		r.ContentType, r.Size, m.defaultQuota(uint(r.OrgID)))
 	if err != nil {
 		return nil, err
 	}
 
-	err = m.objectStorage.UploadFile(ctx, metadata.StorageKey, metadata.ContentType, r.Data, metadata.Size)
+	// The row stays pending until the object is stored. If anything fails, both are removed right away,
+	// and whatever is left after a crash is cleaned up by the reconciler.
+	stored, err := m.storeContent(ctx, metadata, r.Data)
+	if err == nil {
+		err = m.metadataStorage.CommitUpload(ctx, metadata.UUID, stored)
+	}
 	if err != nil {
+		m.discardUpload(ctx, metadata)
 		return nil, err
 	}
 
+	metrics.TransferredBytes.WithLabelValues(metrics.DirectionUpload).Add(float64(len(r.Data)))
+
+	metadata.Stored = stored
+
 	return convertMetadata(metadata), nil
 }
 
+// StartUpload reserves the file and returns the URL the client puts the content to. The file stays pending
+// and invisible until FinishUpload, abandoned uploads are removed by the reconciler.
+func (m *FileManager) StartUpload(
+	ctx context.Context, r *protobuf.StartUploadRequest,
+) (*protobuf.PresignedUpload, error) {
+	// The content put directly to the storage cannot be encrypted
+	if !m.options.PresignEnabled || m.options.Keyring != nil {
+		return nil, models.PresignDisabledError
+	}
+
+	if r.OrgID != 0 {
+		if err := m.checkMembership(ctx, uint(r.OrgID), uint(r.OwnerID)); err != nil {
+			return nil, err
+		}
+	}
+
+	metadata, err := m.metadataStorage.UploadMetadata(ctx, uint(r.OwnerID), uint(r.OrgID), r.Filename,
+		r.ContentType, r.Size, m.defaultQuota(uint(r.OrgID)))
+	if err != nil {
+		return nil, err
+	}
+
+	expireTime := time.Now().Add(m.options.PresignUploadTTL)
+
+	uploadURL, err := m.objectStorage.PresignedPutURL(ctx, metadata.StorageKey, m.options.PresignUploadTTL)
+	if err != nil {
+		m.discardUpload(ctx, metadata)
+		return nil, err
+	}
+
+	return &protobuf.PresignedUpload{
+		File:       convertMetadata(metadata),
+		UploadURL:  uploadURL,
+		ExpireTime: timestamppb.New(expireTime),
+	}, nil
+}
+
+// FinishUpload commits the file if the stored object matches the declared size and the checksum if it is set.
+// A mismatching object is removed together with the file.
+func (m *FileManager) FinishUpload(
+	ctx context.Context, r *protobuf.FinishUploadRequest,
+) (*protobuf.FileMetadata, error) {
+	meta, err := m.metadataStorage.GetPendingMetadata(ctx, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, err
+	}
+
+	info, err := m.objectStorage.StatFile(ctx, meta.StorageKey)
+	if err != nil {
+		if errors.Is(err, models.FileNotExists) {
+			return nil, models.UploadNotFinishedError
+		}
+
+		return nil, err
+	}
+
+	if info.Size != meta.Size || (r.Checksum != "" && !strings.EqualFold(r.Checksum, info.Checksum)) {
+		m.discardUpload(ctx, meta)
+		return nil, models.UploadMismatchError
+	}
+
+	// Content put directly to the storage is neither compressed nor encrypted
+	meta.Stored = models.StoredContent{Size: info.Size, Checksum: info.Checksum}
+	err = m.metadataStorage.CommitUpload(ctx, meta.UUID, meta.Stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertMetadata(meta), nil
+}
+
+func (m *FileManager) GetDownloadURL(
+	ctx context.Context, r *protobuf.GetDownloadURLRequest,
+) (*protobuf.DownloadURL, error) {
+	if !m.options.PresignEnabled {
+		return nil, models.PresignDisabledError
+	}
+
+	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, err
+	}
+	// The storage would return the object as it is stored
+	if meta.DataKey != nil || meta.Stored.Encoding != "" {
+		return nil, models.PresignDisabledError
+	}
+
+	expireTime := time.Now().Add(m.options.PresignDownloadTTL)
+
+	downloadURL, err := m.objectStorage.PresignedGetURL(ctx, meta.StorageKey, meta.Filename,
+		m.options.PresignDownloadTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.DownloadURL{URL: downloadURL, ExpireTime: timestamppb.New(expireTime)}, nil
+}
+
 func (m *FileManager) GetFilesList(ctx context.Context, r *protobuf.GetFilesListRequest,
 ) (*protobuf.GetFilesListResponse, error) {
+	if r.OrgID != 0 {
+		if err := m.checkMembership(ctx, uint(r.OrgID), uint(r.OwnerID)); err != nil {
+			return nil, err
+		}
+	}
+
 	resp, err := m.metadataStorage.GetFilesList(ctx, uint(r.OwnerID), models.FilesListOptions{
 		Limit:       uint(r.Limit),
 		Offset:      uint(r.Offset),
 		WithDeleted: r.WithDeleted,
+		OrgID:       uint(r.OrgID),
+		Filename:    r.Filename,
 	})
 	if err != nil {
 		return nil, err
@@ -63,18 +251,32 @@ func (m *FileManager) GetFilesList(ctx context.Context, r *protobuf.GetFilesList
 	return &protobuf.GetFilesListResponse{Files: list}, nil
 }
 
+// GetFile returns Length bytes of the content starting at Offset, zero Length means the rest of the content.
+// Size is always the size of the whole content.
 func (m *FileManager) GetFile(ctx context.Context, r *protobuf.GetFileRequest) (*protobuf.GetFileResponse, error) {
-	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
+	meta, object, err := m.openFile(ctx, r)
 	if err != nil {
 		return nil, err
-	} else if meta == nil || meta.OwnerID != uint(r.UserID) {
-		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
 	}
+	defer object.Close()
 
-	file, err := m.objectStorage.GetFile(ctx, meta.StorageKey)
+	content, err := m.openContent(meta, object, r.Offset)
 	if err != nil {
 		return nil, err
 	}
+	defer content.Close()
+
+	var reader io.Reader = content
+	if r.Length > 0 {
+		reader = io.LimitReader(content, r.Length)
+	}
+
+	file, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.TransferredBytes.WithLabelValues(metrics.DirectionDownload).Add(float64(len(file)))
 
 	return &protobuf.GetFileResponse{
 		Filename:    meta.Filename,
@@ -84,14 +286,63 @@ func (m *FileManager) GetFile(ctx context.Context, r *protobuf.GetFileRequest) (
 	}, nil
 }
 
+// ReadFile streams the same content as GetFile by chunks, so compressed content is decompressed once for the
+// whole download. The first message carries the filename and the content type. At least one message is sent
+// even for empty content.
+func (m *FileManager) ReadFile(r *protobuf.GetFileRequest, stream protobuf.File_ReadFileServer) error {
+	meta, object, err := m.openFile(stream.Context(), r)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	content, err := m.openContent(meta, object, r.Offset)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	var reader io.Reader = content
+	if r.Length > 0 {
+		reader = io.LimitReader(content, r.Length)
+	}
+
+	response := &protobuf.GetFileResponse{
+		Filename:    meta.Filename,
+		ContentType: meta.ContentType,
+		Size:        meta.Size,
+	}
+	for {
+		// Sent messages may still be buffered, so every chunk gets its own buffer
+		chunk := make([]byte, fileReadChunkSize)
+		n, err := io.ReadFull(reader, chunk)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+
+		response.Data = chunk[:n]
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+		metrics.TransferredBytes.WithLabelValues(metrics.DirectionDownload).Add(float64(n))
+
+		if n < len(chunk) {
+			return nil
+		}
+
+		response = &protobuf.GetFileResponse{Size: meta.Size}
+	}
+}
+
 func (m *FileManager) GetFileMetadata(
 	ctx context.Context, r *protobuf.GetFileMetadataRequest,
 ) (*protobuf.FileMetadata, error) {
 	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
 	if err != nil {
 		return nil, err
-	} else if meta == nil || meta.OwnerID != uint(r.UserID) {
-		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, err
 	}
 
 	return convertMetadata(meta), nil
@@ -99,24 +350,54 @@ func (m *FileManager) GetFileMetadata(
 
 func (m *FileManager) UpdateFile(
 	ctx context.Context, r *protobuf.UpdateFileRequest,
-) (*emptypb.Empty, error) {
+) (_ *emptypb.Empty, err error) {
 	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
 	if err != nil {
 		return nil, err
-	} else if meta == nil || meta.OwnerID != uint(r.UserID) {
-		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, err
 	}
 
-	err = m.objectStorage.UploadFile(ctx, meta.StorageKey, meta.ContentType, r.Data, r.Size)
+	// The quota is checked again when the content is switched, this check only avoids useless uploads
+	if err := m.checkQuota(ctx, meta.OwnerID, meta.OrgID, r.Size-meta.Size); err != nil {
+		return nil, err
+	}
+
+	// New content goes to a new object, so the stored file and its size are switched in one update
+	key := path.Join(path.Dir(meta.StorageKey), uuid.NewString())
+	if err := m.metadataStorage.BeginReplace(ctx, r.UUID, key); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			m.discardReplacement(ctx, r.UUID, key)
+		}
+	}()
+
+	// Files uploaded before encryption was enabled have no data key and stay in plaintext
+	object, stored, err := m.encode(meta, r.Data)
 	if err != nil {
 		return nil, err
 	}
 
-	err = m.metadataStorage.UpdateSize(ctx, r.UUID, r.Size)
+	err = m.objectStorage.UploadFile(ctx, key, meta.ContentType, object, stored.Size)
 	if err != nil {
 		return nil, err
 	}
 
+	metrics.TransferredBytes.WithLabelValues(metrics.DirectionUpload).Add(float64(len(r.Data)))
+
+	oldKey, err := m.metadataStorage.FinishReplace(ctx, r.UUID, key, r.Size, stored, m.defaultQuota(meta.OrgID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.objectStorage.DeleteFiles(context.WithoutCancel(ctx), []string{oldKey}); err != nil {
+		slog.WarnContext(ctx, "Cannot delete replaced object, it is left to the reconciler",
+			"key", oldKey, "error", err)
+	}
+
 	return nil, nil
 }
 
@@ -124,8 +405,9 @@ func (m *FileManager) UpdateFilename(ctx context.Context, r *protobuf.UpdateFile
 	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
 	if err != nil {
 		return nil, err
-	} else if meta == nil || meta.OwnerID != uint(r.UserID) {
-		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, err
 	}
 
 	err = m.metadataStorage.UpdateFilename(ctx, r.UUID, r.Filename)
@@ -140,8 +422,9 @@ func (m *FileManager) DeleteFile(ctx context.Context, r *protobuf.DeleteFileRequ
 	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
 	if err != nil {
 		return nil, err
-	} else if meta == nil || meta.OwnerID != uint(r.UserID) {
-		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, err
 	}
 
 	err = m.metadataStorage.DeleteFile(ctx, r.UUID)
@@ -152,6 +435,282 @@ func (m *FileManager) DeleteFile(ctx context.Context, r *protobuf.DeleteFileRequ
 	return nil, nil
 }
 
+func (m *FileManager) StartPurge(ctx context.Context, r *protobuf.StartPurgeRequest) (*protobuf.PurgeJob, error) {
+	job, err := m.purgeJobStorage.CreatePurgeJob(ctx, uint(r.OwnerID))
+	if err != nil {
+		return nil, err
+	}
+
+	m.purgeScheduler.Schedule(job.ID)
+
+	return convertPurgeJob(job), nil
+}
+
+func (m *FileManager) GetPurgeJob(ctx context.Context, r *protobuf.GetPurgeJobRequest) (*protobuf.PurgeJob, error) {
+	job, err := m.purgeJobStorage.GetPurgeJob(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	} else if job == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.PurgeJobNotExists.Error())
+	}
+
+	return convertPurgeJob(job), nil
+}
+
+func (m *FileManager) StartExport(ctx context.Context, r *protobuf.StartExportRequest) (*protobuf.ExportJob, error) {
+	job, err := m.exportJobStorage.CreateExportJob(ctx, uint(r.OwnerID), r.Email, r.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	m.exportScheduler.Schedule(job.ID)
+
+	return convertExportJob(job), nil
+}
+
+func (m *FileManager) GetExportJob(ctx context.Context, r *protobuf.GetExportJobRequest) (*protobuf.ExportJob, error) {
+	job, err := m.exportJobStorage.GetExportJob(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	} else if job == nil {
+		return nil, status.Errorf(codes.NotFound, "%s", models.ExportJobNotExists.Error())
+	} else if job.OwnerID != uint(r.UserID) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	if job.Status == models.JobStatusDone && job.ExpireTime != nil && time.Now().Before(*job.ExpireTime) {
+		ttl := min(m.options.DownloadURLTTL, time.Until(*job.ExpireTime))
+
+		job.DownloadURL, err = m.objectStorage.PresignedGetURL(ctx, job.ObjectKey, "voblako-export.zip", ttl)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return convertExportJob(job), nil
+}
+
+func (m *FileManager) GetStorageUsage(
+	ctx context.Context, r *protobuf.StorageUsageRequest,
+) (*protobuf.StorageUsage, error) {
+	if r.OrgID != 0 {
+		if err := m.checkCallerMembership(ctx, uint(r.OrgID)); err != nil {
+			return nil, err
+		}
+	}
+
+	usage, err := m.getStorageUsage(ctx, uint(r.OwnerID), uint(r.OrgID))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertStorageUsage(usage), nil
+}
+
+func (m *FileManager) SetQuota(ctx context.Context, r *protobuf.SetQuotaRequest) (*protobuf.StorageUsage, error) {
+	var err error
+	if r.OrgID != 0 {
+		err = m.metadataStorage.SetOrgQuota(ctx, uint(r.OrgID), r.QuotaBytes)
+	} else {
+		err = m.metadataStorage.SetQuota(ctx, uint(r.OwnerID), r.QuotaBytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return m.GetStorageUsage(ctx, &protobuf.StorageUsageRequest{OwnerID: r.OwnerID, OrgID: r.OrgID})
+}
+
+// ForceDeleteFile removes the file regardless of the owner, so it must be available only to administrators
+func (m *FileManager) ForceDeleteFile(
+	ctx context.Context, r *protobuf.ForceDeleteFileRequest,
+) (*protobuf.FileMetadata, error) {
+	meta, err := m.metadataStorage.RemoveFile(ctx, r.UUID)
+	if err != nil {
+		if errors.Is(err, models.FileNotExists) {
+			return nil, status.Errorf(codes.NotFound, "%s", err.Error())
+		}
+
+		return nil, err
+	}
+
+	err = m.objectStorage.DeleteFiles(ctx, []string{meta.StorageKey})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertMetadata(meta), nil
+}
+
+// PutUploadPart stages a part of an S3 multipart upload, the part with the same number is replaced. Parts are
+// kept as is until the gateway assembles the upload, abandoned ones are removed by the multipart cleaner.
+func (m *FileManager) PutUploadPart(ctx context.Context, r *protobuf.PutUploadPartRequest) (*emptypb.Empty, error) {
+	key, err := uploadPartKey(r.UserID, r.UploadID, r.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.objectStorage.UploadFile(ctx, key, "application/octet-stream", r.Data, int64(len(r.Data)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (m *FileManager) GetUploadPart(ctx context.Context, r *protobuf.GetUploadPartRequest) (*protobuf.UploadPart, error) {
+	key, err := uploadPartKey(r.UserID, r.UploadID, r.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.objectStorage.GetFile(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.UploadPart{Data: data}, nil
+}
+
+func (m *FileManager) DeleteUploadParts(
+	ctx context.Context, r *protobuf.DeleteUploadPartsRequest,
+) (*emptypb.Empty, error) {
+	if r.UserID == 0 || uuid.Validate(r.UploadID) != nil {
+		return nil, models.InvalidInputError
+	}
+
+	keys, err := m.objectStorage.ListKeys(ctx, jobs.UploadPartsPrefix(uint(r.UserID), r.UploadID))
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		if err := m.objectStorage.DeleteFiles(ctx, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// uploadPartKey accepts only UUIDv7 upload IDs, the multipart cleaner tells the age of parts by them
+func uploadPartKey(userID uint32, uploadID string, number int32) (string, error) {
+	id, err := uuid.Parse(uploadID)
+	if err != nil || id.Version() != 7 || id.String() != uploadID || userID == 0 || number < 1 {
+		return "", models.InvalidInputError
+	}
+
+	return jobs.UploadPartsPrefix(uint(userID), uploadID) + strconv.Itoa(int(number)), nil
+}
+
+// checkAccess allows the owner to access personal files and any member to access files of the organization
+func (m *FileManager) checkAccess(ctx context.Context, meta *models.FileMetadata, userID uint) error {
+	if meta == nil {
+		return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	if meta.OrgID != 0 {
+		return m.checkMembership(ctx, meta.OrgID, userID)
+	}
+
+	if meta.OwnerID != userID {
+		return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	return nil
+}
+
+func (m *FileManager) checkMembership(ctx context.Context, orgID, userID uint) error {
+	isMember, err := m.membershipChecker.IsMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !isMember {
+		return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	return nil
+}
+
+// checkCallerMembership checks the user from the service token for requests that do not name the user,
+// administrators may access any organization
+func (m *FileManager) checkCallerMembership(ctx context.Context, orgID uint) error {
+	identity, ok := serviceauth.IdentityFromContext(ctx)
+	if !ok || identity.UserID == 0 {
+		return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+	if identity.Role == models.RoleAdmin {
+		return nil
+	}
+
+	return m.checkMembership(ctx, orgID, identity.UserID)
+}
+
+func (m *FileManager) getStorageUsage(ctx context.Context, ownerID, orgID uint) (*models.StorageUsage, error) {
+	if orgID != 0 {
+		return m.metadataStorage.GetOrgStorageUsage(ctx, orgID, m.options.DefaultOrgQuota)
+	}
+
+	return m.metadataStorage.GetStorageUsage(ctx, ownerID, m.options.DefaultQuota)
+}
+
+func (m *FileManager) defaultQuota(orgID uint) int64 {
+	if orgID != 0 {
+		return m.options.DefaultOrgQuota
+	}
+
+	return m.options.DefaultQuota
+}
+
+func (m *FileManager) checkQuota(ctx context.Context, ownerID, orgID uint, delta int64) error {
+	usage, err := m.getStorageUsage(ctx, ownerID, orgID)
+	if err != nil {
+		return err
+	}
+
+	if usage.QuotaBytes > 0 && delta > 0 && usage.UsedBytes+delta > usage.QuotaBytes {
+		return status.Errorf(codes.ResourceExhausted, "%s", models.QuotaExceededError.Error())
+	}
+
+	return nil
+}
+
+func convertStorageUsage(usage *models.StorageUsage) *protobuf.StorageUsage {
+	return &protobuf.StorageUsage{
+		OwnerID:    uint32(usage.OwnerID),
+		OrgID:      uint32(usage.OrgID),
+		UsedBytes:  usage.UsedBytes,
+		FilesCount: usage.FilesCount,
+		QuotaBytes: usage.QuotaBytes,
+	}
+}
+
+func convertExportJob(job *models.ExportJob) *protobuf.ExportJob {
+	return &protobuf.ExportJob{
+		ID:          job.ID,
+		OwnerID:     uint32(job.OwnerID),
+		Status:      job.Status,
+		Size:        job.Size,
+		Error:       job.Error,
+		DownloadURL: job.DownloadURL,
+		CreateTime:  timestamppb.New(job.CreateTime),
+		UpdateTime:  timestamppb.New(job.UpdateTime),
+		ExpireTime:  ptrTimeToProto(job.ExpireTime),
+	}
+}
+
+func convertPurgeJob(job *models.PurgeJob) *protobuf.PurgeJob {
+	return &protobuf.PurgeJob{
+		ID:             job.ID,
+		OwnerID:        uint32(job.OwnerID),
+		Status:         job.Status,
+		DeletedObjects: job.DeletedObjects,
+		Error:          job.Error,
+		CreateTime:     timestamppb.New(job.CreateTime),
+		UpdateTime:     timestamppb.New(job.UpdateTime),
+	}
+}
+
 func convertMetadata(m *models.FileMetadata) *protobuf.FileMetadata {
 	var deletedTime time.Time
 	if t := ptrTimeToProto(m.DeletedTime); t != nil {
@@ -168,6 +727,8 @@ func convertMetadata(m *models.FileMetadata) *protobuf.FileMetadata {
 		UpdateTime:  timestamppb.New(m.UpdateTime),
 		DeletedTime: timestamppb.New(deletedTime),
 		IsDeleted:   m.IsDeleted,
+		OrgID:       uint32(m.OrgID),
+		Checksum:    m.Stored.Checksum,
 	}
 }
 
@@ -177,3 +738,123 @@ func ptrTimeToProto(t *time.Time) *timestamppb.Timestamp {
 	}
 	return timestamppb.New(*t)
 }
+
+// storeContent uploads the content of a new file, which gets its own data key if encryption is enabled
+func (m *FileManager) storeContent(ctx context.Context, meta *models.FileMetadata,
+	data []byte) (models.StoredContent, error) {
+	if m.options.Keyring != nil {
+		_, dataKey, err := m.options.Keyring.GenerateDataKey(meta.UUID)
+		if err != nil {
+			return models.StoredContent{}, err
+		}
+		if err := m.metadataStorage.SetDataKey(ctx, dataKey); err != nil {
+			return models.StoredContent{}, err
+		}
+		meta.DataKey = dataKey
+	}
+
+	object, stored, err := m.encode(meta, data)
+	if err != nil {
+		return models.StoredContent{}, err
+	}
+
+	return stored, m.objectStorage.UploadFile(ctx, meta.StorageKey, meta.ContentType, object, stored.Size)
+}
+
+// encode turns the content into the object, it is compressed first, as encrypted data does not compress
+func (m *FileManager) encode(meta *models.FileMetadata, data []byte) ([]byte, models.StoredContent, error) {
+	compressed, encoding, err := m.options.Compressor.Compress(meta.ContentType, data)
+	if err != nil {
+		return nil, models.StoredContent{}, err
+	}
+
+	object, err := m.options.Keyring.Seal(meta, compressed)
+	if err != nil {
+		return nil, models.StoredContent{}, err
+	}
+
+	checksum := md5.Sum(data)
+
+	return object, models.StoredContent{
+		Encoding: encoding,
+		Size:     int64(len(object)),
+		Checksum: hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// openFile returns the metadata and the object of the requested file, the object is closed by the caller
+func (m *FileManager) openFile(ctx context.Context,
+	r *protobuf.GetFileRequest) (*models.FileMetadata, fileinterfaces.ObjectReader, error) {
+	if r.Offset < 0 || r.Length < 0 {
+		return nil, nil, models.InvalidInputError
+	}
+
+	meta, err := m.metadataStorage.GetMetadata(ctx, r.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.checkAccess(ctx, meta, uint(r.UserID)); err != nil {
+		return nil, nil, err
+	}
+
+	object, err := m.objectStorage.OpenFile(ctx, meta.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return meta, object, nil
+}
+
+// openContent returns the content starting at the offset. The object is read sequentially by blocks of whole
+// encryption chunks, so every chunk is fetched and decrypted once. Plain content is read from the chunk holding
+// the offset, compressed content cannot be read by ranges, so it is decompressed from the start and the part
+// before the offset is skipped.
+func (m *FileManager) openContent(meta *models.FileMetadata, object fileinterfaces.ObjectReader,
+	offset int64) (io.ReadCloser, error) {
+	stored, size, err := m.options.Keyring.OpenReader(meta, object, object.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	start := int64(0)
+	if meta.Stored.Encoding == compression.EncodingIdentity {
+		start = min(offset, size) / encryption.ChunkSize * encryption.ChunkSize
+	}
+
+	blocks := bufio.NewReaderSize(io.NewSectionReader(stored, start, size-start), objectReadBlockSize)
+	content, err := compression.NewReader(meta.Stored.Encoding, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.CopyN(io.Discard, content, offset-start); err != nil && !errors.Is(err, io.EOF) {
+		content.Close()
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// discardUpload is a compensating action for a failed upload. It runs even if the call is cancelled, as
+// cancellation is the usual reason of the failure.
+func (m *FileManager) discardUpload(ctx context.Context, meta *models.FileMetadata) {
+	ctx = context.WithoutCancel(ctx)
+
+	if err := m.objectStorage.DeleteFiles(ctx, []string{meta.StorageKey}); err != nil {
+		slog.WarnContext(ctx, "Cannot delete object of failed upload", "key", meta.StorageKey, "error", err)
+	}
+	if _, err := m.metadataStorage.RemoveFile(ctx, meta.UUID); err != nil {
+		slog.WarnContext(ctx, "Cannot delete metadata of failed upload", "id", meta.UUID, "error", err)
+	}
+}
+
+func (m *FileManager) discardReplacement(ctx context.Context, id, key string) {
+	ctx = context.WithoutCancel(ctx)
+
+	if err := m.objectStorage.DeleteFiles(ctx, []string{key}); err != nil {
+		slog.WarnContext(ctx, "Cannot delete object of failed update", "key", key, "error", err)
+	}
+	if err := m.metadataStorage.ClearPendingKey(ctx, id, key); err != nil {
+		slog.WarnContext(ctx, "Cannot clear pending object of failed update", "id", id, "error", err)
+	}
+}
@@ -0,0 +1,338 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/compression"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/encryption"
+	objectrepo "github.com/IlyaChgn/voblako/internal/pkg/file/repository/object"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeMetadataStorage struct {
+	fileinterfaces.MetadataStorage
+	files   map[string]*models.FileMetadata
+	pending map[string]string
+}
+
+func (s *fakeMetadataStorage) GetMetadata(_ context.Context, id string) (*models.FileMetadata, error) {
+	return s.files[id], nil
+}
+
+func (s *fakeMetadataStorage) GetStorageUsage(_ context.Context, ownerID uint,
+	defaultQuota int64) (*models.StorageUsage, error) {
+	return &models.StorageUsage{OwnerID: ownerID, QuotaBytes: defaultQuota}, nil
+}
+
+func (s *fakeMetadataStorage) BeginReplace(_ context.Context, id, key string) error {
+	s.pending[id] = key
+	return nil
+}
+
+func (s *fakeMetadataStorage) ClearPendingKey(_ context.Context, id, key string) error {
+	if s.pending[id] == key {
+		delete(s.pending, id)
+	}
+
+	return nil
+}
+
+func (s *fakeMetadataStorage) GetOrgStorageUsage(_ context.Context, orgID uint,
+	defaultQuota int64) (*models.StorageUsage, error) {
+	return &models.StorageUsage{OrgID: orgID, QuotaBytes: defaultQuota}, nil
+}
+
+type fakeObjectStorage struct {
+	fileinterfaces.ObjectStorage
+	deleted []string
+}
+
+func (s *fakeObjectStorage) DeleteFiles(_ context.Context, keys []string) error {
+	s.deleted = append(s.deleted, keys...)
+	return nil
+}
+
+type fakeMembershipChecker struct {
+	members map[uint][]uint
+}
+
+func (c *fakeMembershipChecker) IsMember(_ context.Context, orgID, userID uint) (bool, error) {
+	for _, id := range c.members[orgID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func TestFileManager_GetStorageUsage_Org(t *testing.T) {
+	m := NewFileManager(&fakeMetadataStorage{}, nil, nil, nil, nil, nil,
+		&fakeMembershipChecker{members: map[uint][]uint{3: {1}}}, FileManagerOptions{DefaultOrgQuota: 100})
+
+	request := &protobuf.StorageUsageRequest{OrgID: 3}
+
+	member := serviceauth.ContextWithIdentity(context.Background(), &serviceauth.Identity{UserID: 1})
+	usage, err := m.GetStorageUsage(member, request)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), usage.QuotaBytes)
+
+	stranger := serviceauth.ContextWithIdentity(context.Background(), &serviceauth.Identity{UserID: 2})
+	_, err = m.GetStorageUsage(stranger, request)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	admin := serviceauth.ContextWithIdentity(context.Background(),
+		&serviceauth.Identity{UserID: 2, Role: models.RoleAdmin})
+	_, err = m.GetStorageUsage(admin, request)
+	assert.NoError(t, err)
+}
+
+func TestFileManager_UpdateFile_EncodeFailure(t *testing.T) {
+	metadataStorage := &fakeMetadataStorage{
+		files: map[string]*models.FileMetadata{
+			"file": {
+				UUID:       "file",
+				OwnerID:    1,
+				StorageKey: "1/object",
+				DataKey:    &models.DataKey{FileID: "file", Key: []byte("wrapped"), KeyID: "old"},
+			},
+		},
+		pending: make(map[string]string),
+	}
+	objectStorage := &fakeObjectStorage{}
+	// Without a keyring the content of an encrypted file cannot be sealed
+	m := NewFileManager(metadataStorage, objectStorage, nil, nil, nil, nil, nil, FileManagerOptions{})
+
+	_, err := m.UpdateFile(context.Background(), &protobuf.UpdateFileRequest{
+		UUID:   "file",
+		UserID: 1,
+		Data:   []byte("content"),
+		Size:   7,
+	})
+	assert.ErrorIs(t, err, models.MasterKeyMissingError)
+
+	assert.Empty(t, metadataStorage.pending)
+	require.Len(t, objectStorage.deleted, 1)
+	assert.True(t, strings.HasPrefix(objectStorage.deleted[0], "1/"))
+}
+
+func TestFileManager_GetFile_Range(t *testing.T) {
+	master := make([]byte, encryption.KeySize)
+	_, err := rand.Read(master)
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(master)
+	require.NoError(t, err)
+
+	content := make([]byte, 3*encryption.ChunkSize)
+	_, err = rand.Read(content)
+	require.NoError(t, err)
+
+	_, dataKey, err := keyring.GenerateDataKey("file")
+	require.NoError(t, err)
+	meta := &models.FileMetadata{UUID: "file", OwnerID: 1, StorageKey: "1/object", Size: int64(len(content)),
+		DataKey: dataKey}
+	object, err := keyring.Seal(meta, content)
+	require.NoError(t, err)
+
+	objectStorage := objectrepo.NewMemoryStorage()
+	require.NoError(t, objectStorage.UploadFile(context.Background(), meta.StorageKey, "", object,
+		int64(len(object))))
+
+	m := NewFileManager(&fakeMetadataStorage{files: map[string]*models.FileMetadata{"file": meta}},
+		objectStorage, nil, nil, nil, nil, nil, FileManagerOptions{Keyring: keyring})
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   []byte
+	}{
+		{"whole", 0, 0, content},
+		{"across chunks", encryption.ChunkSize - 10, 20, content[encryption.ChunkSize-10 : encryption.ChunkSize+10]},
+		{"rest", int64(len(content)) - 5, 100, content[len(content)-5:]},
+		{"past end", int64(len(content)) + 1, 0, []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := m.GetFile(context.Background(), &protobuf.GetFileRequest{
+				UUID:   "file",
+				UserID: 1,
+				Offset: tt.offset,
+				Length: tt.length,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, file.Data)
+			assert.Equal(t, int64(len(content)), file.Size)
+		})
+	}
+}
+
+// countingObjectStorage counts the objects opened and the reads of them
+type countingObjectStorage struct {
+	fileinterfaces.ObjectStorage
+	opened int
+	reads  int
+	read   int64
+}
+
+func (s *countingObjectStorage) OpenFile(ctx context.Context, key string) (fileinterfaces.ObjectReader, error) {
+	object, err := s.ObjectStorage.OpenFile(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	s.opened++
+
+	return &countingObject{ObjectReader: object, storage: s}, nil
+}
+
+type countingObject struct {
+	fileinterfaces.ObjectReader
+	storage *countingObjectStorage
+}
+
+func (o *countingObject) ReadAt(p []byte, off int64) (int, error) {
+	n, err := o.ObjectReader.ReadAt(p, off)
+	o.storage.reads++
+	o.storage.read += int64(n)
+
+	return n, err
+}
+
+type fakeReadFileStream struct {
+	protobuf.File_ReadFileServer
+	responses []*protobuf.GetFileResponse
+}
+
+func (s *fakeReadFileStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeReadFileStream) Send(r *protobuf.GetFileResponse) error {
+	s.responses = append(s.responses, r)
+	return nil
+}
+
+func TestFileManager_ReadFile_Compressed(t *testing.T) {
+	master := make([]byte, encryption.KeySize)
+	_, err := rand.Read(master)
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(master)
+	require.NoError(t, err)
+	compressor, err := compression.New(config.CompressionConfig{Enabled: true, Algorithm: compression.EncodingZstd})
+	require.NoError(t, err)
+
+	// Random letters compress, but not too well, so the object spans many encryption chunks
+	content := make([]byte, 3*fileReadChunkSize+100)
+	_, err = rand.Read(content)
+	require.NoError(t, err)
+	for i := range content {
+		content[i] = 'a' + content[i]%16
+	}
+
+	_, dataKey, err := keyring.GenerateDataKey("file")
+	require.NoError(t, err)
+	meta := &models.FileMetadata{UUID: "file", OwnerID: 1, StorageKey: "1/object", Filename: "file.txt",
+		ContentType: "text/plain", Size: int64(len(content)), DataKey: dataKey}
+	compressed, encoding, err := compressor.Compress(meta.ContentType, content)
+	require.NoError(t, err)
+	require.Equal(t, compression.EncodingZstd, encoding)
+	meta.Stored.Encoding = encoding
+	object, err := keyring.Seal(meta, compressed)
+	require.NoError(t, err)
+	require.Greater(t, len(object), objectReadBlockSize)
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   []byte
+	}{
+		{"whole", 0, 0, content},
+		{"range", fileReadChunkSize + 10, fileReadChunkSize, content[fileReadChunkSize+10 : 2*fileReadChunkSize+10]},
+		{"past end", int64(len(content)) + 1, 0, []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objectStorage := &countingObjectStorage{ObjectStorage: objectrepo.NewMemoryStorage()}
+			require.NoError(t, objectStorage.UploadFile(context.Background(), meta.StorageKey, "", object,
+				int64(len(object))))
+
+			m := NewFileManager(&fakeMetadataStorage{files: map[string]*models.FileMetadata{"file": meta}},
+				objectStorage, nil, nil, nil, nil, nil, FileManagerOptions{Keyring: keyring})
+
+			stream := &fakeReadFileStream{}
+			require.NoError(t, m.ReadFile(&protobuf.GetFileRequest{
+				UUID:   "file",
+				UserID: 1,
+				Offset: tt.offset,
+				Length: tt.length,
+			}, stream))
+
+			require.NotEmpty(t, stream.responses)
+			assert.Equal(t, meta.Filename, stream.responses[0].Filename)
+
+			data := []byte{}
+			for _, response := range stream.responses {
+				assert.LessOrEqual(t, len(response.Data), fileReadChunkSize)
+				assert.Equal(t, int64(len(content)), response.Size)
+				data = append(data, response.Data...)
+			}
+			assert.Equal(t, tt.want, data)
+
+			// The object is read once for the whole stream, one read per encryption chunk and the header
+			chunks := int(encryption.EncryptedSize(int64(len(compressed)))) / encryption.ChunkSize
+			assert.Equal(t, 1, objectStorage.opened)
+			assert.LessOrEqual(t, objectStorage.reads, chunks+2)
+			assert.LessOrEqual(t, objectStorage.read, int64(len(object)))
+		})
+	}
+}
+
+func TestFileManager_UploadParts(t *testing.T) {
+	const uploadID = "01942117-de80-7abc-8def-0123456789ab"
+
+	objectStorage := objectrepo.NewMemoryStorage()
+	m := NewFileManager(&fakeMetadataStorage{}, objectStorage, nil, nil, nil, nil, nil, FileManagerOptions{})
+	ctx := context.Background()
+
+	for number, data := range []string{"hello ", "world"} {
+		_, err := m.PutUploadPart(ctx, &protobuf.PutUploadPartRequest{
+			UserID: 1, UploadID: uploadID, Number: int32(number + 1), Data: []byte(data),
+		})
+		require.NoError(t, err)
+	}
+
+	part, err := m.GetUploadPart(ctx, &protobuf.GetUploadPartRequest{UserID: 1, UploadID: uploadID, Number: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("world"), part.Data)
+
+	// Parts are kept per user, other users cannot read them by the upload ID
+	_, err = m.GetUploadPart(ctx, &protobuf.GetUploadPartRequest{UserID: 2, UploadID: uploadID, Number: 2})
+	assert.ErrorIs(t, err, models.FileNotExists)
+
+	// Only UUIDv7 upload IDs are accepted, the cleaner could not tell the age of other parts
+	_, err = m.PutUploadPart(ctx, &protobuf.PutUploadPartRequest{
+		UserID: 1, UploadID: "0b6f3c1e-8a7d-4d5c-9e2f-1a2b3c4d5e6f", Number: 1, Data: []byte("x"),
+	})
+	assert.ErrorIs(t, err, models.InvalidInputError)
+
+	_, err = m.DeleteUploadParts(ctx, &protobuf.DeleteUploadPartsRequest{UserID: 1, UploadID: uploadID})
+	require.NoError(t, err)
+
+	keys, err := objectStorage.ListKeys(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// AccessPolicy binds every RPC to its caller. The status of an account deletion is requested after the session
+// has been revoked, and job IDs are random UUIDs. Health checks come from the gateway readiness probe.
+var AccessPolicy = serviceauth.Policy{
+	Public: []string{
+		protobuf.File_GetPurgeJob_FullMethodName,
+		healthpb.Health_Check_FullMethodName,
+	},
+	Admin: []string{
+		protobuf.File_SetQuota_FullMethodName,
+		protobuf.File_ForceDeleteFile_FullMethodName,
+	},
+	Subjects: map[string]serviceauth.Subject{
+		protobuf.File_UploadFile_FullMethodName:        serviceauth.SubjectOf((*protobuf.UploadFileRequest).GetOwnerID),
+		protobuf.File_GetFilesList_FullMethodName:      serviceauth.SubjectOf((*protobuf.GetFilesListRequest).GetOwnerID),
+		protobuf.File_GetFile_FullMethodName:           serviceauth.SubjectOf((*protobuf.GetFileRequest).GetUserID),
+		protobuf.File_ReadFile_FullMethodName:          serviceauth.SubjectOf((*protobuf.GetFileRequest).GetUserID),
+		protobuf.File_GetFileMetadata_FullMethodName:   serviceauth.SubjectOf((*protobuf.GetFileMetadataRequest).GetUserID),
+		protobuf.File_UpdateFile_FullMethodName:        serviceauth.SubjectOf((*protobuf.UpdateFileRequest).GetUserID),
+		protobuf.File_UpdateFilename_FullMethodName:    serviceauth.SubjectOf((*protobuf.UpdateFilenameRequest).GetUserID),
+		protobuf.File_DeleteFile_FullMethodName:        serviceauth.SubjectOf((*protobuf.DeleteFileRequest).GetUserID),
+		protobuf.File_StartPurge_FullMethodName:        serviceauth.SubjectOf((*protobuf.StartPurgeRequest).GetOwnerID),
+		protobuf.File_StartExport_FullMethodName:       serviceauth.SubjectOf((*protobuf.StartExportRequest).GetOwnerID),
+		protobuf.File_GetExportJob_FullMethodName:      serviceauth.SubjectOf((*protobuf.GetExportJobRequest).GetUserID),
+		protobuf.File_GetStorageUsage_FullMethodName:   serviceauth.SubjectOf((*protobuf.StorageUsageRequest).GetOwnerID),
+		protobuf.File_StartUpload_FullMethodName:       serviceauth.SubjectOf((*protobuf.StartUploadRequest).GetOwnerID),
+		protobuf.File_FinishUpload_FullMethodName:      serviceauth.SubjectOf((*protobuf.FinishUploadRequest).GetUserID),
+		protobuf.File_GetDownloadURL_FullMethodName:    serviceauth.SubjectOf((*protobuf.GetDownloadURLRequest).GetUserID),
+		protobuf.File_PutUploadPart_FullMethodName:     serviceauth.SubjectOf((*protobuf.PutUploadPartRequest).GetUserID),
+		protobuf.File_GetUploadPart_FullMethodName:     serviceauth.SubjectOf((*protobuf.GetUploadPartRequest).GetUserID),
+		protobuf.File_DeleteUploadParts_FullMethodName: serviceauth.SubjectOf((*protobuf.DeleteUploadPartsRequest).GetUserID),
+	},
+}
@@ -0,0 +1,33 @@
+package grpc
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestAccessPolicy_CoversEveryMethod(t *testing.T) {
+	methods := protobuf.File_file_proto.Services().ByName("File").Methods()
+
+	for i := range methods.Len() {
+		method := methods.Get(i)
+		name := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+
+		t.Run(string(method.Name()), func(t *testing.T) {
+			public := slices.Contains(AccessPolicy.Public, name) || slices.Contains(AccessPolicy.Admin, name)
+			subject, bound := AccessPolicy.Subjects[name]
+			assert.True(t, public != bound, "method must be either public or bound to a subject")
+
+			if bound {
+				msgType, err := protoregistry.GlobalTypes.FindMessageByName(method.Input().FullName())
+				assert.NoError(t, err)
+
+				_, ok := subject(msgType.New().Interface())
+				assert.True(t, ok, "subject must accept the request of the method")
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ type UploadFileRequest struct {
 	Data          []byte                 `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
 	ContentType   string                 `protobuf:"bytes,4,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
 	Size          int64                  `protobuf:"varint,5,opt,name=Size,proto3" json:"Size,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,6,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -101,12 +102,21 @@ func (x *UploadFileRequest) GetSize() int64 {
 	return 0
 }
 
+func (x *UploadFileRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
 type GetFilesListRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
 	Limit         uint32                 `protobuf:"varint,2,opt,name=Limit,proto3" json:"Limit,omitempty"`
 	Offset        uint32                 `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
 	WithDeleted   bool                   `protobuf:"varint,4,opt,name=WithDeleted,proto3" json:"WithDeleted,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,5,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	Filename      string                 `protobuf:"bytes,6,opt,name=Filename,proto3" json:"Filename,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,6 +179,20 @@ func (x *GetFilesListRequest) GetWithDeleted() bool {
 	return false
 }
 
+func (x *GetFilesListRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *GetFilesListRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
 type GetFilesListResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Files         []*FileMetadata        `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
@@ -217,6 +241,8 @@ type GetFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UUID          string                 `protobuf:"bytes,1,opt,name=UUID,proto3" json:"UUID,omitempty"`
 	UserID        uint32                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Offset        int64                  `protobuf:"varint,3,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	Length        int64                  `protobuf:"varint,4,opt,name=Length,proto3" json:"Length,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -265,6 +291,20 @@ func (x *GetFileRequest) GetUserID() uint32 {
 	return 0
 }
 
+func (x *GetFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetFileRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
 type GetFileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Filename      string                 `protobuf:"bytes,1,opt,name=Filename,proto3" json:"Filename,omitempty"`
@@ -397,6 +437,8 @@ type FileMetadata struct {
 	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=UpdateTime,proto3" json:"UpdateTime,omitempty"`
 	DeletedTime   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=DeletedTime,proto3" json:"DeletedTime,omitempty"`
 	IsDeleted     bool                   `protobuf:"varint,10,opt,name=IsDeleted,proto3" json:"IsDeleted,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,11,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	Checksum      string                 `protobuf:"bytes,12,opt,name=Checksum,proto3" json:"Checksum,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -501,6 +543,20 @@ func (x *FileMetadata) GetIsDeleted() bool {
 	return false
 }
 
+func (x *FileMetadata) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *FileMetadata) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
 type UpdateFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UUID          string                 `protobuf:"bytes,1,opt,name=UUID,proto3" json:"UUID,omitempty"`
@@ -681,77 +737,1354 @@ func (x *DeleteFileRequest) GetUserID() uint32 {
 	return 0
 }
 
-var File_file_proto protoreflect.FileDescriptor
+type StartPurgeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_file_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"file.proto\x12\bprotobuf\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x93\x01\n" +
-	"\x11UploadFileRequest\x12\x18\n" +
-	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x1a\n" +
-	"\bFilename\x18\x02 \x01(\tR\bFilename\x12\x12\n" +
-	"\x04Data\x18\x03 \x01(\fR\x04Data\x12 \n" +
-	"\vContentType\x18\x04 \x01(\tR\vContentType\x12\x12\n" +
-	"\x04Size\x18\x05 \x01(\x03R\x04Size\"\x7f\n" +
-	"\x13GetFilesListRequest\x12\x18\n" +
-	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x14\n" +
-	"\x05Limit\x18\x02 \x01(\rR\x05Limit\x12\x16\n" +
-	"\x06Offset\x18\x03 \x01(\rR\x06Offset\x12 \n" +
-	"\vWithDeleted\x18\x04 \x01(\bR\vWithDeleted\"D\n" +
-	"\x14GetFilesListResponse\x12,\n" +
-	"\x05files\x18\x01 \x03(\v2\x16.protobuf.FileMetadataR\x05files\"<\n" +
-	"\x0eGetFileRequest\x12\x12\n" +
-	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
-	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"w\n" +
-	"\x0fGetFileResponse\x12\x1a\n" +
-	"\bFilename\x18\x01 \x01(\tR\bFilename\x12 \n" +
-	"\vContentType\x18\x02 \x01(\tR\vContentType\x12\x12\n" +
-	"\x04Data\x18\x03 \x01(\fR\x04Data\x12\x12\n" +
-	"\x04Size\x18\x04 \x01(\x03R\x04Size\"D\n" +
-	"\x16GetFileMetadataRequest\x12\x12\n" +
-	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
-	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"\x82\x03\n" +
-	"\fFileMetadata\x12\x12\n" +
-	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x18\n" +
-	"\aOwnerID\x18\x02 \x01(\rR\aOwnerID\x12\x1a\n" +
-	"\bFilename\x18\x03 \x01(\tR\bFilename\x12 \n" +
-	"\vContentType\x18\x04 \x01(\tR\vContentType\x12\x12\n" +
-	"\x04Size\x18\x05 \x01(\x03R\x04Size\x12\x1e\n" +
-	"\n" +
-	"StorageKey\x18\x06 \x01(\tR\n" +
-	"StorageKey\x12:\n" +
-	"\n" +
-	"UploadTime\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"UploadTime\x12:\n" +
-	"\n" +
-	"UpdateTime\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"UpdateTime\x12<\n" +
-	"\vDeletedTime\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vDeletedTime\x12\x1c\n" +
-	"\tIsDeleted\x18\n" +
-	" \x01(\bR\tIsDeleted\"g\n" +
-	"\x11UpdateFileRequest\x12\x12\n" +
-	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
-	"\x06UserID\x18\x02 \x01(\rR\x06UserID\x12\x12\n" +
-	"\x04Data\x18\x03 \x01(\fR\x04Data\x12\x12\n" +
-	"\x04Size\x18\x04 \x01(\x03R\x04Size\"_\n" +
-	"\x15UpdateFilenameRequest\x12\x12\n" +
-	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
-	"\x06UserID\x18\x02 \x01(\rR\x06UserID\x12\x1a\n" +
-	"\bFilename\x18\x03 \x01(\tR\bFilename\"?\n" +
-	"\x11DeleteFileRequest\x12\x12\n" +
-	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
-	"\x06UserID\x18\x02 \x01(\rR\x06UserID2\xf6\x03\n" +
-	"\x04File\x12A\n" +
-	"\n" +
-	"UploadFile\x12\x1b.protobuf.UploadFileRequest\x1a\x16.protobuf.FileMetadata\x12M\n" +
-	"\fGetFilesList\x12\x1d.protobuf.GetFilesListRequest\x1a\x1e.protobuf.GetFilesListResponse\x12>\n" +
-	"\aGetFile\x12\x18.protobuf.GetFileRequest\x1a\x19.protobuf.GetFileResponse\x12K\n" +
-	"\x0fGetFileMetadata\x12 .protobuf.GetFileMetadataRequest\x1a\x16.protobuf.FileMetadata\x12A\n" +
-	"\n" +
-	"UpdateFile\x12\x1b.protobuf.UpdateFileRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
-	"\x0eUpdateFilename\x12\x1f.protobuf.UpdateFilenameRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+func (x *StartPurgeRequest) Reset() {
+	*x = StartPurgeRequest{}
+	mi := &file_file_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartPurgeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartPurgeRequest) ProtoMessage() {}
+
+func (x *StartPurgeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartPurgeRequest.ProtoReflect.Descriptor instead.
+func (*StartPurgeRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StartPurgeRequest) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+type GetPurgeJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPurgeJobRequest) Reset() {
+	*x = GetPurgeJobRequest{}
+	mi := &file_file_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPurgeJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPurgeJobRequest) ProtoMessage() {}
+
+func (x *GetPurgeJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPurgeJobRequest.ProtoReflect.Descriptor instead.
+func (*GetPurgeJobRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetPurgeJobRequest) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+type PurgeJob struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ID             string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	OwnerID        uint32                 `protobuf:"varint,2,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	DeletedObjects int64                  `protobuf:"varint,4,opt,name=DeletedObjects,proto3" json:"DeletedObjects,omitempty"`
+	Error          string                 `protobuf:"bytes,5,opt,name=Error,proto3" json:"Error,omitempty"`
+	CreateTime     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=CreateTime,proto3" json:"CreateTime,omitempty"`
+	UpdateTime     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=UpdateTime,proto3" json:"UpdateTime,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PurgeJob) Reset() {
+	*x = PurgeJob{}
+	mi := &file_file_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeJob) ProtoMessage() {}
+
+func (x *PurgeJob) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeJob.ProtoReflect.Descriptor instead.
+func (*PurgeJob) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PurgeJob) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *PurgeJob) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *PurgeJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PurgeJob) GetDeletedObjects() int64 {
+	if x != nil {
+		return x.DeletedObjects
+	}
+	return 0
+}
+
+func (x *PurgeJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PurgeJob) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *PurgeJob) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+type StartExportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=Email,proto3" json:"Email,omitempty"`
+	Account       []byte                 `protobuf:"bytes,3,opt,name=Account,proto3" json:"Account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartExportRequest) Reset() {
+	*x = StartExportRequest{}
+	mi := &file_file_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartExportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartExportRequest) ProtoMessage() {}
+
+func (x *StartExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartExportRequest.ProtoReflect.Descriptor instead.
+func (*StartExportRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StartExportRequest) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *StartExportRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *StartExportRequest) GetAccount() []byte {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type GetExportJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID        uint32                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExportJobRequest) Reset() {
+	*x = GetExportJobRequest{}
+	mi := &file_file_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExportJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExportJobRequest) ProtoMessage() {}
+
+func (x *GetExportJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExportJobRequest.ProtoReflect.Descriptor instead.
+func (*GetExportJobRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetExportJobRequest) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *GetExportJobRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type ExportJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	OwnerID       uint32                 `protobuf:"varint,2,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=Size,proto3" json:"Size,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=Error,proto3" json:"Error,omitempty"`
+	DownloadURL   string                 `protobuf:"bytes,6,opt,name=DownloadURL,proto3" json:"DownloadURL,omitempty"`
+	CreateTime    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=CreateTime,proto3" json:"CreateTime,omitempty"`
+	UpdateTime    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=UpdateTime,proto3" json:"UpdateTime,omitempty"`
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=ExpireTime,proto3" json:"ExpireTime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportJob) Reset() {
+	*x = ExportJob{}
+	mi := &file_file_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportJob) ProtoMessage() {}
+
+func (x *ExportJob) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportJob.ProtoReflect.Descriptor instead.
+func (*ExportJob) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportJob) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *ExportJob) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *ExportJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ExportJob) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ExportJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ExportJob) GetDownloadURL() string {
+	if x != nil {
+		return x.DownloadURL
+	}
+	return ""
+}
+
+func (x *ExportJob) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *ExportJob) GetUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return nil
+}
+
+func (x *ExportJob) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+type StorageUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,2,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageUsageRequest) Reset() {
+	*x = StorageUsageRequest{}
+	mi := &file_file_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageUsageRequest) ProtoMessage() {}
+
+func (x *StorageUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageUsageRequest.ProtoReflect.Descriptor instead.
+func (*StorageUsageRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StorageUsageRequest) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *StorageUsageRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+type StorageUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	UsedBytes     int64                  `protobuf:"varint,2,opt,name=UsedBytes,proto3" json:"UsedBytes,omitempty"`
+	FilesCount    int64                  `protobuf:"varint,3,opt,name=FilesCount,proto3" json:"FilesCount,omitempty"`
+	QuotaBytes    int64                  `protobuf:"varint,4,opt,name=QuotaBytes,proto3" json:"QuotaBytes,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,5,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageUsage) Reset() {
+	*x = StorageUsage{}
+	mi := &file_file_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageUsage) ProtoMessage() {}
+
+func (x *StorageUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageUsage.ProtoReflect.Descriptor instead.
+func (*StorageUsage) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *StorageUsage) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *StorageUsage) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *StorageUsage) GetFilesCount() int64 {
+	if x != nil {
+		return x.FilesCount
+	}
+	return 0
+}
+
+func (x *StorageUsage) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
+func (x *StorageUsage) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+type SetQuotaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	QuotaBytes    int64                  `protobuf:"varint,2,opt,name=QuotaBytes,proto3" json:"QuotaBytes,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,3,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetQuotaRequest) Reset() {
+	*x = SetQuotaRequest{}
+	mi := &file_file_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetQuotaRequest) ProtoMessage() {}
+
+func (x *SetQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetQuotaRequest) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *SetQuotaRequest) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
+func (x *SetQuotaRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+type ForceDeleteFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UUID          string                 `protobuf:"bytes,1,opt,name=UUID,proto3" json:"UUID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceDeleteFileRequest) Reset() {
+	*x = ForceDeleteFileRequest{}
+	mi := &file_file_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceDeleteFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceDeleteFileRequest) ProtoMessage() {}
+
+func (x *ForceDeleteFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceDeleteFileRequest.ProtoReflect.Descriptor instead.
+func (*ForceDeleteFileRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ForceDeleteFileRequest) GetUUID() string {
+	if x != nil {
+		return x.UUID
+	}
+	return ""
+}
+
+type StartUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerID       uint32                 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	OrgID         uint32                 `protobuf:"varint,2,opt,name=OrgID,proto3" json:"OrgID,omitempty"`
+	Filename      string                 `protobuf:"bytes,3,opt,name=Filename,proto3" json:"Filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,4,opt,name=ContentType,proto3" json:"ContentType,omitempty"`
+	Size          int64                  `protobuf:"varint,5,opt,name=Size,proto3" json:"Size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartUploadRequest) Reset() {
+	*x = StartUploadRequest{}
+	mi := &file_file_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartUploadRequest) ProtoMessage() {}
+
+func (x *StartUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartUploadRequest.ProtoReflect.Descriptor instead.
+func (*StartUploadRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StartUploadRequest) GetOwnerID() uint32 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *StartUploadRequest) GetOrgID() uint32 {
+	if x != nil {
+		return x.OrgID
+	}
+	return 0
+}
+
+func (x *StartUploadRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *StartUploadRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *StartUploadRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type PresignedUpload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileMetadata          `protobuf:"bytes,1,opt,name=File,proto3" json:"File,omitempty"`
+	UploadURL     string                 `protobuf:"bytes,2,opt,name=UploadURL,proto3" json:"UploadURL,omitempty"`
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=ExpireTime,proto3" json:"ExpireTime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresignedUpload) Reset() {
+	*x = PresignedUpload{}
+	mi := &file_file_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PresignedUpload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresignedUpload) ProtoMessage() {}
+
+func (x *PresignedUpload) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresignedUpload.ProtoReflect.Descriptor instead.
+func (*PresignedUpload) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PresignedUpload) GetFile() *FileMetadata {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *PresignedUpload) GetUploadURL() string {
+	if x != nil {
+		return x.UploadURL
+	}
+	return ""
+}
+
+func (x *PresignedUpload) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+type FinishUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UUID          string                 `protobuf:"bytes,1,opt,name=UUID,proto3" json:"UUID,omitempty"`
+	UserID        uint32                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	Checksum      string                 `protobuf:"bytes,3,opt,name=Checksum,proto3" json:"Checksum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FinishUploadRequest) Reset() {
+	*x = FinishUploadRequest{}
+	mi := &file_file_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinishUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinishUploadRequest) ProtoMessage() {}
+
+func (x *FinishUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinishUploadRequest.ProtoReflect.Descriptor instead.
+func (*FinishUploadRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *FinishUploadRequest) GetUUID() string {
+	if x != nil {
+		return x.UUID
+	}
+	return ""
+}
+
+func (x *FinishUploadRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *FinishUploadRequest) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+type GetDownloadURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UUID          string                 `protobuf:"bytes,1,opt,name=UUID,proto3" json:"UUID,omitempty"`
+	UserID        uint32                 `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDownloadURLRequest) Reset() {
+	*x = GetDownloadURLRequest{}
+	mi := &file_file_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDownloadURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDownloadURLRequest) ProtoMessage() {}
+
+func (x *GetDownloadURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDownloadURLRequest.ProtoReflect.Descriptor instead.
+func (*GetDownloadURLRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetDownloadURLRequest) GetUUID() string {
+	if x != nil {
+		return x.UUID
+	}
+	return ""
+}
+
+func (x *GetDownloadURLRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type DownloadURL struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	URL           string                 `protobuf:"bytes,1,opt,name=URL,proto3" json:"URL,omitempty"`
+	ExpireTime    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=ExpireTime,proto3" json:"ExpireTime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadURL) Reset() {
+	*x = DownloadURL{}
+	mi := &file_file_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadURL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadURL) ProtoMessage() {}
+
+func (x *DownloadURL) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadURL.ProtoReflect.Descriptor instead.
+func (*DownloadURL) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DownloadURL) GetURL() string {
+	if x != nil {
+		return x.URL
+	}
+	return ""
+}
+
+func (x *DownloadURL) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+type PutUploadPartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        uint32                 `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	UploadID      string                 `protobuf:"bytes,2,opt,name=UploadID,proto3" json:"UploadID,omitempty"`
+	Number        int32                  `protobuf:"varint,3,opt,name=Number,proto3" json:"Number,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutUploadPartRequest) Reset() {
+	*x = PutUploadPartRequest{}
+	mi := &file_file_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutUploadPartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutUploadPartRequest) ProtoMessage() {}
+
+func (x *PutUploadPartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutUploadPartRequest.ProtoReflect.Descriptor instead.
+func (*PutUploadPartRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PutUploadPartRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *PutUploadPartRequest) GetUploadID() string {
+	if x != nil {
+		return x.UploadID
+	}
+	return ""
+}
+
+func (x *PutUploadPartRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *PutUploadPartRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GetUploadPartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        uint32                 `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	UploadID      string                 `protobuf:"bytes,2,opt,name=UploadID,proto3" json:"UploadID,omitempty"`
+	Number        int32                  `protobuf:"varint,3,opt,name=Number,proto3" json:"Number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUploadPartRequest) Reset() {
+	*x = GetUploadPartRequest{}
+	mi := &file_file_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUploadPartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUploadPartRequest) ProtoMessage() {}
+
+func (x *GetUploadPartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUploadPartRequest.ProtoReflect.Descriptor instead.
+func (*GetUploadPartRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetUploadPartRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *GetUploadPartRequest) GetUploadID() string {
+	if x != nil {
+		return x.UploadID
+	}
+	return ""
+}
+
+func (x *GetUploadPartRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type UploadPart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadPart) Reset() {
+	*x = UploadPart{}
+	mi := &file_file_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadPart) ProtoMessage() {}
+
+func (x *UploadPart) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadPart.ProtoReflect.Descriptor instead.
+func (*UploadPart) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UploadPart) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type DeleteUploadPartsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        uint32                 `protobuf:"varint,1,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	UploadID      string                 `protobuf:"bytes,2,opt,name=UploadID,proto3" json:"UploadID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUploadPartsRequest) Reset() {
+	*x = DeleteUploadPartsRequest{}
+	mi := &file_file_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUploadPartsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUploadPartsRequest) ProtoMessage() {}
+
+func (x *DeleteUploadPartsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_file_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUploadPartsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUploadPartsRequest) Descriptor() ([]byte, []int) {
+	return file_file_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeleteUploadPartsRequest) GetUserID() uint32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *DeleteUploadPartsRequest) GetUploadID() string {
+	if x != nil {
+		return x.UploadID
+	}
+	return ""
+}
+
+var File_file_proto protoreflect.FileDescriptor
+
+const file_file_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"file.proto\x12\bprotobuf\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa9\x01\n" +
+	"\x11UploadFileRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x1a\n" +
+	"\bFilename\x18\x02 \x01(\tR\bFilename\x12\x12\n" +
+	"\x04Data\x18\x03 \x01(\fR\x04Data\x12 \n" +
+	"\vContentType\x18\x04 \x01(\tR\vContentType\x12\x12\n" +
+	"\x04Size\x18\x05 \x01(\x03R\x04Size\x12\x14\n" +
+	"\x05OrgID\x18\x06 \x01(\rR\x05OrgID\"\xb1\x01\n" +
+	"\x13GetFilesListRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x14\n" +
+	"\x05Limit\x18\x02 \x01(\rR\x05Limit\x12\x16\n" +
+	"\x06Offset\x18\x03 \x01(\rR\x06Offset\x12 \n" +
+	"\vWithDeleted\x18\x04 \x01(\bR\vWithDeleted\x12\x14\n" +
+	"\x05OrgID\x18\x05 \x01(\rR\x05OrgID\x12\x1a\n" +
+	"\bFilename\x18\x06 \x01(\tR\bFilename\"D\n" +
+	"\x14GetFilesListResponse\x12,\n" +
+	"\x05files\x18\x01 \x03(\v2\x16.protobuf.FileMetadataR\x05files\"l\n" +
+	"\x0eGetFileRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\x12\x16\n" +
+	"\x06Offset\x18\x03 \x01(\x03R\x06Offset\x12\x16\n" +
+	"\x06Length\x18\x04 \x01(\x03R\x06Length\"w\n" +
+	"\x0fGetFileResponse\x12\x1a\n" +
+	"\bFilename\x18\x01 \x01(\tR\bFilename\x12 \n" +
+	"\vContentType\x18\x02 \x01(\tR\vContentType\x12\x12\n" +
+	"\x04Data\x18\x03 \x01(\fR\x04Data\x12\x12\n" +
+	"\x04Size\x18\x04 \x01(\x03R\x04Size\"D\n" +
+	"\x16GetFileMetadataRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"\xb4\x03\n" +
+	"\fFileMetadata\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x18\n" +
+	"\aOwnerID\x18\x02 \x01(\rR\aOwnerID\x12\x1a\n" +
+	"\bFilename\x18\x03 \x01(\tR\bFilename\x12 \n" +
+	"\vContentType\x18\x04 \x01(\tR\vContentType\x12\x12\n" +
+	"\x04Size\x18\x05 \x01(\x03R\x04Size\x12\x1e\n" +
+	"\n" +
+	"StorageKey\x18\x06 \x01(\tR\n" +
+	"StorageKey\x12:\n" +
+	"\n" +
+	"UploadTime\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"UploadTime\x12:\n" +
+	"\n" +
+	"UpdateTime\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"UpdateTime\x12<\n" +
+	"\vDeletedTime\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vDeletedTime\x12\x1c\n" +
+	"\tIsDeleted\x18\n" +
+	" \x01(\bR\tIsDeleted\x12\x14\n" +
+	"\x05OrgID\x18\v \x01(\rR\x05OrgID\x12\x1a\n" +
+	"\bChecksum\x18\f \x01(\tR\bChecksum\"g\n" +
+	"\x11UpdateFileRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\x12\x12\n" +
+	"\x04Data\x18\x03 \x01(\fR\x04Data\x12\x12\n" +
+	"\x04Size\x18\x04 \x01(\x03R\x04Size\"_\n" +
+	"\x15UpdateFilenameRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\x12\x1a\n" +
+	"\bFilename\x18\x03 \x01(\tR\bFilename\"?\n" +
+	"\x11DeleteFileRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"-\n" +
+	"\x11StartPurgeRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\"$\n" +
+	"\x12GetPurgeJobRequest\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\"\x82\x02\n" +
+	"\bPurgeJob\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x18\n" +
+	"\aOwnerID\x18\x02 \x01(\rR\aOwnerID\x12\x16\n" +
+	"\x06Status\x18\x03 \x01(\tR\x06Status\x12&\n" +
+	"\x0eDeletedObjects\x18\x04 \x01(\x03R\x0eDeletedObjects\x12\x14\n" +
+	"\x05Error\x18\x05 \x01(\tR\x05Error\x12:\n" +
+	"\n" +
+	"CreateTime\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"CreateTime\x12:\n" +
+	"\n" +
+	"UpdateTime\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"UpdateTime\"^\n" +
+	"\x12StartExportRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x14\n" +
+	"\x05Email\x18\x02 \x01(\tR\x05Email\x12\x18\n" +
+	"\aAccount\x18\x03 \x01(\fR\aAccount\"=\n" +
+	"\x13GetExportJobRequest\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"\xcd\x02\n" +
+	"\tExportJob\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x18\n" +
+	"\aOwnerID\x18\x02 \x01(\rR\aOwnerID\x12\x16\n" +
+	"\x06Status\x18\x03 \x01(\tR\x06Status\x12\x12\n" +
+	"\x04Size\x18\x04 \x01(\x03R\x04Size\x12\x14\n" +
+	"\x05Error\x18\x05 \x01(\tR\x05Error\x12 \n" +
+	"\vDownloadURL\x18\x06 \x01(\tR\vDownloadURL\x12:\n" +
+	"\n" +
+	"CreateTime\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"CreateTime\x12:\n" +
+	"\n" +
+	"UpdateTime\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"UpdateTime\x12:\n" +
+	"\n" +
+	"ExpireTime\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ExpireTime\"E\n" +
+	"\x13StorageUsageRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x14\n" +
+	"\x05OrgID\x18\x02 \x01(\rR\x05OrgID\"\x9c\x01\n" +
+	"\fStorageUsage\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x1c\n" +
+	"\tUsedBytes\x18\x02 \x01(\x03R\tUsedBytes\x12\x1e\n" +
+	"\n" +
+	"FilesCount\x18\x03 \x01(\x03R\n" +
+	"FilesCount\x12\x1e\n" +
+	"\n" +
+	"QuotaBytes\x18\x04 \x01(\x03R\n" +
+	"QuotaBytes\x12\x14\n" +
+	"\x05OrgID\x18\x05 \x01(\rR\x05OrgID\"a\n" +
+	"\x0fSetQuotaRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x1e\n" +
+	"\n" +
+	"QuotaBytes\x18\x02 \x01(\x03R\n" +
+	"QuotaBytes\x12\x14\n" +
+	"\x05OrgID\x18\x03 \x01(\rR\x05OrgID\",\n" +
+	"\x16ForceDeleteFileRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\"\x96\x01\n" +
+	"\x12StartUploadRequest\x12\x18\n" +
+	"\aOwnerID\x18\x01 \x01(\rR\aOwnerID\x12\x14\n" +
+	"\x05OrgID\x18\x02 \x01(\rR\x05OrgID\x12\x1a\n" +
+	"\bFilename\x18\x03 \x01(\tR\bFilename\x12 \n" +
+	"\vContentType\x18\x04 \x01(\tR\vContentType\x12\x12\n" +
+	"\x04Size\x18\x05 \x01(\x03R\x04Size\"\x97\x01\n" +
+	"\x0fPresignedUpload\x12*\n" +
+	"\x04File\x18\x01 \x01(\v2\x16.protobuf.FileMetadataR\x04File\x12\x1c\n" +
+	"\tUploadURL\x18\x02 \x01(\tR\tUploadURL\x12:\n" +
+	"\n" +
+	"ExpireTime\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ExpireTime\"]\n" +
+	"\x13FinishUploadRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\x12\x1a\n" +
+	"\bChecksum\x18\x03 \x01(\tR\bChecksum\"C\n" +
+	"\x15GetDownloadURLRequest\x12\x12\n" +
+	"\x04UUID\x18\x01 \x01(\tR\x04UUID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\rR\x06UserID\"[\n" +
+	"\vDownloadURL\x12\x10\n" +
+	"\x03URL\x18\x01 \x01(\tR\x03URL\x12:\n" +
+	"\n" +
+	"ExpireTime\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ExpireTime\"v\n" +
+	"\x14PutUploadPartRequest\x12\x16\n" +
+	"\x06UserID\x18\x01 \x01(\rR\x06UserID\x12\x1a\n" +
+	"\bUploadID\x18\x02 \x01(\tR\bUploadID\x12\x16\n" +
+	"\x06Number\x18\x03 \x01(\x05R\x06Number\x12\x12\n" +
+	"\x04Data\x18\x04 \x01(\fR\x04Data\"b\n" +
+	"\x14GetUploadPartRequest\x12\x16\n" +
+	"\x06UserID\x18\x01 \x01(\rR\x06UserID\x12\x1a\n" +
+	"\bUploadID\x18\x02 \x01(\tR\bUploadID\x12\x16\n" +
+	"\x06Number\x18\x03 \x01(\x05R\x06Number\" \n" +
+	"\n" +
+	"UploadPart\x12\x12\n" +
+	"\x04Data\x18\x01 \x01(\fR\x04Data\"N\n" +
+	"\x18DeleteUploadPartsRequest\x12\x16\n" +
+	"\x06UserID\x18\x01 \x01(\rR\x06UserID\x12\x1a\n" +
+	"\bUploadID\x18\x02 \x01(\tR\bUploadID2\xcf\v\n" +
+	"\x04File\x12A\n" +
+	"\n" +
+	"UploadFile\x12\x1b.protobuf.UploadFileRequest\x1a\x16.protobuf.FileMetadata\x12M\n" +
+	"\fGetFilesList\x12\x1d.protobuf.GetFilesListRequest\x1a\x1e.protobuf.GetFilesListResponse\x12>\n" +
+	"\aGetFile\x12\x18.protobuf.GetFileRequest\x1a\x19.protobuf.GetFileResponse\x12K\n" +
+	"\x0fGetFileMetadata\x12 .protobuf.GetFileMetadataRequest\x1a\x16.protobuf.FileMetadata\x12A\n" +
+	"\n" +
+	"UpdateFile\x12\x1b.protobuf.UpdateFileRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
+	"\x0eUpdateFilename\x12\x1f.protobuf.UpdateFilenameRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\n" +
+	"DeleteFile\x12\x1b.protobuf.DeleteFileRequest\x1a\x16.google.protobuf.Empty\x12=\n" +
 	"\n" +
-	"DeleteFile\x12\x1b.protobuf.DeleteFileRequest\x1a\x16.google.protobuf.EmptyBOZMgithub.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf;protobufb\x06proto3"
+	"StartPurge\x12\x1b.protobuf.StartPurgeRequest\x1a\x12.protobuf.PurgeJob\x12?\n" +
+	"\vGetPurgeJob\x12\x1c.protobuf.GetPurgeJobRequest\x1a\x12.protobuf.PurgeJob\x12@\n" +
+	"\vStartExport\x12\x1c.protobuf.StartExportRequest\x1a\x13.protobuf.ExportJob\x12B\n" +
+	"\fGetExportJob\x12\x1d.protobuf.GetExportJobRequest\x1a\x13.protobuf.ExportJob\x12H\n" +
+	"\x0fGetStorageUsage\x12\x1d.protobuf.StorageUsageRequest\x1a\x16.protobuf.StorageUsage\x12=\n" +
+	"\bSetQuota\x12\x19.protobuf.SetQuotaRequest\x1a\x16.protobuf.StorageUsage\x12K\n" +
+	"\x0fForceDeleteFile\x12 .protobuf.ForceDeleteFileRequest\x1a\x16.protobuf.FileMetadata\x12F\n" +
+	"\vStartUpload\x12\x1c.protobuf.StartUploadRequest\x1a\x19.protobuf.PresignedUpload\x12E\n" +
+	"\fFinishUpload\x12\x1d.protobuf.FinishUploadRequest\x1a\x16.protobuf.FileMetadata\x12H\n" +
+	"\x0eGetDownloadURL\x12\x1f.protobuf.GetDownloadURLRequest\x1a\x15.protobuf.DownloadURL\x12G\n" +
+	"\rPutUploadPart\x12\x1e.protobuf.PutUploadPartRequest\x1a\x16.google.protobuf.Empty\x12E\n" +
+	"\rGetUploadPart\x12\x1e.protobuf.GetUploadPartRequest\x1a\x14.protobuf.UploadPart\x12O\n" +
+	"\x11DeleteUploadParts\x12\".protobuf.DeleteUploadPartsRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\bReadFile\x12\x18.protobuf.GetFileRequest\x1a\x19.protobuf.GetFileResponse0\x01BOZMgithub.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf;protobufb\x06proto3"
 
 var (
 	file_file_proto_rawDescOnce sync.Once
@@ -765,45 +2098,100 @@ func file_file_proto_rawDescGZIP() []byte {
 	return file_file_proto_rawDescData
 }
 
-var file_file_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_file_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_file_proto_goTypes = []any{
-	(*UploadFileRequest)(nil),      // 0: protobuf.UploadFileRequest
-	(*GetFilesListRequest)(nil),    // 1: protobuf.GetFilesListRequest
-	(*GetFilesListResponse)(nil),   // 2: protobuf.GetFilesListResponse
-	(*GetFileRequest)(nil),         // 3: protobuf.GetFileRequest
-	(*GetFileResponse)(nil),        // 4: protobuf.GetFileResponse
-	(*GetFileMetadataRequest)(nil), // 5: protobuf.GetFileMetadataRequest
-	(*FileMetadata)(nil),           // 6: protobuf.FileMetadata
-	(*UpdateFileRequest)(nil),      // 7: protobuf.UpdateFileRequest
-	(*UpdateFilenameRequest)(nil),  // 8: protobuf.UpdateFilenameRequest
-	(*DeleteFileRequest)(nil),      // 9: protobuf.DeleteFileRequest
-	(*timestamppb.Timestamp)(nil),  // 10: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),          // 11: google.protobuf.Empty
+	(*UploadFileRequest)(nil),        // 0: protobuf.UploadFileRequest
+	(*GetFilesListRequest)(nil),      // 1: protobuf.GetFilesListRequest
+	(*GetFilesListResponse)(nil),     // 2: protobuf.GetFilesListResponse
+	(*GetFileRequest)(nil),           // 3: protobuf.GetFileRequest
+	(*GetFileResponse)(nil),          // 4: protobuf.GetFileResponse
+	(*GetFileMetadataRequest)(nil),   // 5: protobuf.GetFileMetadataRequest
+	(*FileMetadata)(nil),             // 6: protobuf.FileMetadata
+	(*UpdateFileRequest)(nil),        // 7: protobuf.UpdateFileRequest
+	(*UpdateFilenameRequest)(nil),    // 8: protobuf.UpdateFilenameRequest
+	(*DeleteFileRequest)(nil),        // 9: protobuf.DeleteFileRequest
+	(*StartPurgeRequest)(nil),        // 10: protobuf.StartPurgeRequest
+	(*GetPurgeJobRequest)(nil),       // 11: protobuf.GetPurgeJobRequest
+	(*PurgeJob)(nil),                 // 12: protobuf.PurgeJob
+	(*StartExportRequest)(nil),       // 13: protobuf.StartExportRequest
+	(*GetExportJobRequest)(nil),      // 14: protobuf.GetExportJobRequest
+	(*ExportJob)(nil),                // 15: protobuf.ExportJob
+	(*StorageUsageRequest)(nil),      // 16: protobuf.StorageUsageRequest
+	(*StorageUsage)(nil),             // 17: protobuf.StorageUsage
+	(*SetQuotaRequest)(nil),          // 18: protobuf.SetQuotaRequest
+	(*ForceDeleteFileRequest)(nil),   // 19: protobuf.ForceDeleteFileRequest
+	(*StartUploadRequest)(nil),       // 20: protobuf.StartUploadRequest
+	(*PresignedUpload)(nil),          // 21: protobuf.PresignedUpload
+	(*FinishUploadRequest)(nil),      // 22: protobuf.FinishUploadRequest
+	(*GetDownloadURLRequest)(nil),    // 23: protobuf.GetDownloadURLRequest
+	(*DownloadURL)(nil),              // 24: protobuf.DownloadURL
+	(*PutUploadPartRequest)(nil),     // 25: protobuf.PutUploadPartRequest
+	(*GetUploadPartRequest)(nil),     // 26: protobuf.GetUploadPartRequest
+	(*UploadPart)(nil),               // 27: protobuf.UploadPart
+	(*DeleteUploadPartsRequest)(nil), // 28: protobuf.DeleteUploadPartsRequest
+	(*timestamppb.Timestamp)(nil),    // 29: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),            // 30: google.protobuf.Empty
 }
 var file_file_proto_depIdxs = []int32{
 	6,  // 0: protobuf.GetFilesListResponse.files:type_name -> protobuf.FileMetadata
-	10, // 1: protobuf.FileMetadata.UploadTime:type_name -> google.protobuf.Timestamp
-	10, // 2: protobuf.FileMetadata.UpdateTime:type_name -> google.protobuf.Timestamp
-	10, // 3: protobuf.FileMetadata.DeletedTime:type_name -> google.protobuf.Timestamp
-	0,  // 4: protobuf.File.UploadFile:input_type -> protobuf.UploadFileRequest
-	1,  // 5: protobuf.File.GetFilesList:input_type -> protobuf.GetFilesListRequest
-	3,  // 6: protobuf.File.GetFile:input_type -> protobuf.GetFileRequest
-	5,  // 7: protobuf.File.GetFileMetadata:input_type -> protobuf.GetFileMetadataRequest
-	7,  // 8: protobuf.File.UpdateFile:input_type -> protobuf.UpdateFileRequest
-	8,  // 9: protobuf.File.UpdateFilename:input_type -> protobuf.UpdateFilenameRequest
-	9,  // 10: protobuf.File.DeleteFile:input_type -> protobuf.DeleteFileRequest
-	6,  // 11: protobuf.File.UploadFile:output_type -> protobuf.FileMetadata
-	2,  // 12: protobuf.File.GetFilesList:output_type -> protobuf.GetFilesListResponse
-	4,  // 13: protobuf.File.GetFile:output_type -> protobuf.GetFileResponse
-	6,  // 14: protobuf.File.GetFileMetadata:output_type -> protobuf.FileMetadata
-	11, // 15: protobuf.File.UpdateFile:output_type -> google.protobuf.Empty
-	11, // 16: protobuf.File.UpdateFilename:output_type -> google.protobuf.Empty
-	11, // 17: protobuf.File.DeleteFile:output_type -> google.protobuf.Empty
-	11, // [11:18] is the sub-list for method output_type
-	4,  // [4:11] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	29, // 1: protobuf.FileMetadata.UploadTime:type_name -> google.protobuf.Timestamp
+	29, // 2: protobuf.FileMetadata.UpdateTime:type_name -> google.protobuf.Timestamp
+	29, // 3: protobuf.FileMetadata.DeletedTime:type_name -> google.protobuf.Timestamp
+	29, // 4: protobuf.PurgeJob.CreateTime:type_name -> google.protobuf.Timestamp
+	29, // 5: protobuf.PurgeJob.UpdateTime:type_name -> google.protobuf.Timestamp
+	29, // 6: protobuf.ExportJob.CreateTime:type_name -> google.protobuf.Timestamp
+	29, // 7: protobuf.ExportJob.UpdateTime:type_name -> google.protobuf.Timestamp
+	29, // 8: protobuf.ExportJob.ExpireTime:type_name -> google.protobuf.Timestamp
+	6,  // 9: protobuf.PresignedUpload.File:type_name -> protobuf.FileMetadata
+	29, // 10: protobuf.PresignedUpload.ExpireTime:type_name -> google.protobuf.Timestamp
+	29, // 11: protobuf.DownloadURL.ExpireTime:type_name -> google.protobuf.Timestamp
+	0,  // 12: protobuf.File.UploadFile:input_type -> protobuf.UploadFileRequest
+	1,  // 13: protobuf.File.GetFilesList:input_type -> protobuf.GetFilesListRequest
+	3,  // 14: protobuf.File.GetFile:input_type -> protobuf.GetFileRequest
+	5,  // 15: protobuf.File.GetFileMetadata:input_type -> protobuf.GetFileMetadataRequest
+	7,  // 16: protobuf.File.UpdateFile:input_type -> protobuf.UpdateFileRequest
+	8,  // 17: protobuf.File.UpdateFilename:input_type -> protobuf.UpdateFilenameRequest
+	9,  // 18: protobuf.File.DeleteFile:input_type -> protobuf.DeleteFileRequest
+	10, // 19: protobuf.File.StartPurge:input_type -> protobuf.StartPurgeRequest
+	11, // 20: protobuf.File.GetPurgeJob:input_type -> protobuf.GetPurgeJobRequest
+	13, // 21: protobuf.File.StartExport:input_type -> protobuf.StartExportRequest
+	14, // 22: protobuf.File.GetExportJob:input_type -> protobuf.GetExportJobRequest
+	16, // 23: protobuf.File.GetStorageUsage:input_type -> protobuf.StorageUsageRequest
+	18, // 24: protobuf.File.SetQuota:input_type -> protobuf.SetQuotaRequest
+	19, // 25: protobuf.File.ForceDeleteFile:input_type -> protobuf.ForceDeleteFileRequest
+	20, // 26: protobuf.File.StartUpload:input_type -> protobuf.StartUploadRequest
+	22, // 27: protobuf.File.FinishUpload:input_type -> protobuf.FinishUploadRequest
+	23, // 28: protobuf.File.GetDownloadURL:input_type -> protobuf.GetDownloadURLRequest
+	25, // 29: protobuf.File.PutUploadPart:input_type -> protobuf.PutUploadPartRequest
+	26, // 30: protobuf.File.GetUploadPart:input_type -> protobuf.GetUploadPartRequest
+	28, // 31: protobuf.File.DeleteUploadParts:input_type -> protobuf.DeleteUploadPartsRequest
+	3,  // 32: protobuf.File.ReadFile:input_type -> protobuf.GetFileRequest
+	6,  // 33: protobuf.File.UploadFile:output_type -> protobuf.FileMetadata
+	2,  // 34: protobuf.File.GetFilesList:output_type -> protobuf.GetFilesListResponse
+	4,  // 35: protobuf.File.GetFile:output_type -> protobuf.GetFileResponse
+	6,  // 36: protobuf.File.GetFileMetadata:output_type -> protobuf.FileMetadata
+	30, // 37: protobuf.File.UpdateFile:output_type -> google.protobuf.Empty
+	30, // 38: protobuf.File.UpdateFilename:output_type -> google.protobuf.Empty
+	30, // 39: protobuf.File.DeleteFile:output_type -> google.protobuf.Empty
+	12, // 40: protobuf.File.StartPurge:output_type -> protobuf.PurgeJob
+	12, // 41: protobuf.File.GetPurgeJob:output_type -> protobuf.PurgeJob
+	15, // 42: protobuf.File.StartExport:output_type -> protobuf.ExportJob
+	15, // 43: protobuf.File.GetExportJob:output_type -> protobuf.ExportJob
+	17, // 44: protobuf.File.GetStorageUsage:output_type -> protobuf.StorageUsage
+	17, // 45: protobuf.File.SetQuota:output_type -> protobuf.StorageUsage
+	6,  // 46: protobuf.File.ForceDeleteFile:output_type -> protobuf.FileMetadata
+	21, // 47: protobuf.File.StartUpload:output_type -> protobuf.PresignedUpload
+	6,  // 48: protobuf.File.FinishUpload:output_type -> protobuf.FileMetadata
+	24, // 49: protobuf.File.GetDownloadURL:output_type -> protobuf.DownloadURL
+	30, // 50: protobuf.File.PutUploadPart:output_type -> google.protobuf.Empty
+	27, // 51: protobuf.File.GetUploadPart:output_type -> protobuf.UploadPart
+	30, // 52: protobuf.File.DeleteUploadParts:output_type -> google.protobuf.Empty
+	4,  // 53: protobuf.File.ReadFile:output_type -> protobuf.GetFileResponse
+	33, // [33:54] is the sub-list for method output_type
+	12, // [12:33] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_file_proto_init() }
@@ -817,7 +2205,7 @@ func file_file_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_file_proto_rawDesc), len(file_file_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,13 +22,27 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	File_UploadFile_FullMethodName      = "/protobuf.File/UploadFile"
-	File_GetFilesList_FullMethodName    = "/protobuf.File/GetFilesList"
-	File_GetFile_FullMethodName         = "/protobuf.File/GetFile"
-	File_GetFileMetadata_FullMethodName = "/protobuf.File/GetFileMetadata"
-	File_UpdateFile_FullMethodName      = "/protobuf.File/UpdateFile"
-	File_UpdateFilename_FullMethodName  = "/protobuf.File/UpdateFilename"
-	File_DeleteFile_FullMethodName      = "/protobuf.File/DeleteFile"
+	File_UploadFile_FullMethodName        = "/protobuf.File/UploadFile"
+	File_GetFilesList_FullMethodName      = "/protobuf.File/GetFilesList"
+	File_GetFile_FullMethodName           = "/protobuf.File/GetFile"
+	File_GetFileMetadata_FullMethodName   = "/protobuf.File/GetFileMetadata"
+	File_UpdateFile_FullMethodName        = "/protobuf.File/UpdateFile"
+	File_UpdateFilename_FullMethodName    = "/protobuf.File/UpdateFilename"
+	File_DeleteFile_FullMethodName        = "/protobuf.File/DeleteFile"
+	File_StartPurge_FullMethodName        = "/protobuf.File/StartPurge"
+	File_GetPurgeJob_FullMethodName       = "/protobuf.File/GetPurgeJob"
+	File_StartExport_FullMethodName       = "/protobuf.File/StartExport"
+	File_GetExportJob_FullMethodName      = "/protobuf.File/GetExportJob"
+	File_GetStorageUsage_FullMethodName   = "/protobuf.File/GetStorageUsage"
+	File_SetQuota_FullMethodName          = "/protobuf.File/SetQuota"
+	File_ForceDeleteFile_FullMethodName   = "/protobuf.File/ForceDeleteFile"
+	File_StartUpload_FullMethodName       = "/protobuf.File/StartUpload"
+	File_FinishUpload_FullMethodName      = "/protobuf.File/FinishUpload"
+	File_GetDownloadURL_FullMethodName    = "/protobuf.File/GetDownloadURL"
+	File_PutUploadPart_FullMethodName     = "/protobuf.File/PutUploadPart"
+	File_GetUploadPart_FullMethodName     = "/protobuf.File/GetUploadPart"
+	File_DeleteUploadParts_FullMethodName = "/protobuf.File/DeleteUploadParts"
+	File_ReadFile_FullMethodName          = "/protobuf.File/ReadFile"
 )
 
 // FileClient is the client API for File service.
@@ -42,6 +56,20 @@ type FileClient interface {
 	UpdateFile(ctx context.Context, in *UpdateFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	UpdateFilename(ctx context.Context, in *UpdateFilenameRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	StartPurge(ctx context.Context, in *StartPurgeRequest, opts ...grpc.CallOption) (*PurgeJob, error)
+	GetPurgeJob(ctx context.Context, in *GetPurgeJobRequest, opts ...grpc.CallOption) (*PurgeJob, error)
+	StartExport(ctx context.Context, in *StartExportRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	GetExportJob(ctx context.Context, in *GetExportJobRequest, opts ...grpc.CallOption) (*ExportJob, error)
+	GetStorageUsage(ctx context.Context, in *StorageUsageRequest, opts ...grpc.CallOption) (*StorageUsage, error)
+	SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*StorageUsage, error)
+	ForceDeleteFile(ctx context.Context, in *ForceDeleteFileRequest, opts ...grpc.CallOption) (*FileMetadata, error)
+	StartUpload(ctx context.Context, in *StartUploadRequest, opts ...grpc.CallOption) (*PresignedUpload, error)
+	FinishUpload(ctx context.Context, in *FinishUploadRequest, opts ...grpc.CallOption) (*FileMetadata, error)
+	GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*DownloadURL, error)
+	PutUploadPart(ctx context.Context, in *PutUploadPartRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetUploadPart(ctx context.Context, in *GetUploadPartRequest, opts ...grpc.CallOption) (*UploadPart, error)
+	DeleteUploadParts(ctx context.Context, in *DeleteUploadPartsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ReadFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetFileResponse], error)
 }
 
 type fileClient struct {
@@ -122,6 +150,155 @@ func (c *fileClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts
 	return out, nil
 }
 
+func (c *fileClient) StartPurge(ctx context.Context, in *StartPurgeRequest, opts ...grpc.CallOption) (*PurgeJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeJob)
+	err := c.cc.Invoke(ctx, File_StartPurge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) GetPurgeJob(ctx context.Context, in *GetPurgeJobRequest, opts ...grpc.CallOption) (*PurgeJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeJob)
+	err := c.cc.Invoke(ctx, File_GetPurgeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) StartExport(ctx context.Context, in *StartExportRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, File_StartExport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) GetExportJob(ctx context.Context, in *GetExportJobRequest, opts ...grpc.CallOption) (*ExportJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportJob)
+	err := c.cc.Invoke(ctx, File_GetExportJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) GetStorageUsage(ctx context.Context, in *StorageUsageRequest, opts ...grpc.CallOption) (*StorageUsage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageUsage)
+	err := c.cc.Invoke(ctx, File_GetStorageUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*StorageUsage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageUsage)
+	err := c.cc.Invoke(ctx, File_SetQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) ForceDeleteFile(ctx context.Context, in *ForceDeleteFileRequest, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, File_ForceDeleteFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) StartUpload(ctx context.Context, in *StartUploadRequest, opts ...grpc.CallOption) (*PresignedUpload, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PresignedUpload)
+	err := c.cc.Invoke(ctx, File_StartUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) FinishUpload(ctx context.Context, in *FinishUploadRequest, opts ...grpc.CallOption) (*FileMetadata, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileMetadata)
+	err := c.cc.Invoke(ctx, File_FinishUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*DownloadURL, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DownloadURL)
+	err := c.cc.Invoke(ctx, File_GetDownloadURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) PutUploadPart(ctx context.Context, in *PutUploadPartRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, File_PutUploadPart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) GetUploadPart(ctx context.Context, in *GetUploadPartRequest, opts ...grpc.CallOption) (*UploadPart, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadPart)
+	err := c.cc.Invoke(ctx, File_GetUploadPart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) DeleteUploadParts(ctx context.Context, in *DeleteUploadPartsRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, File_DeleteUploadParts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileClient) ReadFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &File_ServiceDesc.Streams[0], File_ReadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetFileRequest, GetFileResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type File_ReadFileClient = grpc.ServerStreamingClient[GetFileResponse]
+
 // FileServer is the server API for File service.
 // All implementations must embed UnimplementedFileServer
 // for forward compatibility.
@@ -133,6 +310,20 @@ type FileServer interface {
 	UpdateFile(context.Context, *UpdateFileRequest) (*emptypb.Empty, error)
 	UpdateFilename(context.Context, *UpdateFilenameRequest) (*emptypb.Empty, error)
 	DeleteFile(context.Context, *DeleteFileRequest) (*emptypb.Empty, error)
+	StartPurge(context.Context, *StartPurgeRequest) (*PurgeJob, error)
+	GetPurgeJob(context.Context, *GetPurgeJobRequest) (*PurgeJob, error)
+	StartExport(context.Context, *StartExportRequest) (*ExportJob, error)
+	GetExportJob(context.Context, *GetExportJobRequest) (*ExportJob, error)
+	GetStorageUsage(context.Context, *StorageUsageRequest) (*StorageUsage, error)
+	SetQuota(context.Context, *SetQuotaRequest) (*StorageUsage, error)
+	ForceDeleteFile(context.Context, *ForceDeleteFileRequest) (*FileMetadata, error)
+	StartUpload(context.Context, *StartUploadRequest) (*PresignedUpload, error)
+	FinishUpload(context.Context, *FinishUploadRequest) (*FileMetadata, error)
+	GetDownloadURL(context.Context, *GetDownloadURLRequest) (*DownloadURL, error)
+	PutUploadPart(context.Context, *PutUploadPartRequest) (*emptypb.Empty, error)
+	GetUploadPart(context.Context, *GetUploadPartRequest) (*UploadPart, error)
+	DeleteUploadParts(context.Context, *DeleteUploadPartsRequest) (*emptypb.Empty, error)
+	ReadFile(*GetFileRequest, grpc.ServerStreamingServer[GetFileResponse]) error
 	mustEmbedUnimplementedFileServer()
 }
 
@@ -164,6 +355,48 @@ func (UnimplementedFileServer) UpdateFilename(context.Context, *UpdateFilenameRe
 func (UnimplementedFileServer) DeleteFile(context.Context, *DeleteFileRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
 }
+func (UnimplementedFileServer) StartPurge(context.Context, *StartPurgeRequest) (*PurgeJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartPurge not implemented")
+}
+func (UnimplementedFileServer) GetPurgeJob(context.Context, *GetPurgeJobRequest) (*PurgeJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPurgeJob not implemented")
+}
+func (UnimplementedFileServer) StartExport(context.Context, *StartExportRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartExport not implemented")
+}
+func (UnimplementedFileServer) GetExportJob(context.Context, *GetExportJobRequest) (*ExportJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExportJob not implemented")
+}
+func (UnimplementedFileServer) GetStorageUsage(context.Context, *StorageUsageRequest) (*StorageUsage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStorageUsage not implemented")
+}
+func (UnimplementedFileServer) SetQuota(context.Context, *SetQuotaRequest) (*StorageUsage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQuota not implemented")
+}
+func (UnimplementedFileServer) ForceDeleteFile(context.Context, *ForceDeleteFileRequest) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceDeleteFile not implemented")
+}
+func (UnimplementedFileServer) StartUpload(context.Context, *StartUploadRequest) (*PresignedUpload, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartUpload not implemented")
+}
+func (UnimplementedFileServer) FinishUpload(context.Context, *FinishUploadRequest) (*FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishUpload not implemented")
+}
+func (UnimplementedFileServer) GetDownloadURL(context.Context, *GetDownloadURLRequest) (*DownloadURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadURL not implemented")
+}
+func (UnimplementedFileServer) PutUploadPart(context.Context, *PutUploadPartRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutUploadPart not implemented")
+}
+func (UnimplementedFileServer) GetUploadPart(context.Context, *GetUploadPartRequest) (*UploadPart, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadPart not implemented")
+}
+func (UnimplementedFileServer) DeleteUploadParts(context.Context, *DeleteUploadPartsRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUploadParts not implemented")
+}
+func (UnimplementedFileServer) ReadFile(*GetFileRequest, grpc.ServerStreamingServer[GetFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadFile not implemented")
+}
 func (UnimplementedFileServer) mustEmbedUnimplementedFileServer() {}
 func (UnimplementedFileServer) testEmbeddedByValue()              {}
 
@@ -311,6 +544,251 @@ func _File_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _File_StartPurge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartPurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).StartPurge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_StartPurge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).StartPurge(ctx, req.(*StartPurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_GetPurgeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPurgeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).GetPurgeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_GetPurgeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).GetPurgeJob(ctx, req.(*GetPurgeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_StartExport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).StartExport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_StartExport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).StartExport(ctx, req.(*StartExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_GetExportJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExportJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).GetExportJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_GetExportJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).GetExportJob(ctx, req.(*GetExportJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_GetStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).GetStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_GetStorageUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).GetStorageUsage(ctx, req.(*StorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).SetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_SetQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).SetQuota(ctx, req.(*SetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_ForceDeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceDeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).ForceDeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_ForceDeleteFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).ForceDeleteFile(ctx, req.(*ForceDeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_StartUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).StartUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_StartUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).StartUpload(ctx, req.(*StartUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_FinishUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).FinishUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_FinishUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).FinishUpload(ctx, req.(*FinishUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_GetDownloadURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).GetDownloadURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_GetDownloadURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).GetDownloadURL(ctx, req.(*GetDownloadURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_PutUploadPart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutUploadPartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).PutUploadPart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_PutUploadPart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).PutUploadPart(ctx, req.(*PutUploadPartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_GetUploadPart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadPartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).GetUploadPart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_GetUploadPart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).GetUploadPart(ctx, req.(*GetUploadPartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_DeleteUploadParts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUploadPartsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServer).DeleteUploadParts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: File_DeleteUploadParts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServer).DeleteUploadParts(ctx, req.(*DeleteUploadPartsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _File_ReadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServer).ReadFile(m, &grpc.GenericServerStream[GetFileRequest, GetFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type File_ReadFileServer = grpc.ServerStreamingServer[GetFileResponse]
+
 // File_ServiceDesc is the grpc.ServiceDesc for File service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -346,7 +824,65 @@ var File_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteFile",
 			Handler:    _File_DeleteFile_Handler,
 		},
+		{
+			MethodName: "StartPurge",
+			Handler:    _File_StartPurge_Handler,
+		},
+		{
+			MethodName: "GetPurgeJob",
+			Handler:    _File_GetPurgeJob_Handler,
+		},
+		{
+			MethodName: "StartExport",
+			Handler:    _File_StartExport_Handler,
+		},
+		{
+			MethodName: "GetExportJob",
+			Handler:    _File_GetExportJob_Handler,
+		},
+		{
+			MethodName: "GetStorageUsage",
+			Handler:    _File_GetStorageUsage_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _File_SetQuota_Handler,
+		},
+		{
+			MethodName: "ForceDeleteFile",
+			Handler:    _File_ForceDeleteFile_Handler,
+		},
+		{
+			MethodName: "StartUpload",
+			Handler:    _File_StartUpload_Handler,
+		},
+		{
+			MethodName: "FinishUpload",
+			Handler:    _File_FinishUpload_Handler,
+		},
+		{
+			MethodName: "GetDownloadURL",
+			Handler:    _File_GetDownloadURL_Handler,
+		},
+		{
+			MethodName: "PutUploadPart",
+			Handler:    _File_PutUploadPart_Handler,
+		},
+		{
+			MethodName: "GetUploadPart",
+			Handler:    _File_GetUploadPart_Handler,
+		},
+		{
+			MethodName: "DeleteUploadParts",
+			Handler:    _File_DeleteUploadParts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadFile",
+			Handler:       _File_ReadFile_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "file.proto",
 }
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
@@ -32,7 +34,7 @@ func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal error", "error", err)
 		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadForm)
 		return
 	}
@@ -41,7 +43,7 @@ func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 	buf := new(bytes.Buffer)
 	_, err = io.Copy(buf, file)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal error", "error", err)
 		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		return
 	}
@@ -61,6 +63,15 @@ func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 		filename = "Новый файл"
 	}
 
+	var orgID uint64
+	if value := r.URL.Query().Get("org_id"); value != "" {
+		orgID, err = strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidURLParams)
+			return
+		}
+	}
+
 	user := ctx.Value(h.ctxUserKey).(*models.User)
 	userID := user.ID
 
@@ -69,15 +80,21 @@ func (h *FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 		ContentType: contentType,
 		File:        buf.Bytes(),
 		Size:        header.Size,
+		OrgID:       uint(orgID),
 	})
 	if err != nil {
-		if errors.Is(err, models.InvalidInputError) {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
 			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongFilename)
-			return
+		case errors.Is(err, models.PermissionDeniedError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		case errors.Is(err, models.QuotaExceededError):
+			responses.SendErrResponse(w, responses.StatusRequestEntityTooLarge, responses.ErrQuotaExceeded)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
-		log.Println(err)
-		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		return
 	}
 
@@ -99,13 +116,16 @@ func (h *FileHandler) GetFilesList(w http.ResponseWriter, r *http.Request) {
 
 	files, err := h.usecases.GetFilesList(ctx, userID, options)
 	if err != nil {
-		if errors.Is(err, models.InvalidInputError) {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
 			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidURLParams)
-			return
+		case errors.Is(err, models.PermissionDeniedError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
-		log.Println(err)
-		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		return
 	}
 
@@ -120,7 +140,7 @@ func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) {
 	user := ctx.Value(h.ctxUserKey).(*models.User)
 	userID := user.ID
 
-	file, err := h.usecases.GetFile(ctx, userID, id)
+	file, content, err := h.usecases.OpenFile(ctx, userID, id)
 	if err != nil {
 		switch {
 		case errors.Is(err, models.InvalidInputError):
@@ -128,7 +148,7 @@ func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, models.PermissionDeniedError):
 			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
 		default:
-			log.Println(err)
+			slog.ErrorContext(ctx, "Internal error", "error", err)
 			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
@@ -136,15 +156,11 @@ func (h *FileHandler) GetFile(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", file.ContentType)
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", file.Size))
 	w.Header().Set("Content-Disposition",
 		fmt.Sprintf("attachment; filename=%s", file.Filename))
 
-	if _, err := io.Copy(w, bytes.NewReader(file.File)); err != nil {
-		log.Println(err)
-		http.Error(w, responses.ErrInternalServer, responses.StatusInternalServerError)
-		return
-	}
+	// ServeContent answers Range requests, only the requested bytes are fetched from the file service
+	http.ServeContent(w, r, file.Filename, time.Time{}, content)
 }
 
 func (h *FileHandler) GetMetadata(w http.ResponseWriter, r *http.Request) {
@@ -163,7 +179,7 @@ func (h *FileHandler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, models.PermissionDeniedError):
 			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
 		default:
-			log.Println(err)
+			slog.ErrorContext(ctx, "Internal error", "error", err)
 			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
@@ -181,7 +197,7 @@ func (h *FileHandler) UpdateFile(w http.ResponseWriter, r *http.Request) {
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal error", "error", err)
 		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadForm)
 		return
 	}
@@ -190,7 +206,7 @@ func (h *FileHandler) UpdateFile(w http.ResponseWriter, r *http.Request) {
 	buf := new(bytes.Buffer)
 	_, err = io.Copy(buf, file)
 	if err != nil {
-		log.Println(err)
+		slog.ErrorContext(ctx, "Internal error", "error", err)
 		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		return
 	}
@@ -205,8 +221,10 @@ func (h *FileHandler) UpdateFile(w http.ResponseWriter, r *http.Request) {
 			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
 		case errors.Is(err, models.PermissionDeniedError):
 			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		case errors.Is(err, models.QuotaExceededError):
+			responses.SendErrResponse(w, responses.StatusRequestEntityTooLarge, responses.ErrQuotaExceeded)
 		default:
-			log.Println(err)
+			slog.ErrorContext(ctx, "Internal error", "error", err)
 			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
@@ -242,7 +260,7 @@ func (h *FileHandler) UpdateFilename(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, models.PermissionDeniedError):
 			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
 		default:
-			log.Println(err)
+			slog.ErrorContext(ctx, "Internal error", "error", err)
 			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
@@ -273,7 +291,7 @@ func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, models.PermissionDeniedError):
 			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
 		default:
-			log.Println(err)
+			slog.ErrorContext(ctx, "Internal error", "error", err)
 			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
 		}
 
@@ -282,3 +300,149 @@ func (h *FileHandler) DeleteFile(w http.ResponseWriter, r *http.Request) {
 
 	responses.SendOkResponse(w, nil)
 }
+
+func (h *FileHandler) StartExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+
+	job, err := h.usecases.StartExport(ctx, user)
+	if err != nil {
+		slog.ErrorContext(ctx, "Internal error", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+
+		return
+	}
+
+	responses.SendOkResponse(w, job)
+}
+
+func (h *FileHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+	userID := user.ID
+
+	job, err := h.usecases.GetExportJob(ctx, userID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		case errors.Is(err, models.ExportJobNotExists):
+			responses.SendErrResponse(w, responses.StatusNotFound, responses.ErrJobNotFound)
+		case errors.Is(err, models.PermissionDeniedError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, job)
+}
+
+func (h *FileHandler) StartUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var reqData models.StartUploadRequest
+	err := json.NewDecoder(r.Body).Decode(&reqData)
+	if err != nil {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+	userID := user.ID
+
+	upload, err := h.usecases.StartUpload(ctx, userID, &reqData)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidFilenameError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrWrongFilename)
+		case errors.Is(err, models.InvalidInputError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		case errors.Is(err, models.PermissionDeniedError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		case errors.Is(err, models.QuotaExceededError):
+			responses.SendErrResponse(w, responses.StatusRequestEntityTooLarge, responses.ErrQuotaExceeded)
+		case errors.Is(err, models.PresignDisabledError):
+			responses.SendErrResponse(w, responses.StatusNotImplemented, responses.ErrPresignDisabled)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, upload)
+}
+
+func (h *FileHandler) FinishUpload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	// The body is optional, the checksum is not verified without it
+	var reqData models.FinishUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil && !errors.Is(err, io.EOF) {
+		responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrBadJSON)
+		return
+	}
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+	userID := user.ID
+
+	metadata, err := h.usecases.FinishUpload(ctx, userID, id, reqData.Checksum)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		case errors.Is(err, models.PermissionDeniedError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		case errors.Is(err, models.UploadNotFinishedError):
+			responses.SendErrResponse(w, responses.StatusConflict, responses.ErrUploadNotFinished)
+		case errors.Is(err, models.UploadMismatchError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrUploadMismatch)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, metadata)
+}
+
+func (h *FileHandler) GetDownloadURL(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	user := ctx.Value(h.ctxUserKey).(*models.User)
+	userID := user.ID
+
+	downloadURL, err := h.usecases.GetDownloadURL(ctx, userID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.InvalidInputError):
+			responses.SendErrResponse(w, responses.StatusBadRequest, responses.ErrInvalidID)
+		case errors.Is(err, models.PermissionDeniedError):
+			responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+		case errors.Is(err, models.PresignDisabledError):
+			responses.SendErrResponse(w, responses.StatusNotImplemented, responses.ErrPresignDisabled)
+		default:
+			slog.ErrorContext(ctx, "Internal error", "error", err)
+			responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+		}
+
+		return
+	}
+
+	responses.SendOkResponse(w, downloadURL)
+}
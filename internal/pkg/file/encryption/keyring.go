@@ -0,0 +1,225 @@
+// Package encryption implements envelope encryption of stored objects. Every file has its own data key, which
+// encrypts the content and is stored in the metadata wrapped with the master key.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+)
+
+const KeySize = 32
+
+// Keyring wraps data keys with the current master key and unwraps them with any known one, so data keys wrapped
+// with a previous master key stay readable until they are rotated
+type Keyring struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+func NewKeyring(current []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+
+	for i, key := range append([][]byte{current}, previous...) {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+
+		id := keyID(key)
+		if i == 0 {
+			k.currentID = id
+		}
+		k.keys[id] = aead
+	}
+
+	return k, nil
+}
+
+// Load reads the master keys from the config. It returns nil if encryption is disabled.
+func Load(cfg config.EncryptionConfig) (*Keyring, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	encoded := cfg.MasterKey
+	if encoded == "" {
+		if cfg.KeyFile == "" {
+			return nil, fmt.Errorf("master key is not set")
+		}
+
+		data, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(data)
+	}
+
+	current, err := decodeKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous [][]byte
+	for _, path := range cfg.PreviousKeyFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := decodeKey(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		previous = append(previous, key)
+	}
+
+	return NewKeyring(current, previous...)
+}
+
+func (k *Keyring) CurrentKeyID() string {
+	return k.currentID
+}
+
+// GenerateDataKey returns a new data key and its wrapped form, which is bound to the file
+func (k *Keyring) GenerateDataKey(fileID string) ([]byte, *models.DataKey, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, err
+	}
+
+	wrapped, err := k.wrap(fileID, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, wrapped, nil
+}
+
+func (k *Keyring) UnwrapDataKey(wrapped *models.DataKey) ([]byte, error) {
+	aead, ok := k.keys[wrapped.KeyID]
+	if !ok {
+		return nil, models.MasterKeyMissingError
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(wrapped.Key) < nonceSize {
+		return nil, models.CorruptedObjectError
+	}
+
+	key, err := aead.Open(nil, wrapped.Key[:nonceSize], wrapped.Key[nonceSize:], []byte(wrapped.FileID))
+	if err != nil {
+		return nil, models.CorruptedObjectError
+	}
+
+	return key, nil
+}
+
+// Rewrap wraps the data key with the current master key, the content encrypted with it stays valid
+func (k *Keyring) Rewrap(wrapped *models.DataKey) (*models.DataKey, error) {
+	key, err := k.UnwrapDataKey(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.wrap(wrapped.FileID, key)
+}
+
+// Seal encrypts the content with the data key of the file. Files without a data key are stored as is.
+func (k *Keyring) Seal(meta *models.FileMetadata, data []byte) ([]byte, error) {
+	if meta.DataKey == nil {
+		return data, nil
+	}
+
+	key, err := k.dataKey(meta.DataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return Encrypt(key, data)
+}
+
+// OpenReader returns a reader of the content sealed with Seal, which decrypts only the chunks that are read.
+// Plaintext files are readable with a nil keyring as well.
+func (k *Keyring) OpenReader(meta *models.FileMetadata, r io.ReaderAt, size int64) (io.ReaderAt, int64, error) {
+	if meta.DataKey == nil {
+		return r, size, nil
+	}
+
+	key, err := k.dataKey(meta.DataKey)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reader, err := NewReader(key, r, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reader, reader.Size(), nil
+}
+
+func (k *Keyring) dataKey(wrapped *models.DataKey) ([]byte, error) {
+	if k == nil {
+		return nil, models.MasterKeyMissingError
+	}
+
+	return k.UnwrapDataKey(wrapped)
+}
+
+func (k *Keyring) wrap(fileID string, key []byte) (*models.DataKey, error) {
+	aead := k.keys[k.currentID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &models.DataKey{
+		FileID: fileID,
+		Key:    aead.Seal(nonce, nonce, key, []byte(fileID)),
+		KeyID:  k.currentID,
+	}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// keyID identifies the master key without revealing it
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:4])
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("master key is not valid base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
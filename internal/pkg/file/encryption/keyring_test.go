@@ -0,0 +1,132 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring_SealOpen(t *testing.T) {
+	keyring, err := NewKeyring(newKey(t))
+	require.NoError(t, err)
+
+	_, dataKey, err := keyring.GenerateDataKey("file")
+	require.NoError(t, err)
+	assert.Equal(t, keyring.CurrentKeyID(), dataKey.KeyID)
+
+	meta := &models.FileMetadata{UUID: "file", DataKey: dataKey}
+	sealed, err := keyring.Seal(meta, []byte("secret"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "secret")
+
+	opened, err := openAll(keyring, meta, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), opened)
+}
+
+func TestKeyring_Plaintext(t *testing.T) {
+	var keyring *Keyring
+	meta := &models.FileMetadata{UUID: "file"}
+
+	data, err := openAll(keyring, meta, []byte("plain"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plain"), data)
+
+	meta.DataKey = &models.DataKey{FileID: "file", Key: []byte("wrapped"), KeyID: "old"}
+	_, err = openAll(keyring, meta, []byte("sealed"))
+	assert.ErrorIs(t, err, models.MasterKeyMissingError)
+}
+
+func TestKeyring_BoundToFile(t *testing.T) {
+	keyring, err := NewKeyring(newKey(t))
+	require.NoError(t, err)
+
+	_, dataKey, err := keyring.GenerateDataKey("file")
+	require.NoError(t, err)
+
+	dataKey.FileID = "other"
+	_, err = keyring.UnwrapDataKey(dataKey)
+	assert.ErrorIs(t, err, models.CorruptedObjectError)
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	oldMaster, newMaster := newKey(t), newKey(t)
+
+	oldKeyring, err := NewKeyring(oldMaster)
+	require.NoError(t, err)
+	key, dataKey, err := oldKeyring.GenerateDataKey("file")
+	require.NoError(t, err)
+
+	keyring, err := NewKeyring(newMaster, oldMaster)
+	require.NoError(t, err)
+
+	rewrapped, err := keyring.Rewrap(dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, keyring.CurrentKeyID(), rewrapped.KeyID)
+	assert.NotEqual(t, dataKey.KeyID, rewrapped.KeyID)
+
+	// The previous master key is no longer needed
+	newOnly, err := NewKeyring(newMaster)
+	require.NoError(t, err)
+
+	unwrapped, err := newOnly.UnwrapDataKey(rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, key, unwrapped)
+
+	_, err = newOnly.UnwrapDataKey(dataKey)
+	assert.ErrorIs(t, err, models.MasterKeyMissingError)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	current, previous := newKey(t), newKey(t)
+
+	currentFile := filepath.Join(dir, "current.key")
+	previousFile := filepath.Join(dir, "previous.key")
+	require.NoError(t, os.WriteFile(currentFile, []byte(base64.StdEncoding.EncodeToString(current)+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(previousFile, []byte(base64.StdEncoding.EncodeToString(previous)), 0o600))
+
+	keyring, err := Load(config.EncryptionConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, keyring)
+
+	keyring, err = Load(config.EncryptionConfig{
+		Enabled:          true,
+		KeyFile:          currentFile,
+		PreviousKeyFiles: []string{previousFile},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, keyID(current), keyring.CurrentKeyID())
+	assert.Len(t, keyring.keys, 2)
+
+	keyring, err = Load(config.EncryptionConfig{
+		Enabled:   true,
+		MasterKey: base64.StdEncoding.EncodeToString(previous),
+		KeyFile:   currentFile,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, keyID(previous), keyring.CurrentKeyID())
+
+	_, err = Load(config.EncryptionConfig{Enabled: true, MasterKey: "c2hvcnQ="})
+	assert.Error(t, err)
+
+	_, err = Load(config.EncryptionConfig{Enabled: true})
+	assert.Error(t, err)
+}
+
+func openAll(keyring *Keyring, meta *models.FileMetadata, object []byte) ([]byte, error) {
+	reader, size, err := keyring.OpenReader(meta, bytes.NewReader(object), int64(len(object)))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(io.NewSectionReader(reader, 0, size))
+}
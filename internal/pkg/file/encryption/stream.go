@@ -0,0 +1,252 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+)
+
+// Objects start with a header of the magic and the random nonce prefix, followed by the content split into
+// chunks sealed separately, so any range is decrypted without reading the whole object. The chunk index and
+// the mark of the last chunk are authenticated, so chunks cannot be reordered and the object cannot be truncated.
+const (
+	ChunkSize = 64 * 1024
+
+	magic      = "VBE1"
+	nonceSize  = 12
+	tagSize    = 16
+	headerSize = len(magic) + nonceSize
+
+	sealedChunkSize = ChunkSize + tagSize
+)
+
+// EncryptedSize returns the size of the object holding size bytes of content. Empty content still has one chunk.
+func EncryptedSize(size int64) int64 {
+	chunks := max(1, (size+ChunkSize-1)/ChunkSize)
+
+	return int64(headerSize) + size + chunks*tagSize
+}
+
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, EncryptedSize(int64(len(plaintext)))))
+
+	w, err := NewWriter(key, buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func Decrypt(key, ciphertext []byte) ([]byte, error) {
+	r, err := NewReader(key, bytes.NewReader(ciphertext), int64(len(ciphertext)))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, r.Size())
+	if _, err := r.ReadAt(plaintext, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return plaintext, nil
+}
+
+// Writer encrypts the content written to it, Close must be called to write the last chunk
+type Writer struct {
+	aead   cipher.AEAD
+	w      io.Writer
+	prefix []byte
+	buf    []byte
+	index  uint64
+}
+
+func NewWriter(key []byte, w io.Writer) (*Writer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, nonceSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(append([]byte(magic), prefix...)); err != nil {
+		return nil, err
+	}
+
+	return &Writer{aead: aead, w: w, prefix: prefix, buf: make([]byte, 0, ChunkSize)}, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	written := 0
+
+	for len(p) > 0 {
+		// A full chunk is sealed only when more content follows, as the last chunk is sealed differently
+		if len(w.buf) == ChunkSize {
+			if err := w.flush(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(w.buf[len(w.buf):ChunkSize], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (w *Writer) Close() error {
+	return w.flush(true)
+}
+
+func (w *Writer) flush(last bool) error {
+	sealed := w.aead.Seal(nil, chunkNonce(w.prefix, w.index), w.buf, chunkAD(w.index, last))
+	if _, err := w.w.Write(sealed); err != nil {
+		return err
+	}
+
+	w.buf = w.buf[:0]
+	w.index++
+
+	return nil
+}
+
+// Reader decrypts ranges of an encrypted object, reading only the chunks that hold them
+type Reader struct {
+	aead   cipher.AEAD
+	r      io.ReaderAt
+	prefix []byte
+	size   int64
+	chunks int64
+}
+
+func NewReader(key []byte, r io.ReaderAt, encryptedSize int64) (*Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	body := encryptedSize - int64(headerSize)
+	if body < tagSize {
+		return nil, models.CorruptedObjectError
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, models.CorruptedObjectError
+	}
+
+	chunks := (body + sealedChunkSize - 1) / sealedChunkSize
+	if body-(chunks-1)*sealedChunkSize < tagSize {
+		return nil, models.CorruptedObjectError
+	}
+
+	reader := &Reader{
+		aead:   aead,
+		r:      r,
+		prefix: header[len(magic):],
+		size:   body - chunks*tagSize,
+		chunks: chunks,
+	}
+
+	// Empty content is never read, so its only chunk is checked right away
+	if reader.size == 0 {
+		if _, err := reader.readChunk(0, make([]byte, tagSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	return reader, nil
+}
+
+// Size returns the size of the content
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	read := 0
+	sealed := make([]byte, sealedChunkSize)
+
+	for read < len(p) && off < r.size {
+		index := off / ChunkSize
+
+		chunk, err := r.readChunk(index, sealed)
+		if err != nil {
+			return read, err
+		}
+
+		n := copy(p[read:], chunk[off-index*ChunkSize:])
+		read += n
+		off += int64(n)
+	}
+
+	if read < len(p) {
+		return read, io.EOF
+	}
+
+	return read, nil
+}
+
+// readChunk decrypts the chunk in place, buf must be large enough for a sealed chunk
+func (r *Reader) readChunk(index int64, buf []byte) ([]byte, error) {
+	start := int64(headerSize) + index*sealedChunkSize
+	length := min(sealedChunkSize, int64(headerSize)+r.size+r.chunks*tagSize-start)
+
+	if _, err := r.r.ReadAt(buf[:length], start); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	chunk, err := r.aead.Open(buf[:0], chunkNonce(r.prefix, uint64(index)), buf[:length],
+		chunkAD(uint64(index), index == r.chunks-1))
+	if err != nil {
+		return nil, models.CorruptedObjectError
+	}
+
+	return chunk, nil
+}
+
+// chunkNonce mixes the chunk index into the random prefix of the object
+func chunkNonce(prefix []byte, index uint64) []byte {
+	nonce := make([]byte, nonceSize)
+	copy(nonce, prefix)
+
+	counter := binary.BigEndian.Uint64(nonce[nonceSize-8:])
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], counter^index)
+
+	return nonce
+}
+
+func chunkAD(index uint64, last bool) []byte {
+	ad := binary.BigEndian.AppendUint64(nil, index)
+	if last {
+		return append(ad, 1)
+	}
+
+	return append(ad, 0)
+}
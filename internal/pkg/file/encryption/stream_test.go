@@ -0,0 +1,136 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKey(t *testing.T) []byte {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := newKey(t)
+
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 17} {
+		plaintext := make([]byte, size)
+		_, err := rand.Read(plaintext)
+		require.NoError(t, err)
+
+		ciphertext, err := Encrypt(key, plaintext)
+		require.NoError(t, err)
+		assert.Equal(t, EncryptedSize(int64(size)), int64(len(ciphertext)), "size %d", size)
+
+		decrypted, err := Decrypt(key, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted, "size %d", size)
+	}
+}
+
+func TestEncrypt_UniqueNonces(t *testing.T) {
+	key := newKey(t)
+	plaintext := []byte("same content")
+
+	first, err := Encrypt(key, plaintext)
+	require.NoError(t, err)
+	second, err := Encrypt(key, plaintext)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestReader_ReadAt(t *testing.T) {
+	key := newKey(t)
+	plaintext := make([]byte, 2*ChunkSize+100)
+	_, err := rand.Read(plaintext)
+	require.NoError(t, err)
+
+	ciphertext, err := Encrypt(key, plaintext)
+	require.NoError(t, err)
+
+	reader, err := NewReader(key, bytes.NewReader(ciphertext), int64(len(ciphertext)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(plaintext)), reader.Size())
+
+	// The range crosses the border of the first and the second chunks
+	buf := make([]byte, 200)
+	n, err := reader.ReadAt(buf, ChunkSize-100)
+	require.NoError(t, err)
+	assert.Equal(t, 200, n)
+	assert.Equal(t, plaintext[ChunkSize-100:ChunkSize+100], buf)
+
+	n, err = reader.ReadAt(buf, int64(len(plaintext))-50)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, 50, n)
+	assert.Equal(t, plaintext[len(plaintext)-50:], buf[:n])
+
+	section := io.NewSectionReader(reader, 10, 20)
+	data, err := io.ReadAll(section)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext[10:30], data)
+}
+
+func TestDecrypt_Tampered(t *testing.T) {
+	key := newKey(t)
+	plaintext := make([]byte, 2*ChunkSize+100)
+
+	ciphertext, err := Encrypt(key, plaintext)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		ciphertext func() []byte
+	}{
+		{"FlippedBit", func() []byte {
+			data := bytes.Clone(ciphertext)
+			data[headerSize+10] ^= 1
+			return data
+		}},
+		{"TruncatedAtChunk", func() []byte {
+			return ciphertext[:headerSize+2*sealedChunkSize]
+		}},
+		{"SwappedChunks", func() []byte {
+			data := bytes.Clone(ciphertext)
+			first := bytes.Clone(data[headerSize : headerSize+sealedChunkSize])
+			copy(data[headerSize:], data[headerSize+sealedChunkSize:headerSize+2*sealedChunkSize])
+			copy(data[headerSize+sealedChunkSize:], first)
+			return data
+		}},
+		{"WrongKey", func() []byte {
+			data, err := Encrypt(newKey(t), plaintext)
+			require.NoError(t, err)
+			return data
+		}},
+		{"Plaintext", func() []byte {
+			return plaintext
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decrypt(key, tt.ciphertext())
+			assert.ErrorIs(t, err, models.CorruptedObjectError)
+		})
+	}
+}
+
+func TestDecrypt_EmptyTampered(t *testing.T) {
+	key := newKey(t)
+
+	ciphertext, err := Encrypt(key, nil)
+	require.NoError(t, err)
+	ciphertext[len(ciphertext)-1] ^= 1
+
+	_, err = Decrypt(key, ciphertext)
+	assert.ErrorIs(t, err, models.CorruptedObjectError)
+}
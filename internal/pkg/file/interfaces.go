@@ -2,6 +2,8 @@ package file
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 )
@@ -9,26 +11,121 @@ import (
 type MetadataStorage interface {
 	GetFilesList(ctx context.Context, ownerID uint, options models.FilesListOptions) ([]*models.FileMetadata, error)
 	GetMetadata(ctx context.Context, id string) (*models.FileMetadata, error)
+	GetPendingMetadata(ctx context.Context, id string) (*models.FileMetadata, error)
 
-	UploadMetadata(ctx context.Context, ownerID uint, filename, contentType string,
-		size int64) (*models.FileMetadata, error)
+	// UploadMetadata and FinishReplace return models.QuotaExceededError if the size does not fit into
+	// the quota of the owner or the organization, defaultQuota is used if no quota is set
+	UploadMetadata(ctx context.Context, ownerID, orgID uint, filename, contentType string,
+		size, defaultQuota int64) (*models.FileMetadata, error)
 	UpdateFilename(ctx context.Context, id string, filename string) error
-	UpdateSize(ctx context.Context, id string, size int64) error
+	// Files are uploaded as pending rows and become visible after CommitUpload
+	CommitUpload(ctx context.Context, id string, stored models.StoredContent) error
+	// Content is replaced by uploading a new object, which is referenced as pending until FinishReplace
+	BeginReplace(ctx context.Context, id, key string) error
+	FinishReplace(ctx context.Context, id, key string, size int64, stored models.StoredContent,
+		defaultQuota int64) (oldKey string, err error)
+	ClearPendingKey(ctx context.Context, id, key string) error
+	// Data keys of encrypted files are set while the file is pending and rewrapped on master key rotation
+	SetDataKey(ctx context.Context, key *models.DataKey) error
+	GetStaleDataKeys(ctx context.Context, keyID string, limit uint) ([]*models.DataKey, error)
+	ReplaceDataKey(ctx context.Context, oldKey, newKey *models.DataKey) error
 	DeleteFile(ctx context.Context, id string) error
+	// DeleteOwnerFiles deletes personal files of the owner, files uploaded to organizations are kept without owner
+	DeleteOwnerFiles(ctx context.Context, ownerID uint) (int64, error)
+	RemoveFile(ctx context.Context, id string) (*models.FileMetadata, error)
+
+	GetStorageUsage(ctx context.Context, ownerID uint, defaultQuota int64) (*models.StorageUsage, error)
+	GetOrgStorageUsage(ctx context.Context, orgID uint, defaultQuota int64) (*models.StorageUsage, error)
+	SetQuota(ctx context.Context, ownerID uint, quota int64) error
+	SetOrgQuota(ctx context.Context, orgID uint, quota int64) error
+
+	// GetStorageStats returns the number and the total size of files that are not deleted and the number of
+	// bytes saved by compression
+	GetStorageStats(ctx context.Context) (files, bytes, savedBytes int64, err error)
+	GetObjectRefs(ctx context.Context) ([]*models.ObjectRef, error)
+}
+
+type MembershipChecker interface {
+	IsMember(ctx context.Context, orgID, userID uint) (bool, error)
+}
+
+type UserChecker interface {
+	UserExists(ctx context.Context, userID uint) (bool, error)
 }
 
 type ObjectStorage interface {
 	UploadFile(ctx context.Context, key, contentType string, file []byte, size int64) error
 
 	GetFile(ctx context.Context, key string) ([]byte, error)
+	// OpenFile returns models.FileNotExists if there is no object with the key, the reader must be closed
+	OpenFile(ctx context.Context, key string) (ObjectReader, error)
+
+	UploadStream(ctx context.Context, key, contentType string, reader io.Reader, size int64) error
+	PresignedGetURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+	PresignedPutURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// StatFile returns models.FileNotExists if there is no object with the key
+	StatFile(ctx context.Context, key string) (*models.ObjectInfo, error)
+
+	ListKeys(ctx context.Context, prefix string) ([]string, error)
+	DeleteFiles(ctx context.Context, keys []string) error
+}
+
+// ObjectReader reads an object by ranges, only the ranges that are read are fetched from the storage
+type ObjectReader interface {
+	io.ReaderAt
+	io.Closer
+	Size() int64
+}
+
+type PurgeJobStorage interface {
+	// CreatePurgeJob creates a waiting job, which starts only after the owner is deleted
+	CreatePurgeJob(ctx context.Context, ownerID uint) (*models.PurgeJob, error)
+	GetPurgeJob(ctx context.Context, id string) (*models.PurgeJob, error)
+	GetUnfinishedPurgeJobs(ctx context.Context) ([]*models.PurgeJob, error)
+	UpdatePurgeJob(ctx context.Context, job *models.PurgeJob) error
+}
+
+type ExportJobStorage interface {
+	CreateExportJob(ctx context.Context, ownerID uint, email string, account []byte) (*models.ExportJob, error)
+	GetExportJob(ctx context.Context, id string) (*models.ExportJob, error)
+	GetUnfinishedExportJobs(ctx context.Context) ([]*models.ExportJob, error)
+	GetExpiredExportJobs(ctx context.Context) ([]*models.ExportJob, error)
+	UpdateExportJob(ctx context.Context, job *models.ExportJob) error
+}
+
+type JobScheduler interface {
+	Schedule(id string)
 }
 
 type FileUsecases interface {
 	UploadFile(ctx context.Context, ownerID uint, data *models.GeneralFileData) (*models.FileMetadata, error)
 	GetFilesList(ctx context.Context, ownerID uint, options models.FilesListOptions) ([]*models.FileMetadata, error)
-	GetFile(ctx context.Context, userID uint, id string) (*models.GeneralFileData, error)
+	// OpenFile returns the metadata and a reader of the content, which fetches only the parts that are read
+	OpenFile(ctx context.Context, userID uint, id string) (*models.GeneralFileData, io.ReadSeeker, error)
 	GetMetadata(ctx context.Context, userID uint, id string) (*models.FileMetadata, error)
 	UpdateFile(ctx context.Context, userID uint, id string, file []byte, size int64) error
 	UpdateFilename(ctx context.Context, userID uint, id string, filename string) error
 	DeleteFile(ctx context.Context, userID uint, id string) error
+
+	StartPurge(ctx context.Context, ownerID uint) (*models.PurgeJob, error)
+	GetPurgeJob(ctx context.Context, id string) (*models.PurgeJob, error)
+
+	StartExport(ctx context.Context, user *models.User) (*models.ExportJob, error)
+	GetExportJob(ctx context.Context, userID uint, id string) (*models.ExportJob, error)
+
+	GetStorageUsage(ctx context.Context, ownerID uint) (*models.StorageUsage, error)
+	SetQuota(ctx context.Context, ownerID uint, quota int64) (*models.StorageUsage, error)
+	GetOrgStorageUsage(ctx context.Context, orgID uint) (*models.StorageUsage, error)
+	SetOrgQuota(ctx context.Context, orgID uint, quota int64) (*models.StorageUsage, error)
+	ForceDeleteFile(ctx context.Context, id string) (*models.FileMetadata, error)
+
+	StartUpload(ctx context.Context, ownerID uint, req *models.StartUploadRequest) (*models.PresignedUpload, error)
+	FinishUpload(ctx context.Context, userID uint, id, checksum string) (*models.FileMetadata, error)
+	GetDownloadURL(ctx context.Context, userID uint, id string) (*models.DownloadURL, error)
+
+	// Parts of S3 multipart uploads are staged in the object storage until the upload is completed.
+	// GetUploadPart returns models.FileNotExists for parts that were not uploaded.
+	PutUploadPart(ctx context.Context, userID uint, uploadID string, number int, data []byte) error
+	GetUploadPart(ctx context.Context, userID uint, uploadID string, number int) ([]byte, error)
+	DeleteUploadParts(ctx context.Context, userID uint, uploadID string) error
 }
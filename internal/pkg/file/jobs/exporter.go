@@ -0,0 +1,330 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/compression"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/encryption"
+	"github.com/IlyaChgn/voblako/internal/pkg/mailer"
+)
+
+const (
+	exportQueueSize      = 100
+	exportRescanPeriod   = time.Minute
+	exportMaxAttempts    = 3
+	exportAttemptTimeout = time.Hour
+	exportPageSize       = 100
+
+	exportContentType = "application/zip"
+	manifestName      = "manifest.json"
+	filesDir          = "files"
+)
+
+type ExporterOptions struct {
+	Retention time.Duration
+	NotifyURL string
+	Keyring   *encryption.Keyring
+}
+
+// Exporter assembles all files of a user and a JSON manifest into a ZIP archive stored next to the user files,
+// so the archive is also removed by the account purge
+type Exporter struct {
+	jobStorage      fileinterfaces.ExportJobStorage
+	metadataStorage fileinterfaces.MetadataStorage
+	objectStorage   fileinterfaces.ObjectStorage
+	mailer          mailer.Mailer
+	options         ExporterOptions
+
+	queue chan string
+}
+
+func NewExporter(
+	jobStorage fileinterfaces.ExportJobStorage,
+	metadataStorage fileinterfaces.MetadataStorage,
+	objectStorage fileinterfaces.ObjectStorage,
+	sender mailer.Mailer,
+	options ExporterOptions,
+) *Exporter {
+	return &Exporter{
+		jobStorage:      jobStorage,
+		metadataStorage: metadataStorage,
+		objectStorage:   objectStorage,
+		mailer:          sender,
+		options:         options,
+		queue:           make(chan string, exportQueueSize),
+	}
+}
+
+func ExportObjectKey(ownerID uint, jobID string) string {
+	return fmt.Sprintf("%d/exports/%s.zip", ownerID, jobID)
+}
+
+func (e *Exporter) Schedule(id string) {
+	select {
+	case e.queue <- id:
+	default:
+	}
+}
+
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(exportRescanPeriod)
+	defer ticker.Stop()
+
+	e.rescan(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-e.queue:
+			job, err := e.jobStorage.GetExportJob(ctx, id)
+			if err != nil {
+				slog.ErrorContext(ctx, "Something went wrong while getting export job", "error", err)
+				continue
+			}
+			if job != nil {
+				e.process(ctx, job)
+			}
+		case <-ticker.C:
+			e.rescan(ctx)
+		}
+	}
+}
+
+func (e *Exporter) rescan(ctx context.Context) {
+	jobs, err := e.jobStorage.GetUnfinishedExportJobs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while getting unfinished export jobs", "error", err)
+	}
+
+	for _, job := range jobs {
+		e.process(ctx, job)
+	}
+
+	e.removeExpired(ctx)
+}
+
+func (e *Exporter) removeExpired(ctx context.Context) {
+	jobs, err := e.jobStorage.GetExpiredExportJobs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while getting expired export jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if err := e.objectStorage.DeleteFiles(ctx, []string{job.ObjectKey}); err != nil {
+			slog.ErrorContext(ctx, "Something went wrong while removing expired export", "error", err)
+			continue
+		}
+
+		job.Status = models.JobStatusExpired
+		if err := e.jobStorage.UpdateExportJob(ctx, job); err != nil {
+			slog.ErrorContext(ctx, "Something went wrong while updating export job", "error", err)
+		}
+	}
+}
+
+func (e *Exporter) process(ctx context.Context, job *models.ExportJob) {
+	if job.Status != models.JobStatusPending && job.Status != models.JobStatusRunning {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, exportAttemptTimeout)
+	defer cancel()
+
+	job.Status = models.JobStatusRunning
+	job.Attempts++
+	if err := e.jobStorage.UpdateExportJob(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while updating export job", "error", err)
+		return
+	}
+
+	err := e.export(ctx, job)
+	if err != nil {
+		slog.ErrorContext(ctx, "Export job failed", "job_id", job.ID, "error", err)
+
+		job.Error = err.Error()
+		job.Status = models.JobStatusPending
+		if job.Attempts >= exportMaxAttempts {
+			job.Status = models.JobStatusFailed
+		}
+	} else {
+		expireTime := time.Now().Add(e.options.Retention)
+
+		job.Error = ""
+		job.Status = models.JobStatusDone
+		job.ExpireTime = &expireTime
+	}
+
+	if err := e.jobStorage.UpdateExportJob(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while updating export job", "error", err)
+		return
+	}
+
+	if job.Status == models.JobStatusDone {
+		e.notify(ctx, job)
+	}
+}
+
+func (e *Exporter) export(ctx context.Context, job *models.ExportJob) error {
+	archive, err := os.CreateTemp("", "voblako-export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if err := e.writeArchive(ctx, job, archive); err != nil {
+		return err
+	}
+
+	size, err := archive.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := ExportObjectKey(job.OwnerID, job.ID)
+	if err := e.objectStorage.UploadStream(ctx, key, exportContentType, archive, size); err != nil {
+		return err
+	}
+
+	job.ObjectKey = key
+	job.Size = size
+
+	return nil
+}
+
+// copyContent streams the content into the archive, encrypted objects are decrypted chunk by chunk and
+// compressed ones are decompressed as they are copied
+func (e *Exporter) copyContent(ctx context.Context, w io.Writer, meta *models.FileMetadata) error {
+	object, err := e.objectStorage.OpenFile(ctx, meta.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	compressed, size, err := e.options.Keyring.OpenReader(meta, object, object.Size())
+	if err != nil {
+		return err
+	}
+	content, err := compression.NewReader(meta.Stored.Encoding, io.NewSectionReader(compressed, 0, size))
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	_, err = io.Copy(w, content)
+
+	return err
+}
+
+func (e *Exporter) writeArchive(ctx context.Context, job *models.ExportJob, w io.Writer) error {
+	zipWriter := zip.NewWriter(w)
+
+	manifest := &models.ExportManifest{
+		Account:    job.Account,
+		Files:      []*models.ExportManifestFile{},
+		CreateTime: time.Now(),
+	}
+	usedPaths := make(map[string]struct{})
+
+	for offset := uint(0); ; offset += exportPageSize {
+		files, err := e.metadataStorage.GetFilesList(ctx, job.OwnerID, models.FilesListOptions{
+			Limit:  exportPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, meta := range files {
+			filePath := uniquePath(usedPaths, path.Join(filesDir, sanitizeName(meta.Filename)))
+
+			fileWriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+				Name:     filePath,
+				Method:   zip.Deflate,
+				Modified: meta.UpdateTime,
+			})
+			if err != nil {
+				return err
+			}
+			if err := e.copyContent(ctx, fileWriter, meta); err != nil {
+				return err
+			}
+
+			manifest.Files = append(manifest.Files, &models.ExportManifestFile{
+				FileMetadata: *meta,
+				Path:         filePath,
+			})
+		}
+
+		if len(files) < exportPageSize {
+			break
+		}
+	}
+
+	manifestWriter, err := zipWriter.Create(manifestName)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(manifestWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+
+	return zipWriter.Close()
+}
+
+func (e *Exporter) notify(ctx context.Context, job *models.ExportJob) {
+	if job.Email == "" {
+		return
+	}
+
+	body := fmt.Sprintf("Your data export is ready and will be available until %s.\n"+
+		"Download it here:\n%s", job.ExpireTime.Format(time.RFC1123), fmt.Sprintf(e.options.NotifyURL, job.ID))
+
+	if err := e.mailer.Send(ctx, job.Email, "Voblako data export is ready", body); err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while sending export notification", "error", err)
+	}
+}
+
+// sanitizeName keeps archive entries inside the files directory
+func sanitizeName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+
+	return name
+}
+
+func uniquePath(used map[string]struct{}, filePath string) string {
+	ext := path.Ext(filePath)
+	base := strings.TrimSuffix(filePath, ext)
+
+	candidate := filePath
+	for i := 1; ; i++ {
+		if _, exists := used[candidate]; !exists {
+			used[candidate] = struct{}{}
+			return candidate
+		}
+
+		candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+}
@@ -0,0 +1,138 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExportJobStorage struct {
+	fileinterfaces.ExportJobStorage
+	updates []models.ExportJob
+}
+
+func (s *fakeExportJobStorage) UpdateExportJob(_ context.Context, job *models.ExportJob) error {
+	s.updates = append(s.updates, *job)
+	return nil
+}
+
+type fakeExportMetadataStorage struct {
+	fileinterfaces.MetadataStorage
+	files []*models.FileMetadata
+}
+
+func (s *fakeExportMetadataStorage) GetFilesList(_ context.Context, _ uint,
+	options models.FilesListOptions) ([]*models.FileMetadata, error) {
+	if options.Offset >= uint(len(s.files)) {
+		return nil, nil
+	}
+
+	return s.files[options.Offset:min(options.Offset+options.Limit, uint(len(s.files)))], nil
+}
+
+type fakeExportObjectStorage struct {
+	fileinterfaces.ObjectStorage
+	objects map[string][]byte
+}
+
+func (s *fakeExportObjectStorage) OpenFile(_ context.Context, key string) (fileinterfaces.ObjectReader, error) {
+	return fakeObject{Reader: bytes.NewReader(s.objects[key])}, nil
+}
+
+type fakeObject struct {
+	*bytes.Reader
+}
+
+func (fakeObject) Close() error {
+	return nil
+}
+
+func (s *fakeExportObjectStorage) UploadStream(_ context.Context, key, _ string, reader io.Reader, _ int64) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	s.objects[key] = data
+	return nil
+}
+
+type fakeMailer struct {
+	to   string
+	body string
+}
+
+func (m *fakeMailer) Send(_ context.Context, to, _, body string) error {
+	m.to = to
+	m.body = body
+	return nil
+}
+
+func TestExporter_Process(t *testing.T) {
+	jobStorage := &fakeExportJobStorage{}
+	metadataStorage := &fakeExportMetadataStorage{files: []*models.FileMetadata{
+		{UUID: "a", Filename: "notes.txt", StorageKey: "1/a"},
+		{UUID: "b", Filename: "notes.txt", StorageKey: "1/b"},
+		{UUID: "c", Filename: "../evil", StorageKey: "1/c"},
+	}}
+	objectStorage := &fakeExportObjectStorage{objects: map[string][]byte{
+		"1/a": []byte("first"),
+		"1/b": []byte("second"),
+		"1/c": []byte("third"),
+	}}
+	sender := &fakeMailer{}
+
+	exporter := NewExporter(jobStorage, metadataStorage, objectStorage, sender, ExporterOptions{
+		NotifyURL: "http://localhost/export/%s",
+	})
+
+	job := &models.ExportJob{
+		ID:      "job",
+		OwnerID: 1,
+		Status:  models.JobStatusPending,
+		Email:   "test@example.com",
+		Account: []byte(`{"id":1,"email":"test@example.com"}`),
+	}
+	exporter.process(context.Background(), job)
+
+	require.Equal(t, models.JobStatusDone, job.Status)
+	assert.Equal(t, ExportObjectKey(1, "job"), job.ObjectKey)
+	assert.NotNil(t, job.ExpireTime)
+	assert.Equal(t, "test@example.com", sender.to)
+	assert.Contains(t, sender.body, "http://localhost/export/job")
+
+	archive := objectStorage.objects[job.ObjectKey]
+	assert.Equal(t, int64(len(archive)), job.Size)
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	contents := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		contents[file.Name] = string(data)
+	}
+
+	assert.Equal(t, "first", contents["files/notes.txt"])
+	assert.Equal(t, "second", contents["files/notes (1).txt"])
+	assert.Equal(t, "third", contents["files/.._evil"])
+
+	var manifest models.ExportManifest
+	require.NoError(t, json.Unmarshal([]byte(contents[manifestName]), &manifest))
+	assert.JSONEq(t, string(job.Account), string(manifest.Account))
+	assert.Len(t, manifest.Files, 3)
+	assert.Equal(t, "files/notes (1).txt", manifest.Files[1].Path)
+}
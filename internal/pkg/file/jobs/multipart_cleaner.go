@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/google/uuid"
+)
+
+// uploadPartKeyRegexp matches staged parts of multipart uploads, the upload ID is a UUIDv7, which holds the
+// time the upload was created
+var uploadPartKeyRegexp = regexp.MustCompile(
+	`^\d+/multipart/([0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[0-9a-f]{4}-[0-9a-f]{12})/\d+$`)
+
+// UploadPartsPrefix is the prefix of the staged parts of the upload, they are removed with the other objects
+// of the user when the account is purged
+func UploadPartsPrefix(userID uint, uploadID string) string {
+	return fmt.Sprintf("%d/multipart/%s/", userID, uploadID)
+}
+
+// MultipartCleanerOptions.Retention must be longer than the lifetime of uploads in the gateway
+type MultipartCleanerOptions struct {
+	Interval  time.Duration
+	Retention time.Duration
+}
+
+// MultipartCleaner removes parts of uploads that were neither completed nor aborted
+type MultipartCleaner struct {
+	objectStorage fileinterfaces.ObjectStorage
+
+	options MultipartCleanerOptions
+	now     func() time.Time
+}
+
+func NewMultipartCleaner(objectStorage fileinterfaces.ObjectStorage,
+	options MultipartCleanerOptions) *MultipartCleaner {
+	return &MultipartCleaner{
+		objectStorage: objectStorage,
+		options:       options,
+		now:           time.Now,
+	}
+}
+
+func (c *MultipartCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := c.Clean(ctx); err != nil {
+				slog.ErrorContext(ctx, "Something went wrong while removing abandoned upload parts", "error", err)
+			}
+		}
+	}
+}
+
+// Clean removes the parts of uploads created before the retention period and returns their number
+func (c *MultipartCleaner) Clean(ctx context.Context) (int, error) {
+	keys, err := c.objectStorage.ListKeys(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	expired := c.now().Add(-c.options.Retention)
+
+	var garbage []string
+	for _, key := range keys {
+		match := uploadPartKeyRegexp.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+
+		uploadID, err := uuid.Parse(match[1])
+		if err != nil {
+			continue
+		}
+		if time.Unix(uploadID.Time().UnixTime()).Before(expired) {
+			garbage = append(garbage, key)
+		}
+	}
+
+	for start := 0; start < len(garbage); start += reconcileBatchSize {
+		end := min(start+reconcileBatchSize, len(garbage))
+		if err := c.objectStorage.DeleteFiles(ctx, garbage[start:end]); err != nil {
+			return start, err
+		}
+	}
+
+	if len(garbage) > 0 {
+		slog.InfoContext(ctx, "Abandoned upload parts removed", "deleted_objects", len(garbage))
+	}
+
+	return len(garbage), nil
+}
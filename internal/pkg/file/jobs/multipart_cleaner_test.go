@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultipartCleaner_Clean(t *testing.T) {
+	const (
+		oldUpload   = "01942117-de80-7abc-8def-0123456789ab" // 2025-01-01 09:00 UTC
+		freshUpload = "01942185-bb80-7abc-8def-0123456789ab" // 2025-01-01 11:00 UTC
+	)
+
+	objectStorage := &fakeObjectStorage{keys: []string{
+		UploadPartsPrefix(1, oldUpload) + "1",
+		UploadPartsPrefix(1, oldUpload) + "2",
+		UploadPartsPrefix(2, freshUpload) + "1",
+		committedKey,
+		ExportObjectKey(1, oldUpload),
+	}}
+
+	cleaner := NewMultipartCleaner(objectStorage, MultipartCleanerOptions{Retention: 2 * time.Hour})
+	cleaner.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	deleted, err := cleaner.Clean(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, deleted)
+	assert.ElementsMatch(t, []string{
+		UploadPartsPrefix(2, freshUpload) + "1",
+		committedKey,
+		ExportObjectKey(1, oldUpload),
+	}, objectStorage.keys)
+}
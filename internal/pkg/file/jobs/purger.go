@@ -0,0 +1,184 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+)
+
+const (
+	purgeQueueSize      = 100
+	purgeBatchSize      = 1000
+	purgeRescanPeriod   = time.Minute
+	purgeMaxAttempts    = 5
+	purgeAttemptTimeout = 30 * time.Minute
+	// purgeWaitTimeout is how long a waiting job is kept while the owner still exists
+	purgeWaitTimeout = time.Hour
+)
+
+// Purger removes all objects and metadata of an owner. Jobs are stored in Postgres, so unfinished ones
+// are resumed after restarts and failed attempts are retried on the next rescan. Jobs are created before
+// the owner is deleted and wait until the auth service confirms the deletion.
+type Purger struct {
+	jobStorage      fileinterfaces.PurgeJobStorage
+	metadataStorage fileinterfaces.MetadataStorage
+	objectStorage   fileinterfaces.ObjectStorage
+	userChecker     fileinterfaces.UserChecker
+
+	queue chan string
+}
+
+func NewPurger(
+	jobStorage fileinterfaces.PurgeJobStorage,
+	metadataStorage fileinterfaces.MetadataStorage,
+	objectStorage fileinterfaces.ObjectStorage,
+	userChecker fileinterfaces.UserChecker,
+) *Purger {
+	return &Purger{
+		jobStorage:      jobStorage,
+		metadataStorage: metadataStorage,
+		objectStorage:   objectStorage,
+		userChecker:     userChecker,
+		queue:           make(chan string, purgeQueueSize),
+	}
+}
+
+// Schedule never blocks: if the queue is full, the job is picked up by the next rescan
+func (p *Purger) Schedule(id string) {
+	select {
+	case p.queue <- id:
+	default:
+	}
+}
+
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeRescanPeriod)
+	defer ticker.Stop()
+
+	p.rescan(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-p.queue:
+			job, err := p.jobStorage.GetPurgeJob(ctx, id)
+			if err != nil {
+				slog.ErrorContext(ctx, "Something went wrong while getting purge job", "error", err)
+				continue
+			}
+			if job != nil {
+				p.process(ctx, job)
+			}
+		case <-ticker.C:
+			p.rescan(ctx)
+		}
+	}
+}
+
+func (p *Purger) rescan(ctx context.Context) {
+	jobs, err := p.jobStorage.GetUnfinishedPurgeJobs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while getting unfinished purge jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		p.process(ctx, job)
+	}
+}
+
+func (p *Purger) process(ctx context.Context, job *models.PurgeJob) {
+	if job.Status == models.JobStatusWaiting && !p.activate(ctx, job) {
+		return
+	}
+	if job.Status != models.JobStatusPending && job.Status != models.JobStatusRunning {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, purgeAttemptTimeout)
+	defer cancel()
+
+	job.Status = models.JobStatusRunning
+	job.Attempts++
+	if err := p.jobStorage.UpdatePurgeJob(ctx, job); err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while updating purge job", "error", err)
+		return
+	}
+
+	err := p.purge(ctx, job)
+	if err != nil {
+		slog.ErrorContext(ctx, "Purge job failed", "job_id", job.ID, "error", err)
+
+		job.Error = err.Error()
+		job.Status = models.JobStatusPending
+		if job.Attempts >= purgeMaxAttempts {
+			job.Status = models.JobStatusFailed
+		}
+	} else {
+		job.Error = ""
+		job.Status = models.JobStatusDone
+	}
+
+	// The attempt context may be already expired, but the result still has to be saved
+	if err := p.jobStorage.UpdatePurgeJob(context.WithoutCancel(ctx), job); err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while updating purge job", "error", err)
+	}
+}
+
+// activate starts the waiting job if the owner has been deleted. If the owner still exists after
+// purgeWaitTimeout, the deletion has failed and the job is cancelled.
+func (p *Purger) activate(ctx context.Context, job *models.PurgeJob) bool {
+	exists, err := p.userChecker.UserExists(ctx, job.OwnerID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Something went wrong while checking owner of purge job", "job_id", job.ID,
+			"error", err)
+		return false
+	}
+
+	if exists {
+		if time.Since(job.CreateTime) < purgeWaitTimeout {
+			return false
+		}
+
+		job.Status = models.JobStatusCancelled
+		if err := p.jobStorage.UpdatePurgeJob(ctx, job); err != nil {
+			slog.ErrorContext(ctx, "Something went wrong while updating purge job", "error", err)
+		}
+
+		return false
+	}
+
+	job.Status = models.JobStatusPending
+
+	return true
+}
+
+func (p *Purger) purge(ctx context.Context, job *models.PurgeJob) error {
+	keys, err := p.objectStorage.ListKeys(ctx, fmt.Sprintf("%d/", job.OwnerID))
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(keys); start += purgeBatchSize {
+		batch := keys[start:min(start+purgeBatchSize, len(keys))]
+		if err := p.objectStorage.DeleteFiles(ctx, batch); err != nil {
+			return err
+		}
+
+		job.DeletedObjects += int64(len(batch))
+		if err := p.jobStorage.UpdatePurgeJob(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	// Metadata goes last, so an interrupted job still finds the remaining objects by the owner prefix.
+	// Objects of organizations are not under the prefix, they stay in the shared storage.
+	_, err = p.metadataStorage.DeleteOwnerFiles(ctx, job.OwnerID)
+
+	return err
+}
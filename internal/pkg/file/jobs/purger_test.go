@@ -0,0 +1,153 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobStorage struct {
+	fileinterfaces.PurgeJobStorage
+	updates []models.PurgeJob
+}
+
+func (s *fakeJobStorage) UpdatePurgeJob(_ context.Context, job *models.PurgeJob) error {
+	s.updates = append(s.updates, *job)
+	return nil
+}
+
+type fakeMetadataStorage struct {
+	fileinterfaces.MetadataStorage
+	deletedOwner uint
+}
+
+func (s *fakeMetadataStorage) DeleteOwnerFiles(_ context.Context, ownerID uint) (int64, error) {
+	s.deletedOwner = ownerID
+	return 0, nil
+}
+
+type fakeUserChecker struct {
+	exists bool
+}
+
+func (c *fakeUserChecker) UserExists(_ context.Context, _ uint) (bool, error) {
+	return c.exists, nil
+}
+
+type fakeObjectStorage struct {
+	fileinterfaces.ObjectStorage
+	keys      []string
+	deleteErr error
+}
+
+func (s *fakeObjectStorage) ListKeys(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for _, key := range s.keys {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *fakeObjectStorage) DeleteFiles(_ context.Context, keys []string) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+
+	for _, key := range keys {
+		for i, stored := range s.keys {
+			if stored == key {
+				s.keys = append(s.keys[:i], s.keys[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestPurger_Process(t *testing.T) {
+	jobStorage := &fakeJobStorage{}
+	metadataStorage := &fakeMetadataStorage{}
+	objectStorage := &fakeObjectStorage{keys: []string{"1/a", "1/b", "12/c", "2/d", "org/1/e"}}
+
+	purger := NewPurger(jobStorage, metadataStorage, objectStorage, &fakeUserChecker{})
+
+	job := &models.PurgeJob{ID: "job", OwnerID: 1, Status: models.JobStatusPending}
+	purger.process(context.Background(), job)
+
+	assert.Equal(t, models.JobStatusDone, job.Status)
+	assert.Equal(t, int64(2), job.DeletedObjects)
+	assert.Equal(t, []string{"12/c", "2/d", "org/1/e"}, objectStorage.keys)
+	assert.Equal(t, uint(1), metadataStorage.deletedOwner)
+	assert.Equal(t, models.JobStatusRunning, jobStorage.updates[0].Status)
+}
+
+func TestPurger_Process_Retry(t *testing.T) {
+	jobStorage := &fakeJobStorage{}
+	metadataStorage := &fakeMetadataStorage{}
+	objectStorage := &fakeObjectStorage{keys: []string{"1/a"}, deleteErr: errors.New("minio is down")}
+
+	purger := NewPurger(jobStorage, metadataStorage, objectStorage, &fakeUserChecker{})
+
+	job := &models.PurgeJob{ID: "job", OwnerID: 1, Status: models.JobStatusPending}
+	purger.process(context.Background(), job)
+
+	assert.Equal(t, models.JobStatusPending, job.Status)
+	assert.Equal(t, "minio is down", job.Error)
+	assert.Zero(t, metadataStorage.deletedOwner)
+
+	for job.Status == models.JobStatusPending {
+		purger.process(context.Background(), job)
+	}
+
+	assert.Equal(t, models.JobStatusFailed, job.Status)
+	assert.Equal(t, purgeMaxAttempts, job.Attempts)
+}
+
+func TestPurger_Process_Waiting(t *testing.T) {
+	jobStorage := &fakeJobStorage{}
+	metadataStorage := &fakeMetadataStorage{}
+	objectStorage := &fakeObjectStorage{keys: []string{"1/a"}}
+	userChecker := &fakeUserChecker{exists: true}
+
+	purger := NewPurger(jobStorage, metadataStorage, objectStorage, userChecker)
+
+	// The owner is not deleted yet, so nothing is removed
+	job := &models.PurgeJob{ID: "job", OwnerID: 1, Status: models.JobStatusWaiting, CreateTime: time.Now()}
+	purger.process(context.Background(), job)
+
+	assert.Equal(t, models.JobStatusWaiting, job.Status)
+	assert.Equal(t, []string{"1/a"}, objectStorage.keys)
+	assert.Empty(t, jobStorage.updates)
+
+	userChecker.exists = false
+	purger.process(context.Background(), job)
+
+	assert.Equal(t, models.JobStatusDone, job.Status)
+	assert.Empty(t, objectStorage.keys)
+	assert.Equal(t, uint(1), metadataStorage.deletedOwner)
+}
+
+func TestPurger_Process_DeletionFailed(t *testing.T) {
+	jobStorage := &fakeJobStorage{}
+	objectStorage := &fakeObjectStorage{keys: []string{"1/a"}}
+
+	purger := NewPurger(jobStorage, &fakeMetadataStorage{}, objectStorage, &fakeUserChecker{exists: true})
+
+	job := &models.PurgeJob{ID: "job", OwnerID: 1, Status: models.JobStatusWaiting,
+		CreateTime: time.Now().Add(-purgeWaitTimeout)}
+	purger.process(context.Background(), job)
+
+	assert.Equal(t, models.JobStatusCancelled, job.Status)
+	assert.Equal(t, []string{"1/a"}, objectStorage.keys)
+	assert.Equal(t, models.JobStatusCancelled, jobStorage.updates[0].Status)
+}
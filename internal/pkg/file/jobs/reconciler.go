@@ -0,0 +1,179 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+)
+
+const reconcileBatchSize = 1000
+
+// fileKeyRegexp matches keys of file objects, exports and other objects under the bucket are not checked
+var fileKeyRegexp = regexp.MustCompile(`^(org/)?\d+/[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// ReconcilerOptions.GracePeriod protects uploads in progress: rows changed more recently are not reported
+type ReconcilerOptions struct {
+	Interval    time.Duration
+	GracePeriod time.Duration
+	Repair      bool
+}
+
+// Reconciler finds metadata rows without objects and objects without rows
+type Reconciler struct {
+	metadataStorage fileinterfaces.MetadataStorage
+	objectStorage   fileinterfaces.ObjectStorage
+
+	options ReconcilerOptions
+	now     func() time.Time
+}
+
+func NewReconciler(
+	metadataStorage fileinterfaces.MetadataStorage,
+	objectStorage fileinterfaces.ObjectStorage,
+	options ReconcilerOptions,
+) *Reconciler {
+	return &Reconciler{
+		metadataStorage: metadataStorage,
+		objectStorage:   objectStorage,
+		options:         options,
+		now:             time.Now,
+	}
+}
+
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil {
+				slog.ErrorContext(ctx, "Something went wrong while reconciling storage", "error", err)
+			}
+		}
+	}
+}
+
+// Reconcile checks the whole storage once and repairs it if the options allow
+func (r *Reconciler) Reconcile(ctx context.Context) (*models.ReconcileReport, error) {
+	// Objects are listed before rows are read. Rows are always written before their objects, so an object
+	// uploaded meanwhile is still referenced by the rows read afterwards.
+	keys, err := r.objectStorage.ListKeys(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := r.metadataStorage.GetObjectRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReconcileReport{CheckedFiles: len(refs)}
+
+	stored := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if fileKeyRegexp.MatchString(key) {
+			stored[key] = true
+		}
+	}
+	report.CheckedObjects = len(stored)
+
+	referenced := make(map[string]bool, len(refs))
+	settled := r.now().Add(-r.options.GracePeriod)
+
+	var staleUploads, staleReplacements []*models.ObjectRef
+	for _, ref := range refs {
+		referenced[ref.StorageKey] = true
+		if ref.PendingKey != "" {
+			referenced[ref.PendingKey] = true
+		}
+
+		if ref.UpdateTime.After(settled) {
+			continue
+		}
+
+		switch {
+		case ref.UploadState == models.UploadStatePending:
+			staleUploads = append(staleUploads, ref)
+			report.StaleUploads = append(report.StaleUploads, ref.FileID)
+		case !stored[ref.StorageKey]:
+			report.MissingObjects = append(report.MissingObjects, ref.FileID)
+		}
+
+		if ref.PendingKey != "" {
+			staleReplacements = append(staleReplacements, ref)
+			report.StaleReplacements = append(report.StaleReplacements, ref.PendingKey)
+		}
+	}
+
+	for key := range stored {
+		if !referenced[key] {
+			report.OrphanObjects = append(report.OrphanObjects, key)
+		}
+	}
+
+	if report.Consistent() {
+		slog.InfoContext(ctx, "Storage is consistent",
+			"files", report.CheckedFiles, "objects", report.CheckedObjects)
+		return report, nil
+	}
+
+	slog.WarnContext(ctx, "Storage is inconsistent",
+		"missing_objects", len(report.MissingObjects), "stale_uploads", len(report.StaleUploads),
+		"stale_replacements", len(report.StaleReplacements), "orphan_objects", len(report.OrphanObjects))
+
+	if r.options.Repair {
+		if err := r.repair(ctx, report, staleUploads, staleReplacements); err != nil {
+			return report, err
+		}
+		report.Repaired = true
+	}
+
+	return report, nil
+}
+
+// repair removes rows whose objects are lost and objects nobody refers to. Rows of files with lost objects are
+// removed as well, as they cannot be downloaded anyway.
+func (r *Reconciler) repair(ctx context.Context, report *models.ReconcileReport,
+	staleUploads, staleReplacements []*models.ObjectRef) error {
+	var garbage []string
+
+	for _, ref := range staleUploads {
+		garbage = append(garbage, ref.StorageKey)
+	}
+	for _, ref := range staleReplacements {
+		if err := r.metadataStorage.ClearPendingKey(ctx, ref.FileID, ref.PendingKey); err != nil {
+			return err
+		}
+		garbage = append(garbage, ref.PendingKey)
+	}
+	garbage = append(garbage, report.OrphanObjects...)
+
+	// Objects go first, so a failure leaves rows that are found again by the next run
+	for start := 0; start < len(garbage); start += reconcileBatchSize {
+		end := min(start+reconcileBatchSize, len(garbage))
+		if err := r.objectStorage.DeleteFiles(ctx, garbage[start:end]); err != nil {
+			return err
+		}
+	}
+
+	for _, ids := range [][]string{report.StaleUploads, report.MissingObjects} {
+		for _, id := range ids {
+			if _, err := r.metadataStorage.RemoveFile(ctx, id); err != nil && !errors.Is(err, models.FileNotExists) {
+				return err
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "Storage repaired", "deleted_objects", len(garbage),
+		"deleted_files", len(report.StaleUploads)+len(report.MissingObjects))
+
+	return nil
+}
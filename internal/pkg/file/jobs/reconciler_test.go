@@ -0,0 +1,113 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	committedKey = "1/0b6f3c1e-8a7d-4d5c-9e2f-1a2b3c4d5e6f"
+	missingKey   = "1/1c7a4d2f-9b8e-4e6d-8f3a-2b3c4d5e6f70"
+	pendingKey   = "1/2d8b5e3a-ac9f-4f7e-9a4b-3c4d5e6f7081"
+	replacingKey = "org/2/3e9c6f4b-bdaa-4a8f-8b5c-4d5e6f708192"
+	orphanKey    = "2/4fad7a5c-cebb-4b9a-9c6d-5e6f708192a3"
+	freshKey     = "2/5abe8b6d-dfcc-4cab-8d7e-6f708192a3b4"
+)
+
+type fakeReconcileMetadataStorage struct {
+	fileinterfaces.MetadataStorage
+	refs        []*models.ObjectRef
+	removed     []string
+	clearedKeys []string
+}
+
+func (s *fakeReconcileMetadataStorage) GetObjectRefs(_ context.Context) ([]*models.ObjectRef, error) {
+	return s.refs, nil
+}
+
+func (s *fakeReconcileMetadataStorage) RemoveFile(_ context.Context, id string) (*models.FileMetadata, error) {
+	s.removed = append(s.removed, id)
+	return &models.FileMetadata{}, nil
+}
+
+func (s *fakeReconcileMetadataStorage) ClearPendingKey(_ context.Context, _, key string) error {
+	s.clearedKeys = append(s.clearedKeys, key)
+	return nil
+}
+
+func newTestReconciler(repair bool) (*Reconciler, *fakeReconcileMetadataStorage, *fakeObjectStorage) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-2 * time.Hour)
+
+	metadataStorage := &fakeReconcileMetadataStorage{refs: []*models.ObjectRef{
+		{FileID: "committed", StorageKey: committedKey, UploadState: models.UploadStateCommitted, UpdateTime: old},
+		{FileID: "missing", StorageKey: missingKey, UploadState: models.UploadStateCommitted, UpdateTime: old},
+		{FileID: "pending", StorageKey: pendingKey, UploadState: models.UploadStatePending, UpdateTime: old},
+		{FileID: "replacing", StorageKey: committedKey, PendingKey: replacingKey,
+			UploadState: models.UploadStateCommitted, UpdateTime: old},
+		{FileID: "fresh", StorageKey: freshKey, UploadState: models.UploadStatePending, UpdateTime: now},
+	}}
+	objectStorage := &fakeObjectStorage{keys: []string{
+		committedKey, pendingKey, replacingKey, orphanKey, "exports/1/archive.zip",
+	}}
+
+	reconciler := NewReconciler(metadataStorage, objectStorage, ReconcilerOptions{
+		GracePeriod: time.Hour,
+		Repair:      repair,
+	})
+	reconciler.now = func() time.Time { return now }
+
+	return reconciler, metadataStorage, objectStorage
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	reconciler, metadataStorage, objectStorage := newTestReconciler(false)
+
+	report, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, report.Consistent())
+	assert.False(t, report.Repaired)
+	assert.Equal(t, 5, report.CheckedFiles)
+	assert.Equal(t, 4, report.CheckedObjects)
+	assert.Equal(t, []string{"missing"}, report.MissingObjects)
+	assert.Equal(t, []string{"pending"}, report.StaleUploads)
+	assert.Equal(t, []string{replacingKey}, report.StaleReplacements)
+	assert.Equal(t, []string{orphanKey}, report.OrphanObjects)
+
+	assert.Empty(t, metadataStorage.removed)
+	assert.Empty(t, metadataStorage.clearedKeys)
+	assert.Len(t, objectStorage.keys, 5)
+}
+
+func TestReconciler_Reconcile_Repair(t *testing.T) {
+	reconciler, metadataStorage, objectStorage := newTestReconciler(true)
+
+	report, err := reconciler.Reconcile(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Repaired)
+	assert.ElementsMatch(t, []string{"pending", "missing"}, metadataStorage.removed)
+	assert.Equal(t, []string{replacingKey}, metadataStorage.clearedKeys)
+	assert.Equal(t, []string{committedKey, "exports/1/archive.zip"}, objectStorage.keys)
+}
+
+func TestReconciler_Reconcile_Consistent(t *testing.T) {
+	metadataStorage := &fakeReconcileMetadataStorage{refs: []*models.ObjectRef{
+		{FileID: "committed", StorageKey: committedKey, UploadState: models.UploadStateCommitted},
+	}}
+	objectStorage := &fakeObjectStorage{keys: []string{committedKey}}
+
+	report, err := NewReconciler(metadataStorage, objectStorage, ReconcilerOptions{Repair: true}).
+		Reconcile(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Consistent())
+	assert.False(t, report.Repaired)
+}
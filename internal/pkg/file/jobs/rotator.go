@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/encryption"
+)
+
+const rotateBatchSize = 500
+
+// RotateDataKeys rewraps the data keys wrapped with previous master keys, the content is not re-encrypted.
+// After it finishes the previous master keys can be removed from the config.
+func RotateDataKeys(ctx context.Context, metadataStorage fileinterfaces.MetadataStorage,
+	keyring *encryption.Keyring) (int64, error) {
+	var rotated int64
+
+	for {
+		keys, err := metadataStorage.GetStaleDataKeys(ctx, keyring.CurrentKeyID(), rotateBatchSize)
+		if err != nil {
+			return rotated, err
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		for _, key := range keys {
+			newKey, err := keyring.Rewrap(key)
+			if err != nil {
+				return rotated, err
+			}
+
+			// A key changed meanwhile is already wrapped with the current master key
+			err = metadataStorage.ReplaceDataKey(ctx, key, newKey)
+			if err != nil && !errors.Is(err, models.UpdateConflictError) {
+				return rotated, err
+			}
+			if err == nil {
+				rotated++
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "Data keys rotated", "key_id", keyring.CurrentKeyID(), "rotated", rotated)
+
+	return rotated, nil
+}
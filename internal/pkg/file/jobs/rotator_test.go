@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeyMetadataStorage struct {
+	fileinterfaces.MetadataStorage
+	keys map[string]*models.DataKey
+}
+
+func (s *fakeKeyMetadataStorage) GetStaleDataKeys(_ context.Context, keyID string,
+	limit uint) ([]*models.DataKey, error) {
+	var stale []*models.DataKey
+	for _, key := range s.keys {
+		if key.KeyID != keyID && uint(len(stale)) < limit {
+			stale = append(stale, key)
+		}
+	}
+
+	return stale, nil
+}
+
+func (s *fakeKeyMetadataStorage) ReplaceDataKey(_ context.Context, oldKey, newKey *models.DataKey) error {
+	if s.keys[oldKey.FileID].KeyID != oldKey.KeyID {
+		return models.UpdateConflictError
+	}
+	s.keys[oldKey.FileID] = newKey
+
+	return nil
+}
+
+func newMasterKey(t *testing.T) []byte {
+	key := make([]byte, encryption.KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestRotateDataKeys(t *testing.T) {
+	oldMaster, newMaster := newMasterKey(t), newMasterKey(t)
+
+	oldKeyring, err := encryption.NewKeyring(oldMaster)
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(newMaster, oldMaster)
+	require.NoError(t, err)
+
+	storage := &fakeKeyMetadataStorage{keys: make(map[string]*models.DataKey)}
+	dataKeys := make(map[string][]byte)
+	for _, id := range []string{"a", "b", "c"} {
+		key, wrapped, err := oldKeyring.GenerateDataKey(id)
+		require.NoError(t, err)
+
+		storage.keys[id], dataKeys[id] = wrapped, key
+	}
+	_, storage.keys["d"], err = keyring.GenerateDataKey("d")
+	require.NoError(t, err)
+
+	rotated, err := RotateDataKeys(context.Background(), storage, keyring)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), rotated)
+
+	newOnly, err := encryption.NewKeyring(newMaster)
+	require.NoError(t, err)
+	for id, key := range dataKeys {
+		unwrapped, err := newOnly.UnwrapDataKey(storage.keys[id])
+		require.NoError(t, err)
+		assert.Equal(t, key, unwrapped)
+	}
+}
+
+func TestRotateDataKeys_UnknownMasterKey(t *testing.T) {
+	lostKeyring, err := encryption.NewKeyring(newMasterKey(t))
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(newMasterKey(t))
+	require.NoError(t, err)
+
+	storage := &fakeKeyMetadataStorage{keys: make(map[string]*models.DataKey)}
+	_, storage.keys["a"], err = lostKeyring.GenerateDataKey("a")
+	require.NoError(t, err)
+
+	_, err = RotateDataKeys(context.Background(), storage, keyring)
+	assert.ErrorIs(t, err, models.MasterKeyMissingError)
+}
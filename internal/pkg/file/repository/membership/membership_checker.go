@@ -0,0 +1,37 @@
+package membership
+
+import (
+	"context"
+
+	authproto "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type membershipChecker struct {
+	client authproto.AuthClient
+}
+
+// NewMembershipChecker asks the auth service, because organizations and their members are stored there
+func NewMembershipChecker(client authproto.AuthClient) fileinterfaces.MembershipChecker {
+	return &membershipChecker{client: client}
+}
+
+func (c *membershipChecker) IsMember(ctx context.Context, orgID, userID uint) (bool, error) {
+	_, err := c.client.GetMembership(ctx, &authproto.MembershipRequest{
+		OrgID:  uint32(orgID),
+		UserID: uint32(userID),
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,34 @@
+package membership
+
+import (
+	"context"
+
+	authproto "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type userChecker struct {
+	client authproto.AuthClient
+}
+
+// NewUserChecker asks the auth service, so purges start only after the user is really deleted
+func NewUserChecker(client authproto.AuthClient) fileinterfaces.UserChecker {
+	return &userChecker{client: client}
+}
+
+func (c *userChecker) UserExists(ctx context.Context, userID uint) (bool, error) {
+	_, err := c.client.GetUserByID(ctx, &authproto.UserIDData{ID: uint32(userID)})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type exportJobStorage struct {
+	pool dbinit.PostgresPool
+}
+
+func NewExportJobStorage(pool dbinit.PostgresPool) fileinterfaces.ExportJobStorage {
+	return &exportJobStorage{pool: pool}
+}
+
+// CreateExportJob returns the unfinished job of the owner if there is one
+func (s *exportJobStorage) CreateExportJob(
+	ctx context.Context, ownerID uint, email string, account []byte,
+) (*models.ExportJob, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var job models.ExportJob
+
+	line := tx.QueryRow(ctx, CreateExportJobQuery, uuid.NewString(), ownerID, email, account)
+	if err := scanExportJob(line, &job); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (s *exportJobStorage) GetExportJob(ctx context.Context, id string) (*models.ExportJob, error) {
+	var job models.ExportJob
+
+	line := s.pool.QueryRow(ctx, GetExportJobQuery, id)
+	if err := scanExportJob(line, &job); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (s *exportJobStorage) GetUnfinishedExportJobs(ctx context.Context) ([]*models.ExportJob, error) {
+	return s.getExportJobs(ctx, GetUnfinishedExportJobsQuery)
+}
+
+func (s *exportJobStorage) GetExpiredExportJobs(ctx context.Context) ([]*models.ExportJob, error) {
+	return s.getExportJobs(ctx, GetExpiredExportJobsQuery)
+}
+
+func (s *exportJobStorage) UpdateExportJob(ctx context.Context, job *models.ExportJob) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, UpdateExportJobQuery, job.ID, job.Status, job.ObjectKey, job.Size, job.Attempts,
+		job.Error, job.ExpireTime)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *exportJobStorage) getExportJobs(ctx context.Context, query string) ([]*models.ExportJob, error) {
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*models.ExportJob
+	for rows.Next() {
+		var job models.ExportJob
+		if err := scanExportJob(rows, &job); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func scanExportJob(row pgx.Row, job *models.ExportJob) error {
+	return row.Scan(&job.ID, &job.OwnerID, &job.Email, &job.Account, &job.Status, &job.ObjectKey, &job.Size,
+		&job.Attempts, &job.Error, &job.CreateTime, &job.UpdateTime, &job.ExpireTime)
+}
@@ -11,7 +11,13 @@ import (
 func (s *metadataStorage) GetFilesList(
 	ctx context.Context, ownerID uint, options models.FilesListOptions,
 ) ([]*models.FileMetadata, error) {
-	rows, err := s.pool.Query(ctx, GetFilesListQuery, ownerID, options.WithDeleted, options.Limit, options.Offset)
+	query, spaceID := GetFilesListQuery, ownerID
+	if options.OrgID != 0 {
+		query, spaceID = GetOrgFilesListQuery, options.OrgID
+	}
+
+	rows, err := s.pool.Query(ctx, query, spaceID, options.WithDeleted, options.Limit, options.Offset,
+		options.Filename)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -22,24 +28,34 @@ func (s *metadataStorage) GetFilesList(
 
 	var list []*models.FileMetadata
 	for rows.Next() {
-		var meta models.FileMetadata
-		if err := rows.Scan(&meta.UUID, &meta.OwnerID, &meta.Filename, &meta.ContentType, &meta.Size,
-			&meta.UploadTime, &meta.UpdateTime, &meta.StorageKey, &meta.IsDeleted, &meta.DeletedTime); err != nil {
+		meta, err := scanMetadata(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		list = append(list, &meta)
+		list = append(list, meta)
 	}
 
 	return list, nil
 }
 
 func (s *metadataStorage) GetMetadata(ctx context.Context, id string) (*models.FileMetadata, error) {
-	var meta models.FileMetadata
+	meta, err := scanMetadata(s.pool.QueryRow(ctx, GetMetadataQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
 
-	row := s.pool.QueryRow(ctx, GetMetadataQuery, id)
-	if err := row.Scan(&meta.UUID, &meta.OwnerID, &meta.Filename, &meta.ContentType, &meta.Size,
-		&meta.UploadTime, &meta.UpdateTime, &meta.StorageKey, &meta.IsDeleted, &meta.DeletedTime); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// GetPendingMetadata returns the file which is uploaded but not committed yet
+func (s *metadataStorage) GetPendingMetadata(ctx context.Context, id string) (*models.FileMetadata, error) {
+	meta, err := scanMetadata(s.pool.QueryRow(ctx, GetPendingMetadataQuery, id))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
@@ -47,5 +63,105 @@ func (s *metadataStorage) GetMetadata(ctx context.Context, id string) (*models.F
 		return nil, err
 	}
 
+	return meta, nil
+}
+
+func (s *metadataStorage) GetStaleDataKeys(ctx context.Context, keyID string,
+	limit uint) ([]*models.DataKey, error) {
+	rows, err := s.pool.Query(ctx, GetStaleDataKeysQuery, keyID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*models.DataKey
+	for rows.Next() {
+		var key models.DataKey
+		if err := rows.Scan(&key.FileID, &key.Key, &key.KeyID); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetStorageUsage returns the usage of the personal space and its quota or defaultQuota if it is not set
+func (s *metadataStorage) GetStorageUsage(
+	ctx context.Context, ownerID uint, defaultQuota int64,
+) (*models.StorageUsage, error) {
+	usage := models.StorageUsage{OwnerID: ownerID}
+
+	row := s.pool.QueryRow(ctx, GetStorageUsageQuery, ownerID, defaultQuota)
+	if err := row.Scan(&usage.UsedBytes, &usage.FilesCount, &usage.QuotaBytes); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+func (s *metadataStorage) GetStorageStats(ctx context.Context) (files, bytes, savedBytes int64, err error) {
+	row := s.pool.QueryRow(ctx, GetStorageStatsQuery)
+	if err := row.Scan(&files, &bytes, &savedBytes); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return files, bytes, savedBytes, nil
+}
+
+func (s *metadataStorage) GetOrgStorageUsage(
+	ctx context.Context, orgID uint, defaultQuota int64,
+) (*models.StorageUsage, error) {
+	usage := models.StorageUsage{OrgID: orgID}
+
+	row := s.pool.QueryRow(ctx, GetOrgStorageUsageQuery, orgID, defaultQuota)
+	if err := row.Scan(&usage.UsedBytes, &usage.FilesCount, &usage.QuotaBytes); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+func (s *metadataStorage) GetObjectRefs(ctx context.Context) ([]*models.ObjectRef, error) {
+	rows, err := s.pool.Query(ctx, GetObjectRefsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []*models.ObjectRef
+	for rows.Next() {
+		var ref models.ObjectRef
+		if err := rows.Scan(&ref.FileID, &ref.StorageKey, &ref.PendingKey, &ref.UploadState,
+			&ref.UpdateTime); err != nil {
+			return nil, err
+		}
+
+		refs = append(refs, &ref)
+	}
+
+	return refs, rows.Err()
+}
+
+// scanMetadata reads the columns selected by the metadata queries
+func scanMetadata(row pgx.Row) (*models.FileMetadata, error) {
+	var (
+		meta      models.FileMetadata
+		dataKey   []byte
+		dataKeyID string
+	)
+
+	if err := row.Scan(&meta.UUID, &meta.OwnerID, &meta.Filename, &meta.ContentType, &meta.Size,
+		&meta.UploadTime, &meta.UpdateTime, &meta.StorageKey, &meta.IsDeleted, &meta.DeletedTime,
+		&meta.OrgID, &dataKey, &dataKeyID, &meta.Stored.Encoding, &meta.Stored.Size,
+		&meta.Stored.Checksum); err != nil {
+		return nil, err
+	}
+
+	if dataKey != nil {
+		meta.DataKey = &models.DataKey{FileID: meta.UUID, Key: dataKey, KeyID: dataKeyID}
+	}
+
 	return &meta, nil
 }
@@ -3,24 +3,67 @@ package repository
 const (
 	GetFilesListQuery = `
 		SELECT id, owner_id, filename, content_type, "size", upload_time, update_time, storage_key, 
-		       is_deleted, deleted_time
+		       is_deleted, deleted_time, COALESCE(org_id, 0), data_key, COALESCE(data_key_id, ''),
+		       COALESCE(content_encoding, ''), COALESCE(stored_size, "size"), COALESCE(checksum, '')
 		FROM public.file_metadata
-		WHERE owner_id = $1 AND is_deleted = $2
+		WHERE owner_id = $1 AND org_id IS NULL AND is_deleted = $2 AND upload_state = 'committed'
+		      AND ($5 = '' OR filename = $5)
+		ORDER BY upload_time, id
+		LIMIT $3 OFFSET $4;
+	`
+
+	GetOrgFilesListQuery = `
+		SELECT id, owner_id, filename, content_type, "size", upload_time, update_time, storage_key, 
+		       is_deleted, deleted_time, COALESCE(org_id, 0), data_key, COALESCE(data_key_id, ''),
+		       COALESCE(content_encoding, ''), COALESCE(stored_size, "size"), COALESCE(checksum, '')
+		FROM public.file_metadata
+		WHERE org_id = $1 AND is_deleted = $2 AND upload_state = 'committed'
+		      AND ($5 = '' OR filename = $5)
+		ORDER BY upload_time, id
 		LIMIT $3 OFFSET $4;
 	`
 
 	GetMetadataQuery = `
 		SELECT id, owner_id, filename, content_type, "size", upload_time, update_time, storage_key, 
-		       is_deleted, deleted_time
+		       is_deleted, deleted_time, COALESCE(org_id, 0), data_key, COALESCE(data_key_id, ''),
+		       COALESCE(content_encoding, ''), COALESCE(stored_size, "size"), COALESCE(checksum, '')
 		FROM public.file_metadata
-		WHERE id = $1 AND NOT(is_deleted);
+		WHERE id = $1 AND NOT(is_deleted) AND upload_state = 'committed';
+	`
+
+	GetPendingMetadataQuery = `
+		SELECT id, owner_id, filename, content_type, "size", upload_time, update_time, storage_key, 
+		       is_deleted, deleted_time, COALESCE(org_id, 0), data_key, COALESCE(data_key_id, ''),
+		       COALESCE(content_encoding, ''), COALESCE(stored_size, "size"), COALESCE(checksum, '')
+		FROM public.file_metadata
+		WHERE id = $1 AND upload_state = 'pending';
+	`
+
+	SetDataKeyQuery = `
+		UPDATE public.file_metadata
+		SET data_key = $2, data_key_id = $3
+		WHERE id = $1 AND upload_state = 'pending';
+	`
+
+	GetStaleDataKeysQuery = `
+		SELECT id, data_key, data_key_id
+		FROM public.file_metadata
+		WHERE data_key_id <> $1
+		LIMIT $2;
+	`
+
+	ReplaceDataKeyQuery = `
+		UPDATE public.file_metadata
+		SET data_key = $3, data_key_id = $4
+		WHERE id = $1 AND data_key_id = $2;
 	`
 
 	UploadMetadataQuery = `
-		INSERT INTO public.file_metadata (id, owner_id, filename, content_type, size, storage_key)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO public.file_metadata (id, owner_id, org_id, filename, content_type, size, storage_key,
+		                                  upload_state)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7, 'pending')
 		RETURNING id, owner_id, filename, content_type, "size", upload_time, update_time, storage_key, 
-		       is_deleted, deleted_time;
+		       is_deleted, deleted_time, COALESCE(org_id, 0);
 	`
 
 	UpdateFilenameQuery = `
@@ -29,10 +72,37 @@ const (
 		WHERE id = $1;
 	`
 
-	UpdateSizeQuery = `
+	CommitUploadQuery = `
 		UPDATE public.file_metadata
-		SET size = $2
-		WHERE id = $1;
+		SET upload_state = 'committed', content_encoding = NULLIF($2, ''), stored_size = $3,
+		    checksum = NULLIF($4, '')
+		WHERE id = $1 AND upload_state = 'pending';
+	`
+
+	BeginReplaceQuery = `
+		UPDATE public.file_metadata
+		SET pending_storage_key = $2
+		WHERE id = $1 AND NOT(is_deleted);
+	`
+
+	FinishReplaceQuery = `
+		UPDATE public.file_metadata AS f
+		SET storage_key = f.pending_storage_key, size = $3, pending_storage_key = NULL,
+		    content_encoding = NULLIF($4, ''), stored_size = $5, checksum = NULLIF($6, '')
+		FROM (SELECT storage_key FROM public.file_metadata WHERE id = $1 FOR UPDATE) AS old
+		WHERE f.id = $1 AND f.pending_storage_key = $2
+		RETURNING old.storage_key;
+	`
+
+	ClearPendingKeyQuery = `
+		UPDATE public.file_metadata
+		SET pending_storage_key = NULL
+		WHERE id = $1 AND pending_storage_key = $2;
+	`
+
+	GetObjectRefsQuery = `
+		SELECT id, storage_key, COALESCE(pending_storage_key, ''), upload_state, update_time
+		FROM public.file_metadata;
 	`
 
 	DeleteFileQuery = `
@@ -40,4 +110,150 @@ const (
 		SET is_deleted = TRUE
 		WHERE id = $1;
 	`
+
+	DeleteOwnerFilesQuery = `
+		DELETE
+		FROM public.file_metadata
+		WHERE owner_id = $1 AND org_id IS NULL;
+	`
+
+	// Files of organizations stay in the shared storage, only the reference to the uploader is removed
+	DetachOwnerOrgFilesQuery = `
+		UPDATE public.file_metadata
+		SET owner_id = 0
+		WHERE owner_id = $1 AND org_id IS NOT NULL;
+	`
+
+	DeleteOwnerQuotaQuery = `
+		DELETE
+		FROM public.storage_quota
+		WHERE owner_id = $1;
+	`
+
+	RemoveFileQuery = `
+		DELETE
+		FROM public.file_metadata
+		WHERE id = $1
+		RETURNING id, owner_id, filename, content_type, "size", upload_time, update_time, storage_key, 
+		       is_deleted, deleted_time, COALESCE(org_id, 0);
+	`
+
+	// LockStorageQuery serializes changes of the used storage of an owner or an organization
+	LockStorageQuery = `
+		SELECT pg_advisory_xact_lock($1, $2);
+	`
+
+	GetFileOwnerQuery = `
+		SELECT owner_id, COALESCE(org_id, 0)
+		FROM public.file_metadata
+		WHERE id = $1;
+	`
+
+	GetFileSizeQuery = `
+		SELECT "size"
+		FROM public.file_metadata
+		WHERE id = $1;
+	`
+
+	GetStorageUsageQuery = `
+		SELECT COALESCE(SUM("size") FILTER (WHERE NOT(is_deleted)), 0),
+		       COUNT(*) FILTER (WHERE NOT(is_deleted)),
+		       COALESCE((SELECT quota_bytes FROM public.storage_quota WHERE owner_id = $1), $2)
+		FROM public.file_metadata
+		WHERE owner_id = $1 AND org_id IS NULL;
+	`
+
+	GetStorageStatsQuery = `
+		SELECT COUNT(*), COALESCE(SUM("size"), 0),
+		       COALESCE(SUM("size" - stored_size) FILTER (WHERE content_encoding IS NOT NULL), 0)
+		FROM public.file_metadata
+		WHERE NOT(is_deleted);
+	`
+
+	GetOrgStorageUsageQuery = `
+		SELECT COALESCE(SUM("size") FILTER (WHERE NOT(is_deleted)), 0),
+		       COUNT(*) FILTER (WHERE NOT(is_deleted)),
+		       COALESCE((SELECT quota_bytes FROM public.org_storage_quota WHERE org_id = $1), $2)
+		FROM public.file_metadata
+		WHERE org_id = $1;
+	`
+
+	SetQuotaQuery = `
+		INSERT INTO public.storage_quota (owner_id, quota_bytes)
+		VALUES ($1, $2)
+		ON CONFLICT (owner_id)
+		DO UPDATE SET quota_bytes = EXCLUDED.quota_bytes, update_time = NOW();
+	`
+
+	SetOrgQuotaQuery = `
+		INSERT INTO public.org_storage_quota (org_id, quota_bytes)
+		VALUES ($1, $2)
+		ON CONFLICT (org_id)
+		DO UPDATE SET quota_bytes = EXCLUDED.quota_bytes, update_time = NOW();
+	`
+
+	CreatePurgeJobQuery = `
+		INSERT INTO public.purge_job (id, owner_id, status)
+		VALUES ($1, $2, 'waiting')
+		ON CONFLICT (owner_id) WHERE status IN ('waiting', 'pending', 'running')
+		DO UPDATE SET update_time = NOW()
+		RETURNING id, owner_id, status, deleted_objects, attempts, error, create_time, update_time;
+	`
+
+	GetPurgeJobQuery = `
+		SELECT id, owner_id, status, deleted_objects, attempts, error, create_time, update_time
+		FROM public.purge_job
+		WHERE id = $1;
+	`
+
+	GetUnfinishedPurgeJobsQuery = `
+		SELECT id, owner_id, status, deleted_objects, attempts, error, create_time, update_time
+		FROM public.purge_job
+		WHERE status IN ('waiting', 'pending', 'running')
+		ORDER BY create_time;
+	`
+
+	UpdatePurgeJobQuery = `
+		UPDATE public.purge_job
+		SET status = $2, deleted_objects = $3, attempts = $4, error = $5, update_time = NOW()
+		WHERE id = $1;
+	`
+
+	CreateExportJobQuery = `
+		INSERT INTO public.export_job (id, owner_id, email, account)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (owner_id) WHERE status IN ('pending', 'running')
+		DO UPDATE SET update_time = NOW()
+		RETURNING id, owner_id, email, account, status, object_key, size, attempts, error, 
+		       create_time, update_time, expire_time;
+	`
+
+	GetExportJobQuery = `
+		SELECT id, owner_id, email, account, status, object_key, size, attempts, error, 
+		       create_time, update_time, expire_time
+		FROM public.export_job
+		WHERE id = $1;
+	`
+
+	GetUnfinishedExportJobsQuery = `
+		SELECT id, owner_id, email, account, status, object_key, size, attempts, error, 
+		       create_time, update_time, expire_time
+		FROM public.export_job
+		WHERE status IN ('pending', 'running')
+		ORDER BY create_time;
+	`
+
+	GetExpiredExportJobsQuery = `
+		SELECT id, owner_id, email, account, status, object_key, size, attempts, error, 
+		       create_time, update_time, expire_time
+		FROM public.export_job
+		WHERE status = 'done' AND expire_time < NOW();
+	`
+
+	UpdateExportJobQuery = `
+		UPDATE public.export_job
+		SET status = $2, object_key = $3, size = $4, attempts = $5, error = $6, expire_time = $7, 
+		    update_time = NOW()
+		WHERE id = $1;
+	`
 )
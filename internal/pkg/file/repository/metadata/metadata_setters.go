@@ -2,14 +2,22 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/IlyaChgn/voblako/internal/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Lock keys of LockStorageQuery, the second key is the ID of the owner or the organization
+const (
+	storageLockOwner = 1
+	storageLockOrg   = 2
 )
 
 func (s *metadataStorage) UploadMetadata(
-	ctx context.Context, ownerID uint, filename, contentType string, size int64,
+	ctx context.Context, ownerID, orgID uint, filename, contentType string, size, defaultQuota int64,
 ) (*models.FileMetadata, error) {
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
@@ -17,13 +25,26 @@ func (s *metadataStorage) UploadMetadata(
 	}
 	defer tx.Rollback(ctx)
 
+	// Pending files are counted as used, so the size is reserved as soon as the row is committed
+	if err := lockStorage(ctx, tx, ownerID, orgID); err != nil {
+		return nil, err
+	}
+	if err := checkQuota(ctx, tx, ownerID, orgID, size, defaultQuota); err != nil {
+		return nil, err
+	}
+
 	var meta models.FileMetadata
 	id := uuid.NewString()
 	key := fmt.Sprintf("%d/%s", ownerID, id)
+	if orgID != 0 {
+		// Organization files must survive the purge of the uploader, so they are kept out of the user prefix
+		key = fmt.Sprintf("org/%d/%s", orgID, id)
+	}
 
-	line := tx.QueryRow(ctx, UploadMetadataQuery, id, ownerID, filename, contentType, size, key)
+	line := tx.QueryRow(ctx, UploadMetadataQuery, id, ownerID, orgID, filename, contentType, size, key)
 	if err := line.Scan(&meta.UUID, &meta.OwnerID, &meta.Filename, &meta.ContentType, &meta.Size,
-		&meta.UploadTime, &meta.UpdateTime, &meta.StorageKey, &meta.IsDeleted, &meta.DeletedTime); err != nil {
+		&meta.UploadTime, &meta.UpdateTime, &meta.StorageKey, &meta.IsDeleted, &meta.DeletedTime,
+		&meta.OrgID); err != nil {
 		return nil, err
 	}
 
@@ -53,21 +74,105 @@ func (s *metadataStorage) UpdateFilename(ctx context.Context, id string, filenam
 	return nil
 }
 
-func (s *metadataStorage) UpdateSize(ctx context.Context, id string, size int64) error {
-	tx, err := s.pool.Begin(ctx)
+func (s *metadataStorage) CommitUpload(ctx context.Context, id string, stored models.StoredContent) error {
+	tag, err := s.pool.Exec(ctx, CommitUploadQuery, id, stored.Encoding, stored.Size, stored.Checksum)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback(ctx)
+	if tag.RowsAffected() == 0 {
+		return models.FileNotExists
+	}
+
+	return nil
+}
 
-	_, err = tx.Exec(ctx, UpdateSizeQuery, id, size)
+func (s *metadataStorage) BeginReplace(ctx context.Context, id, key string) error {
+	tag, err := s.pool.Exec(ctx, BeginReplaceQuery, id, key)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return models.FileNotExists
+	}
+
+	return nil
+}
+
+// FinishReplace fails with UpdateConflictError if another replacement has started after the given one
+func (s *metadataStorage) FinishReplace(ctx context.Context, id, key string, size int64,
+	stored models.StoredContent, defaultQuota int64) (string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var ownerID, orgID uint
+	if err := tx.QueryRow(ctx, GetFileOwnerQuery, id).Scan(&ownerID, &orgID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", models.FileNotExists
+		}
+
+		return "", err
+	}
+
+	if err := lockStorage(ctx, tx, ownerID, orgID); err != nil {
+		return "", err
+	}
+
+	// The size is read under the lock, so concurrent replacements are counted correctly
+	var oldSize int64
+	if err := tx.QueryRow(ctx, GetFileSizeQuery, id).Scan(&oldSize); err != nil {
+		return "", err
+	}
+	if err := checkQuota(ctx, tx, ownerID, orgID, size-oldSize, defaultQuota); err != nil {
+		return "", err
+	}
+
+	var oldKey string
+	if err := tx.QueryRow(ctx, FinishReplaceQuery, id, key, size, stored.Encoding, stored.Size,
+		stored.Checksum).Scan(&oldKey); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", models.UpdateConflictError
+		}
+
+		return "", err
+	}
 
 	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+
+	return oldKey, nil
+}
+
+func (s *metadataStorage) ClearPendingKey(ctx context.Context, id, key string) error {
+	_, err := s.pool.Exec(ctx, ClearPendingKeyQuery, id, key)
+	return err
+}
+
+// SetDataKey is allowed only while the file is pending, so the content is never stored without its key
+func (s *metadataStorage) SetDataKey(ctx context.Context, key *models.DataKey) error {
+	tag, err := s.pool.Exec(ctx, SetDataKeyQuery, key.FileID, key.Key, key.KeyID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.FileNotExists
+	}
+
+	return nil
+}
+
+// ReplaceDataKey returns models.UpdateConflictError if the key was changed since it was read
+func (s *metadataStorage) ReplaceDataKey(ctx context.Context, oldKey, newKey *models.DataKey) error {
+	tag, err := s.pool.Exec(ctx, ReplaceDataKeyQuery, oldKey.FileID, oldKey.KeyID, newKey.Key, newKey.KeyID)
+	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return models.UpdateConflictError
+	}
 
 	return nil
 }
@@ -90,3 +195,122 @@ func (s *metadataStorage) DeleteFile(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (s *metadataStorage) DeleteOwnerFiles(ctx context.Context, ownerID uint) (int64, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, DeleteOwnerFilesQuery, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, DetachOwnerOrgFilesQuery, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, DeleteOwnerQuotaQuery, ownerID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (s *metadataStorage) RemoveFile(ctx context.Context, id string) (*models.FileMetadata, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var meta models.FileMetadata
+
+	line := tx.QueryRow(ctx, RemoveFileQuery, id)
+	if err := line.Scan(&meta.UUID, &meta.OwnerID, &meta.Filename, &meta.ContentType, &meta.Size,
+		&meta.UploadTime, &meta.UpdateTime, &meta.StorageKey, &meta.IsDeleted, &meta.DeletedTime,
+		&meta.OrgID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.FileNotExists
+		}
+
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
+func (s *metadataStorage) SetQuota(ctx context.Context, ownerID uint, quota int64) error {
+	return s.setQuota(ctx, SetQuotaQuery, ownerID, quota)
+}
+
+func (s *metadataStorage) SetOrgQuota(ctx context.Context, orgID uint, quota int64) error {
+	return s.setQuota(ctx, SetOrgQuotaQuery, orgID, quota)
+}
+
+func (s *metadataStorage) setQuota(ctx context.Context, query string, spaceID uint, quota int64) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query, spaceID, quota)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// lockStorage serializes changes of the used storage of the owner or the organization until the transaction
+// ends, so concurrent uploads cannot exceed the quota together
+func lockStorage(ctx context.Context, tx pgx.Tx, ownerID, orgID uint) error {
+	if orgID != 0 {
+		_, err := tx.Exec(ctx, LockStorageQuery, storageLockOrg, orgID)
+		return err
+	}
+
+	_, err := tx.Exec(ctx, LockStorageQuery, storageLockOwner, ownerID)
+
+	return err
+}
+
+// checkQuota must be called under lockStorage. Zero quota means that the storage is unlimited.
+func checkQuota(ctx context.Context, tx pgx.Tx, ownerID, orgID uint, delta, defaultQuota int64) error {
+	if delta <= 0 {
+		return nil
+	}
+
+	query, id := GetStorageUsageQuery, ownerID
+	if orgID != 0 {
+		query, id = GetOrgStorageUsageQuery, orgID
+	}
+
+	var used, files, quota int64
+	if err := tx.QueryRow(ctx, query, id, defaultQuota).Scan(&used, &files, &quota); err != nil {
+		return err
+	}
+
+	if quota > 0 && used+delta > quota {
+		return models.QuotaExceededError
+	}
+
+	return nil
+}
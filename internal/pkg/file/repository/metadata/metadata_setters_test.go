@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataStorage_UploadMetadata(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewMetadataStorage(mock)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(storageLockOwner, uint(1)).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery("SELECT COALESCE\\(SUM").WithArgs(uint(1), int64(100)).
+		WillReturnRows(pgxmock.NewRows([]string{"used", "files", "quota"}).AddRow(int64(60), int64(2), int64(100)))
+	mock.ExpectQuery("INSERT INTO public.file_metadata").
+		WithArgs(pgxmock.AnyArg(), uint(1), uint(0), "a.txt", "text/plain", int64(40), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "owner_id", "filename", "content_type", "size", "upload_time",
+			"update_time", "storage_key", "is_deleted", "deleted_time", "org_id"}).
+			AddRow("id", uint(1), "a.txt", "text/plain", int64(40), now, now, "1/id", false, nil, uint(0)))
+	mock.ExpectCommit()
+
+	meta, err := s.UploadMetadata(context.Background(), 1, 0, "a.txt", "text/plain", 40, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(40), meta.Size)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMetadataStorage_UploadMetadata_QuotaExceeded(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewMetadataStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(storageLockOrg, uint(3)).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery("FROM public.org_storage_quota").WithArgs(uint(3), int64(0)).
+		WillReturnRows(pgxmock.NewRows([]string{"used", "files", "quota"}).AddRow(int64(60), int64(2), int64(100)))
+	mock.ExpectRollback()
+
+	_, err = s.UploadMetadata(context.Background(), 1, 3, "a.txt", "text/plain", 41, 0)
+
+	assert.ErrorIs(t, err, models.QuotaExceededError)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMetadataStorage_FinishReplace_QuotaExceeded(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewMetadataStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT owner_id, COALESCE\\(org_id, 0\\)").WithArgs("id").
+		WillReturnRows(pgxmock.NewRows([]string{"owner_id", "org_id"}).AddRow(uint(1), uint(0)))
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(storageLockOwner, uint(1)).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery("SELECT \"size\"").WithArgs("id").
+		WillReturnRows(pgxmock.NewRows([]string{"size"}).AddRow(int64(10)))
+	mock.ExpectQuery("FROM public.storage_quota").WithArgs(uint(1), int64(100)).
+		WillReturnRows(pgxmock.NewRows([]string{"used", "files", "quota"}).AddRow(int64(90), int64(2), int64(100)))
+	mock.ExpectRollback()
+
+	_, err = s.FinishReplace(context.Background(), "id", "1/new", 21, models.StoredContent{Size: 21}, 100)
+
+	assert.ErrorIs(t, err, models.QuotaExceededError)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMetadataStorage_DeleteOwnerFiles(t *testing.T) {
+	mock, err := pgxmock.NewPool(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	s := NewMetadataStorage(mock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM public.file_metadata WHERE owner_id = \\$1 AND org_id IS NULL").
+		WithArgs(uint(1)).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	// Files of organizations stay, but lose the reference to the deleted user
+	mock.ExpectExec("UPDATE public.file_metadata SET owner_id = 0 WHERE owner_id = \\$1 AND org_id IS NOT NULL").
+		WithArgs(uint(1)).WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectExec("DELETE FROM public.storage_quota").WithArgs(uint(1)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+
+	deleted, err := s.DeleteOwnerFiles(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
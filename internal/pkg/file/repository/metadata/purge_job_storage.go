@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type purgeJobStorage struct {
+	pool dbinit.PostgresPool
+}
+
+func NewPurgeJobStorage(pool dbinit.PostgresPool) fileinterfaces.PurgeJobStorage {
+	return &purgeJobStorage{pool: pool}
+}
+
+// CreatePurgeJob returns the unfinished job of the owner if there is one, so repeated calls are idempotent
+func (s *purgeJobStorage) CreatePurgeJob(ctx context.Context, ownerID uint) (*models.PurgeJob, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var job models.PurgeJob
+
+	line := tx.QueryRow(ctx, CreatePurgeJobQuery, uuid.NewString(), ownerID)
+	if err := scanPurgeJob(line, &job); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (s *purgeJobStorage) GetPurgeJob(ctx context.Context, id string) (*models.PurgeJob, error) {
+	var job models.PurgeJob
+
+	line := s.pool.QueryRow(ctx, GetPurgeJobQuery, id)
+	if err := scanPurgeJob(line, &job); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (s *purgeJobStorage) GetUnfinishedPurgeJobs(ctx context.Context) ([]*models.PurgeJob, error) {
+	rows, err := s.pool.Query(ctx, GetUnfinishedPurgeJobsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*models.PurgeJob
+	for rows.Next() {
+		var job models.PurgeJob
+		if err := scanPurgeJob(rows, &job); err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (s *purgeJobStorage) UpdatePurgeJob(ctx context.Context, job *models.PurgeJob) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, UpdatePurgeJobQuery, job.ID, job.Status, job.DeletedObjects, job.Attempts, job.Error)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func scanPurgeJob(row pgx.Row, job *models.PurgeJob) error {
+	return row.Scan(&job.ID, &job.OwnerID, &job.Status, &job.DeletedObjects, &job.Attempts, &job.Error,
+		&job.CreateTime, &job.UpdateTime)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/repository/object/storagetest"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) fileinterfaces.ObjectStorage {
+		storage, err := NewFSStorage(t.TempDir())
+		require.NoError(t, err)
+
+		return storage
+	})
+}
+
+func TestMemoryStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) fileinterfaces.ObjectStorage {
+		return NewMemoryStorage()
+	})
+}
+
+// The MinIO backend is checked only against a real server, e.g. MINIO_TEST_ENDPOINT=localhost:9000
+func TestObjectStorage_Conformance(t *testing.T) {
+	endpoint := os.Getenv("MINIO_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MINIO_TEST_ENDPOINT is not set")
+	}
+
+	storagetest.Run(t, func(t *testing.T) fileinterfaces.ObjectStorage {
+		bucket := "voblako-test-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+		client, err := dbinit.NewMinioClient(endpoint, os.Getenv("MINIO_TEST_ACCESS_KEY"),
+			os.Getenv("MINIO_TEST_SECRET_KEY"), bucket)
+		require.NoError(t, err)
+
+		return NewObjectStorage(client, nil, bucket)
+	})
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
+)
+
+const (
+	fsObjectsDir = "objects"
+	fsTempDir    = "tmp"
+)
+
+// fsStorage keeps objects under root/objects/<aa>/<bb>/<encoded key>, where aa and bb are the first bytes of
+// the key hash, so directories stay small. Keys are encoded, so they cannot escape the root.
+type fsStorage struct {
+	root string
+}
+
+func NewFSStorage(root string) (fileinterfaces.ObjectStorage, error) {
+	for _, dir := range []string{fsObjectsDir, fsTempDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o750); err != nil {
+			return nil, err
+		}
+	}
+
+	return &fsStorage{root: root}, nil
+}
+
+func (s *fsStorage) UploadFile(ctx context.Context, key, contentType string, file []byte, size int64) error {
+	return s.UploadStream(ctx, key, contentType, bytes.NewReader(file), size)
+}
+
+// UploadStream writes into a temporary file and renames it, so readers never see a partial object
+func (s *fsStorage) UploadStream(ctx context.Context, key, _ string, reader io.Reader, size int64) (err error) {
+	defer metrics.ObserveStorage(metrics.StorageFS, "put_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageFS, "put_object")
+	defer tracing.End(span, &err)
+
+	temp, err := os.CreateTemp(filepath.Join(s.root, fsTempDir), "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	defer temp.Close()
+
+	written, err := io.Copy(temp, readerWithContext(ctx, reader))
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return io.ErrUnexpectedEOF
+	}
+	if err := temp.Sync(); err != nil {
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), path)
+}
+
+func (s *fsStorage) PresignedGetURL(_ context.Context, _, _ string, _ time.Duration) (string, error) {
+	return "", models.PresignNotSupportedError
+}
+
+func (s *fsStorage) PresignedPutURL(_ context.Context, _ string, _ time.Duration) (string, error) {
+	return "", models.PresignNotSupportedError
+}
+
+func (s *fsStorage) StatFile(ctx context.Context, key string) (_ *models.ObjectInfo, err error) {
+	defer metrics.ObserveStorage(metrics.StorageFS, "stat_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageFS, "stat_object")
+	defer tracing.End(span, &err)
+
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, models.FileNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	size, err := io.Copy(hash, readerWithContext(ctx, file))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ObjectInfo{Size: size, Checksum: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+func (s *fsStorage) GetFile(ctx context.Context, key string) (_ []byte, err error) {
+	defer metrics.ObserveStorage(metrics.StorageFS, "get_object", time.Now())
+	_, span := tracing.StartStorage(ctx, metrics.StorageFS, "get_object")
+	defer tracing.End(span, &err)
+
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, models.FileNotExists
+	}
+
+	return data, err
+}
+
+func (s *fsStorage) OpenFile(ctx context.Context, key string) (_ fileinterfaces.ObjectReader, err error) {
+	defer metrics.ObserveStorage(metrics.StorageFS, "open_object", time.Now())
+	_, span := tracing.StartStorage(ctx, metrics.StorageFS, "open_object")
+	defer tracing.End(span, &err)
+
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, models.FileNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Objects are replaced by renames, so the open file keeps the content it was opened with
+	return &fsObject{File: file, size: info.Size()}, nil
+}
+
+type fsObject struct {
+	*os.File
+	size int64
+}
+
+func (o *fsObject) Size() int64 {
+	return o.size
+}
+
+func (s *fsStorage) ListKeys(ctx context.Context, prefix string) (_ []string, err error) {
+	defer metrics.ObserveStorage(metrics.StorageFS, "list_objects", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageFS, "list_objects")
+	defer tracing.End(span, &err)
+
+	var keys []string
+
+	err = filepath.WalkDir(filepath.Join(s.root, fsObjectsDir), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		key, err := base64.RawURLEncoding.DecodeString(entry.Name())
+		if err != nil {
+			// Files not written by the storage are skipped
+			return nil
+		}
+		if strings.HasPrefix(string(key), prefix) {
+			keys = append(keys, string(key))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+func (s *fsStorage) DeleteFiles(ctx context.Context, keys []string) (err error) {
+	defer metrics.ObserveStorage(metrics.StorageFS, "remove_objects", time.Now())
+	_, span := tracing.StartStorage(ctx, metrics.StorageFS, "remove_objects")
+	defer tracing.End(span, &err)
+
+	for _, key := range keys {
+		if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fsStorage) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	shard := hex.EncodeToString(hash[:2])
+
+	return filepath.Join(s.root, fsObjectsDir, shard[:2], shard[2:], base64.RawURLEncoding.EncodeToString([]byte(key)))
+}
+
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+// readerWithContext stops long copies once the call is cancelled, as files are not aware of the context
+func readerWithContext(ctx context.Context, reader io.Reader) io.Reader {
+	return &contextReader{ctx: ctx, reader: reader}
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.reader.Read(p)
+}
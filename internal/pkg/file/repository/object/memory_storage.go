@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+)
+
+// memoryStorage keeps objects in the process memory, it is meant for development and tests
+type memoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+func NewMemoryStorage() fileinterfaces.ObjectStorage {
+	return &memoryStorage{objects: make(map[string][]byte)}
+}
+
+func (s *memoryStorage) UploadFile(ctx context.Context, key, contentType string, file []byte, size int64) error {
+	return s.UploadStream(ctx, key, contentType, bytes.NewReader(file), size)
+}
+
+func (s *memoryStorage) UploadStream(ctx context.Context, key, _ string, reader io.Reader, size int64) error {
+	defer metrics.ObserveStorage(metrics.StorageMemory, "put_object", time.Now())
+
+	data, err := io.ReadAll(readerWithContext(ctx, reader))
+	if err != nil {
+		return err
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return io.ErrUnexpectedEOF
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = data
+
+	return nil
+}
+
+func (s *memoryStorage) PresignedGetURL(_ context.Context, _, _ string, _ time.Duration) (string, error) {
+	return "", models.PresignNotSupportedError
+}
+
+func (s *memoryStorage) PresignedPutURL(_ context.Context, _ string, _ time.Duration) (string, error) {
+	return "", models.PresignNotSupportedError
+}
+
+func (s *memoryStorage) StatFile(_ context.Context, key string) (*models.ObjectInfo, error) {
+	defer metrics.ObserveStorage(metrics.StorageMemory, "stat_object", time.Now())
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, models.FileNotExists
+	}
+
+	sum := md5.Sum(data)
+
+	return &models.ObjectInfo{Size: int64(len(data)), Checksum: hex.EncodeToString(sum[:])}, nil
+}
+
+func (s *memoryStorage) GetFile(_ context.Context, key string) ([]byte, error) {
+	defer metrics.ObserveStorage(metrics.StorageMemory, "get_object", time.Now())
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, models.FileNotExists
+	}
+
+	return slices.Clone(data), nil
+}
+
+func (s *memoryStorage) OpenFile(_ context.Context, key string) (fileinterfaces.ObjectReader, error) {
+	defer metrics.ObserveStorage(metrics.StorageMemory, "open_object", time.Now())
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, models.FileNotExists
+	}
+
+	// Objects are replaced as a whole, so the reader keeps the content it was opened with
+	return memoryObject{Reader: bytes.NewReader(data)}, nil
+}
+
+type memoryObject struct {
+	*bytes.Reader
+}
+
+func (memoryObject) Close() error {
+	return nil
+}
+
+func (s *memoryStorage) ListKeys(_ context.Context, prefix string) ([]string, error) {
+	defer metrics.ObserveStorage(metrics.StorageMemory, "list_objects", time.Now())
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+func (s *memoryStorage) DeleteFiles(_ context.Context, keys []string) error {
+	defer metrics.ObserveStorage(metrics.StorageMemory, "remove_objects", time.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.objects, key)
+	}
+
+	return nil
+}
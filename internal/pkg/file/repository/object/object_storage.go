@@ -3,26 +3,61 @@ package repository
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/IlyaChgn/voblako/internal/models"
 	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
+
 	"github.com/minio/minio-go/v7"
 )
 
 type objectStorage struct {
 	bucketName string
 	client     *minio.Client
+
+	// presignClient signs URLs for the public endpoint, which may differ from the internal one
+	presignClient *minio.Client
 }
 
-func NewObjectStorage(client *minio.Client, bucketName string) fileinterfaces.ObjectStorage {
+func NewObjectStorage(client, presignClient *minio.Client, bucketName string) fileinterfaces.ObjectStorage {
+	if presignClient == nil {
+		presignClient = client
+	}
+
 	return &objectStorage{
-		bucketName: bucketName,
-		client:     client,
+		bucketName:    bucketName,
+		client:        client,
+		presignClient: presignClient,
+	}
+}
+
+func (s *objectStorage) UploadFile(ctx context.Context, key, contentType string, file []byte, size int64) (err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "put_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "put_object")
+	defer tracing.End(span, &err)
+
+	_, err = s.client.PutObject(ctx, s.bucketName, key, bytes.NewReader(file), size,
+		minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return err
 	}
+
+	return nil
 }
 
-func (s *objectStorage) UploadFile(ctx context.Context, key, contentType string, file []byte, size int64) error {
-	_, err := s.client.PutObject(ctx, s.bucketName, key, bytes.NewReader(file), size,
+func (s *objectStorage) UploadStream(ctx context.Context, key, contentType string, reader io.Reader,
+	size int64) (err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "put_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "put_object")
+	defer tracing.End(span, &err)
+
+	_, err = s.client.PutObject(ctx, s.bucketName, key, reader, size,
 		minio.PutObjectOptions{ContentType: contentType})
 	if err != nil {
 		return err
@@ -31,17 +66,148 @@ func (s *objectStorage) UploadFile(ctx context.Context, key, contentType string,
 	return nil
 }
 
-func (s *objectStorage) GetFile(ctx context.Context, key string) ([]byte, error) {
+func (s *objectStorage) PresignedGetURL(ctx context.Context, key, filename string,
+	ttl time.Duration) (_ string, err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "presign", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "presign")
+	defer tracing.End(span, &err)
+
+	params := make(url.Values)
+	params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	presignedURL, err := s.presignClient.PresignedGetObject(ctx, s.bucketName, key, ttl, params)
+	if err != nil {
+		return "", err
+	}
+
+	return presignedURL.String(), nil
+}
+
+func (s *objectStorage) PresignedPutURL(ctx context.Context, key string, ttl time.Duration) (_ string, err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "presign", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "presign")
+	defer tracing.End(span, &err)
+
+	presignedURL, err := s.presignClient.PresignedPutObject(ctx, s.bucketName, key, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	return presignedURL.String(), nil
+}
+
+// StatFile takes the checksum from the ETag, which is the MD5 of the content for objects put in one request
+func (s *objectStorage) StatFile(ctx context.Context, key string) (_ *models.ObjectInfo, err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "stat_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "stat_object")
+	defer tracing.End(span, &err)
+
+	info, err := s.client.StatObject(ctx, s.bucketName, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, models.FileNotExists
+		}
+
+		return nil, err
+	}
+
+	return &models.ObjectInfo{Size: info.Size, Checksum: strings.Trim(info.ETag, `"`)}, nil
+}
+
+func (s *objectStorage) GetFile(ctx context.Context, key string) (_ []byte, err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "get_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "get_object")
+	defer tracing.End(span, &err)
+
 	obj, err := s.client.GetObject(ctx, s.bucketName, key, minio.GetObjectOptions{})
 	if err != nil {
 		return nil, err
 	}
 	defer obj.Close()
 
+	// The object is requested lazily, so a missing key is reported by the first read
 	data, err := io.ReadAll(obj)
 	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, models.FileNotExists
+		}
+
 		return nil, err
 	}
 
 	return data, nil
 }
+
+// OpenFile requests only the ranges that are read, so large objects are never loaded as a whole
+func (s *objectStorage) OpenFile(ctx context.Context, key string) (_ fileinterfaces.ObjectReader, err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "open_object", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "open_object")
+	defer tracing.End(span, &err)
+
+	obj, err := s.client.GetObject(ctx, s.bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, models.FileNotExists
+		}
+
+		return nil, err
+	}
+
+	return &minioObject{Object: obj, size: info.Size}, nil
+}
+
+type minioObject struct {
+	*minio.Object
+	size int64
+}
+
+func (o *minioObject) Size() int64 {
+	return o.size
+}
+
+func (s *objectStorage) ListKeys(ctx context.Context, prefix string) (_ []string, err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "list_objects", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "list_objects")
+	defer tracing.End(span, &err)
+
+	var keys []string
+
+	for obj := range s.client.ListObjects(ctx, s.bucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+
+		keys = append(keys, obj.Key)
+	}
+
+	return keys, nil
+}
+
+func (s *objectStorage) DeleteFiles(ctx context.Context, keys []string) (err error) {
+	defer metrics.ObserveStorage(metrics.StorageMinio, "remove_objects", time.Now())
+	ctx, span := tracing.StartStorage(ctx, metrics.StorageMinio, "remove_objects")
+	defer tracing.End(span, &err)
+
+	objects := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		objects <- minio.ObjectInfo{Key: key}
+	}
+	close(objects)
+
+	for removeErr := range s.client.RemoveObjects(ctx, s.bucketName, objects, minio.RemoveObjectsOptions{}) {
+		if removeErr.Err != nil {
+			return removeErr.Err
+		}
+	}
+
+	return nil
+}
@@ -72,7 +72,7 @@ func TestObjectStorage_UploadFile(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	storage := NewObjectStorage(mc, "test-bucket")
+	storage := NewObjectStorage(mc, nil, "test-bucket")
 
 	tests := []struct {
 		name        string
@@ -157,7 +157,7 @@ func TestObjectStorage_GetFile(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	storage := NewObjectStorage(mc, "test-bucket")
+	storage := NewObjectStorage(mc, nil, "test-bucket")
 
 	tests := []struct {
 		name    string
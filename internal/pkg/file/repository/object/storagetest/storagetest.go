@@ -0,0 +1,217 @@
+// Package storagetest contains the conformance suite every ObjectStorage backend has to pass
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run checks the behaviour the file service relies on. newStorage must return an empty storage on every call.
+func Run(t *testing.T, newStorage func(t *testing.T) fileinterfaces.ObjectStorage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, storage fileinterfaces.ObjectStorage)
+	}{
+		{"UploadAndGet", testUploadAndGet},
+		{"Overwrite", testOverwrite},
+		{"UploadStream", testUploadStream},
+		{"ShortStream", testShortStream},
+		{"GetMissing", testGetMissing},
+		{"OpenFile", testOpenFile},
+		{"OpenMissing", testOpenMissing},
+		{"ListKeys", testListKeys},
+		{"DeleteFiles", testDeleteFiles},
+		{"ConcurrentUploads", testConcurrentUploads},
+		{"PresignedGetURL", testPresignedGetURL},
+		{"PresignedPutURL", testPresignedPutURL},
+		{"StatFile", testStatFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+func testUploadAndGet(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+	data := []byte("hello, voblako")
+
+	require.NoError(t, storage.UploadFile(ctx, "1/file", "text/plain", data, int64(len(data))))
+
+	got, err := storage.GetFile(ctx, "1/file")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func testOverwrite(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+
+	require.NoError(t, storage.UploadFile(ctx, "1/file", "text/plain", []byte("old content"), 11))
+	require.NoError(t, storage.UploadFile(ctx, "1/file", "text/plain", []byte("new"), 3))
+
+	got, err := storage.GetFile(ctx, "1/file")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new"), got)
+}
+
+func testUploadStream(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 100_000)
+
+	err := storage.UploadStream(ctx, "exports/1.zip", "application/zip", bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	got, err := storage.GetFile(ctx, "exports/1.zip")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+// A stream shorter than announced must not leave a partial object behind
+func testShortStream(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+
+	err := storage.UploadStream(ctx, "1/short", "text/plain", strings.NewReader("abc"), 10)
+	assert.Error(t, err)
+
+	_, err = storage.GetFile(ctx, "1/short")
+	assert.ErrorIs(t, err, models.FileNotExists)
+}
+
+func testGetMissing(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	_, err := storage.GetFile(context.Background(), "1/missing")
+	assert.ErrorIs(t, err, models.FileNotExists)
+}
+
+func testOpenFile(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+
+	require.NoError(t, storage.UploadFile(ctx, "1/file", "text/plain", data, int64(len(data))))
+
+	object, err := storage.OpenFile(ctx, "1/file")
+	require.NoError(t, err)
+	defer object.Close()
+	assert.Equal(t, int64(len(data)), object.Size())
+
+	buf := make([]byte, 15)
+	n, err := object.ReadAt(buf, 4995)
+	require.NoError(t, err)
+	assert.Equal(t, 15, n)
+	assert.Equal(t, data[4995:5010], buf)
+
+	n, err = object.ReadAt(buf, int64(len(data))-5)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, data[len(data)-5:], buf[:n])
+}
+
+func testOpenMissing(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	_, err := storage.OpenFile(context.Background(), "1/missing")
+	assert.ErrorIs(t, err, models.FileNotExists)
+}
+
+func testListKeys(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+
+	for _, key := range []string{"1/a", "1/b", "12/c", "org/1/d"} {
+		require.NoError(t, storage.UploadFile(ctx, key, "text/plain", []byte(key), int64(len(key))))
+	}
+
+	keys, err := storage.ListKeys(ctx, "1/")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1/a", "1/b"}, keys)
+
+	keys, err = storage.ListKeys(ctx, "org/")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"org/1/d"}, keys)
+
+	keys, err = storage.ListKeys(ctx, "2/")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func testDeleteFiles(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+
+	for _, key := range []string{"1/a", "1/b", "1/c"} {
+		require.NoError(t, storage.UploadFile(ctx, key, "text/plain", []byte(key), int64(len(key))))
+	}
+
+	// Missing keys are not an error, purges are retried after partial failures
+	require.NoError(t, storage.DeleteFiles(ctx, []string{"1/a", "1/b", "1/missing"}))
+	require.NoError(t, storage.DeleteFiles(ctx, nil))
+
+	_, err := storage.GetFile(ctx, "1/a")
+	assert.ErrorIs(t, err, models.FileNotExists)
+
+	keys, err := storage.ListKeys(ctx, "1/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1/c"}, keys)
+}
+
+func testConcurrentUploads(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+	const writers = 8
+
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Go(func() {
+			data := bytes.Repeat([]byte(fmt.Sprint(i)), 64*1024)
+			assert.NoError(t, storage.UploadStream(ctx, "1/same", "text/plain", bytes.NewReader(data),
+				int64(len(data))))
+		})
+	}
+	wg.Wait()
+
+	got, err := storage.GetFile(ctx, "1/same")
+	require.NoError(t, err)
+	require.Len(t, got, 64*1024)
+	assert.Equal(t, bytes.Repeat(got[:1], len(got)), got, "object is a mix of several uploads")
+}
+
+func testPresignedGetURL(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+	require.NoError(t, storage.UploadFile(ctx, "1/file", "text/plain", []byte("data"), 4))
+
+	url, err := storage.PresignedGetURL(ctx, "1/file", "file.txt", time.Minute)
+	if err != nil {
+		assert.ErrorIs(t, err, models.PresignNotSupportedError)
+		return
+	}
+	assert.NotEmpty(t, url)
+}
+
+func testPresignedPutURL(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	url, err := storage.PresignedPutURL(context.Background(), "1/file", time.Minute)
+	if err != nil {
+		assert.ErrorIs(t, err, models.PresignNotSupportedError)
+		return
+	}
+	assert.NotEmpty(t, url)
+}
+
+func testStatFile(t *testing.T, storage fileinterfaces.ObjectStorage) {
+	ctx := context.Background()
+	require.NoError(t, storage.UploadFile(ctx, "1/file", "text/plain", []byte("hello, voblako"), 14))
+
+	info, err := storage.StatFile(ctx, "1/file")
+	require.NoError(t, err)
+	assert.Equal(t, int64(14), info.Size)
+	assert.Equal(t, "c88e6c1bed3da6cd04e23daac9b57ca3", info.Checksum)
+
+	_, err = storage.StatFile(ctx, "1/missing")
+	assert.ErrorIs(t, err, models.FileNotExists)
+}
@@ -2,6 +2,9 @@ package usecases
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"time"
 	"unicode/utf8"
 
@@ -9,6 +12,7 @@ import (
 	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
 	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
 	"github.com/google/uuid"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -30,12 +34,21 @@ func (uc *fileUsecases) UploadFile(ctx context.Context, ownerID uint,
 
 	metadata, err := uc.client.UploadFile(ctx, &protobuf.UploadFileRequest{
 		OwnerID:     uint32(ownerID),
+		OrgID:       uint32(data.OrgID),
 		Filename:    data.Filename,
 		Data:        data.File,
 		ContentType: data.ContentType,
 		Size:        data.Size,
 	})
 	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.PermissionDenied:
+			return nil, models.PermissionDeniedError
+		case codes.ResourceExhausted:
+			return nil, models.QuotaExceededError
+		}
+
 		return nil, err
 	}
 
@@ -53,8 +66,15 @@ func (uc *fileUsecases) GetFilesList(ctx context.Context, ownerID uint,
 		Limit:       uint32(options.Limit),
 		Offset:      uint32(options.Offset),
 		WithDeleted: options.WithDeleted,
+		OrgID:       uint32(options.OrgID),
+		Filename:    options.Filename,
 	})
 	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.PermissionDenied {
+			return nil, models.PermissionDeniedError
+		}
+
 		return nil, err
 	}
 
@@ -66,31 +86,60 @@ func (uc *fileUsecases) GetFilesList(ctx context.Context, ownerID uint,
 	return list, nil
 }
 
-func (uc *fileUsecases) GetFile(ctx context.Context, userID uint, id string) (*models.GeneralFileData, error) {
+func (uc *fileUsecases) OpenFile(ctx context.Context, userID uint,
+	id string) (*models.GeneralFileData, io.ReadSeeker, error) {
 	err := uuid.Validate(id)
 	if err != nil {
-		return nil, models.InvalidInputError
+		return nil, nil, models.InvalidInputError
 	}
 
-	fileData, err := uc.client.GetFile(ctx, &protobuf.GetFileRequest{
-		UUID:   id,
-		UserID: uint32(userID),
-	})
-	if err != nil {
-		st, _ := status.FromError(err)
-		if st.Code() == codes.PermissionDenied {
-			return nil, models.PermissionDeniedError
-		}
+	// The first part comes with the metadata, so small files are fetched by a single message
+	reader := &fileReader{
+		ctx:    ctx,
+		uc:     uc,
+		userID: userID,
+		id:     id,
+	}
+	if err := reader.open(0); err != nil {
+		return nil, nil, err
+	}
 
-		return nil, err
+	fileData, err := reader.stream.Recv()
+	if err != nil {
+		reader.Close()
+		return nil, nil, convertReadError(err)
 	}
+	reader.size, reader.buf = fileData.Size, fileData.Data
 
 	return &models.GeneralFileData{
 		Filename:    fileData.Filename,
 		ContentType: fileData.ContentType,
-		File:        fileData.Data,
 		Size:        fileData.Size,
-	}, nil
+	}, reader, nil
+}
+
+func (uc *fileUsecases) readFile(ctx context.Context, userID uint, id string,
+	offset int64) (grpc.ServerStreamingClient[protobuf.GetFileResponse], error) {
+	return uc.client.ReadFile(ctx, &protobuf.GetFileRequest{
+		UUID:   id,
+		UserID: uint32(userID),
+		Offset: offset,
+	})
+}
+
+// convertReadError converts an error received from a ReadFile stream. A stream ending before the content does
+// means that the content has been replaced.
+func convertReadError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return models.FileChangedError
+	}
+
+	st, _ := status.FromError(err)
+	if st.Code() == codes.PermissionDenied {
+		return models.PermissionDeniedError
+	}
+
+	return err
 }
 
 func (uc *fileUsecases) GetMetadata(ctx context.Context, userID uint, id string) (*models.FileMetadata, error) {
@@ -129,8 +178,11 @@ func (uc *fileUsecases) UpdateFile(ctx context.Context, userID uint, id string,
 	})
 	if err != nil {
 		st, _ := status.FromError(err)
-		if st.Code() == codes.PermissionDenied {
+		switch st.Code() {
+		case codes.PermissionDenied:
 			return models.PermissionDeniedError
+		case codes.ResourceExhausted:
+			return models.QuotaExceededError
 		}
 
 		return err
@@ -188,6 +240,313 @@ func (uc *fileUsecases) DeleteFile(ctx context.Context, userID uint, id string)
 	return nil
 }
 
+func (uc *fileUsecases) StartPurge(ctx context.Context, ownerID uint) (*models.PurgeJob, error) {
+	job, err := uc.client.StartPurge(ctx, &protobuf.StartPurgeRequest{OwnerID: uint32(ownerID)})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertPurgeJob(job), nil
+}
+
+func (uc *fileUsecases) GetPurgeJob(ctx context.Context, id string) (*models.PurgeJob, error) {
+	err := uuid.Validate(id)
+	if err != nil {
+		return nil, models.InvalidInputError
+	}
+
+	job, err := uc.client.GetPurgeJob(ctx, &protobuf.GetPurgeJobRequest{ID: id})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.PurgeJobNotExists
+		}
+
+		return nil, err
+	}
+
+	return convertPurgeJob(job), nil
+}
+
+func (uc *fileUsecases) StartExport(ctx context.Context, user *models.User) (*models.ExportJob, error) {
+	account, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := uc.client.StartExport(ctx, &protobuf.StartExportRequest{
+		OwnerID: uint32(user.ID),
+		Email:   user.Email,
+		Account: account,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertExportJob(job), nil
+}
+
+func (uc *fileUsecases) GetExportJob(ctx context.Context, userID uint, id string) (*models.ExportJob, error) {
+	err := uuid.Validate(id)
+	if err != nil {
+		return nil, models.InvalidInputError
+	}
+
+	job, err := uc.client.GetExportJob(ctx, &protobuf.GetExportJobRequest{
+		ID:     id,
+		UserID: uint32(userID),
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.NotFound:
+			return nil, models.ExportJobNotExists
+		case codes.PermissionDenied:
+			return nil, models.PermissionDeniedError
+		}
+
+		return nil, err
+	}
+
+	return convertExportJob(job), nil
+}
+
+func (uc *fileUsecases) GetStorageUsage(ctx context.Context, ownerID uint) (*models.StorageUsage, error) {
+	usage, err := uc.client.GetStorageUsage(ctx, &protobuf.StorageUsageRequest{OwnerID: uint32(ownerID)})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertStorageUsage(usage), nil
+}
+
+func (uc *fileUsecases) SetQuota(ctx context.Context, ownerID uint, quota int64) (*models.StorageUsage, error) {
+	if quota < 0 {
+		return nil, models.InvalidInputError
+	}
+
+	usage, err := uc.client.SetQuota(ctx, &protobuf.SetQuotaRequest{
+		OwnerID:    uint32(ownerID),
+		QuotaBytes: quota,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertStorageUsage(usage), nil
+}
+
+func (uc *fileUsecases) GetOrgStorageUsage(ctx context.Context, orgID uint) (*models.StorageUsage, error) {
+	usage, err := uc.client.GetStorageUsage(ctx, &protobuf.StorageUsageRequest{OrgID: uint32(orgID)})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertStorageUsage(usage), nil
+}
+
+func (uc *fileUsecases) SetOrgQuota(ctx context.Context, orgID uint, quota int64) (*models.StorageUsage, error) {
+	if quota < 0 {
+		return nil, models.InvalidInputError
+	}
+
+	usage, err := uc.client.SetQuota(ctx, &protobuf.SetQuotaRequest{
+		OrgID:      uint32(orgID),
+		QuotaBytes: quota,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return convertStorageUsage(usage), nil
+}
+
+func (uc *fileUsecases) ForceDeleteFile(ctx context.Context, id string) (*models.FileMetadata, error) {
+	err := uuid.Validate(id)
+	if err != nil {
+		return nil, models.InvalidInputError
+	}
+
+	metadata, err := uc.client.ForceDeleteFile(ctx, &protobuf.ForceDeleteFileRequest{UUID: id})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.FileNotExists
+		}
+
+		return nil, err
+	}
+
+	return convertMetadata(metadata), nil
+}
+
+func (uc *fileUsecases) StartUpload(ctx context.Context, ownerID uint,
+	req *models.StartUploadRequest) (*models.PresignedUpload, error) {
+	if utf8.RuneCountInString(req.Filename) < 1 || utf8.RuneCountInString(req.Filename) > 50 {
+		return nil, models.InvalidFilenameError
+	}
+	if req.Size < 0 {
+		return nil, models.InvalidInputError
+	}
+
+	upload, err := uc.client.StartUpload(ctx, &protobuf.StartUploadRequest{
+		OwnerID:     uint32(ownerID),
+		OrgID:       uint32(req.OrgID),
+		Filename:    req.Filename,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.PermissionDenied:
+			return nil, models.PermissionDeniedError
+		case codes.ResourceExhausted:
+			return nil, models.QuotaExceededError
+		case codes.Unimplemented:
+			return nil, models.PresignDisabledError
+		}
+
+		return nil, err
+	}
+
+	return &models.PresignedUpload{
+		File:       convertMetadata(upload.File),
+		UploadURL:  upload.UploadURL,
+		ExpireTime: upload.ExpireTime.AsTime(),
+	}, nil
+}
+
+func (uc *fileUsecases) FinishUpload(ctx context.Context, userID uint,
+	id, checksum string) (*models.FileMetadata, error) {
+	err := uuid.Validate(id)
+	if err != nil {
+		return nil, models.InvalidInputError
+	}
+
+	metadata, err := uc.client.FinishUpload(ctx, &protobuf.FinishUploadRequest{
+		UUID:     id,
+		UserID:   uint32(userID),
+		Checksum: checksum,
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.PermissionDenied:
+			return nil, models.PermissionDeniedError
+		case codes.FailedPrecondition:
+			return nil, models.UploadNotFinishedError
+		case codes.InvalidArgument:
+			return nil, models.UploadMismatchError
+		}
+
+		return nil, err
+	}
+
+	return convertMetadata(metadata), nil
+}
+
+func (uc *fileUsecases) GetDownloadURL(ctx context.Context, userID uint, id string) (*models.DownloadURL, error) {
+	err := uuid.Validate(id)
+	if err != nil {
+		return nil, models.InvalidInputError
+	}
+
+	downloadURL, err := uc.client.GetDownloadURL(ctx, &protobuf.GetDownloadURLRequest{
+		UUID:   id,
+		UserID: uint32(userID),
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		switch st.Code() {
+		case codes.PermissionDenied:
+			return nil, models.PermissionDeniedError
+		case codes.Unimplemented:
+			return nil, models.PresignDisabledError
+		}
+
+		return nil, err
+	}
+
+	return &models.DownloadURL{URL: downloadURL.URL, ExpireTime: downloadURL.ExpireTime.AsTime()}, nil
+}
+
+func (uc *fileUsecases) PutUploadPart(ctx context.Context, userID uint, uploadID string, number int,
+	data []byte) error {
+	_, err := uc.client.PutUploadPart(ctx, &protobuf.PutUploadPartRequest{
+		UserID:   uint32(userID),
+		UploadID: uploadID,
+		Number:   int32(number),
+		Data:     data,
+	})
+
+	return err
+}
+
+func (uc *fileUsecases) GetUploadPart(ctx context.Context, userID uint, uploadID string,
+	number int) ([]byte, error) {
+	part, err := uc.client.GetUploadPart(ctx, &protobuf.GetUploadPartRequest{
+		UserID:   uint32(userID),
+		UploadID: uploadID,
+		Number:   int32(number),
+	})
+	if err != nil {
+		st, _ := status.FromError(err)
+		if st.Code() == codes.NotFound {
+			return nil, models.FileNotExists
+		}
+
+		return nil, err
+	}
+
+	return part.Data, nil
+}
+
+func (uc *fileUsecases) DeleteUploadParts(ctx context.Context, userID uint, uploadID string) error {
+	_, err := uc.client.DeleteUploadParts(ctx, &protobuf.DeleteUploadPartsRequest{
+		UserID:   uint32(userID),
+		UploadID: uploadID,
+	})
+
+	return err
+}
+
+func convertStorageUsage(usage *protobuf.StorageUsage) *models.StorageUsage {
+	return &models.StorageUsage{
+		OwnerID:    uint(usage.OwnerID),
+		OrgID:      uint(usage.OrgID),
+		UsedBytes:  usage.UsedBytes,
+		FilesCount: usage.FilesCount,
+		QuotaBytes: usage.QuotaBytes,
+	}
+}
+
+func convertExportJob(job *protobuf.ExportJob) *models.ExportJob {
+	return &models.ExportJob{
+		ID:          job.ID,
+		OwnerID:     uint(job.OwnerID),
+		Status:      job.Status,
+		Size:        job.Size,
+		Error:       job.Error,
+		DownloadURL: job.DownloadURL,
+		CreateTime:  job.CreateTime.AsTime(),
+		UpdateTime:  job.UpdateTime.AsTime(),
+		ExpireTime:  protoToPtrTime(job.ExpireTime),
+	}
+}
+
+func convertPurgeJob(job *protobuf.PurgeJob) *models.PurgeJob {
+	return &models.PurgeJob{
+		ID:             job.ID,
+		OwnerID:        uint(job.OwnerID),
+		Status:         job.Status,
+		DeletedObjects: job.DeletedObjects,
+		Error:          job.Error,
+		CreateTime:     job.CreateTime.AsTime(),
+		UpdateTime:     job.UpdateTime.AsTime(),
+	}
+}
+
 func convertMetadata(meta *protobuf.FileMetadata) *models.FileMetadata {
 	return &models.FileMetadata{
 		UUID:        meta.UUID,
@@ -196,9 +555,11 @@ func convertMetadata(meta *protobuf.FileMetadata) *models.FileMetadata {
 		ContentType: meta.ContentType,
 		Size:        meta.Size,
 		IsDeleted:   meta.IsDeleted,
+		OrgID:       uint(meta.OrgID),
 		UploadTime:  meta.UploadTime.AsTime(),
 		UpdateTime:  meta.UpdateTime.AsTime(),
 		DeletedTime: protoToPtrTime(meta.DeletedTime),
+		Stored:      models.StoredContent{Checksum: meta.Checksum},
 	}
 }
 
@@ -0,0 +1,123 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fileReader streams the content from the file service as it is read, so compressed content is decompressed
+// once for a sequential read and the whole file is never held in memory. A seek outside of the streamed part
+// starts a new stream from the new offset, so range requests transfer only the requested bytes.
+type fileReader struct {
+	ctx    context.Context
+	uc     *fileUsecases
+	userID uint
+	id     string
+	size   int64
+
+	offset int64
+	stream grpc.ServerStreamingClient[protobuf.GetFileResponse]
+	cancel context.CancelFunc
+	// buf holds the last received part of the content, which starts at bufOffset
+	buf       []byte
+	bufOffset int64
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		r.Close()
+		return 0, io.EOF
+	}
+
+	if r.offset < r.bufOffset || r.offset >= r.bufOffset+int64(len(r.buf)) {
+		if err := r.fill(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buf[r.offset-r.bufOffset:])
+	r.offset += int64(n)
+
+	return n, nil
+}
+
+// fill receives the part holding the offset. The current stream is continued if the offset follows the last
+// part, otherwise a new one is started.
+func (r *fileReader) fill() error {
+	if r.stream == nil || r.offset != r.bufOffset+int64(len(r.buf)) {
+		if err := r.open(r.offset); err != nil {
+			return err
+		}
+	}
+
+	fileData, err := r.stream.Recv()
+	// The stream is limited by the call timeout, so a long download goes on with a new one
+	if status.Code(err) == codes.DeadlineExceeded && r.ctx.Err() == nil {
+		if err := r.open(r.offset); err != nil {
+			return err
+		}
+		fileData, err = r.stream.Recv()
+	}
+	if err != nil {
+		r.Close()
+		return convertReadError(err)
+	}
+
+	// A changed size means that the content has been replaced since the first part was received
+	if fileData.Size != r.size || len(fileData.Data) == 0 {
+		r.Close()
+		return models.FileChangedError
+	}
+
+	r.buf, r.bufOffset = fileData.Data, r.offset
+
+	return nil
+}
+
+func (r *fileReader) open(offset int64) error {
+	r.Close()
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	stream, err := r.uc.readFile(ctx, r.userID, r.id, offset)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	r.stream, r.cancel = stream, cancel
+
+	return nil
+}
+
+func (r *fileReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	r.offset = offset
+
+	return offset, nil
+}
+
+// Close stops the current stream, reading after it starts a new one
+func (r *fileReader) Close() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.stream, r.cancel = nil, nil
+
+	return nil
+}
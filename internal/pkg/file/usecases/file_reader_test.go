@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testChunkSize = 1 << 20
+
+type fakeFileClient struct {
+	protobuf.FileClient
+	content  []byte
+	requests []*protobuf.GetFileRequest
+	// expire makes the next continued stream fail with an exceeded deadline
+	expire bool
+}
+
+func (c *fakeFileClient) ReadFile(ctx context.Context, r *protobuf.GetFileRequest,
+	_ ...grpc.CallOption) (grpc.ServerStreamingClient[protobuf.GetFileResponse], error) {
+	c.requests = append(c.requests, r)
+
+	return &fakeReadFileStream{ctx: ctx, client: c, offset: r.Offset}, nil
+}
+
+type fakeReadFileStream struct {
+	grpc.ServerStreamingClient[protobuf.GetFileResponse]
+	ctx    context.Context
+	client *fakeFileClient
+	offset int64
+	sent   bool
+}
+
+func (s *fakeReadFileStream) Recv() (*protobuf.GetFileResponse, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, status.Error(codes.Canceled, err.Error())
+	}
+
+	size := int64(len(s.client.content))
+	if s.sent && s.client.expire {
+		s.client.expire = false
+		return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+	if s.sent && s.offset >= size {
+		return nil, io.EOF
+	}
+
+	offset := min(s.offset, size)
+	end := min(offset+testChunkSize, size)
+	s.offset, s.sent = end, true
+
+	return &protobuf.GetFileResponse{
+		Filename: "file.bin",
+		Data:     s.client.content[offset:end],
+		Size:     size,
+	}, nil
+}
+
+func testContent() []byte {
+	content := make([]byte, 3*testChunkSize)
+	for i := range content {
+		content[i] = byte(i)
+	}
+
+	return content
+}
+
+func TestFileUsecases_OpenFile(t *testing.T) {
+	content := testContent()
+	client := &fakeFileClient{content: content}
+	uc := NewFileUsecases(client)
+
+	file, reader, err := uc.OpenFile(context.Background(), 1, uuid.NewString())
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), file.Size)
+	require.Len(t, client.requests, 1)
+
+	// A sequential read goes on with the same stream
+	buf := make([]byte, 100)
+	_, err = io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	assert.Equal(t, content[:100], buf)
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content[100:], rest)
+	assert.Len(t, client.requests, 1)
+
+	// A seek outside of the received part starts a stream from the new offset
+	offset := int64(testChunkSize + 10)
+	_, err = reader.Seek(offset, io.SeekStart)
+	require.NoError(t, err)
+	rest, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content[offset:], rest)
+	require.Len(t, client.requests, 2)
+	assert.Equal(t, offset, client.requests[1].Offset)
+
+	// The last part is still held, so the end is served from it
+	_, err = reader.Seek(-10, io.SeekEnd)
+	require.NoError(t, err)
+	rest, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content[len(content)-10:], rest)
+	assert.Len(t, client.requests, 2)
+}
+
+func TestFileUsecases_OpenFile_DeadlineExceeded(t *testing.T) {
+	content := testContent()
+	client := &fakeFileClient{content: content}
+	uc := NewFileUsecases(client)
+
+	_, reader, err := uc.OpenFile(context.Background(), 1, uuid.NewString())
+	require.NoError(t, err)
+
+	client.expire = true
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+	require.Len(t, client.requests, 2)
+	assert.Equal(t, int64(testChunkSize), client.requests[1].Offset)
+}
+
+func TestFileUsecases_OpenFile_Changed(t *testing.T) {
+	client := &fakeFileClient{content: make([]byte, 2*testChunkSize)}
+	uc := NewFileUsecases(client)
+
+	_, reader, err := uc.OpenFile(context.Background(), 1, uuid.NewString())
+	require.NoError(t, err)
+
+	client.content = make([]byte, testChunkSize+1)
+
+	_, err = io.ReadAll(reader)
+	assert.ErrorIs(t, err, models.FileChangedError)
+}
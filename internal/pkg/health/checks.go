@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+func PostgresCheck(pool pinger) Check {
+	return pool.Ping
+}
+
+func RedisCheck(client *redis.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}
+}
+
+func MinioCheck(client *minio.Client, bucket string) Check {
+	return func(ctx context.Context) error {
+		exists, err := client.BucketExists(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("bucket %s does not exist", bucket)
+		}
+
+		return nil
+	}
+}
+
+// DirCheck reports whether the directory of a local storage is still available
+func DirCheck(path string) Check {
+	return func(ctx context.Context) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", path)
+		}
+
+		return nil
+	}
+}
+
+// GRPCCheck asks the health service of a downstream server about its overall status
+func GRPCCheck(conn grpc.ClientConnInterface) Check {
+	client := healthpb.NewHealthClient(conn)
+
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("service is %s", resp.Status)
+		}
+
+		return nil
+	}
+}
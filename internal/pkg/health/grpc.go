@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// NewServer creates a gRPC health service that reports NOT_SERVING until the first check passes
+func NewServer(services ...string) *health.Server {
+	server := health.NewServer()
+	setStatus(server, healthpb.HealthCheckResponse_NOT_SERVING, services)
+
+	return server
+}
+
+// Watch runs the checks every interval and updates the status of the overall server and the given services.
+// It returns when ctx is done.
+func Watch(ctx context.Context, checker *Checker, server *health.Server, interval time.Duration,
+	services ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := false
+
+	for {
+		report := checker.Run(ctx)
+		if report.Healthy() != healthy {
+			healthy = report.Healthy()
+			slog.Info("Health status changed", "status", report.Status, "checks", report.Checks)
+		}
+
+		status := healthpb.HealthCheckResponse_SERVING
+		if !healthy {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		setStatus(server, status, services)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func setStatus(server *health.Server, status healthpb.HealthCheckResponse_ServingStatus, services []string) {
+	server.SetServingStatus("", status)
+	for _, service := range services {
+		server.SetServingStatus(service, status)
+	}
+}
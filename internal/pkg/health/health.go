@@ -0,0 +1,93 @@
+// Package health checks the dependencies of a service and reports the result over gRPC and HTTP
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOk          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// Check returns an error if the dependency cannot be used
+type Check func(ctx context.Context) error
+
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (r *Report) Healthy() bool {
+	return r.Status == StatusOk
+}
+
+type Checker struct {
+	checks   map[string]Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewChecker creates a checker running every check with its own timeout, so one hanging dependency does not
+// delay the whole report
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  make(map[string]Check),
+		timeout: timeout,
+	}
+}
+
+func (c *Checker) Add(name string, check Check) {
+	c.checks[name] = check
+}
+
+// Drain makes every following report unavailable, so balancers stop sending requests before the server stops
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Run(ctx context.Context) *Report {
+	if c.draining.Load() {
+		return &Report{Status: StatusDraining, Checks: map[string]string{}}
+	}
+
+	report := &Report{
+		Status: StatusOk,
+		Checks: make(map[string]string, len(c.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, check := range c.checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			result := StatusOk
+			if err := check(checkCtx); err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = result
+			if result != StatusOk {
+				report.Status = StatusUnavailable
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return report
+}
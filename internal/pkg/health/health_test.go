@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestChecker_Run(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("postgres", func(ctx context.Context) error { return nil })
+	checker.Add("minio", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	report := checker.Run(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, report.Healthy())
+	assert.Equal(t, StatusOk, report.Checks["postgres"])
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["minio"])
+}
+
+func TestChecker_Drain(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("postgres", func(ctx context.Context) error { return nil })
+	require.True(t, checker.Run(context.Background()).Healthy())
+
+	checker.Drain()
+
+	report := checker.Run(context.Background())
+	assert.False(t, report.Healthy())
+	assert.Equal(t, StatusDraining, report.Status)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	healthy := true
+	checker := NewChecker(time.Second)
+	checker.Add("redis", func(ctx context.Context) error {
+		if !healthy {
+			return assert.AnError
+		}
+		return nil
+	})
+	handler := ReadinessHandler(checker)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	healthy = false
+	recorder = httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var report Report
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, assert.AnError.Error(), report.Checks["redis"])
+}
+
+func TestWatch(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("postgres", func(ctx context.Context) error { return nil })
+
+	server := NewServer("file.File")
+	resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "file.File"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, checker, server, time.Hour, "file.File")
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "file.File"})
+		return err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
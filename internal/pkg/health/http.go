@@ -0,0 +1,36 @@
+package health
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// LivenessHandler answers while the process is able to serve HTTP, dependencies are not checked
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, &Report{Status: StatusOk})
+	}
+}
+
+// ReadinessHandler runs the checks on every request and answers 503 if any of them fails
+func ReadinessHandler(checker *Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, checker.Run(r.Context()))
+	}
+}
+
+func writeReport(w http.ResponseWriter, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if report.Healthy() {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.Error("Something went wrong while sending health report", "error", err)
+	}
+}
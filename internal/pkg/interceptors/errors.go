@@ -0,0 +1,110 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/minio/minio-go/v7"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var modelCodes = []struct {
+	code codes.Code
+	errs []error
+}{
+	{codes.NotFound, []error{
+		models.UserNotExists, models.OrgNotExists, models.FileNotExists, models.PurgeJobNotExists,
+		models.ExportJobNotExists, models.SessionNotExistsError, models.InvalidVerificationToken,
+		models.AccessKeyNotExists,
+	}},
+	{codes.AlreadyExists, []error{models.UserAlreadyExists, models.IdentityConflictError}},
+	{codes.PermissionDenied, []error{models.PermissionDeniedError, models.UserDisabledError}},
+	{codes.InvalidArgument, []error{
+		models.InvalidInputError, models.InvalidFilenameError, models.InvalidEmailError, models.InvalidRoleError,
+		models.InvalidOrgNameError, models.InvalidOrgRoleError, models.IncorrectPasswordLen,
+		models.PasswordsNotMatch, models.UploadMismatchError,
+	}},
+	{codes.FailedPrecondition, []error{
+		models.UserNotVerified, models.UserAlreadyVerified, models.OrgOwnerImmutableError,
+		models.UploadNotFinishedError,
+	}},
+	{codes.ResourceExhausted, []error{models.QuotaExceededError, models.AccessKeyLimitError}},
+	{codes.Aborted, []error{models.UpdateConflictError}},
+	{codes.Unauthenticated, []error{models.InvalidServiceTokenError}},
+	{codes.Unimplemented, []error{models.PresignNotSupportedError, models.PresignDisabledError}},
+	{codes.DataLoss, []error{models.CorruptedObjectError}},
+}
+
+var pgCodes = map[string]codes.Code{
+	"23505": codes.AlreadyExists,      // unique_violation
+	"23503": codes.FailedPrecondition, // foreign_key_violation
+	"23514": codes.InvalidArgument,    // check_violation
+	"22001": codes.InvalidArgument,    // string_data_right_truncation
+	"40001": codes.Aborted,            // serialization_failure
+	"53300": codes.Unavailable,        // too_many_connections
+	"57014": codes.DeadlineExceeded,   // query_canceled
+}
+
+// ToStatus converts an error returned by a handler into a gRPC status. Errors that already carry a status
+// are left as is, unknown errors become Internal without exposing their text to the client.
+func ToStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	for _, entry := range modelCodes {
+		for _, target := range entry.errs {
+			if errors.Is(err, target) {
+				return status.Errorf(entry.code, "%s", target.Error())
+			}
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), pgconn.Timeout(err):
+		return status.Errorf(codes.DeadlineExceeded, "%s", err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "%s", err.Error())
+	case errors.Is(err, pgx.ErrNoRows):
+		return status.Errorf(codes.NotFound, "%s", err.Error())
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if code, ok := pgCodes[pgErr.Code]; ok {
+			return status.Errorf(code, "%s", pgErr.Message)
+		}
+	}
+
+	if minioErr := minio.ToErrorResponse(err); minioErr.Code == "NoSuchKey" {
+		return status.Errorf(codes.NotFound, "%s", models.FileNotExists.Error())
+	}
+
+	slog.ErrorContext(ctx, "Internal error", "error", err)
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+func UnaryServerErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, ToStatus(ctx, err)
+	}
+}
+
+func StreamServerErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return ToStatus(ss.Context(), handler(srv, ss))
+	}
+}
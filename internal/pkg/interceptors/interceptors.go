@@ -0,0 +1,51 @@
+// Package interceptors contains the gRPC interceptor chain shared by all services and their clients
+package interceptors
+
+import (
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// ServerOptions returns the common server chain. Logging goes right after the request ID, so it sees
+// the final status of the call including recovered panics. Spans are started by the stats handler before
+// any interceptor runs, so log records of the call carry its trace ID.
+func ServerOptions(timeout time.Duration) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			UnaryServerRequestID(),
+			UnaryServerLogging(),
+			UnaryServerMetrics(),
+			UnaryServerRecovery(),
+			UnaryServerErrors(),
+			UnaryServerDeadline(timeout),
+		),
+		grpc.ChainStreamInterceptor(
+			StreamServerRequestID(),
+			StreamServerLogging(),
+			StreamServerMetrics(),
+			StreamServerRecovery(),
+			StreamServerErrors(),
+			StreamServerDeadline(timeout),
+		),
+	}
+}
+
+func ClientOptions(timeout time.Duration) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(
+			UnaryClientRequestID(),
+			UnaryClientLogging(),
+			UnaryClientMetrics(),
+			UnaryClientDeadline(timeout),
+		),
+		grpc.WithChainStreamInterceptor(
+			StreamClientRequestID(),
+			StreamClientLogging(),
+			StreamClientDeadline(timeout),
+		),
+	}
+}
@@ -0,0 +1,72 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{"nil", nil, codes.OK, ""},
+		{"status", status.Error(codes.AlreadyExists, "session"), codes.AlreadyExists, "session"},
+		{"model", models.FileNotExists, codes.NotFound, models.FileNotExists.Error()},
+		{"wrapped model", fmt.Errorf("loading: %w", models.QuotaExceededError), codes.ResourceExhausted,
+			models.QuotaExceededError.Error()},
+		{"no rows", pgx.ErrNoRows, codes.NotFound, pgx.ErrNoRows.Error()},
+		{"unique violation", &pgconn.PgError{Code: "23505", Message: "duplicate key"}, codes.AlreadyExists,
+			"duplicate key"},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded, context.DeadlineExceeded.Error()},
+		{"minio", minio.ErrorResponse{Code: "NoSuchKey"}, codes.NotFound, models.FileNotExists.Error()},
+		{"unknown", fmt.Errorf("connection reset"), codes.Internal, "internal error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _ := status.FromError(ToStatus(context.Background(), tt.err))
+
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+		})
+	}
+}
+
+func TestUnaryServerRecovery(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) {
+		panic("nil map")
+	}
+
+	resp, err := UnaryServerRecovery()(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/protobuf.File/GetFile"}, handler)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestUnaryServerDeadline(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil, nil
+	}
+
+	_, err := UnaryServerDeadline(time.Second)(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/protobuf.File/GetFile"}, handler)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func UnaryServerLogging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, "served", info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+func StreamServerLogging() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), "served stream", info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func UnaryClientLogging() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCall(ctx, "called", method, start, err)
+
+		return err
+	}
+}
+
+// StreamClientLogging logs only opening of a stream, because the client owns it afterwards
+func StreamClientLogging() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		logCall(ctx, "opened stream", method, start, err)
+
+		return stream, err
+	}
+}
+
+func logCall(ctx context.Context, action, method string, start time.Time, err error) {
+	st, _ := status.FromError(err)
+	attrs := []any{
+		"method", method,
+		"code", st.Code().String(),
+		"duration", time.Since(start),
+	}
+
+	if err != nil {
+		slog.WarnContext(ctx, "gRPC "+action, append(attrs, "error", st.Message())...)
+		return
+	}
+
+	slog.InfoContext(ctx, "gRPC "+action, attrs...)
+}
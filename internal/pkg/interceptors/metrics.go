@@ -0,0 +1,48 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func UnaryServerMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observe(metrics.GRPCServerDuration, info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+func StreamServerMetrics() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observe(metrics.GRPCServerDuration, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func UnaryClientMetrics() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observe(metrics.GRPCClientDuration, method, start, err)
+
+		return err
+	}
+}
+
+func observe(histogram *prometheus.HistogramVec, method string, start time.Time, err error) {
+	histogram.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
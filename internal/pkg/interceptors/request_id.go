@@ -0,0 +1,60 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+// UnaryServerRequestID takes the request ID from the call metadata, calls without one get a new ID
+func UnaryServerRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		return handler(incomingRequestID(ctx), req)
+	}
+}
+
+func StreamServerRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: incomingRequestID(ss.Context())})
+	}
+}
+
+func UnaryClientRequestID() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientRequestID() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func incomingRequestID(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) > 0 && values[0] != "" {
+			return logger.WithRequestID(ctx, values[0])
+		}
+	}
+
+	return logger.WithRequestID(ctx, uuid.NewString())
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	requestID := logger.RequestID(ctx)
+	if requestID == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, requestID)
+}
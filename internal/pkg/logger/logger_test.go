@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_RequestID(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log := New(buf, config.LogConfig{Level: "debug", Format: "json"}).With("service", "file")
+
+	ctx := WithRequestID(context.Background(), "abc-123")
+	log.DebugContext(ctx, "Uploading file", "size", 10)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "abc-123", record[RequestIDKey])
+	assert.Equal(t, "file", record["service"])
+	assert.Equal(t, "DEBUG", record["level"])
+}
+
+func TestLogger_Level(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log := New(buf, config.LogConfig{Level: "warn"})
+
+	log.Info("Skipped")
+	assert.Empty(t, buf.String())
+
+	log.Warn("Written")
+	assert.Contains(t, buf.String(), "Written")
+}
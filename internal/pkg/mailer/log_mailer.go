@@ -0,0 +1,19 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logMailer is used when SMTP is not configured, e.g. for local development.
+type logMailer struct{}
+
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, body string) error {
+	slog.InfoContext(ctx, "Mail is written to log", "to", to, "subject", subject, "body", body)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package metrics contains Prometheus collectors shared by the gateway and the services
+package metrics
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "voblako"
+
+var (
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of HTTP requests by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	GRPCServerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Duration of gRPC calls handled by the service.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	GRPCClientDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_handling_seconds",
+		Help:      "Duration of gRPC calls made to other services.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	TransferredBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "file_transferred_bytes_total",
+		Help:      "Bytes of file contents uploaded and downloaded.",
+	}, []string{"direction"})
+
+	StorageOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Duration of MinIO and Postgres operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"storage", "operation"})
+
+	SessionLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_lookups_total",
+		Help:      "Session lookups in Redis by result.",
+	}, []string{"result"})
+
+	RateLimitEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ratelimit_events_total",
+		Help:      "Rate limiter decisions and registered failures.",
+	}, []string{"event"})
+)
+
+const (
+	DirectionUpload   = "upload"
+	DirectionDownload = "download"
+
+	StorageMinio    = "minio"
+	StorageFS       = "fs"
+	StorageMemory   = "memory"
+	StoragePostgres = "postgres"
+)
+
+// ObserveStorage is meant to be deferred at the start of a storage operation
+func ObserveStorage(storage, operation string, start time.Time) {
+	StorageOperationDuration.WithLabelValues(storage, operation).Observe(time.Since(start).Seconds())
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve exposes /metrics on a separate address, so it is not reachable through the public API. Routes are
+// served next to it, so orchestrators without a gRPC client can probe the service.
+func Serve(addr string, routes map[string]http.Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	for pattern, handler := range routes {
+		mux.Handle(pattern, handler)
+	}
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		slog.Info("Serving metrics", "address", addr)
+
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Metrics server failed", "error", err)
+		}
+	}()
+
+	return srv
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware_RouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(HTTPMiddleware())
+	router.HandleFunc("/api/files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+
+	for _, id := range []string{"a", "b"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/files/"+id, nil))
+	}
+
+	count := testutil.CollectAndCount(HTTPRequestDuration, "voblako_http_request_duration_seconds")
+	assert.Equal(t, 1, count)
+}
+
+func TestSQLCommand(t *testing.T) {
+	assert.Equal(t, "SELECT", sqlCommand("\n\t\tselect id FROM public.user"))
+	assert.Equal(t, "INSERT", sqlCommand("INSERT INTO public.organization (name) VALUES ($1)"))
+	assert.Equal(t, "other", sqlCommand("BEGIN"))
+	assert.Equal(t, "unknown", sqlCommand(""))
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	operation string
+	time      time.Time
+}
+
+// PostgresTracer records query latencies by the SQL command, e.g. SELECT or INSERT
+type PostgresTracer struct{}
+
+func (t *PostgresTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn,
+	data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{
+		operation: sqlCommand(data.SQL),
+		time:      time.Now(),
+	})
+}
+
+func (t *PostgresTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	if start, ok := ctx.Value(queryStartKey{}).(queryStart); ok {
+		ObserveStorage(StoragePostgres, start.operation, start.time)
+	}
+}
+
+func sqlCommand(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	command := strings.ToUpper(fields[0])
+	switch command {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH":
+		return command
+	}
+
+	return "other"
+}
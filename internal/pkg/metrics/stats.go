@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StatsFunc returns the number of stored files, their total size and the number of bytes saved by compression
+type StatsFunc func(ctx context.Context) (files, bytes, savedBytes int64, err error)
+
+type statsCollector struct {
+	stats StatsFunc
+
+	files      *prometheus.Desc
+	bytes      *prometheus.Desc
+	savedBytes *prometheus.Desc
+}
+
+// RegisterStorageStats adds gauges that are computed on every scrape
+func RegisterStorageStats(stats StatsFunc) {
+	prometheus.MustRegister(&statsCollector{
+		stats: stats,
+		files: prometheus.NewDesc(namespace+"_stored_files", "Number of files that are not deleted.", nil, nil),
+		bytes: prometheus.NewDesc(namespace+"_stored_bytes", "Total size of stored files in bytes.", nil, nil),
+		savedBytes: prometheus.NewDesc(namespace+"_compression_saved_bytes",
+			"Difference between the size of compressed files and the size of their objects.", nil, nil),
+	})
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.files
+	ch <- c.bytes
+	ch <- c.savedBytes
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	files, bytes, savedBytes, err := c.stats(ctx)
+	if err != nil {
+		slog.Error("Something went wrong while collecting storage stats", "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.files, prometheus.GaugeValue, float64(files))
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, float64(bytes))
+	ch <- prometheus.MustNewConstMetric(c.savedBytes, prometheus.GaugeValue, float64(savedBytes))
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/gorilla/mux"
+)
+
+// AdminRequiredMiddleware must be used after LoginRequiredMiddleware
+func AdminRequiredMiddleware(ctxUserKey string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(ctxUserKey).(*models.User)
+			if !ok || user == nil {
+				responses.SendErrResponse(w, responses.StatusUnauthorized, responses.ErrNotAuthorized)
+
+				return
+			}
+
+			if user.Role != models.RoleAdmin {
+				responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrForbidden)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/middleware/ratelimit"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const basicAuthScope = "basic"
+
+type BasicAuthOptions struct {
+	Realm      string
+	CtxUserKey string
+	// IPLimit limits the credential checks per client IP within the window of the limiter
+	IPLimit    int
+	TrustProxy bool
+	// CacheTTL is how long a successful credential check is reused, zero disables the cache
+	CacheTTL time.Duration
+}
+
+// BasicAuthMiddleware authenticates clients that cannot keep cookies, e.g. WebDAV drives. The username is
+// the email and the password is either the account password or a session token of the same user.
+// Wrong credentials lock the email out the same way as failed logins do. Clients send the credentials with
+// every request, so successful checks are cached briefly and only the checks that miss the cache are limited
+// per client IP.
+func BasicAuthMiddleware(uc authinterface.AuthUsecases, limiter ratelimit.Limiter,
+	opts BasicAuthOptions) mux.MiddlewareFunc {
+	cache := newCredentialsCache(opts.CacheTTL)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			email, password, ok := r.BasicAuth()
+			email = strings.ToLower(strings.TrimSpace(email))
+			if !ok || email == "" {
+				sendUnauthorized(w, opts.Realm)
+
+				return
+			}
+
+			user, ok := cache.get(email, password)
+			if !ok {
+				if !checkLimits(w, r, limiter, opts, email) {
+					return
+				}
+
+				user, ok = checkCredentials(w, r, uc, limiter, opts.Realm, email, password)
+				if !ok {
+					return
+				}
+				cache.put(email, password, user)
+			}
+
+			ctx = context.WithValue(ctx, opts.CtxUserKey, user)
+			r = r.WithContext(ctx)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// checkLimits responds with 429 if the client IP made too many checks or the email is locked out
+func checkLimits(w http.ResponseWriter, r *http.Request, limiter ratelimit.Limiter, opts BasicAuthOptions,
+	email string) bool {
+	ctx := r.Context()
+
+	ipKey := ratelimit.IPKey(basicAuthScope, ratelimit.ClientIP(r, opts.TrustProxy))
+	allowed, retryAfter, err := limiter.Allow(ctx, ipKey, opts.IPLimit)
+	if err != nil {
+		// Rate limiting must not make authentication unavailable
+		slog.ErrorContext(ctx, "Something went wrong while checking rate limit", "error", err)
+	} else if !allowed {
+		ratelimit.SendTooManyRequests(w, retryAfter)
+
+		return false
+	}
+
+	if lockout, err := limiter.Locked(ctx, ratelimit.EmailKey(basicAuthScope, email)); err == nil && lockout > 0 {
+		ratelimit.SendTooManyRequests(w, lockout)
+
+		return false
+	}
+
+	return true
+}
+
+// checkCredentials responds with the error if the credentials are not accepted
+func checkCredentials(w http.ResponseWriter, r *http.Request, uc authinterface.AuthUsecases,
+	limiter ratelimit.Limiter, realm, email, password string) (*models.User, bool) {
+	ctx := r.Context()
+	emailKey := ratelimit.EmailKey(basicAuthScope, email)
+
+	user, err := authenticate(ctx, uc, email, password)
+	switch {
+	case err == nil:
+		if err := limiter.Reset(ctx, emailKey); err != nil {
+			slog.ErrorContext(ctx, "Something went wrong while resetting failed attempts", "error", err)
+		}
+
+		return user, true
+	case errors.Is(err, models.UserNotExists), errors.Is(err, models.PasswordsNotMatch):
+		if _, err := limiter.RegisterFailure(ctx, emailKey); err != nil {
+			slog.ErrorContext(ctx, "Something went wrong while registering failed attempt", "error", err)
+		}
+		sendUnauthorized(w, realm)
+	case errors.Is(err, models.UserNotVerified):
+		responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrNotVerified)
+	case errors.Is(err, models.UserDisabledError):
+		responses.SendErrResponse(w, responses.StatusForbidden, responses.ErrUserDisabled)
+	default:
+		slog.ErrorContext(ctx, "Something went wrong while checking credentials", "error", err)
+		responses.SendErrResponse(w, responses.StatusInternalServerError, responses.ErrInternalServer)
+	}
+
+	return nil, false
+}
+
+// authenticate treats passwords shaped like session IDs as tokens, they never fit the password length limits
+func authenticate(ctx context.Context, uc authinterface.AuthUsecases, email,
+	password string) (*models.User, error) {
+	if uuid.Validate(password) != nil {
+		return uc.Authenticate(ctx, email, password)
+	}
+
+	user, isAuth := uc.CheckAuth(ctx, password)
+	if !isAuth || !strings.EqualFold(user.Email, email) {
+		return nil, models.PasswordsNotMatch
+	}
+
+	return user, nil
+}
+
+func sendUnauthorized(w http.ResponseWriter, realm string) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="`+realm+`", charset="UTF-8"`)
+	responses.SendErrResponse(w, responses.StatusUnauthorized, responses.ErrNotAuthorized)
+}
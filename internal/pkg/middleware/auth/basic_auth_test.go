@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserKey = "user"
+
+type fakeAuthUsecases struct {
+	authinterface.AuthUsecases
+	checks int
+}
+
+func (uc *fakeAuthUsecases) Authenticate(_ context.Context, email, password string) (*models.User, error) {
+	uc.checks++
+	if password != "password" {
+		return nil, models.PasswordsNotMatch
+	}
+
+	return &models.User{ID: 1, Email: email}, nil
+}
+
+type fakeLimiter struct {
+	attempts map[string]int
+	failures map[string]int
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, key string, limit int) (bool, time.Duration, error) {
+	if l.attempts[key] >= limit {
+		return false, 10 * time.Second, nil
+	}
+	l.attempts[key]++
+
+	return true, 0, nil
+}
+
+func (l *fakeLimiter) Locked(_ context.Context, _ string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (l *fakeLimiter) RegisterFailure(_ context.Context, key string) (time.Duration, error) {
+	l.failures[key]++
+	return 0, nil
+}
+
+func (l *fakeLimiter) Reset(_ context.Context, key string) error {
+	delete(l.failures, key)
+	return nil
+}
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	uc := &fakeAuthUsecases{}
+	limiter := &fakeLimiter{attempts: make(map[string]int), failures: make(map[string]int)}
+	middleware := BasicAuthMiddleware(uc, limiter, BasicAuthOptions{
+		Realm:      "voblako",
+		CtxUserKey: testUserKey,
+		IPLimit:    3,
+		CacheTTL:   time.Minute,
+	})
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Value(testUserKey).(*models.User)
+		assert.True(t, ok)
+	}))
+
+	serve := func(password string) int {
+		req := httptest.NewRequest("PROPFIND", "/dav/", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.SetBasicAuth("user@example.com", password)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		return w.Code
+	}
+
+	// Successful checks are reused, so the password is checked once
+	for range 5 {
+		assert.Equal(t, http.StatusOK, serve("password"))
+	}
+	assert.Equal(t, 1, uc.checks)
+
+	// Wrong passwords are never cached and count against the client IP
+	assert.Equal(t, http.StatusUnauthorized, serve("wrong"))
+	assert.Equal(t, http.StatusUnauthorized, serve("wrong"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("wrong"))
+	assert.Equal(t, 3, uc.checks)
+	assert.Equal(t, 2, limiter.failures["basic:email:user@example.com"])
+
+	assert.Equal(t, http.StatusOK, serve("password"))
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+)
+
+// credentialsCache remembers successful credential checks, so password hashes are not checked on every
+// request. Entries are keyed by a keyed hash of the credentials, so the passwords are not kept in memory.
+type credentialsCache struct {
+	ttl time.Duration
+	key []byte
+
+	mu      sync.Mutex
+	entries map[string]credentialsEntry
+}
+
+type credentialsEntry struct {
+	user    *models.User
+	expires time.Time
+}
+
+func newCredentialsCache(ttl time.Duration) *credentialsCache {
+	key := make([]byte, sha256.Size)
+	_, _ = rand.Read(key)
+
+	return &credentialsCache{ttl: ttl, key: key, entries: make(map[string]credentialsEntry)}
+}
+
+func (c *credentialsCache) get(email, password string) (*models.User, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[c.hash(email, password)]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+
+	return entry.user, true
+}
+
+// put also drops the expired entries, it is called only after a credential check, which is rate limited
+func (c *credentialsCache) put(email, password string, user *models.User) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for hash, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, hash)
+		}
+	}
+
+	c.entries[c.hash(email, password)] = credentialsEntry{user: user, expires: now.Add(c.ttl)}
+}
+
+func (c *credentialsCache) hash(email, password string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(email))
+	mac.Write([]byte{0})
+	mac.Write([]byte(password))
+
+	return string(mac.Sum(nil))
+}
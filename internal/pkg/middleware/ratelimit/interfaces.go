@@ -0,0 +1,18 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+type Limiter interface {
+	// Allow reports whether an attempt for the key fits into the sliding window and registers it if it does.
+	// If it does not, the duration until the next attempt is allowed is returned.
+	Allow(ctx context.Context, key string, limit int) (bool, time.Duration, error)
+
+	// Locked returns the remaining lockout duration for the key, or zero if the key is not locked
+	Locked(ctx context.Context, key string) (time.Duration, error)
+	// RegisterFailure counts a failed attempt and returns the lockout duration if it was imposed
+	RegisterFailure(ctx context.Context, key string) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,15 @@
+package ratelimit
+
+import "github.com/IlyaChgn/voblako/internal/pkg/metrics"
+
+const (
+	metricBlockedByIP    = "blocked_by_ip"
+	metricBlockedByEmail = "blocked_by_email"
+	metricBlockedLocked  = "blocked_by_lockout"
+	metricLockouts       = "lockouts"
+	metricFailures       = "failures"
+)
+
+func countEvent(event string) {
+	metrics.RateLimitEvents.WithLabelValues(event).Inc()
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/gorilla/mux"
+)
+
+const maxInspectedBodySize = 1 << 16
+
+type Options struct {
+	// Scope separates counters of different endpoints, e.g. login and signup
+	Scope      string
+	IPLimit    int
+	EmailLimit int
+	TrustProxy bool
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// RateLimitMiddleware limits attempts per client IP and per email from the JSON body.
+// Rejected credentials (401 responses) for an email lead to a growing lockout, other errors,
+// e.g. unverified emails, malformed requests or unavailable services, are not attributed to the client.
+func RateLimitMiddleware(limiter Limiter, opts Options) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			ip := ClientIP(r, opts.TrustProxy)
+			allowed, retryAfter, err := limiter.Allow(ctx, IPKey(opts.Scope, ip), opts.IPLimit)
+			if err != nil {
+				// Rate limiting must not make authentication unavailable
+				slog.ErrorContext(ctx, "Something went wrong while checking rate limit", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+			if !allowed {
+				countEvent(metricBlockedByIP)
+				SendTooManyRequests(w, retryAfter)
+
+				return
+			}
+
+			email := extractEmail(r)
+			if email == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			emailKey := EmailKey(opts.Scope, email)
+			if lockout, err := limiter.Locked(ctx, emailKey); err == nil && lockout > 0 {
+				countEvent(metricBlockedLocked)
+				SendTooManyRequests(w, lockout)
+
+				return
+			}
+
+			allowed, retryAfter, err = limiter.Allow(ctx, emailKey, opts.EmailLimit)
+			if err == nil && !allowed {
+				countEvent(metricBlockedByEmail)
+				SendTooManyRequests(w, retryAfter)
+
+				return
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status == http.StatusOK {
+				if err := limiter.Reset(ctx, emailKey); err != nil {
+					slog.ErrorContext(ctx, "Something went wrong while resetting failed attempts", "error", err)
+				}
+
+				return
+			}
+			if recorder.status != http.StatusUnauthorized {
+				return
+			}
+
+			countEvent(metricFailures)
+			lockout, err := limiter.RegisterFailure(ctx, emailKey)
+			if err != nil {
+				slog.ErrorContext(ctx, "Something went wrong while registering failed attempt", "error", err)
+			} else if lockout > 0 {
+				countEvent(metricLockouts)
+			}
+		})
+	}
+}
+
+// SendTooManyRequests responds with 429 and tells the client when to retry
+func SendTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	responses.SendErrResponse(w, responses.StatusTooManyRequests, responses.ErrTooManyRequests)
+}
+
+// extractEmail reads the email from the request body and restores the body for the next handler
+func extractEmail(r *http.Request) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxInspectedBodySize))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var data struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(data.Email))
+}
+
+// ClientIP returns the address of the client, the headers of the proxy are trusted only if trustProxy is set
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return realIP
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,159 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLimiter struct {
+	attempts map[string]int
+	failures map[string]int
+	locked   map[string]time.Duration
+}
+
+func newFakeLimiter() *fakeLimiter {
+	return &fakeLimiter{
+		attempts: make(map[string]int),
+		failures: make(map[string]int),
+		locked:   make(map[string]time.Duration),
+	}
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, key string, limit int) (bool, time.Duration, error) {
+	if l.attempts[key] >= limit {
+		return false, 10 * time.Second, nil
+	}
+	l.attempts[key]++
+
+	return true, 0, nil
+}
+
+func (l *fakeLimiter) Locked(_ context.Context, key string) (time.Duration, error) {
+	return l.locked[key], nil
+}
+
+func (l *fakeLimiter) RegisterFailure(_ context.Context, key string) (time.Duration, error) {
+	l.failures[key]++
+	if l.failures[key] >= 2 {
+		l.locked[key] = time.Minute
+		return time.Minute, nil
+	}
+
+	return 0, nil
+}
+
+func (l *fakeLimiter) Reset(_ context.Context, key string) error {
+	delete(l.failures, key)
+	delete(l.locked, key)
+
+	return nil
+}
+
+func newLoginRequest(email string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login",
+		strings.NewReader(`{"email": "`+email+`", "password": "password"}`))
+	req.RemoteAddr = "10.0.0.1:12345"
+
+	return req
+}
+
+func TestRateLimitMiddleware_IPLimit(t *testing.T) {
+	limiter := newFakeLimiter()
+	handler := RateLimitMiddleware(limiter, Options{Scope: "login", IPLimit: 2, EmailLimit: 10})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newLoginRequest("test@example.com"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newLoginRequest("test@example.com"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware_Lockout(t *testing.T) {
+	limiter := newFakeLimiter()
+	handler := RateLimitMiddleware(limiter, Options{Scope: "login", IPLimit: 10, EmailLimit: 10})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newLoginRequest("Test@Example.com"))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newLoginRequest("test@example.com"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware_CountsOnlyRejectedCredentials(t *testing.T) {
+	limiter := newFakeLimiter()
+
+	status := http.StatusBadRequest
+	handler := RateLimitMiddleware(limiter, Options{Scope: "login", IPLimit: 10, EmailLimit: 10})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+	// 403 means that the password is correct, but the email is not verified
+	for _, status = range []int{http.StatusBadRequest, http.StatusForbidden, http.StatusInternalServerError,
+		http.StatusServiceUnavailable} {
+		handler.ServeHTTP(httptest.NewRecorder(), newLoginRequest("test@example.com"))
+	}
+	assert.Empty(t, limiter.failures)
+
+	status = http.StatusUnauthorized
+	handler.ServeHTTP(httptest.NewRecorder(), newLoginRequest("test@example.com"))
+	assert.Equal(t, 1, limiter.failures[EmailKey("login", "test@example.com")])
+}
+
+func TestRateLimitMiddleware_BodyIsPreserved(t *testing.T) {
+	limiter := newFakeLimiter()
+
+	var body string
+	handler := RateLimitMiddleware(limiter, Options{Scope: "login", IPLimit: 10, EmailLimit: 10})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := io.ReadAll(r.Body)
+			body = string(raw)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newLoginRequest("test@example.com"))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, body, "test@example.com")
+	assert.Empty(t, limiter.failures)
+}
+
+func TestRedisLimiter_LockoutDuration(t *testing.T) {
+	l := &redisLimiter{
+		maxFailures: 3,
+		lockoutBase: 30 * time.Second,
+		lockoutMax:  2 * time.Minute,
+	}
+
+	assert.Equal(t, time.Duration(0), l.lockoutDuration(2))
+	assert.Equal(t, 30*time.Second, l.lockoutDuration(3))
+	assert.Equal(t, time.Minute, l.lockoutDuration(4))
+	assert.Equal(t, 2*time.Minute, l.lockoutDuration(5))
+	assert.Equal(t, 2*time.Minute, l.lockoutDuration(10))
+}
@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	windowKeyPrefix  = "ratelimit:window:"
+	failureKeyPrefix = "ratelimit:failures:"
+	lockKeyPrefix    = "ratelimit:lock:"
+)
+
+// allowScript removes attempts older than the window and records the new one only if it fits into the limit,
+// so rejected attempts do not extend the window. It returns -1 if the attempt is allowed, otherwise the time
+// of the oldest attempt in the window in microseconds.
+var allowScript = redis.NewScript(`
+	redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[2])
+	if redis.call('ZCARD', KEYS[1]) < tonumber(ARGV[4]) then
+		redis.call('ZADD', KEYS[1], ARGV[1], ARGV[5])
+		redis.call('PEXPIRE', KEYS[1], ARGV[3])
+		return -1
+	end
+
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	if #oldest == 0 then
+		return tonumber(ARGV[1])
+	end
+
+	return tonumber(oldest[2])
+`)
+
+type redisLimiter struct {
+	client *redis.Client
+
+	window      time.Duration
+	maxFailures int
+	lockoutBase time.Duration
+	lockoutMax  time.Duration
+
+	now func() time.Time
+}
+
+func NewRedisLimiter(client *redis.Client, window time.Duration, maxFailures int,
+	lockoutBase, lockoutMax time.Duration) Limiter {
+	return &redisLimiter{
+		client:      client,
+		window:      window,
+		maxFailures: maxFailures,
+		lockoutBase: lockoutBase,
+		lockoutMax:  lockoutMax,
+		now:         time.Now,
+	}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, limit int) (bool, time.Duration, error) {
+	now := l.now()
+
+	// Timestamps are passed as arguments, Lua numbers lose precision when they are converted to strings
+	oldest, err := allowScript.Run(ctx, l.client, []string{windowKeyPrefix + key}, now.UnixMicro(),
+		now.Add(-l.window).UnixMicro(), l.window.Milliseconds(), limit, uuid.NewString()).Int64()
+	if err != nil {
+		return false, 0, err
+	}
+
+	if oldest < 0 {
+		return true, 0, nil
+	}
+
+	// The window frees up when the oldest attempt in it expires
+	return false, time.UnixMicro(oldest).Add(l.window).Sub(now), nil
+}
+
+func (l *redisLimiter) Locked(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := l.client.PTTL(ctx, lockKeyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// Negative values mean that the key does not exist or has no expiration
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (l *redisLimiter) RegisterFailure(ctx context.Context, key string) (time.Duration, error) {
+	failureKey := failureKeyPrefix + key
+
+	var failures *redis.IntCmd
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		failures = pipe.Incr(ctx, failureKey)
+		pipe.PExpire(ctx, failureKey, l.lockoutMax)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	lockout := l.lockoutDuration(int(failures.Val()))
+	if lockout == 0 {
+		return 0, nil
+	}
+
+	err = l.client.Set(ctx, lockKeyPrefix+key, failures.Val(), lockout).Err()
+	if err != nil {
+		return 0, err
+	}
+
+	return lockout, nil
+}
+
+func (l *redisLimiter) Reset(ctx context.Context, key string) error {
+	return l.client.Del(ctx, failureKeyPrefix+key, lockKeyPrefix+key).Err()
+}
+
+// lockoutDuration doubles the lockout for every failure after the allowed number
+func (l *redisLimiter) lockoutDuration(failures int) time.Duration {
+	if failures < l.maxFailures {
+		return 0
+	}
+
+	lockout := l.lockoutBase
+	for i := l.maxFailures; i < failures && lockout < l.lockoutMax; i++ {
+		lockout *= 2
+	}
+
+	return min(lockout, l.lockoutMax)
+}
+
+func EmailKey(scope, email string) string {
+	return fmt.Sprintf("%s:email:%s", scope, email)
+}
+
+func IPKey(scope, ip string) string {
+	return fmt.Sprintf("%s:ip:%s", scope, ip)
+}
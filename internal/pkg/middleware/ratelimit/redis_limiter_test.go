@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ignoreMember matches script calls without the member of the attempt, which is random
+func ignoreMember(expected, actual []interface{}) error {
+	if len(expected) != len(actual) || !reflect.DeepEqual(expected[:len(expected)-1], actual[:len(actual)-1]) {
+		return fmt.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	return nil
+}
+
+func newTestRedisLimiter(client *redis.Client, now *time.Time) *redisLimiter {
+	l := NewRedisLimiter(client, time.Minute, 3, 30*time.Second, 2*time.Minute).(*redisLimiter)
+	l.now = func() time.Time { return *now }
+
+	return l
+}
+
+func TestRedisLimiter_WindowExpiry(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	now := time.Unix(1700000000, 0)
+	l := newTestRedisLimiter(client, &now)
+
+	key := IPKey("login", "10.0.0.1")
+	expectAttempt := func(oldest int64) {
+		mock.CustomMatch(ignoreMember).ExpectEvalSha(allowScript.Hash(), []string{windowKeyPrefix + key},
+			now.UnixMicro(), now.Add(-time.Minute).UnixMicro(), time.Minute.Milliseconds(), 2, "").SetVal(oldest)
+	}
+
+	expectAttempt(-1)
+	allowed, _, err := l.Allow(context.Background(), key, 2)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Rejected attempts are not recorded, so the window frees up when the oldest allowed attempt expires
+	expectAttempt(now.Add(-45 * time.Second).UnixMicro())
+	allowed, retryAfter, err := l.Allow(context.Background(), key, 2)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 15*time.Second, retryAfter)
+
+	now = now.Add(5 * time.Second)
+	expectAttempt(now.Add(-50 * time.Second).UnixMicro())
+	allowed, retryAfter, err = l.Allow(context.Background(), key, 2)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 10*time.Second, retryAfter)
+
+	now = now.Add(10 * time.Second)
+	expectAttempt(-1)
+	allowed, _, err = l.Allow(context.Background(), key, 2)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedisLimiter_RegisterFailure(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	now := time.Now()
+	l := newTestRedisLimiter(client, &now)
+
+	key := EmailKey("login", "test@example.com")
+	expectFailure := func(failures int64) {
+		mock.ExpectTxPipeline()
+		mock.ExpectIncr(failureKeyPrefix + key).SetVal(failures)
+		mock.ExpectPExpire(failureKeyPrefix+key, 2*time.Minute).SetVal(true)
+		mock.ExpectTxPipelineExec()
+	}
+
+	expectFailure(2)
+	lockout, err := l.RegisterFailure(context.Background(), key)
+	require.NoError(t, err)
+	assert.Zero(t, lockout)
+
+	expectFailure(3)
+	mock.ExpectSet(lockKeyPrefix+key, int64(3), 30*time.Second).SetVal("OK")
+	lockout, err = l.RegisterFailure(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, lockout)
+
+	mock.ExpectDel(failureKeyPrefix+key, lockKeyPrefix+key).SetVal(2)
+	assert.NoError(t, l.Reset(context.Background(), key))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,33 @@
+package requestid
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// IDs from clients are accepted only if they cannot break log lines or headers
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestIDMiddleware takes the request ID from the X-Request-ID header or generates a new one.
+// The ID is stored in the request context and returned in the response header.
+func RequestIDMiddleware() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(responses.RequestIDHeader)
+			if !validRequestID.MatchString(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			w.Header().Set(responses.RequestIDHeader, requestID)
+
+			ctx := logger.WithRequestID(r.Context(), requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"accepted", "abc-123", "abc-123"},
+		{"generated", "", ""},
+		{"rejected", "bad id\nwith newline", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromCtx string
+			handler := RequestIDMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromCtx = logger.RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/files/list", nil)
+			if tt.header != "" {
+				req.Header.Set(responses.RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.NotEmpty(t, fromCtx)
+			assert.Equal(t, fromCtx, rec.Header().Get(responses.RequestIDHeader))
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, fromCtx)
+			} else {
+				assert.NotEqual(t, tt.header, fromCtx)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+-- The schema of the init scripts used before migrations. Tables are created only if missing, so databases
+-- initialized by those scripts are adopted as is and upgraded by the following migrations.
+
+CREATE TABLE IF NOT EXISTS public.user (
+    id INT NOT NULL
+        GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    email TEXT UNIQUE NOT NULL
+        CHECK (email <> '')
+        CONSTRAINT max_len_email CHECK(LENGTH(email) <= 100),
+    password_hash TEXT NOT NULL
+        CHECK (password_hash <> '')
+        CONSTRAINT max_len_password_hash CHECK(LENGTH(password_hash) <= 256)
+);
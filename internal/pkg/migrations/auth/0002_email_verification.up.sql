@@ -0,0 +1,2 @@
+ALTER TABLE public.user
+    ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
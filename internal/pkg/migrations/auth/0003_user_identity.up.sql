@@ -0,0 +1,13 @@
+-- Accounts of external OpenID Connect providers linked to users
+CREATE TABLE IF NOT EXISTS public.user_identity (
+    id INT NOT NULL
+        GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id INT NOT NULL
+        REFERENCES public.user (id) ON DELETE CASCADE,
+    provider TEXT NOT NULL
+        CHECK (provider <> ''),
+    subject TEXT NOT NULL
+        CHECK (subject <> ''),
+    created_time TIMESTAMP DEFAULT NOW() NOT NULL,
+    CONSTRAINT unique_provider_subject UNIQUE (provider, subject)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE public.user
+    DROP COLUMN IF EXISTS disabled,
+    DROP COLUMN IF EXISTS role;
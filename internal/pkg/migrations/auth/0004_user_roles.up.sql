@@ -0,0 +1,5 @@
+-- The first administrator is appointed manually: UPDATE public.user SET role = 'admin' WHERE email = '...';
+ALTER TABLE public.user
+    ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user'
+        CHECK (role IN ('user', 'admin')),
+    ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE;
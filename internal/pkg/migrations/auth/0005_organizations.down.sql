@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.organization_member;
+DROP TABLE IF EXISTS public.organization;
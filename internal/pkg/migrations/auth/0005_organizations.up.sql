@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS public.organization (
+    id INT NOT NULL
+        GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL
+        CHECK (name <> '')
+        CONSTRAINT max_len_org_name CHECK(LENGTH(name) <= 50),
+    created_time TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.organization_member (
+    org_id INT NOT NULL
+        REFERENCES public.organization (id) ON DELETE CASCADE,
+    user_id INT NOT NULL
+        REFERENCES public.user (id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member'
+        CHECK (role IN ('owner', 'admin', 'member')),
+    created_time TIMESTAMP DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (org_id, user_id)
+);
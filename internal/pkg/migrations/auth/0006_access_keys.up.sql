@@ -0,0 +1,12 @@
+-- SigV4 signatures are computed with the secret itself, so it is stored as is and cannot be hashed
+CREATE TABLE IF NOT EXISTS public.access_key (
+    id TEXT NOT NULL PRIMARY KEY
+        CHECK (id <> ''),
+    user_id INT NOT NULL
+        REFERENCES public.user (id) ON DELETE CASCADE,
+    secret TEXT NOT NULL
+        CHECK (secret <> ''),
+    created_time TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS access_key_user_id_idx ON public.access_key (user_id);
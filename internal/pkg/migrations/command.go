@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Run executes a migrate subcommand: up, down [steps] or version. Without arguments it applies migrations.
+func Run(ctx context.Context, pool *pgxpool.Pool, migrations []*Migration, lockID int64, args []string) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	migrator := NewMigrator(conn, migrations, lockID)
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Database is up to date", "applied", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		slog.Info("Migrations reverted", "reverted", reverted)
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Current schema version", "version", version)
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or version", command)
+	}
+
+	return nil
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.file_metadata;
+DROP FUNCTION IF EXISTS set_deleted_time();
+DROP FUNCTION IF EXISTS change_metadata_update_time();
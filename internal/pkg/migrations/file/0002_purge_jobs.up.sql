@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS public.purge_job (
+    id UUID PRIMARY KEY NOT NULL,
+    owner_id INT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    deleted_objects BIGINT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    create_time TIMESTAMP DEFAULT NOW() NOT NULL,
+    update_time TIMESTAMP DEFAULT NOW() NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_unfinished_purge_job
+    ON public.purge_job (owner_id)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS public.export_job (
+    id UUID PRIMARY KEY NOT NULL,
+    owner_id INT NOT NULL,
+    email TEXT NOT NULL DEFAULT '',
+    account JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'done', 'failed', 'expired')),
+    object_key TEXT NOT NULL DEFAULT '',
+    size BIGINT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    create_time TIMESTAMP DEFAULT NOW() NOT NULL,
+    update_time TIMESTAMP DEFAULT NOW() NOT NULL,
+    expire_time TIMESTAMP DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_unfinished_export_job
+    ON public.export_job (owner_id)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,7 @@
+-- Users without a row get the default quota from the config
+CREATE TABLE IF NOT EXISTS public.storage_quota (
+    owner_id INT PRIMARY KEY NOT NULL,
+    quota_bytes BIGINT NOT NULL
+        CHECK (quota_bytes >= 0),
+    update_time TIMESTAMP DEFAULT NOW() NOT NULL
+);
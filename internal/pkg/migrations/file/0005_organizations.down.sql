@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.org_storage_quota;
+
+DROP INDEX IF EXISTS public.file_metadata_org_id;
+
+ALTER TABLE public.file_metadata
+    DROP COLUMN IF EXISTS org_id;
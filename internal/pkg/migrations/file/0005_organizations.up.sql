@@ -0,0 +1,14 @@
+-- Files of an organization keep the uploader in owner_id
+ALTER TABLE public.file_metadata
+    ADD COLUMN IF NOT EXISTS org_id INT DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS file_metadata_org_id
+    ON public.file_metadata (org_id)
+    WHERE org_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.org_storage_quota (
+    org_id INT PRIMARY KEY NOT NULL,
+    quota_bytes BIGINT NOT NULL
+        CHECK (quota_bytes >= 0),
+    update_time TIMESTAMP DEFAULT NOW() NOT NULL
+);
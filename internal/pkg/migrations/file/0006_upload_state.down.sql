@@ -0,0 +1,3 @@
+ALTER TABLE public.file_metadata
+    DROP COLUMN IF EXISTS pending_storage_key,
+    DROP COLUMN IF EXISTS upload_state;
@@ -0,0 +1,6 @@
+-- Rows are inserted as pending before the object is uploaded and committed after it. A replacement object is
+-- referenced by pending_storage_key while it is being uploaded, so the reconciler does not treat it as orphaned.
+ALTER TABLE public.file_metadata
+    ADD COLUMN IF NOT EXISTS upload_state TEXT NOT NULL DEFAULT 'committed'
+        CHECK (upload_state IN ('pending', 'committed')),
+    ADD COLUMN IF NOT EXISTS pending_storage_key TEXT DEFAULT NULL;
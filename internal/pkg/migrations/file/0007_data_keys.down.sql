@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS public.file_metadata_data_key_id_idx;
+
+ALTER TABLE public.file_metadata
+    DROP COLUMN IF EXISTS data_key_id,
+    DROP COLUMN IF EXISTS data_key;
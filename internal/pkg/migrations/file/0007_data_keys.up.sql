@@ -0,0 +1,8 @@
+-- data_key is the key of the file content wrapped with the master key data_key_id. Files without it are
+-- stored in plaintext.
+ALTER TABLE public.file_metadata
+    ADD COLUMN IF NOT EXISTS data_key BYTEA DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS data_key_id TEXT DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS file_metadata_data_key_id_idx ON public.file_metadata (data_key_id)
+    WHERE data_key_id IS NOT NULL;
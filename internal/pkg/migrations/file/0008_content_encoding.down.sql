@@ -0,0 +1,3 @@
+ALTER TABLE public.file_metadata
+    DROP COLUMN IF EXISTS stored_size,
+    DROP COLUMN IF EXISTS content_encoding;
@@ -0,0 +1,5 @@
+-- stored_size is the size of the object, "size" stays the size of the content and is used for quotas.
+-- NULL values mean that the object is stored as is.
+ALTER TABLE public.file_metadata
+    ADD COLUMN IF NOT EXISTS content_encoding TEXT DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS stored_size BIGINT DEFAULT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE public.file_metadata
+    DROP COLUMN IF EXISTS checksum;
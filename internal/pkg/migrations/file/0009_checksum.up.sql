@@ -0,0 +1,3 @@
+-- MD5 of the content before compression and encryption, it is used as the ETag of S3 objects
+ALTER TABLE public.file_metadata
+    ADD COLUMN IF NOT EXISTS checksum TEXT DEFAULT NULL;
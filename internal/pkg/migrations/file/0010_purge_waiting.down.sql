@@ -0,0 +1,13 @@
+DELETE
+FROM public.purge_job
+WHERE status IN ('waiting', 'cancelled');
+
+DROP INDEX IF EXISTS unique_unfinished_purge_job;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_unfinished_purge_job
+    ON public.purge_job (owner_id)
+    WHERE status IN ('pending', 'running');
+
+ALTER TABLE public.purge_job
+    DROP CONSTRAINT IF EXISTS purge_job_status_check,
+    ADD CONSTRAINT purge_job_status_check
+        CHECK (status IN ('pending', 'running', 'done', 'failed'));
@@ -0,0 +1,11 @@
+-- Purge jobs are recorded as waiting before the user is deleted and start only after the auth service
+-- confirms the deletion. Jobs of users whose deletion has failed are cancelled.
+ALTER TABLE public.purge_job
+    DROP CONSTRAINT IF EXISTS purge_job_status_check,
+    ADD CONSTRAINT purge_job_status_check
+        CHECK (status IN ('waiting', 'pending', 'running', 'done', 'failed', 'cancelled'));
+
+DROP INDEX IF EXISTS unique_unfinished_purge_job;
+CREATE UNIQUE INDEX IF NOT EXISTS unique_unfinished_purge_job
+    ON public.purge_job (owner_id)
+    WHERE status IN ('waiting', 'pending', 'running');
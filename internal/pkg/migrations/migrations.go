@@ -0,0 +1,247 @@
+// Package migrations applies the versioned schema of the auth and file databases. Files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql and are embedded into the binaries.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed auth/*.sql
+var authFS embed.FS
+
+//go:embed file/*.sql
+var fileFS embed.FS
+
+// Lock IDs of pg_advisory_lock, they only have to differ from other advisory locks in the same database
+const (
+	AuthLockID int64 = 7_221_001
+	FileLockID int64 = 7_221_002
+)
+
+func Auth() ([]*Migration, error) {
+	return Load(authFS, "auth")
+}
+
+func File() ([]*Migration, error) {
+	return Load(fileFS, "file")
+}
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Conn is a single connection, as the advisory lock belongs to the session that took it
+type Conn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var fileNameRegexp = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load reads migrations from the directory of fsys. Every version needs an up file, down files are optional.
+func Load(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := fileNameRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	slices.SortFunc(migrations, func(a, b *Migration) int {
+		return int(a.Version - b.Version)
+	})
+
+	return migrations, nil
+}
+
+const (
+	createTableQuery = `
+		CREATE TABLE IF NOT EXISTS public.schema_migrations (
+			version BIGINT PRIMARY KEY NOT NULL,
+			name TEXT NOT NULL,
+			applied_time TIMESTAMP DEFAULT NOW() NOT NULL
+		)`
+	lockQuery           = `SELECT pg_advisory_lock($1)`
+	unlockQuery         = `SELECT pg_advisory_unlock($1)`
+	currentVersionQuery = `SELECT COALESCE(MAX(version), 0) FROM public.schema_migrations`
+	insertVersionQuery  = `INSERT INTO public.schema_migrations (version, name) VALUES ($1, $2)`
+	deleteVersionQuery  = `DELETE FROM public.schema_migrations WHERE version = $1`
+)
+
+type Migrator struct {
+	conn       Conn
+	migrations []*Migration
+	lockID     int64
+}
+
+func NewMigrator(conn Conn, migrations []*Migration, lockID int64) *Migrator {
+	return &Migrator{
+		conn:       conn,
+		migrations: migrations,
+		lockID:     lockID,
+	}
+}
+
+// Up applies all migrations newer than the current version, each one in its own transaction
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+
+	err := m.locked(ctx, func(version int64) error {
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			err := m.apply(ctx, migration.Up, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, insertVersionQuery, migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the given number of the latest applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+
+	err := m.locked(ctx, func(version int64) error {
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be reverted", migration.Version, migration.Name)
+			}
+
+			err := m.apply(ctx, migration.Down, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, deleteVersionQuery, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			slog.Info("Reverted migration", "version", migration.Version, "name", migration.Name)
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	var version int64
+
+	err := m.locked(ctx, func(current int64) error {
+		version = current
+		return nil
+	})
+
+	return version, err
+}
+
+// locked runs fn under the advisory lock, so services started together do not apply migrations twice
+func (m *Migrator) locked(ctx context.Context, fn func(version int64) error) (err error) {
+	if _, err := m.conn.Exec(ctx, lockQuery, m.lockID); err != nil {
+		return err
+	}
+	defer func() {
+		// The lock is released even if ctx is already cancelled
+		if _, unlockErr := m.conn.Exec(context.WithoutCancel(ctx), unlockQuery, m.lockID); err == nil {
+			err = unlockErr
+		}
+	}()
+
+	if _, err := m.conn.Exec(ctx, createTableQuery); err != nil {
+		return err
+	}
+
+	var version int64
+	if err := m.conn.QueryRow(ctx, currentVersionQuery).Scan(&version); err != nil {
+		return err
+	}
+
+	return fn(version)
+}
+
+func (m *Migrator) apply(ctx context.Context, sql string, record func(tx pgx.Tx) error) error {
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,264 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0002_add_role.up.sql":   {Data: []byte("ALTER TABLE a ADD role TEXT;")},
+		"sql/0001_init.up.sql":       {Data: []byte("CREATE TABLE a ();")},
+		"sql/0001_init.down.sql":     {Data: []byte("DROP TABLE a;")},
+		"sql/0002_add_role.down.sql": {Data: []byte("ALTER TABLE a DROP role;")},
+	}
+
+	migrations, err := Load(fsys, "sql")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "init", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE a;", migrations[0].Down)
+	assert.Equal(t, "add_role", migrations[1].Name)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"no up file":        {"sql/0001_init.down.sql": {Data: []byte("DROP TABLE a;")}},
+		"bad name":          {"sql/init.sql": {Data: []byte("CREATE TABLE a ();")}},
+		"duplicate version": {"sql/0001_a.up.sql": {}, "sql/0001_b.up.sql": {}},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(fsys, "sql")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	for _, load := range []func() ([]*Migration, error){Auth, File} {
+		migrations, err := load()
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+
+		for _, migration := range migrations {
+			assert.NotEmpty(t, migration.Down, "migration %d_%s", migration.Version, migration.Name)
+		}
+	}
+}
+
+var testMigrations = []*Migration{
+	{Version: 1, Name: "init", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"},
+	{Version: 2, Name: "add_role", Up: "ALTER TABLE a ADD role TEXT;", Down: "ALTER TABLE a DROP role;"},
+}
+
+func expectLocked(mock pgxmock.PgxConnIface, version int64) {
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(AuthLockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS public.schema_migrations").
+		WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mock.ExpectQuery("SELECT COALESCE").
+		WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(version))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	mock, err := pgxmock.NewConn(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer mock.Close(context.Background())
+
+	expectLocked(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE a ADD role").WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+	mock.ExpectExec("INSERT INTO public.schema_migrations").WithArgs(int64(2), "add_role").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(AuthLockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	applied, err := NewMigrator(mock, testMigrations, AuthLockID).Up(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_UpFailureRollsBack(t *testing.T) {
+	mock, err := pgxmock.NewConn(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer mock.Close(context.Background())
+
+	expectLocked(mock, 0)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE a").WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(AuthLockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	applied, err := NewMigrator(mock, testMigrations, AuthLockID).Up(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 0, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Down(t *testing.T) {
+	mock, err := pgxmock.NewConn(pgxmock.QueryMatcherOption(pgxmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer mock.Close(context.Background())
+
+	expectLocked(mock, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE a DROP role").WillReturnResult(pgxmock.NewResult("ALTER TABLE", 0))
+	mock.ExpectExec("DELETE FROM public.schema_migrations").WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(AuthLockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	reverted, err := NewMigrator(mock, testMigrations, AuthLockID).Down(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var commentRegexp = regexp.MustCompile(`(?m)^--.*$`)
+
+// normalizeSchema drops comments and whitespace. Triggers of the first migration are replaced if they exist,
+// the init scripts created them unconditionally.
+func normalizeSchema(sql string) string {
+	sql = commentRegexp.ReplaceAllString(sql, "")
+	sql = strings.ReplaceAll(sql, "CREATE OR REPLACE TRIGGER", "CREATE TRIGGER")
+
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+func TestInitMatchesBaseline(t *testing.T) {
+	for name, load := range map[string]func() ([]*Migration, error){"auth": Auth, "file": File} {
+		t.Run(name, func(t *testing.T) {
+			migrations, err := load()
+			require.NoError(t, err)
+
+			baseline, err := os.ReadFile("testdata/baseline_" + name + ".sql")
+			require.NoError(t, err)
+
+			assert.Equal(t, normalizeSchema(string(baseline)), normalizeSchema(migrations[0].Up))
+		})
+	}
+}
+
+// TestMigrator_UpgradesBaseline needs a PostgreSQL server, TEST_POSTGRES_DSN is a user allowed to create
+// databases. A database built by the init scripts used before migrations must end up with the same schema
+// as a new one.
+func TestMigrator_UpgradesBaseline(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	for name, load := range map[string]func() ([]*Migration, error){"auth": Auth, "file": File} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			migrations, err := load()
+			require.NoError(t, err)
+
+			baseline, err := os.ReadFile("testdata/baseline_" + name + ".sql")
+			require.NoError(t, err)
+
+			upgraded := createDatabase(t, dsn)
+			_, err = upgraded.Exec(ctx, string(baseline))
+			require.NoError(t, err)
+
+			migrator := NewMigrator(upgraded, migrations, AuthLockID)
+			applied, err := migrator.Up(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, len(migrations), applied)
+
+			fresh := createDatabase(t, dsn)
+			_, err = NewMigrator(fresh, migrations, AuthLockID).Up(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, schemaOf(t, fresh), schemaOf(t, upgraded))
+
+			// Down steps revert everything, only the table of versions stays
+			reverted, err := migrator.Down(ctx, len(migrations))
+			require.NoError(t, err)
+			assert.Equal(t, len(migrations), reverted)
+
+			empty := createDatabase(t, dsn)
+			_, err = NewMigrator(empty, nil, AuthLockID).Version(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, schemaOf(t, empty), schemaOf(t, upgraded))
+		})
+	}
+}
+
+func createDatabase(t *testing.T, dsn string) *pgx.Conn {
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	require.NoError(t, err)
+
+	name := fmt.Sprintf("voblako_migrations_%d", time.Now().UnixNano())
+	_, err = admin.Exec(ctx, "CREATE DATABASE "+name)
+	require.NoError(t, err)
+
+	config, err := pgx.ParseConfig(dsn)
+	require.NoError(t, err)
+	config.Database = name
+
+	conn, err := pgx.ConnectConfig(ctx, config)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close(ctx)
+		_, _ = admin.Exec(ctx, "DROP DATABASE IF EXISTS "+name)
+		admin.Close(ctx)
+	})
+
+	return conn
+}
+
+// schemaOf lists columns, constraints, indexes, triggers and functions of the public schema
+func schemaOf(t *testing.T, conn *pgx.Conn) []string {
+	rows, err := conn.Query(context.Background(), `
+		SELECT 'column ' || table_name || '.' || column_name || ' ' || data_type || ' ' || is_nullable || ' ' ||
+			COALESCE(column_default, '')
+		FROM information_schema.columns
+		WHERE table_schema = 'public'
+		UNION ALL
+		SELECT 'constraint ' || conrelid::regclass::text || ' ' || conname || ' ' || pg_get_constraintdef(oid)
+		FROM pg_constraint
+		WHERE connamespace = 'public'::regnamespace
+		UNION ALL
+		SELECT 'index ' || indexname || ' ' || indexdef
+		FROM pg_indexes
+		WHERE schemaname = 'public'
+		UNION ALL
+		SELECT 'trigger ' || tgrelid::regclass::text || ' ' || tgname
+		FROM pg_trigger
+		WHERE NOT tgisinternal
+		UNION ALL
+		SELECT 'function ' || proname
+		FROM pg_proc
+		WHERE pronamespace = 'public'::regnamespace
+		ORDER BY 1`)
+	require.NoError(t, err)
+
+	schema, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+
+	return schema
+}
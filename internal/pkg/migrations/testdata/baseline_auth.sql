@@ -1,5 +1,3 @@
-\c voblako_db;
-
 CREATE TABLE IF NOT EXISTS public.user (
     id INT NOT NULL
         GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
@@ -1,5 +1,3 @@
-\c voblako_db;
-
 CREATE TABLE IF NOT EXISTS public.file_metadata (
     id UUID PRIMARY KEY UNIQUE NOT NULL,
     owner_id INT NOT NULL,
@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	swaggerfiles "github.com/swaggo/files/v2"
+)
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>voblako API</title>
+  <link rel="stylesheet" href="%[1]s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[1]s/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "%[2]s", dom_id: "#swagger-ui", withCredentials: true});
+    };
+  </script>
+</body>
+</html>
+`
+
+// DocsHandler serves Swagger UI at docsPath showing the document served at specURL. The assets of Swagger UI
+// are embedded into the binary and served under docsPath, so the page works without access to the internet.
+func DocsHandler(docsPath, specURL string) http.Handler {
+	page := []byte(fmt.Sprintf(docsPage, html.EscapeString(docsPath), html.EscapeString(specURL)))
+	assets := http.StripPrefix(docsPath, http.FileServerFS(swaggerfiles.FS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == docsPath:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write(page)
+		case strings.HasPrefix(r.URL.Path, docsPath+"/") && !strings.HasSuffix(r.URL.Path, "/"):
+			assets.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
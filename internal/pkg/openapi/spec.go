@@ -0,0 +1,81 @@
+// Package openapi serves the OpenAPI document of the REST gateway and validates requests against it
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+// Formats other than uuid are annotations, e.g. emails are checked by the handlers, which answer with more
+// specific errors
+func init() {
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewCallbackValidator(uuid.Validate))
+}
+
+// Spec is the parsed document, it is served as JSON
+type Spec struct {
+	doc  *openapi3.T
+	json []byte
+}
+
+// Load parses the embedded document, references must resolve and the document must be valid
+func Load() (*Spec, error) {
+	return parse(document)
+}
+
+func parse(data []byte) (*Spec, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	spec := &Spec{doc: doc}
+	if spec.json, err = doc.MarshalJSON(); err != nil {
+		return nil, fmt.Errorf("cannot convert OpenAPI document to JSON: %w", err)
+	}
+
+	return spec, nil
+}
+
+// Operation returns nil if the document has no operation for the method and the path
+func (spec *Spec) Operation(method, path string) *openapi3.Operation {
+	item := spec.doc.Paths.Value(path)
+	if item == nil {
+		return nil
+	}
+
+	return item.GetOperation(method)
+}
+
+// Operations lists the operations of the document by the method and the path, e.g. "GET /api/files/{id}"
+func (spec *Spec) Operations() []string {
+	var operations []string
+	for path, item := range spec.doc.Paths.Map() {
+		for method := range item.Operations() {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(operations)
+
+	return operations
+}
+
+// ServeHTTP serves the document as JSON
+func (spec *Spec) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(spec.json)
+}
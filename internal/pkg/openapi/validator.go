@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"regexp"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gorilla/mux"
+)
+
+const (
+	contentTypeJSON      = "application/json"
+	contentTypeMultipart = "multipart/form-data"
+
+	// maxJSONBody limits bodies read for validation, file contents are sent as multipart forms
+	maxJSONBody = 1 << 20
+)
+
+// templateVariable matches variables of mux path templates with their patterns, e.g. {id:[0-9]+}
+var templateVariable = regexp.MustCompile(`\{([^:}]+)(:[^}]*)?\}`)
+
+var errMultipartExpected = errors.New("multipart form is expected")
+
+// validationOptions leave authentication to the handlers and keep requests as they are
+var validationOptions = &openapi3filter.Options{
+	AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+	SkipSettingDefaults: true,
+}
+
+// multipartOptions skip bodies of multipart forms, they are streamed to the handlers
+var multipartOptions = &openapi3filter.Options{
+	AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+	SkipSettingDefaults: true,
+	ExcludeRequestBody:  true,
+}
+
+// ValidationMiddleware rejects requests that do not match the operation of their route. Routes without
+// an operation, e.g. metrics, are passed as they are.
+func ValidationMiddleware(spec *Spec) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			path := PathFromTemplate(template)
+			operation := spec.Operation(r.Method, path)
+			if operation == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := spec.validateRequest(w, r, path, operation); err != nil {
+				slog.DebugContext(r.Context(), "Request does not match OpenAPI specification",
+					"operation", operation.OperationID, "error", err)
+				responses.SendErrResponse(w, responses.StatusBadRequest, errorStatus(err))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PathFromTemplate removes patterns of variables from the path template of a mux route
+func PathFromTemplate(template string) string {
+	return templateVariable.ReplaceAllString(template, "{$1}")
+}
+
+func (spec *Spec) validateRequest(w http.ResponseWriter, r *http.Request, path string,
+	operation *openapi3.Operation) error {
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: mux.Vars(r),
+		Route: &routers.Route{
+			Spec:      spec.doc,
+			Path:      path,
+			PathItem:  spec.doc.Paths.Value(path),
+			Method:    r.Method,
+			Operation: operation,
+		},
+		Options: validationOptions,
+	}
+
+	body := operation.RequestBody
+	switch {
+	case body == nil:
+	case body.Value.Content.Get(contentTypeMultipart) != nil:
+		input.Options = multipartOptions
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			return err
+		}
+
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != contentTypeMultipart || params["boundary"] == "" {
+			return errMultipartExpected
+		}
+
+		return nil
+	case body.Value.Content.Get(contentTypeJSON) != nil:
+		// Handlers decode bodies as JSON whatever their content type is, so they are validated as JSON.
+		// The validator reads the body of the copy and puts it back, the handler gets it from there.
+		validated := r.Clone(r.Context())
+		validated.Header.Set("Content-Type", contentTypeJSON)
+		validated.Body = http.MaxBytesReader(w, r.Body, maxJSONBody)
+		input.Request = validated
+
+		defer func() {
+			r.Body = validated.Body
+		}()
+	}
+
+	return openapi3filter.ValidateRequest(r.Context(), input)
+}
+
+// errorStatus returns the status the handlers would send for the invalid part of the request
+func errorStatus(err error) string {
+	if errors.Is(err, errMultipartExpected) {
+		return responses.ErrBadForm
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if errors.As(err, &reqErr) && reqErr.Parameter != nil {
+		if reqErr.Parameter.In == openapi3.ParameterInPath {
+			return responses.ErrInvalidID
+		}
+
+		return responses.ErrInvalidURLParams
+	}
+
+	return responses.ErrBadJSON
+}
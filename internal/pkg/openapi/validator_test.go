@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFileID = "0b5a3f7e-5c1d-4c56-9a3e-2f1d7e9b8c41"
+
+// newTestRouter registers a few routes of the gateway, their handlers answer with the body they received
+func newTestRouter(t *testing.T) *mux.Router {
+	spec, err := Load()
+	require.NoError(t, err)
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		_, _ = w.Write(body)
+	})
+
+	router := mux.NewRouter()
+	router.Use(ValidationMiddleware(spec))
+	router.Handle("/api/auth/login", echo).Methods("POST")
+	router.Handle("/api/files", echo).Methods("POST")
+	router.Handle("/api/files/list", echo).Methods("POST")
+	router.Handle("/api/files/uploads/{id}", echo).Methods("POST")
+	router.Handle("/api/files/{id}", echo).Methods("GET")
+	router.Handle("/api/orgs/{id:[0-9]+}/members/{userID:[0-9]+}", echo).Methods("DELETE")
+	router.Handle("/metrics", echo).Methods("GET")
+
+	return router
+}
+
+func TestValidationMiddleware(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  string
+	}{
+		{"valid body", "POST", "/api/auth/login", "", `{"email":"user@example.com","password":"password"}`, ""},
+		{"missing property", "POST", "/api/auth/login", "", `{"email":"user@example.com"}`, responses.ErrBadJSON},
+		{"wrong type", "POST", "/api/auth/login", "", `{"email":"user@example.com","password":1}`,
+			responses.ErrBadJSON},
+		{"null body", "POST", "/api/auth/login", "", `null`, responses.ErrBadJSON},
+		{"empty body", "POST", "/api/auth/login", "", ``, responses.ErrBadJSON},
+		{"invalid JSON", "POST", "/api/auth/login", "", `{"email":`, responses.ErrBadJSON},
+		{"negative integer", "POST", "/api/files/list", "", `{"limit":-1}`, responses.ErrBadJSON},
+		{"fractional integer", "POST", "/api/files/list", "", `{"limit":1.5}`, responses.ErrBadJSON},
+		{"unknown property", "POST", "/api/files/list", "", `{"limit":10,"sort":"name"}`, ""},
+		{"optional body", "POST", "/api/files/uploads/" + testFileID, "", ``, ""},
+		{"invalid path parameter", "GET", "/api/files/1", "", ``, responses.ErrInvalidID},
+		{"valid path parameter", "GET", "/api/files/" + testFileID, "", ``, ""},
+		{"pattern of route", "DELETE", "/api/orgs/1/members/2", "", ``, ""},
+		{"invalid query parameter", "POST", "/api/files?org_id=first", "multipart/form-data; boundary=x", ``,
+			responses.ErrInvalidURLParams},
+		{"multipart form", "POST", "/api/files?org_id=1", "multipart/form-data; boundary=x", ``, ""},
+		{"not multipart form", "POST", "/api/files", "application/json", `{}`, responses.ErrBadForm},
+		{"route without operation", "GET", "/metrics", "", ``, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if test.wantStatus == "" {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, test.body, rec.Body.String())
+				return
+			}
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var resp responses.ErrResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, test.wantStatus, resp.Status)
+		})
+	}
+}
+
+func TestPathFromTemplate(t *testing.T) {
+	assert.Equal(t, "/api/orgs/{id}/members/{userID}", PathFromTemplate("/api/orgs/{id:[0-9]+}/members/{userID:[0-9]+}"))
+	assert.Equal(t, "/api/files/{id}", PathFromTemplate("/api/files/{id}"))
+}
+
+func TestLoad(t *testing.T) {
+	spec, err := Load()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	spec.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	var document map[string]any
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+	assert.Contains(t, document["paths"], "/api/files/{id}")
+
+	_, err = parse([]byte(`
+paths:
+  /api/files:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Missing'
+`))
+	assert.ErrorContains(t, err, "#/components/schemas/Missing")
+}
+
+func TestDocsHandler(t *testing.T) {
+	handler := DocsHandler("/api/docs", "/api/openapi.json")
+
+	tests := []struct {
+		path        string
+		wantCode    int
+		contentType string
+	}{
+		{"/api/docs", http.StatusOK, "text/html; charset=utf-8"},
+		{"/api/docs/swagger-ui-bundle.js", http.StatusOK, "text/javascript; charset=utf-8"},
+		{"/api/docs/swagger-ui.css", http.StatusOK, "text/css; charset=utf-8"},
+		{"/api/docs/", http.StatusNotFound, ""},
+		{"/api/docs/missing.js", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			assert.Equal(t, test.wantCode, rec.Code)
+			if test.contentType != "" {
+				assert.Equal(t, test.contentType, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+	assert.Contains(t, rec.Body.String(), `src="/api/docs/swagger-ui-bundle.js"`)
+	assert.NotContains(t, rec.Body.String(), "unpkg.com")
+}
@@ -0,0 +1,615 @@
+package rest
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	s3interfaces "github.com/IlyaChgn/voblako/internal/pkg/s3"
+	"github.com/IlyaChgn/voblako/internal/pkg/s3/sigv4"
+	s3uc "github.com/IlyaChgn/voblako/internal/pkg/s3/usecases"
+)
+
+// maxXMLBodySize limits request bodies of the operations that take XML
+const maxXMLBodySize = 1 << 20
+
+// S3Handler serves path-style S3 requests, the first path segment is the bucket and the rest is the key.
+// Every request is signed with SigV4 by an access key of the user.
+type S3Handler struct {
+	authUsecases          authinterface.AuthUsecases
+	usecases              s3interfaces.S3Usecases
+	region                string
+	maxObjectSize         int64
+	allowUnverifiedUpload bool
+}
+
+func NewS3Handler(authUsecases authinterface.AuthUsecases, usecases s3interfaces.S3Usecases, region string,
+	maxObjectSize int64, allowUnverifiedUpload bool) *S3Handler {
+	return &S3Handler{
+		authUsecases:          authUsecases,
+		usecases:              usecases,
+		region:                region,
+		maxObjectSize:         maxObjectSize,
+		allowUnverifiedUpload: allowUnverifiedUpload,
+	}
+}
+
+type s3Request struct {
+	user   *models.User
+	sig    *sigv4.Signature
+	bucket string
+	key    string
+	query  url.Values
+}
+
+func (h *S3Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := h.authenticate(r)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	req.bucket, req.key, _ = strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	req.query = r.URL.Query()
+
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		if !req.user.Verified && !h.allowUnverifiedUpload {
+			sendError(w, r, errUnverified)
+			return
+		}
+	}
+
+	switch {
+	case req.bucket == "":
+		h.serveService(w, r, req)
+	case req.key == "":
+		h.serveBucket(w, r, req)
+	default:
+		h.serveObject(w, r, req)
+	}
+}
+
+func (h *S3Handler) authenticate(r *http.Request) (*s3Request, error) {
+	if r.Header.Get("Authorization") == "" && r.URL.Query().Get("X-Amz-Algorithm") == "" {
+		return nil, models.PermissionDeniedError
+	}
+
+	sig, err := sigv4.Parse(r, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	key, user, err := h.authUsecases.GetAccessKey(r.Context(), sig.AccessKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sig.Verify(r, key.Secret); err != nil {
+		return nil, err
+	}
+
+	return &s3Request{user: user, sig: sig}, nil
+}
+
+func (h *S3Handler) serveService(w http.ResponseWriter, r *http.Request, req *s3Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, r, errMethodNotAllowed)
+		return
+	}
+
+	h.listBuckets(w, r, req)
+}
+
+func (h *S3Handler) serveBucket(w http.ResponseWriter, r *http.Request, req *s3Request) {
+	bucket, err := h.usecases.GetBucket(r.Context(), req.user.ID, req.bucket)
+	if err != nil {
+		if r.Method == http.MethodPut && errors.Is(err, models.BucketNotExists) {
+			sendError(w, r, errIllegalBucketCreation)
+			return
+		}
+
+		sendErr(w, r, err)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && req.query.Has("location"):
+		sendXML(w, http.StatusOK, locationResponse{Xmlns: xmlns, Location: h.region})
+	case r.Method == http.MethodGet && req.query.Get("list-type") == "2":
+		h.listObjectsV2(w, r, req, bucket)
+	case r.Method == http.MethodGet && !hasSubresource(req.query):
+		h.listObjectsV1(w, r, req, bucket)
+	case r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPut && !hasSubresource(req.query):
+		// Buckets always exist, creating an own bucket succeeds like in us-east-1
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPost && req.query.Has("delete"):
+		h.deleteObjects(w, r, req, bucket)
+	default:
+		sendError(w, r, errNotImplemented)
+	}
+}
+
+func (h *S3Handler) serveObject(w http.ResponseWriter, r *http.Request, req *s3Request) {
+	bucket, err := h.usecases.GetBucket(r.Context(), req.user.ID, req.bucket)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	uploadID := req.query.Get("uploadId")
+	copySource := r.Header.Get("X-Amz-Copy-Source")
+
+	switch {
+	case r.Method == http.MethodGet && !hasSubresource(req.query):
+		h.getObject(w, r, req, bucket)
+	case r.Method == http.MethodHead:
+		h.headObject(w, r, req, bucket)
+	case r.Method == http.MethodPut && uploadID != "" && copySource == "":
+		h.uploadPart(w, r, req, uploadID)
+	case r.Method == http.MethodPut && copySource != "" && !hasSubresource(req.query):
+		h.copyObject(w, r, req, bucket, copySource)
+	case r.Method == http.MethodPut && copySource == "" && !hasSubresource(req.query):
+		h.putObject(w, r, req, bucket)
+	case r.Method == http.MethodDelete && uploadID != "":
+		h.abortMultipartUpload(w, r, req, uploadID)
+	case r.Method == http.MethodDelete && !hasSubresource(req.query):
+		h.deleteObject(w, r, req, bucket)
+	case r.Method == http.MethodPost && req.query.Has("uploads"):
+		h.createMultipartUpload(w, r, req, bucket)
+	case r.Method == http.MethodPost && uploadID != "":
+		h.completeMultipartUpload(w, r, req, uploadID)
+	default:
+		sendError(w, r, errNotImplemented)
+	}
+}
+
+func (h *S3Handler) listBuckets(w http.ResponseWriter, r *http.Request, req *s3Request) {
+	buckets, err := h.usecases.ListBuckets(r.Context(), req.user.ID)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	resp := listBucketsResponse{
+		Xmlns: xmlns,
+		Owner: owner{ID: strconv.Itoa(int(req.user.ID)), DisplayName: req.user.Email},
+	}
+	for _, bucket := range buckets {
+		resp.Buckets = append(resp.Buckets, bucketEntry{Name: bucket.Name, CreationDate: formatTime(time.Unix(0, 0))})
+	}
+
+	sendXML(w, http.StatusOK, resp)
+}
+
+func (h *S3Handler) listObjectsV2(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	options, err := listOptions(req.query)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	options.StartAfter = req.query.Get("start-after")
+	token := req.query.Get("continuation-token")
+	if token != "" {
+		rawToken, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			sendErr(w, r, models.InvalidInputError)
+			return
+		}
+		options.StartAfter = max(options.StartAfter, string(rawToken))
+	}
+
+	list, err := h.usecases.ListObjects(r.Context(), req.user.ID, bucket, options)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	encode := encoder(req.query)
+	resp := listObjectsV2Response{
+		Xmlns:             xmlns,
+		Name:              bucket.Name,
+		Prefix:            encode(options.Prefix),
+		Delimiter:         encode(options.Delimiter),
+		StartAfter:        encode(req.query.Get("start-after")),
+		ContinuationToken: token,
+		EncodingType:      req.query.Get("encoding-type"),
+		MaxKeys:           options.MaxKeys,
+		KeyCount:          len(list.Objects) + len(list.CommonPrefixes),
+		IsTruncated:       list.IsTruncated,
+	}
+	if list.IsTruncated {
+		resp.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(list.NextStartAfter))
+	}
+	resp.Contents, resp.CommonPrefixes = convertList(list, encode)
+
+	sendXML(w, http.StatusOK, resp)
+}
+
+func (h *S3Handler) listObjectsV1(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	options, err := listOptions(req.query)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+	options.StartAfter = req.query.Get("marker")
+
+	list, err := h.usecases.ListObjects(r.Context(), req.user.ID, bucket, options)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	encode := encoder(req.query)
+	resp := listObjectsV1Response{
+		Xmlns:        xmlns,
+		Name:         bucket.Name,
+		Prefix:       encode(options.Prefix),
+		Marker:       encode(options.StartAfter),
+		Delimiter:    encode(options.Delimiter),
+		EncodingType: req.query.Get("encoding-type"),
+		MaxKeys:      options.MaxKeys,
+		IsTruncated:  list.IsTruncated,
+	}
+	if list.IsTruncated {
+		resp.NextMarker = encode(list.NextStartAfter)
+	}
+	resp.Contents, resp.CommonPrefixes = convertList(list, encode)
+
+	sendXML(w, http.StatusOK, resp)
+}
+
+func (h *S3Handler) getObject(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	object, content, err := h.usecases.GetObject(r.Context(), req.user.ID, bucket, req.key)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	setObjectHeaders(w, object)
+	// ServeContent answers Range and conditional requests using the ETag and Last-Modified headers, only the
+	// requested range is fetched from the file service
+	http.ServeContent(w, r, path.Base(req.key), object.LastModified, content)
+}
+
+func (h *S3Handler) headObject(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	object, err := h.usecases.HeadObject(r.Context(), req.user.ID, bucket, req.key)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	setObjectHeaders(w, object)
+	w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *S3Handler) putObject(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	data, err := h.readPayload(w, r, req)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	object, err := h.usecases.PutObject(r.Context(), req.user.ID, bucket, req.key, contentType, data)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", quote(object.ETag))
+	w.WriteHeader(http.StatusOK)
+}
+
+// copyObject reads the source through the usecases, so the source bucket is checked like any other bucket
+func (h *S3Handler) copyObject(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket,
+	copySource string) {
+	source, err := url.PathUnescape(copySource)
+	if err != nil {
+		sendError(w, r, errInvalidCopySource)
+		return
+	}
+	sourceBucket, sourceKey, ok := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	if !ok || sourceKey == "" {
+		sendError(w, r, errInvalidCopySource)
+		return
+	}
+
+	fromBucket, err := h.usecases.GetBucket(r.Context(), req.user.ID, sourceBucket)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	sourceObject, content, err := h.usecases.GetObject(r.Context(), req.user.ID, fromBucket, sourceKey)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+	// The copy is uploaded as a whole like any other object, so it is limited the same way
+	if h.maxObjectSize > 0 && sourceObject.Size > h.maxObjectSize {
+		sendErr(w, r, models.ObjectTooLargeError)
+		return
+	}
+
+	var reader io.Reader = content
+	if h.maxObjectSize > 0 {
+		reader = io.LimitReader(content, h.maxObjectSize+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+	// The source may have been replaced by a larger one after it was opened
+	if h.maxObjectSize > 0 && int64(len(data)) > h.maxObjectSize {
+		sendErr(w, r, models.ObjectTooLargeError)
+		return
+	}
+
+	object, err := h.usecases.PutObject(r.Context(), req.user.ID, bucket, req.key, sourceObject.ContentType, data)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	sendXML(w, http.StatusOK, copyObjectResponse{
+		Xmlns:        xmlns,
+		LastModified: formatTime(object.LastModified),
+		ETag:         quote(object.ETag),
+	})
+}
+
+func (h *S3Handler) deleteObject(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	if err := h.usecases.DeleteObject(r.Context(), req.user.ID, bucket, req.key); err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *S3Handler) deleteObjects(w http.ResponseWriter, r *http.Request, req *s3Request, bucket *models.Bucket) {
+	var body deleteObjectsRequest
+	if err := h.readXML(w, r, req, &body); err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	resp := deleteObjectsResponse{Xmlns: xmlns}
+	for _, object := range body.Objects {
+		err := h.usecases.DeleteObject(r.Context(), req.user.ID, bucket, object.Key)
+		if err != nil {
+			s3Err := convertError(err)
+			resp.Errors = append(resp.Errors, deleteErrorEntry{Key: object.Key, Code: s3Err.Code,
+				Message: s3Err.Message})
+
+			continue
+		}
+
+		if !body.Quiet {
+			resp.Deleted = append(resp.Deleted, deletedEntry{Key: object.Key})
+		}
+	}
+
+	sendXML(w, http.StatusOK, resp)
+}
+
+func (h *S3Handler) createMultipartUpload(w http.ResponseWriter, r *http.Request, req *s3Request,
+	bucket *models.Bucket) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	upload, err := h.usecases.CreateMultipartUpload(r.Context(), req.user.ID, bucket, req.key, contentType)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	sendXML(w, http.StatusOK, initiateMultipartUploadResponse{
+		Xmlns:    xmlns,
+		Bucket:   bucket.Name,
+		Key:      req.key,
+		UploadID: upload.ID,
+	})
+}
+
+func (h *S3Handler) uploadPart(w http.ResponseWriter, r *http.Request, req *s3Request, uploadID string) {
+	number, err := strconv.Atoi(req.query.Get("partNumber"))
+	if err != nil {
+		sendErr(w, r, models.InvalidInputError)
+		return
+	}
+
+	data, err := h.readPayload(w, r, req)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	etag, err := h.usecases.UploadPart(r.Context(), req.user.ID, uploadID, number, data)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", quote(etag))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *S3Handler) completeMultipartUpload(w http.ResponseWriter, r *http.Request, req *s3Request,
+	uploadID string) {
+	var body completeMultipartUploadRequest
+	if err := h.readXML(w, r, req, &body); err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	parts := make([]models.CompletedPart, len(body.Parts))
+	for i, part := range body.Parts {
+		parts[i] = models.CompletedPart{Number: part.PartNumber, ETag: part.ETag}
+	}
+
+	object, err := h.usecases.CompleteMultipartUpload(r.Context(), req.user.ID, uploadID, parts)
+	if err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	sendXML(w, http.StatusOK, completeMultipartUploadResponse{
+		Xmlns:  xmlns,
+		Bucket: req.bucket,
+		Key:    object.Key,
+		ETag:   quote(object.ETag),
+	})
+}
+
+func (h *S3Handler) abortMultipartUpload(w http.ResponseWriter, r *http.Request, req *s3Request,
+	uploadID string) {
+	if err := h.usecases.AbortMultipartUpload(r.Context(), req.user.ID, uploadID); err != nil {
+		sendErr(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readPayload reads the verified body of an upload. The limit leaves room for the framing of aws-chunked bodies.
+func (h *S3Handler) readPayload(w http.ResponseWriter, r *http.Request, req *s3Request) ([]byte, error) {
+	body := r.Body
+	if h.maxObjectSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, h.maxObjectSize+h.maxObjectSize/16+maxXMLBodySize)
+	}
+
+	data, err := req.sig.ReadPayload(body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, models.ObjectTooLargeError
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (h *S3Handler) readXML(w http.ResponseWriter, r *http.Request, req *s3Request, v any) error {
+	data, err := req.sig.ReadPayload(http.MaxBytesReader(w, r.Body, maxXMLBodySize))
+	if err != nil {
+		return err
+	}
+
+	if err := xml.Unmarshal(data, v); err != nil {
+		return models.MalformedXMLError
+	}
+
+	return nil
+}
+
+func listOptions(query url.Values) (models.ListObjectsOptions, error) {
+	options := models.ListObjectsOptions{
+		Prefix:    query.Get("prefix"),
+		Delimiter: query.Get("delimiter"),
+		MaxKeys:   s3uc.MaxListKeys,
+	}
+
+	if rawMaxKeys := query.Get("max-keys"); rawMaxKeys != "" {
+		maxKeys, err := strconv.Atoi(rawMaxKeys)
+		if err != nil || maxKeys < 0 {
+			return options, models.InvalidInputError
+		}
+		options.MaxKeys = min(maxKeys, s3uc.MaxListKeys)
+	}
+
+	return options, nil
+}
+
+// encoder returns the function applied to keys in listings, clients ask for URL encoding to get keys with
+// characters that XML cannot carry
+func encoder(query url.Values) func(string) string {
+	if query.Get("encoding-type") == "url" {
+		return func(s string) string {
+			return strings.ReplaceAll(url.PathEscape(s), "%2F", "/")
+		}
+	}
+
+	return func(s string) string {
+		return s
+	}
+}
+
+func convertList(list *models.ObjectsList, encode func(string) string) ([]objectEntry, []commonPrefix) {
+	contents := make([]objectEntry, len(list.Objects))
+	for i, object := range list.Objects {
+		contents[i] = objectEntry{
+			Key:          encode(object.Key),
+			LastModified: formatTime(object.LastModified),
+			ETag:         quote(object.ETag),
+			Size:         object.Size,
+			StorageClass: storageClass,
+		}
+	}
+
+	prefixes := make([]commonPrefix, len(list.CommonPrefixes))
+	for i, prefix := range list.CommonPrefixes {
+		prefixes[i] = commonPrefix{Prefix: encode(prefix)}
+	}
+
+	return contents, prefixes
+}
+
+func setObjectHeaders(w http.ResponseWriter, object *models.Object) {
+	w.Header().Set("ETag", quote(object.ETag))
+	w.Header().Set("Last-Modified", object.LastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("Accept-Ranges", "bytes")
+}
+
+// hasSubresource reports whether the query selects an operation on a subresource, e.g. ?acl or ?tagging,
+// which are not supported. Signature and listing parameters are not subresources, response overrides
+// of GetObject are ignored.
+func hasSubresource(query url.Values) bool {
+	for name := range query {
+		if !strings.HasPrefix(name, "X-Amz-") && !strings.HasPrefix(name, "response-") && name != "x-id" &&
+			!listParams[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+var listParams = map[string]bool{
+	"prefix":             true,
+	"delimiter":          true,
+	"marker":             true,
+	"max-keys":           true,
+	"encoding-type":      true,
+	"list-type":          true,
+	"start-after":        true,
+	"continuation-token": true,
+	"fetch-owner":        true,
+}
+
+func quote(etag string) string {
+	return `"` + etag + `"`
+}
@@ -0,0 +1,187 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	s3interfaces "github.com/IlyaChgn/voblako/internal/pkg/s3"
+	"github.com/IlyaChgn/voblako/internal/pkg/s3/sigv4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKeyID  = "VBTESTKEY"
+	testSecret = "secret"
+	testRegion = "us-east-1"
+)
+
+type fakeAuthUsecases struct {
+	authinterface.AuthUsecases
+	user *models.User
+}
+
+func (uc *fakeAuthUsecases) GetAccessKey(_ context.Context, id string) (*models.AccessKey, *models.User, error) {
+	if id != testKeyID {
+		return nil, nil, models.AccessKeyNotExists
+	}
+
+	return &models.AccessKey{ID: id, Secret: testSecret, UserID: uc.user.ID}, uc.user, nil
+}
+
+type fakeS3Usecases struct {
+	s3interfaces.S3Usecases
+	objects map[string][]byte
+}
+
+func (uc *fakeS3Usecases) GetBucket(_ context.Context, _ uint, name string) (*models.Bucket, error) {
+	if name != "home" {
+		return nil, models.BucketNotExists
+	}
+
+	return &models.Bucket{Name: name}, nil
+}
+
+func (uc *fakeS3Usecases) ListObjects(_ context.Context, _ uint, _ *models.Bucket,
+	options models.ListObjectsOptions) (*models.ObjectsList, error) {
+	list := &models.ObjectsList{}
+	for key := range uc.objects {
+		if strings.HasPrefix(key, options.Prefix) {
+			list.Objects = append(list.Objects, &models.Object{Key: key})
+		}
+	}
+
+	return list, nil
+}
+
+func (uc *fakeS3Usecases) GetObject(_ context.Context, _ uint, _ *models.Bucket,
+	key string) (*models.Object, io.ReadSeeker, error) {
+	data, ok := uc.objects[key]
+	if !ok {
+		return nil, nil, models.FileNotExists
+	}
+
+	return &models.Object{Key: key, Size: int64(len(data)), ETag: "etag"}, bytes.NewReader(data), nil
+}
+
+func (uc *fakeS3Usecases) PutObject(_ context.Context, _ uint, _ *models.Bucket, key, _ string,
+	data []byte) (*models.Object, error) {
+	uc.objects[key] = data
+	checksum := md5.Sum(data)
+
+	return &models.Object{Key: key, ETag: hex.EncodeToString(checksum[:])}, nil
+}
+
+func newTestHandler(verified bool) (*S3Handler, *fakeS3Usecases) {
+	uc := &fakeS3Usecases{objects: make(map[string][]byte)}
+	auth := &fakeAuthUsecases{user: &models.User{ID: 1, Verified: verified}}
+
+	return NewS3Handler(auth, uc, testRegion, 1024, false), uc
+}
+
+func serve(h http.Handler, method, target, body, keyID string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "http://localhost:9100"+target, strings.NewReader(body))
+	sigv4.Sign(r, keyID, testSecret, testRegion, sigv4.UnsignedPayload, time.Now())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestS3Handler_PutAndGetObject(t *testing.T) {
+	h, uc := newTestHandler(true)
+
+	w := serve(h, http.MethodPut, "/home/docs/a.txt", "hello", testKeyID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"5d41402abc4b2a76b9719d911017c592"`, w.Header().Get("ETag"))
+	assert.Equal(t, []byte("hello"), uc.objects["docs/a.txt"])
+
+	w = serve(h, http.MethodGet, "/home/docs/a.txt", "", testKeyID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+
+	w = serve(h, http.MethodGet, "/home?list-type=2&prefix=docs/", "", testKeyID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<Key>docs/a.txt</Key>")
+
+	w = serve(h, http.MethodGet, "/home/missing.txt", "", testKeyID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>NoSuchKey</Code>")
+}
+
+func TestS3Handler_CopyObject(t *testing.T) {
+	h, uc := newTestHandler(true)
+	uc.objects["a.txt"] = []byte("hello")
+	uc.objects["large.bin"] = make([]byte, 1025)
+
+	copyObject := func(source string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "http://localhost:9100/home/copy", nil)
+		r.Header.Set("X-Amz-Copy-Source", source)
+		sigv4.Sign(r, testKeyID, testSecret, testRegion, sigv4.UnsignedPayload, time.Now())
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	w := copyObject("/home/a.txt")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []byte("hello"), uc.objects["copy"])
+
+	// Sources over the upload limit are rejected before they are read
+	w = copyObject("/home/large.bin")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>EntityTooLarge</Code>")
+	assert.Equal(t, []byte("hello"), uc.objects["copy"])
+}
+
+func TestS3Handler_Errors(t *testing.T) {
+	h, _ := newTestHandler(false)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		keyID  string
+		status int
+		code   string
+	}{
+		{"unknown access key", http.MethodGet, "/", "VBUNKNOWN", http.StatusForbidden, "InvalidAccessKeyId"},
+		{"unknown bucket", http.MethodGet, "/photos", testKeyID, http.StatusNotFound, "NoSuchBucket"},
+		{"unsupported subresource", http.MethodGet, "/home?acl", testKeyID, http.StatusNotImplemented,
+			"NotImplemented"},
+		{"unverified upload", http.MethodPut, "/home/a.txt", testKeyID, http.StatusForbidden, "AccessDenied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h, tt.method, tt.target, "", tt.keyID)
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), "<Code>"+tt.code+"</Code>")
+		})
+	}
+}
+
+func TestS3Handler_TamperedRequest(t *testing.T) {
+	h, _ := newTestHandler(true)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost:9100/home/a.txt", nil)
+	sigv4.Sign(r, testKeyID, testSecret, testRegion, sigv4.UnsignedPayload, time.Now())
+	r.URL.Path = "/home/b.txt"
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "<Code>SignatureDoesNotMatch</Code>")
+}
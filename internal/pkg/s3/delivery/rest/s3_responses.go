@@ -0,0 +1,254 @@
+package rest
+
+import (
+	"encoding/xml"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+)
+
+const (
+	xmlns         = "http://s3.amazonaws.com/doc/2006-03-01/"
+	xmlTimeFormat = "2006-01-02T15:04:05.000Z"
+	storageClass  = "STANDARD"
+)
+
+type s3Error struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+var (
+	errAccessDenied          = s3Error{http.StatusForbidden, "AccessDenied", "Access Denied"}
+	errNotImplemented        = s3Error{http.StatusNotImplemented, "NotImplemented", "The operation is not supported"}
+	errMethodNotAllowed      = s3Error{http.StatusMethodNotAllowed, "MethodNotAllowed", "The method is not allowed"}
+	errInvalidCopySource     = s3Error{http.StatusBadRequest, "InvalidArgument", "Copy source is invalid"}
+	errInternal              = s3Error{http.StatusInternalServerError, "InternalError", "Internal server error"}
+	errUnverified            = s3Error{http.StatusForbidden, "AccessDenied", "Email is not verified"}
+	errIllegalBucketCreation = s3Error{http.StatusForbidden, "AccessDenied", "Buckets cannot be created"}
+)
+
+// convertError maps errors of the usecases and the signature check to S3 error codes
+func convertError(err error) s3Error {
+	switch {
+	case errors.Is(err, models.AccessKeyNotExists):
+		return s3Error{http.StatusForbidden, "InvalidAccessKeyId", err.Error()}
+	case errors.Is(err, models.SignatureMismatchError):
+		return s3Error{http.StatusForbidden, "SignatureDoesNotMatch", err.Error()}
+	case errors.Is(err, models.MalformedSignatureError):
+		return s3Error{http.StatusBadRequest, "AuthorizationHeaderMalformed", err.Error()}
+	case errors.Is(err, models.RequestExpiredError):
+		return s3Error{http.StatusForbidden, "RequestTimeTooSkewed", err.Error()}
+	case errors.Is(err, models.PayloadMismatchError):
+		return s3Error{http.StatusBadRequest, "XAmzContentSHA256Mismatch", err.Error()}
+	case errors.Is(err, models.UserDisabledError), errors.Is(err, models.PermissionDeniedError):
+		return errAccessDenied
+	case errors.Is(err, models.BucketNotExists):
+		return s3Error{http.StatusNotFound, "NoSuchBucket", err.Error()}
+	case errors.Is(err, models.FileNotExists):
+		return s3Error{http.StatusNotFound, "NoSuchKey", err.Error()}
+	case errors.Is(err, models.MultipartUploadNotExists):
+		return s3Error{http.StatusNotFound, "NoSuchUpload", err.Error()}
+	case errors.Is(err, models.InvalidPartError):
+		return s3Error{http.StatusBadRequest, "InvalidPart", err.Error()}
+	case errors.Is(err, models.InvalidPartOrderError):
+		return s3Error{http.StatusBadRequest, "InvalidPartOrder", err.Error()}
+	case errors.Is(err, models.InvalidFilenameError):
+		return s3Error{http.StatusBadRequest, "KeyTooLongError", err.Error()}
+	case errors.Is(err, models.ObjectTooLargeError):
+		return s3Error{http.StatusBadRequest, "EntityTooLarge", err.Error()}
+	case errors.Is(err, models.TooManyUploadsError):
+		return s3Error{http.StatusBadRequest, "TooManyUploads", err.Error()}
+	case errors.Is(err, models.QuotaExceededError):
+		return s3Error{http.StatusForbidden, "QuotaExceeded", err.Error()}
+	case errors.Is(err, models.UpdateConflictError):
+		return s3Error{http.StatusConflict, "OperationAborted", err.Error()}
+	case errors.Is(err, models.MalformedXMLError):
+		return s3Error{http.StatusBadRequest, "MalformedXML", err.Error()}
+	case errors.Is(err, models.InvalidInputError):
+		return s3Error{http.StatusBadRequest, "InvalidArgument", err.Error()}
+	}
+
+	return errInternal
+}
+
+type errorResponse struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+func sendError(w http.ResponseWriter, r *http.Request, s3Err s3Error) {
+	requestID := w.Header().Get(responses.RequestIDHeader)
+	w.Header().Set("x-amz-request-id", requestID)
+
+	// Responses to HEAD requests have no body, so clients only see the status
+	if r.Method == http.MethodHead {
+		w.WriteHeader(s3Err.Status)
+
+		return
+	}
+
+	sendXML(w, s3Err.Status, errorResponse{
+		Code:      s3Err.Code,
+		Message:   s3Err.Message,
+		Resource:  r.URL.Path,
+		RequestID: requestID,
+	})
+}
+
+func sendErr(w http.ResponseWriter, r *http.Request, err error) {
+	s3Err := convertError(err)
+	if s3Err.Status == http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "Internal error", "error", err)
+	}
+
+	sendError(w, r, s3Err)
+}
+
+func sendXML(w http.ResponseWriter, status int, body any) {
+	data, err := xml.Marshal(body)
+	if err != nil {
+		slog.Error("Cannot marshal S3 response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	w.Write([]byte(xml.Header))
+	w.Write(data)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(xmlTimeFormat)
+}
+
+type owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type bucketEntry struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+type listBucketsResponse struct {
+	XMLName xml.Name      `xml:"ListAllMyBucketsResult"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Owner   owner         `xml:"Owner"`
+	Buckets []bucketEntry `xml:"Buckets>Bucket"`
+}
+
+type locationResponse struct {
+	XMLName  xml.Name `xml:"LocationConstraint"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:",chardata"`
+}
+
+type objectEntry struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type listObjectsV2Response struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Xmlns                 string         `xml:"xmlns,attr"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	StartAfter            string         `xml:"StartAfter,omitempty"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	EncodingType          string         `xml:"EncodingType,omitempty"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	KeyCount              int            `xml:"KeyCount"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	Contents              []objectEntry  `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type listObjectsV1Response struct {
+	XMLName        xml.Name       `xml:"ListBucketResult"`
+	Xmlns          string         `xml:"xmlns,attr"`
+	Name           string         `xml:"Name"`
+	Prefix         string         `xml:"Prefix"`
+	Marker         string         `xml:"Marker"`
+	NextMarker     string         `xml:"NextMarker,omitempty"`
+	Delimiter      string         `xml:"Delimiter,omitempty"`
+	EncodingType   string         `xml:"EncodingType,omitempty"`
+	MaxKeys        int            `xml:"MaxKeys"`
+	IsTruncated    bool           `xml:"IsTruncated"`
+	Contents       []objectEntry  `xml:"Contents"`
+	CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type copyObjectResponse struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	LastModified string   `xml:"LastModified"`
+	ETag         string   `xml:"ETag"`
+}
+
+type initiateMultipartUploadResponse struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type completeMultipartUploadRequest struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResponse struct {
+	XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Bucket  string   `xml:"Bucket"`
+	Key     string   `xml:"Key"`
+	ETag    string   `xml:"ETag"`
+}
+
+type deleteObjectsRequest struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type deletedEntry struct {
+	Key string `xml:"Key"`
+}
+
+type deleteErrorEntry struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type deleteObjectsResponse struct {
+	XMLName xml.Name           `xml:"DeleteResult"`
+	Xmlns   string             `xml:"xmlns,attr"`
+	Deleted []deletedEntry     `xml:"Deleted"`
+	Errors  []deleteErrorEntry `xml:"Error"`
+}
@@ -0,0 +1,44 @@
+package s3
+
+import (
+	"context"
+	"io"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+)
+
+// MultipartStorage keeps uploads in progress and the sizes of their parts, the parts themselves are staged in
+// the file service
+type MultipartStorage interface {
+	// CreateUpload returns models.TooManyUploadsError if the user already has maxOpen uploads in progress,
+	// zero maxOpen disables the check
+	CreateUpload(ctx context.Context, upload *models.MultipartUpload, maxOpen int) error
+	// GetUpload returns models.MultipartUploadNotExists for unknown, completed and expired uploads
+	GetUpload(ctx context.Context, id string) (*models.MultipartUpload, error)
+	// ReservePart returns models.ObjectTooLargeError if the parts of the upload would exceed the limit,
+	// negative limit disables the check
+	ReservePart(ctx context.Context, id string, number int, size, limit int64) error
+	DeleteUpload(ctx context.Context, upload *models.MultipartUpload) error
+}
+
+// S3Usecases serves the spaces of the user as buckets, object keys are filenames
+type S3Usecases interface {
+	ListBuckets(ctx context.Context, userID uint) ([]*models.Bucket, error)
+	GetBucket(ctx context.Context, userID uint, name string) (*models.Bucket, error)
+
+	ListObjects(ctx context.Context, userID uint, bucket *models.Bucket,
+		options models.ListObjectsOptions) (*models.ObjectsList, error)
+	HeadObject(ctx context.Context, userID uint, bucket *models.Bucket, key string) (*models.Object, error)
+	GetObject(ctx context.Context, userID uint, bucket *models.Bucket, key string) (*models.Object, io.ReadSeeker, error)
+	PutObject(ctx context.Context, userID uint, bucket *models.Bucket, key, contentType string,
+		data []byte) (*models.Object, error)
+	DeleteObject(ctx context.Context, userID uint, bucket *models.Bucket, key string) error
+
+	CreateMultipartUpload(ctx context.Context, userID uint, bucket *models.Bucket,
+		key, contentType string) (*models.MultipartUpload, error)
+	// UploadPart returns the ETag of the part
+	UploadPart(ctx context.Context, userID uint, uploadID string, number int, data []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, userID uint, uploadID string,
+		parts []models.CompletedPart) (*models.Object, error)
+	AbortMultipartUpload(ctx context.Context, userID uint, uploadID string) error
+}
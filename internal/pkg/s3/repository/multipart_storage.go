@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	s3interfaces "github.com/IlyaChgn/voblako/internal/pkg/s3"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	multipartKeyPrefix   = "s3:upload:"
+	userUploadsKeyPrefix = "s3:uploads:"
+)
+
+// createUploadScript drops expired uploads from the set of the user and adds the new one only if the user has
+// fewer than ARGV[3] uploads in progress. Zero limit disables the check. It returns 0 if the upload is rejected.
+var createUploadScript = redis.NewScript(`
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+	local limit = tonumber(ARGV[3])
+	if limit > 0 and redis.call('ZCARD', KEYS[2]) >= limit then
+		return 0
+	end
+
+	redis.call('SET', KEYS[1], ARGV[5], 'PX', ARGV[6])
+	redis.call('ZADD', KEYS[2], ARGV[2], ARGV[4])
+	redis.call('PEXPIRE', KEYS[2], ARGV[6])
+	return 1
+`)
+
+// reservePartScript records the size of the part if the sizes of all parts of the upload fit into ARGV[3],
+// a part with the same number is replaced. Negative limit disables the check. It returns -1 if the upload does
+// not exist and 0 if the part is rejected.
+var reservePartScript = redis.NewScript(`
+	local ttl = redis.call('PTTL', KEYS[1])
+	if ttl < 0 then
+		return -1
+	end
+
+	local total = tonumber(ARGV[2])
+	local sizes = redis.call('HGETALL', KEYS[2])
+	for i = 1, #sizes, 2 do
+		if sizes[i] ~= ARGV[1] then
+			total = total + tonumber(sizes[i + 1])
+		end
+	end
+
+	local limit = tonumber(ARGV[3])
+	if limit >= 0 and total > limit then
+		return 0
+	end
+
+	redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+	redis.call('PEXPIRE', KEYS[2], ttl)
+	return 1
+`)
+
+type multipartStorage struct {
+	client    *redis.Client
+	uploadTTL time.Duration
+
+	now func() time.Time
+}
+
+func NewMultipartStorage(client *redis.Client, uploadTTL time.Duration) s3interfaces.MultipartStorage {
+	return &multipartStorage{
+		client:    client,
+		uploadTTL: uploadTTL,
+		now:       time.Now,
+	}
+}
+
+func uploadKey(id string) string {
+	return multipartKeyPrefix + id
+}
+
+func partsKey(id string) string {
+	return multipartKeyPrefix + id + ":parts"
+}
+
+func userUploadsKey(userID uint) string {
+	return userUploadsKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func (s *multipartStorage) CreateUpload(ctx context.Context, upload *models.MultipartUpload, maxOpen int) error {
+	rawData, err := json.Marshal(upload)
+	if err != nil {
+		return models.MarshallingSessionError
+	}
+
+	// Uploads of the user are scored by the time they expire, so the set forgets them together with Redis
+	now := s.now()
+	created, err := createUploadScript.Run(ctx, s.client,
+		[]string{uploadKey(upload.ID), userUploadsKey(upload.UserID)}, now.UnixMilli(),
+		now.Add(s.uploadTTL).UnixMilli(), maxOpen, upload.ID, rawData, s.uploadTTL.Milliseconds()).Int64()
+	if err != nil {
+		return models.AddToRedisError
+	}
+	if created == 0 {
+		return models.TooManyUploadsError
+	}
+
+	return nil
+}
+
+func (s *multipartStorage) GetUpload(ctx context.Context, id string) (*models.MultipartUpload, error) {
+	rawData, err := s.client.Get(ctx, uploadKey(id)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, models.MultipartUploadNotExists
+		}
+
+		return nil, err
+	}
+
+	var upload models.MultipartUpload
+	if err := json.Unmarshal([]byte(rawData), &upload); err != nil {
+		return nil, models.MultipartUploadNotExists
+	}
+
+	return &upload, nil
+}
+
+// ReservePart records the size of the part, sizes expire together with the upload
+func (s *multipartStorage) ReservePart(ctx context.Context, id string, number int, size, limit int64) error {
+	reserved, err := reservePartScript.Run(ctx, s.client, []string{uploadKey(id), partsKey(id)},
+		number, size, limit).Int64()
+	if err != nil {
+		return models.AddToRedisError
+	}
+
+	switch reserved {
+	case -1:
+		return models.MultipartUploadNotExists
+	case 0:
+		return models.ObjectTooLargeError
+	}
+
+	return nil
+}
+
+func (s *multipartStorage) DeleteUpload(ctx context.Context, upload *models.MultipartUpload) error {
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, uploadKey(upload.ID), partsKey(upload.ID))
+	pipe.ZRem(ctx, userUploadsKey(upload.UserID), upload.ID)
+
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
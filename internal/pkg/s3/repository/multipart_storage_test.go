@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+const uploadTTL = time.Hour
+
+func TestMultipartStorage_GetUpload(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	storage := NewMultipartStorage(client, uploadTTL)
+
+	upload := &models.MultipartUpload{ID: "upload", UserID: 1, Bucket: "home", Key: "a.txt"}
+	rawUpload, _ := json.Marshal(upload)
+	mock.ExpectGet(uploadKey("upload")).SetVal(string(rawUpload))
+	mock.ExpectGet(uploadKey("missing")).SetErr(redis.Nil)
+
+	result, err := storage.GetUpload(context.Background(), "upload")
+	assert.NoError(t, err)
+	assert.Equal(t, upload, result)
+
+	_, err = storage.GetUpload(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.MultipartUploadNotExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMultipartStorage_CreateUpload(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	storage := NewMultipartStorage(client, uploadTTL).(*multipartStorage)
+	now := time.Unix(1700000000, 0)
+	storage.now = func() time.Time { return now }
+
+	upload := &models.MultipartUpload{ID: "upload", UserID: 1, Bucket: "home", Key: "a.txt"}
+	rawUpload, _ := json.Marshal(upload)
+	expectCreate := func(created int64) {
+		mock.ExpectEvalSha(createUploadScript.Hash(), []string{uploadKey("upload"), userUploadsKey(1)},
+			now.UnixMilli(), now.Add(uploadTTL).UnixMilli(), 2, "upload", rawUpload,
+			uploadTTL.Milliseconds()).SetVal(created)
+	}
+
+	expectCreate(1)
+	assert.NoError(t, storage.CreateUpload(context.Background(), upload, 2))
+
+	expectCreate(0)
+	assert.ErrorIs(t, storage.CreateUpload(context.Background(), upload, 2), models.TooManyUploadsError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMultipartStorage_ReservePart(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	storage := NewMultipartStorage(client, uploadTTL)
+
+	keys := []string{uploadKey("upload"), partsKey("upload")}
+	mock.ExpectEvalSha(reservePartScript.Hash(), keys, 2, int64(5), int64(10)).SetVal(int64(1))
+	mock.ExpectEvalSha(reservePartScript.Hash(), keys, 3, int64(6), int64(10)).SetVal(int64(0))
+	mock.ExpectEvalSha(reservePartScript.Hash(), keys, 1, int64(5), int64(-1)).SetVal(int64(-1))
+
+	assert.NoError(t, storage.ReservePart(context.Background(), "upload", 2, 5, 10))
+	assert.ErrorIs(t, storage.ReservePart(context.Background(), "upload", 3, 6, 10), models.ObjectTooLargeError)
+	assert.ErrorIs(t, storage.ReservePart(context.Background(), "upload", 1, 5, -1),
+		models.MultipartUploadNotExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMultipartStorage_DeleteUpload(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	storage := NewMultipartStorage(client, uploadTTL)
+
+	mock.ExpectTxPipeline()
+	mock.ExpectDel(uploadKey("upload"), partsKey("upload")).SetVal(2)
+	mock.ExpectZRem(userUploadsKey(1), "upload").SetVal(1)
+	mock.ExpectTxPipelineExec()
+
+	upload := &models.MultipartUpload{ID: "upload", UserID: 1}
+	assert.NoError(t, storage.DeleteUpload(context.Background(), upload))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,369 @@
+package sigv4
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+)
+
+const (
+	algorithm        = "AWS4-HMAC-SHA256"
+	chunkAlgorithm   = "AWS4-HMAC-SHA256-PAYLOAD"
+	scopeTerminator  = "aws4_request"
+	service          = "s3"
+	timeFormat       = "20060102T150405Z"
+	dateFormat       = "20060102"
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	UnsignedPayload        = "UNSIGNED-PAYLOAD"
+	StreamingPayload       = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	StreamingUnsignedTrail = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+	MaxClockSkew  = 15 * time.Minute
+	maxPresignTTL = 7 * 24 * time.Hour
+)
+
+// Signature is the parsed signature of the request. After Verify it also reads the payload, which is
+// signed separately when it is streamed in chunks.
+type Signature struct {
+	AccessKeyID string
+
+	time          time.Time
+	scope         string
+	region        string
+	signedHeaders []string
+	signature     string
+	payloadHash   string
+	presigned     bool
+
+	signingKey []byte
+}
+
+// Parse reads the signature from the Authorization header or from the query of a presigned URL
+func Parse(r *http.Request, now time.Time) (*Signature, error) {
+	if r.URL.Query().Get("X-Amz-Algorithm") != "" {
+		return parsePresigned(r, now)
+	}
+
+	auth := r.Header.Get("Authorization")
+	rest, ok := strings.CutPrefix(auth, algorithm+" ")
+	if !ok {
+		return nil, models.MalformedSignatureError
+	}
+
+	fields := make(map[string]string)
+	for _, field := range strings.Split(rest, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, models.MalformedSignatureError
+		}
+		fields[name] = value
+	}
+
+	sig := &Signature{
+		signedHeaders: strings.Split(fields["SignedHeaders"], ";"),
+		signature:     fields["Signature"],
+		payloadHash:   r.Header.Get("X-Amz-Content-Sha256"),
+	}
+	if err := sig.parseCredential(fields["Credential"], r.Header.Get("X-Amz-Date")); err != nil {
+		return nil, err
+	}
+	if sig.payloadHash == "" {
+		return nil, models.MalformedSignatureError
+	}
+
+	if skew := now.Sub(sig.time); skew > MaxClockSkew || skew < -MaxClockSkew {
+		return nil, models.RequestExpiredError
+	}
+
+	return sig, nil
+}
+
+func parsePresigned(r *http.Request, now time.Time) (*Signature, error) {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != algorithm {
+		return nil, models.MalformedSignatureError
+	}
+
+	sig := &Signature{
+		signedHeaders: strings.Split(query.Get("X-Amz-SignedHeaders"), ";"),
+		signature:     query.Get("X-Amz-Signature"),
+		payloadHash:   UnsignedPayload,
+		presigned:     true,
+	}
+	if err := sig.parseCredential(query.Get("X-Amz-Credential"), query.Get("X-Amz-Date")); err != nil {
+		return nil, err
+	}
+
+	expires, err := strconv.Atoi(query.Get("X-Amz-Expires"))
+	if err != nil || expires < 0 || time.Duration(expires)*time.Second > maxPresignTTL {
+		return nil, models.MalformedSignatureError
+	}
+	if now.Before(sig.time.Add(-MaxClockSkew)) || now.After(sig.time.Add(time.Duration(expires)*time.Second)) {
+		return nil, models.RequestExpiredError
+	}
+
+	return sig, nil
+}
+
+// parseCredential reads "<access key>/<date>/<region>/s3/aws4_request"
+func (sig *Signature) parseCredential(credential, date string) error {
+	parts := strings.Split(credential, "/")
+	if len(parts) != 5 || parts[0] == "" || parts[3] != service || parts[4] != scopeTerminator {
+		return models.MalformedSignatureError
+	}
+
+	t, err := time.Parse(timeFormat, date)
+	if err != nil || t.Format(dateFormat) != parts[1] {
+		return models.MalformedSignatureError
+	}
+	// Without the signed host a request signed for another endpoint could be replayed here
+	if sig.signature == "" || !slices.Contains(sig.signedHeaders, "host") {
+		return models.MalformedSignatureError
+	}
+
+	sig.AccessKeyID = parts[0]
+	sig.region = parts[2]
+	sig.scope = strings.Join(parts[1:], "/")
+	sig.time = t
+
+	return nil
+}
+
+// Sign signs the request with the Authorization header. Host and x-amz-* headers are signed, the payload
+// hash is sent as is, e.g. UnsignedPayload.
+func Sign(r *http.Request, accessKeyID, secret, region, payloadHash string, t time.Time) {
+	t = t.UTC()
+	r.Header.Set("X-Amz-Date", t.Format(timeFormat))
+	r.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host"}
+	for name := range r.Header {
+		if name = strings.ToLower(name); strings.HasPrefix(name, "x-amz-") {
+			signedHeaders = append(signedHeaders, name)
+		}
+	}
+	sort.Strings(signedHeaders)
+
+	sig := &Signature{
+		AccessKeyID:   accessKeyID,
+		time:          t,
+		scope:         strings.Join([]string{t.Format(dateFormat), region, service, scopeTerminator}, "/"),
+		region:        region,
+		signedHeaders: signedHeaders,
+		payloadHash:   payloadHash,
+		signingKey:    signingKey(secret, t.Format(dateFormat), region),
+	}
+	signature := hex.EncodeToString(hmacSHA256(sig.signingKey, sig.stringToSign(r)))
+
+	r.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", algorithm,
+		accessKeyID, sig.scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// Verify checks the signature with the secret of the access key
+func (sig *Signature) Verify(r *http.Request, secret string) error {
+	sig.signingKey = signingKey(secret, sig.time.Format(dateFormat), sig.region)
+
+	expected := hex.EncodeToString(hmacSHA256(sig.signingKey, sig.stringToSign(r)))
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return models.SignatureMismatchError
+	}
+
+	return nil
+}
+
+// ReadPayload reads the body of the verified request and checks it against the signed hash
+func (sig *Signature) ReadPayload(body io.Reader) ([]byte, error) {
+	switch sig.payloadHash {
+	case UnsignedPayload:
+		return io.ReadAll(body)
+	case StreamingPayload:
+		return sig.readChunks(bufio.NewReader(body), true)
+	case StreamingUnsignedTrail:
+		return sig.readChunks(bufio.NewReader(body), false)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	if hex.EncodeToString(hash[:]) != strings.ToLower(sig.payloadHash) {
+		return nil, models.PayloadMismatchError
+	}
+
+	return data, nil
+}
+
+// readChunks decodes the aws-chunked body. Every chunk is "<hex size>[;chunk-signature=<sig>]\r\n<data>\r\n",
+// the last one is empty and may be followed by trailing headers, which are skipped. Chunks are copied instead
+// of allocated by the declared size, so the size of the body is bounded by the reader.
+func (sig *Signature) readChunks(body *bufio.Reader, signed bool) ([]byte, error) {
+	var (
+		data          bytes.Buffer
+		prevSignature = sig.signature
+	)
+
+	for {
+		header, err := body.ReadString('\n')
+		if err != nil {
+			return nil, chunkError(err)
+		}
+
+		sizeField, extension, _ := strings.Cut(strings.TrimRight(header, "\r\n"), ";")
+		size, err := strconv.ParseInt(sizeField, 16, 64)
+		if err != nil || size < 0 {
+			return nil, models.PayloadMismatchError
+		}
+
+		chunkStart := data.Len()
+		if _, err := io.CopyN(&data, body, size); err != nil {
+			return nil, chunkError(err)
+		}
+
+		if signed {
+			chunkSignature, _ := strings.CutPrefix(extension, "chunk-signature=")
+			chunkHash := sha256.Sum256(data.Bytes()[chunkStart:])
+			stringToSign := strings.Join([]string{
+				chunkAlgorithm, sig.time.Format(timeFormat), sig.scope, prevSignature, emptyPayloadHash,
+				hex.EncodeToString(chunkHash[:]),
+			}, "\n")
+
+			expected := hex.EncodeToString(hmacSHA256(sig.signingKey, stringToSign))
+			if !hmac.Equal([]byte(expected), []byte(chunkSignature)) {
+				return nil, models.SignatureMismatchError
+			}
+			prevSignature = chunkSignature
+		}
+
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+
+		if _, err := body.Discard(2); err != nil {
+			return nil, chunkError(err)
+		}
+	}
+}
+
+// chunkError reports a truncated body as a payload mismatch and keeps other errors of the reader
+func chunkError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return models.PayloadMismatchError
+	}
+
+	return err
+}
+
+func (sig *Signature) stringToSign(r *http.Request) string {
+	canonicalHash := sha256.Sum256([]byte(sig.canonicalRequest(r)))
+
+	return strings.Join([]string{
+		algorithm, sig.time.Format(timeFormat), sig.scope, hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+}
+
+func (sig *Signature) canonicalRequest(r *http.Request) string {
+	var headers strings.Builder
+	for _, name := range sig.signedHeaders {
+		fmt.Fprintf(&headers, "%s:%s\n", name, headerValue(r, name))
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		escape(r.URL.Path, false),
+		sig.canonicalQuery(r.URL.Query()),
+		headers.String(),
+		strings.Join(sig.signedHeaders, ";"),
+		sig.payloadHash,
+	}, "\n")
+}
+
+func (sig *Signature) canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if sig.presigned && key == "X-Amz-Signature" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, escape(key, true)+"="+escape(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// headerValue returns the value as it was signed, Go moves some headers out of the header map
+func headerValue(r *http.Request, name string) string {
+	var values []string
+	switch name {
+	case "host":
+		values = []string{r.Host}
+	case "content-length":
+		values = []string{strconv.FormatInt(r.ContentLength, 10)}
+	case "transfer-encoding":
+		values = r.TransferEncoding
+	default:
+		values = r.Header.Values(name)
+	}
+
+	for i, value := range values {
+		values[i] = strings.Join(strings.Fields(value), " ")
+	}
+
+	return strings.Join(values, ",")
+}
+
+// escape encodes everything except unreserved characters, slashes are kept in paths
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func signingKey(secret, date, region string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+
+	return hmacSHA256(key, scopeTerminator)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,497 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	s3interfaces "github.com/IlyaChgn/voblako/internal/pkg/s3"
+
+	"github.com/google/uuid"
+)
+
+const (
+	HomeBucket      = "home"
+	orgBucketPrefix = "org-"
+
+	MaxListKeys   = 1000
+	listPageSize  = 1000
+	maxPartNumber = 10000
+	maxKeyLength  = 50
+)
+
+type s3Usecases struct {
+	fileUsecases     fileinterfaces.FileUsecases
+	orgUsecases      authinterface.OrgUsecases
+	multipartStorage s3interfaces.MultipartStorage
+	maxObjectSize    int64
+	maxOpenUploads   int
+}
+
+func NewS3Usecases(fileUsecases fileinterfaces.FileUsecases, orgUsecases authinterface.OrgUsecases,
+	multipartStorage s3interfaces.MultipartStorage, maxObjectSize int64, maxOpenUploads int) s3interfaces.S3Usecases {
+	return &s3Usecases{
+		fileUsecases:     fileUsecases,
+		orgUsecases:      orgUsecases,
+		multipartStorage: multipartStorage,
+		maxObjectSize:    maxObjectSize,
+		maxOpenUploads:   maxOpenUploads,
+	}
+}
+
+func (uc *s3Usecases) ListBuckets(ctx context.Context, userID uint) ([]*models.Bucket, error) {
+	orgs, err := uc.orgUsecases.ListOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := []*models.Bucket{{Name: HomeBucket}}
+	for _, org := range orgs {
+		buckets = append(buckets, &models.Bucket{Name: orgBucketPrefix + strconv.Itoa(int(org.ID)), OrgID: org.ID})
+	}
+
+	return buckets, nil
+}
+
+// GetBucket resolves "home" to the personal space and "org-<id>" to the space of the organization
+func (uc *s3Usecases) GetBucket(ctx context.Context, userID uint, name string) (*models.Bucket, error) {
+	if name == HomeBucket {
+		return &models.Bucket{Name: name}, nil
+	}
+
+	rawID, ok := strings.CutPrefix(name, orgBucketPrefix)
+	if !ok {
+		return nil, models.BucketNotExists
+	}
+	orgID, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil || orgID == 0 || strconv.FormatUint(orgID, 10) != rawID {
+		return nil, models.BucketNotExists
+	}
+
+	_, err = uc.orgUsecases.GetOrganization(ctx, userID, uint(orgID))
+	if err != nil {
+		if errors.Is(err, models.OrgNotExists) || errors.Is(err, models.PermissionDeniedError) {
+			return nil, models.BucketNotExists
+		}
+
+		return nil, err
+	}
+
+	return &models.Bucket{Name: name, OrgID: uint(orgID)}, nil
+}
+
+// ListObjects lists the keys in lexicographic order. Keys with the delimiter after the prefix are rolled up
+// into common prefixes, which count towards MaxKeys like objects.
+func (uc *s3Usecases) ListObjects(ctx context.Context, userID uint, bucket *models.Bucket,
+	options models.ListObjectsOptions) (*models.ObjectsList, error) {
+	if options.MaxKeys < 0 {
+		return nil, models.InvalidInputError
+	}
+	maxKeys := min(options.MaxKeys, MaxListKeys)
+
+	objects, err := uc.loadObjects(ctx, userID, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.ObjectsList{}
+	var lastPrefix string
+	for _, object := range objects {
+		if !strings.HasPrefix(object.Key, options.Prefix) || object.Key <= options.StartAfter {
+			continue
+		}
+
+		commonPrefix := ""
+		if options.Delimiter != "" {
+			rest := object.Key[len(options.Prefix):]
+			if i := strings.Index(rest, options.Delimiter); i >= 0 {
+				commonPrefix = options.Prefix + rest[:i+len(options.Delimiter)]
+				if commonPrefix == lastPrefix || commonPrefix <= options.StartAfter {
+					continue
+				}
+			}
+		}
+
+		if len(list.Objects)+len(list.CommonPrefixes) == maxKeys {
+			list.IsTruncated = true
+
+			break
+		}
+
+		if commonPrefix != "" {
+			list.CommonPrefixes = append(list.CommonPrefixes, commonPrefix)
+			list.NextStartAfter = commonPrefix
+			lastPrefix = commonPrefix
+		} else {
+			list.Objects = append(list.Objects, object)
+			list.NextStartAfter = object.Key
+		}
+	}
+
+	if !list.IsTruncated {
+		list.NextStartAfter = ""
+	}
+
+	return list, nil
+}
+
+func (uc *s3Usecases) HeadObject(ctx context.Context, userID uint, bucket *models.Bucket,
+	key string) (*models.Object, error) {
+	file, err := uc.findFile(ctx, userID, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertObject(file), nil
+}
+
+func (uc *s3Usecases) GetObject(ctx context.Context, userID uint, bucket *models.Bucket,
+	key string) (*models.Object, io.ReadSeeker, error) {
+	file, err := uc.findFile(ctx, userID, bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, content, err := uc.fileUsecases.OpenFile(ctx, userID, file.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return convertObject(file), content, nil
+}
+
+// PutObject replaces the content of the file with the key or uploads a new file. The content type of a
+// replaced file is kept.
+func (uc *s3Usecases) PutObject(ctx context.Context, userID uint, bucket *models.Bucket, key, contentType string,
+	data []byte) (*models.Object, error) {
+	if err := uc.validateKey(key); err != nil {
+		return nil, err
+	}
+	if uc.maxObjectSize > 0 && int64(len(data)) > uc.maxObjectSize {
+		return nil, models.ObjectTooLargeError
+	}
+
+	file, err := uc.findFile(ctx, userID, bucket, key)
+	switch {
+	case errors.Is(err, models.FileNotExists):
+		file, err = uc.fileUsecases.UploadFile(ctx, userID, &models.GeneralFileData{
+			OrgID:       bucket.OrgID,
+			Filename:    key,
+			ContentType: contentType,
+			File:        data,
+			Size:        int64(len(data)),
+		})
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		err = uc.fileUsecases.UpdateFile(ctx, userID, file.UUID, data, int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	checksum := md5.Sum(data)
+	object := convertObject(file)
+	object.Size = int64(len(data))
+	object.ETag = hex.EncodeToString(checksum[:])
+	object.LastModified = time.Now()
+
+	return object, nil
+}
+
+// DeleteObject succeeds for missing keys like S3 does
+func (uc *s3Usecases) DeleteObject(ctx context.Context, userID uint, bucket *models.Bucket, key string) error {
+	file, err := uc.findFile(ctx, userID, bucket, key)
+	if err != nil {
+		if errors.Is(err, models.FileNotExists) {
+			return nil
+		}
+
+		return err
+	}
+
+	return uc.fileUsecases.DeleteFile(ctx, userID, file.UUID)
+}
+
+func (uc *s3Usecases) CreateMultipartUpload(ctx context.Context, userID uint, bucket *models.Bucket,
+	key, contentType string) (*models.MultipartUpload, error) {
+	if err := uc.validateKey(key); err != nil {
+		return nil, err
+	}
+
+	// The file service tells the age of staged parts by the time in the ID
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &models.MultipartUpload{
+		ID:          id.String(),
+		UserID:      userID,
+		Bucket:      bucket.Name,
+		OrgID:       bucket.OrgID,
+		Key:         key,
+		ContentType: contentType,
+		CreateTime:  time.Now(),
+	}
+	if err := uc.multipartStorage.CreateUpload(ctx, upload, uc.maxOpenUploads); err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+func (uc *s3Usecases) UploadPart(ctx context.Context, userID uint, uploadID string, number int,
+	data []byte) (string, error) {
+	if number < 1 || number > maxPartNumber {
+		return "", models.InvalidInputError
+	}
+	if uc.maxObjectSize > 0 && int64(len(data)) > uc.maxObjectSize {
+		return "", models.ObjectTooLargeError
+	}
+
+	upload, err := uc.getUpload(ctx, userID, uploadID)
+	if err != nil {
+		return "", err
+	}
+
+	limit, byQuota, err := uc.uploadLimit(ctx, userID, upload)
+	if err != nil {
+		return "", err
+	}
+
+	// The size is reserved before the part is staged, so concurrent parts cannot exceed the limit together
+	err = uc.multipartStorage.ReservePart(ctx, uploadID, number, int64(len(data)), limit)
+	if err != nil {
+		if errors.Is(err, models.ObjectTooLargeError) && byQuota {
+			return "", models.QuotaExceededError
+		}
+
+		return "", err
+	}
+
+	if err := uc.fileUsecases.PutUploadPart(ctx, userID, uploadID, number, data); err != nil {
+		return "", err
+	}
+
+	checksum := md5.Sum(data)
+
+	return hex.EncodeToString(checksum[:]), nil
+}
+
+// CompleteMultipartUpload stores the listed parts as one object, parts that are not listed are dropped
+func (uc *s3Usecases) CompleteMultipartUpload(ctx context.Context, userID uint, uploadID string,
+	parts []models.CompletedPart) (*models.Object, error) {
+	upload, err := uc.getUpload(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parts) == 0 {
+		return nil, models.InvalidPartError
+	}
+	for i := 1; i < len(parts); i++ {
+		if parts[i].Number <= parts[i-1].Number {
+			return nil, models.InvalidPartOrderError
+		}
+	}
+
+	// Parts are fetched one by one, only the assembled object is held in memory
+	var data bytes.Buffer
+	for _, part := range parts {
+		partData, err := uc.fileUsecases.GetUploadPart(ctx, userID, uploadID, part.Number)
+		if err != nil {
+			if errors.Is(err, models.FileNotExists) || errors.Is(err, models.InvalidInputError) {
+				return nil, models.InvalidPartError
+			}
+
+			return nil, err
+		}
+
+		checksum := md5.Sum(partData)
+		if hex.EncodeToString(checksum[:]) != strings.ToLower(strings.Trim(part.ETag, `"`)) {
+			return nil, models.InvalidPartError
+		}
+		if uc.maxObjectSize > 0 && int64(data.Len()+len(partData)) > uc.maxObjectSize {
+			return nil, models.ObjectTooLargeError
+		}
+		data.Write(partData)
+	}
+
+	object, err := uc.PutObject(ctx, userID, &models.Bucket{Name: upload.Bucket, OrgID: upload.OrgID},
+		upload.Key, upload.ContentType, data.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.deleteUpload(ctx, upload); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (uc *s3Usecases) AbortMultipartUpload(ctx context.Context, userID uint, uploadID string) error {
+	upload, err := uc.getUpload(ctx, userID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	return uc.deleteUpload(ctx, upload)
+}
+
+// deleteUpload removes the staged parts first, so the upload can be aborted again if that fails. Parts left
+// after a crash are removed by the file service once the upload expires.
+func (uc *s3Usecases) deleteUpload(ctx context.Context, upload *models.MultipartUpload) error {
+	if err := uc.fileUsecases.DeleteUploadParts(ctx, upload.UserID, upload.ID); err != nil {
+		return err
+	}
+
+	return uc.multipartStorage.DeleteUpload(ctx, upload)
+}
+
+// uploadLimit returns the total size the parts of the upload may take and whether it is set by the quota.
+// The limit is the smaller of the object size limit and the space left in the quota of the bucket, -1 means
+// that the upload is not limited.
+func (uc *s3Usecases) uploadLimit(ctx context.Context, userID uint,
+	upload *models.MultipartUpload) (int64, bool, error) {
+	var (
+		usage *models.StorageUsage
+		err   error
+	)
+	if upload.OrgID != 0 {
+		usage, err = uc.fileUsecases.GetOrgStorageUsage(ctx, upload.OrgID)
+	} else {
+		usage, err = uc.fileUsecases.GetStorageUsage(ctx, userID)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	limit := int64(-1)
+	if uc.maxObjectSize > 0 {
+		limit = uc.maxObjectSize
+	}
+	if usage.QuotaBytes > 0 {
+		left := max(usage.QuotaBytes-usage.UsedBytes, 0)
+		if limit < 0 || left < limit {
+			return left, true, nil
+		}
+	}
+
+	return limit, false, nil
+}
+
+// getUpload hides uploads of other users
+func (uc *s3Usecases) getUpload(ctx context.Context, userID uint, uploadID string) (*models.MultipartUpload, error) {
+	upload, err := uc.multipartStorage.GetUpload(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.UserID != userID {
+		return nil, models.MultipartUploadNotExists
+	}
+
+	return upload, nil
+}
+
+func (uc *s3Usecases) validateKey(key string) error {
+	if key == "" || utf8.RuneCountInString(key) > maxKeyLength {
+		return models.InvalidFilenameError
+	}
+
+	return nil
+}
+
+// findFile returns the oldest file with the key, files are listed in the order of upload
+func (uc *s3Usecases) findFile(ctx context.Context, userID uint, bucket *models.Bucket,
+	key string) (*models.FileMetadata, error) {
+	files, err := uc.fileUsecases.GetFilesList(ctx, userID, models.FilesListOptions{
+		Limit:    1,
+		OrgID:    bucket.OrgID,
+		Filename: key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, models.FileNotExists
+	}
+
+	return files[0], nil
+}
+
+// loadObjects returns all objects of the bucket sorted by key, only the oldest file is seen for duplicate names
+func (uc *s3Usecases) loadObjects(ctx context.Context, userID uint, bucket *models.Bucket) ([]*models.Object, error) {
+	var (
+		objects []*models.Object
+		seen    = make(map[string]bool)
+	)
+
+	for offset := uint(0); ; offset += listPageSize {
+		files, err := uc.fileUsecases.GetFilesList(ctx, userID, models.FilesListOptions{
+			Limit:  listPageSize,
+			Offset: offset,
+			OrgID:  bucket.OrgID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if !seen[file.Filename] {
+				seen[file.Filename] = true
+				objects = append(objects, convertObject(file))
+			}
+		}
+
+		if len(files) < listPageSize {
+			break
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+
+	return objects, nil
+}
+
+// convertObject uses the content checksum as the ETag. Files stored before checksums were kept get an ETag
+// with a part count suffix, so clients do not take it for the MD5 of the content.
+func convertObject(file *models.FileMetadata) *models.Object {
+	etag := file.Stored.Checksum
+	if etag == "" {
+		checksum := md5.Sum([]byte(fmt.Sprintf("%s:%d", file.UUID, file.UpdateTime.UnixNano())))
+		etag = hex.EncodeToString(checksum[:]) + "-1"
+	}
+
+	lastModified := file.UpdateTime
+	if lastModified.IsZero() {
+		lastModified = file.UploadTime
+	}
+
+	return &models.Object{
+		Key:          file.Filename,
+		UUID:         file.UUID,
+		ContentType:  file.ContentType,
+		Size:         file.Size,
+		ETag:         etag,
+		LastModified: lastModified,
+	}
+}
@@ -0,0 +1,339 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authinterface "github.com/IlyaChgn/voblako/internal/pkg/auth"
+	fileinterfaces "github.com/IlyaChgn/voblako/internal/pkg/file"
+	s3interfaces "github.com/IlyaChgn/voblako/internal/pkg/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFileUsecases struct {
+	fileinterfaces.FileUsecases
+	files map[string]*models.GeneralFileData
+	next  int
+	parts map[string][]byte
+	usage models.StorageUsage
+}
+
+func (uc *fakeFileUsecases) UploadFile(_ context.Context, _ uint,
+	data *models.GeneralFileData) (*models.FileMetadata, error) {
+	uc.next++
+	id := fmt.Sprintf("%08d-0000-0000-0000-000000000000", uc.next)
+	uc.files[id] = data
+
+	return &models.FileMetadata{UUID: id, OrgID: data.OrgID, Filename: data.Filename}, nil
+}
+
+func (uc *fakeFileUsecases) GetFilesList(_ context.Context, _ uint,
+	options models.FilesListOptions) ([]*models.FileMetadata, error) {
+	ids := make([]string, 0, len(uc.files))
+	for id, file := range uc.files {
+		if file.OrgID == options.OrgID && (options.Filename == "" || file.Filename == options.Filename) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var list []*models.FileMetadata
+	for _, id := range ids[min(int(options.Offset), len(ids)):] {
+		file := uc.files[id]
+		checksum := md5.Sum(file.File)
+		list = append(list, &models.FileMetadata{
+			UUID:     id,
+			Filename: file.Filename,
+			Size:     int64(len(file.File)),
+			Stored:   models.StoredContent{Checksum: hex.EncodeToString(checksum[:])},
+		})
+	}
+
+	return list, nil
+}
+
+func (uc *fakeFileUsecases) OpenFile(_ context.Context, _ uint,
+	id string) (*models.GeneralFileData, io.ReadSeeker, error) {
+	return uc.files[id], bytes.NewReader(uc.files[id].File), nil
+}
+
+func (uc *fakeFileUsecases) UpdateFile(_ context.Context, _ uint, id string, file []byte, _ int64) error {
+	uc.files[id].File = file
+
+	return nil
+}
+
+func (uc *fakeFileUsecases) DeleteFile(_ context.Context, _ uint, id string) error {
+	delete(uc.files, id)
+
+	return nil
+}
+
+func (uc *fakeFileUsecases) GetStorageUsage(_ context.Context, _ uint) (*models.StorageUsage, error) {
+	return &uc.usage, nil
+}
+
+func (uc *fakeFileUsecases) GetOrgStorageUsage(_ context.Context, _ uint) (*models.StorageUsage, error) {
+	return &uc.usage, nil
+}
+
+func (uc *fakeFileUsecases) PutUploadPart(_ context.Context, userID uint, uploadID string, number int,
+	data []byte) error {
+	uc.parts[fmt.Sprintf("%d/%s/%d", userID, uploadID, number)] = data
+
+	return nil
+}
+
+func (uc *fakeFileUsecases) GetUploadPart(_ context.Context, userID uint, uploadID string,
+	number int) ([]byte, error) {
+	data, ok := uc.parts[fmt.Sprintf("%d/%s/%d", userID, uploadID, number)]
+	if !ok {
+		return nil, models.FileNotExists
+	}
+
+	return data, nil
+}
+
+func (uc *fakeFileUsecases) DeleteUploadParts(_ context.Context, userID uint, uploadID string) error {
+	for key := range uc.parts {
+		if strings.HasPrefix(key, fmt.Sprintf("%d/%s/", userID, uploadID)) {
+			delete(uc.parts, key)
+		}
+	}
+
+	return nil
+}
+
+type fakeOrgUsecases struct {
+	authinterface.OrgUsecases
+}
+
+func (uc *fakeOrgUsecases) ListOrganizations(_ context.Context, _ uint) ([]*models.Organization, error) {
+	return []*models.Organization{{ID: 7, Name: "team"}}, nil
+}
+
+func (uc *fakeOrgUsecases) GetOrganization(_ context.Context, _, orgID uint) (*models.Organization, error) {
+	if orgID != 7 {
+		return nil, models.PermissionDeniedError
+	}
+
+	return &models.Organization{ID: orgID}, nil
+}
+
+type fakeMultipartStorage struct {
+	s3interfaces.MultipartStorage
+	uploads map[string]*models.MultipartUpload
+	sizes   map[string]map[int]int64
+}
+
+func (s *fakeMultipartStorage) CreateUpload(_ context.Context, upload *models.MultipartUpload, maxOpen int) error {
+	open := 0
+	for _, other := range s.uploads {
+		if other.UserID == upload.UserID {
+			open++
+		}
+	}
+	if maxOpen > 0 && open >= maxOpen {
+		return models.TooManyUploadsError
+	}
+
+	s.uploads[upload.ID] = upload
+	s.sizes[upload.ID] = make(map[int]int64)
+
+	return nil
+}
+
+func (s *fakeMultipartStorage) GetUpload(_ context.Context, id string) (*models.MultipartUpload, error) {
+	upload, ok := s.uploads[id]
+	if !ok {
+		return nil, models.MultipartUploadNotExists
+	}
+
+	return upload, nil
+}
+
+func (s *fakeMultipartStorage) ReservePart(_ context.Context, id string, number int, size, limit int64) error {
+	total := size
+	for other, otherSize := range s.sizes[id] {
+		if other != number {
+			total += otherSize
+		}
+	}
+	if limit >= 0 && total > limit {
+		return models.ObjectTooLargeError
+	}
+
+	s.sizes[id][number] = size
+
+	return nil
+}
+
+func (s *fakeMultipartStorage) DeleteUpload(_ context.Context, upload *models.MultipartUpload) error {
+	delete(s.uploads, upload.ID)
+	delete(s.sizes, upload.ID)
+
+	return nil
+}
+
+func newTestUsecases() (*s3Usecases, *fakeFileUsecases) {
+	files := &fakeFileUsecases{
+		files: make(map[string]*models.GeneralFileData),
+		parts: make(map[string][]byte),
+	}
+	multipart := &fakeMultipartStorage{
+		uploads: make(map[string]*models.MultipartUpload),
+		sizes:   make(map[string]map[int]int64),
+	}
+
+	return NewS3Usecases(files, &fakeOrgUsecases{}, multipart, 1024, 2).(*s3Usecases), files
+}
+
+func TestS3Usecases_GetBucket(t *testing.T) {
+	uc, _ := newTestUsecases()
+
+	bucket, err := uc.GetBucket(context.Background(), 1, "org-7")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), bucket.OrgID)
+
+	for _, name := range []string{"org-8", "org-07", "photos"} {
+		_, err = uc.GetBucket(context.Background(), 1, name)
+		assert.ErrorIs(t, err, models.BucketNotExists, name)
+	}
+}
+
+func TestS3Usecases_ListObjects(t *testing.T) {
+	uc, _ := newTestUsecases()
+	bucket := &models.Bucket{Name: HomeBucket}
+	for _, key := range []string{"b.txt", "docs/a.txt", "docs/b.txt", "docs/sub/c.txt", "a.txt"} {
+		_, err := uc.PutObject(context.Background(), 1, bucket, key, "text/plain", []byte(key))
+		require.NoError(t, err)
+	}
+
+	list, err := uc.ListObjects(context.Background(), 1, bucket, models.ListObjectsOptions{
+		Delimiter: "/",
+		MaxKeys:   2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a.txt", list.Objects[0].Key)
+	assert.Equal(t, "b.txt", list.Objects[1].Key)
+	assert.True(t, list.IsTruncated)
+
+	list, err = uc.ListObjects(context.Background(), 1, bucket, models.ListObjectsOptions{
+		Delimiter:  "/",
+		StartAfter: list.NextStartAfter,
+		MaxKeys:    MaxListKeys,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Objects)
+	assert.Equal(t, []string{"docs/"}, list.CommonPrefixes)
+	assert.False(t, list.IsTruncated)
+
+	list, err = uc.ListObjects(context.Background(), 1, bucket, models.ListObjectsOptions{
+		Prefix:    "docs/",
+		Delimiter: "/",
+		MaxKeys:   MaxListKeys,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, list.Objects, 2)
+	assert.Equal(t, []string{"docs/sub/"}, list.CommonPrefixes)
+}
+
+func TestS3Usecases_PutObject_ReplacesExistingKey(t *testing.T) {
+	uc, files := newTestUsecases()
+	bucket := &models.Bucket{Name: HomeBucket}
+
+	_, err := uc.PutObject(context.Background(), 1, bucket, "a.txt", "text/plain", []byte("old"))
+	require.NoError(t, err)
+	object, err := uc.PutObject(context.Background(), 1, bucket, "a.txt", "text/plain", []byte("new"))
+	require.NoError(t, err)
+
+	assert.Len(t, files.files, 1)
+	data := []byte("new")
+	checksum := md5.Sum(data)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), object.ETag)
+
+	_, err = uc.PutObject(context.Background(), 1, bucket, "a.txt", "text/plain", make([]byte, 2048))
+	assert.ErrorIs(t, err, models.ObjectTooLargeError)
+
+	assert.NoError(t, uc.DeleteObject(context.Background(), 1, bucket, "a.txt"))
+	assert.NoError(t, uc.DeleteObject(context.Background(), 1, bucket, "a.txt"))
+	assert.Empty(t, files.files)
+}
+
+func TestS3Usecases_MultipartUpload(t *testing.T) {
+	uc, files := newTestUsecases()
+	bucket := &models.Bucket{Name: "org-7", OrgID: 7}
+
+	upload, err := uc.CreateMultipartUpload(context.Background(), 1, bucket, "big.bin", "application/octet-stream")
+	require.NoError(t, err)
+
+	etag1, err := uc.UploadPart(context.Background(), 1, upload.ID, 1, []byte("hello "))
+	require.NoError(t, err)
+	etag2, err := uc.UploadPart(context.Background(), 1, upload.ID, 2, []byte("world"))
+	require.NoError(t, err)
+
+	_, err = uc.UploadPart(context.Background(), 2, upload.ID, 3, []byte("!"))
+	assert.ErrorIs(t, err, models.MultipartUploadNotExists)
+
+	_, err = uc.CompleteMultipartUpload(context.Background(), 1, upload.ID,
+		[]models.CompletedPart{{Number: 2, ETag: etag2}, {Number: 1, ETag: etag1}})
+	assert.ErrorIs(t, err, models.InvalidPartOrderError)
+
+	_, err = uc.CompleteMultipartUpload(context.Background(), 1, upload.ID,
+		[]models.CompletedPart{{Number: 1, ETag: etag2}})
+	assert.ErrorIs(t, err, models.InvalidPartError)
+
+	object, err := uc.CompleteMultipartUpload(context.Background(), 1, upload.ID,
+		[]models.CompletedPart{{Number: 1, ETag: `"` + etag1 + `"`}, {Number: 2, ETag: etag2}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), object.Size)
+
+	for _, file := range files.files {
+		assert.Equal(t, "hello world", string(file.File))
+		assert.Equal(t, uint(7), file.OrgID)
+	}
+
+	assert.Empty(t, files.parts)
+	assert.ErrorIs(t, uc.AbortMultipartUpload(context.Background(), 1, upload.ID), models.MultipartUploadNotExists)
+}
+
+func TestS3Usecases_MultipartUpload_Limits(t *testing.T) {
+	uc, files := newTestUsecases()
+	bucket := &models.Bucket{Name: HomeBucket}
+
+	upload, err := uc.CreateMultipartUpload(context.Background(), 1, bucket, "big.bin", "application/octet-stream")
+	require.NoError(t, err)
+	_, err = uc.CreateMultipartUpload(context.Background(), 1, bucket, "other.bin", "application/octet-stream")
+	require.NoError(t, err)
+	_, err = uc.CreateMultipartUpload(context.Background(), 1, bucket, "third.bin", "application/octet-stream")
+	assert.ErrorIs(t, err, models.TooManyUploadsError)
+
+	// Parts count towards the object size limit together, a replaced part is counted once
+	_, err = uc.UploadPart(context.Background(), 1, upload.ID, 1, make([]byte, 600))
+	require.NoError(t, err)
+	_, err = uc.UploadPart(context.Background(), 1, upload.ID, 2, make([]byte, 600))
+	assert.ErrorIs(t, err, models.ObjectTooLargeError)
+	_, err = uc.UploadPart(context.Background(), 1, upload.ID, 1, make([]byte, 400))
+	require.NoError(t, err)
+	_, err = uc.UploadPart(context.Background(), 1, upload.ID, 2, make([]byte, 600))
+	require.NoError(t, err)
+
+	files.usage = models.StorageUsage{UsedBytes: 900, QuotaBytes: 1000}
+	_, err = uc.UploadPart(context.Background(), 1, upload.ID, 3, make([]byte, 1))
+	assert.ErrorIs(t, err, models.QuotaExceededError)
+
+	require.NoError(t, uc.AbortMultipartUpload(context.Background(), 1, upload.ID))
+	assert.Empty(t, files.parts)
+
+	_, err = uc.CreateMultipartUpload(context.Background(), 1, bucket, "third.bin", "application/octet-stream")
+	assert.NoError(t, err)
+}
@@ -1,29 +1,53 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
 
 	authproto "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
 	authdel "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/rest"
+	authrepo "github.com/IlyaChgn/voblako/internal/pkg/auth/repository"
 	authuc "github.com/IlyaChgn/voblako/internal/pkg/auth/usecases"
 	"github.com/IlyaChgn/voblako/internal/pkg/config"
+	davdel "github.com/IlyaChgn/voblako/internal/pkg/file/delivery/dav"
 	fileproto "github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
 	filedel "github.com/IlyaChgn/voblako/internal/pkg/file/delivery/rest"
 	fileuc "github.com/IlyaChgn/voblako/internal/pkg/file/usecases"
+	"github.com/IlyaChgn/voblako/internal/pkg/health"
+	"github.com/IlyaChgn/voblako/internal/pkg/interceptors"
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
 	"github.com/IlyaChgn/voblako/internal/pkg/middleware/auth"
+	"github.com/IlyaChgn/voblako/internal/pkg/middleware/ratelimit"
+	"github.com/IlyaChgn/voblako/internal/pkg/middleware/requestid"
+	"github.com/IlyaChgn/voblako/internal/pkg/openapi"
+	s3del "github.com/IlyaChgn/voblako/internal/pkg/s3/delivery/rest"
+	s3repo "github.com/IlyaChgn/voblako/internal/pkg/s3/repository"
+	s3uc "github.com/IlyaChgn/voblako/internal/pkg/s3/usecases"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/dbinit"
 	routers "github.com/IlyaChgn/voblako/internal/pkg/server/delivery"
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/IlyaChgn/voblako/internal/pkg/serviceauth"
+	"github.com/IlyaChgn/voblako/internal/pkg/shutdown"
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
 
 	"github.com/gorilla/handlers"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	davPrefix = "/dav"
+	davRealm  = "voblako"
 )
 
 type Server struct {
 	server *http.Server
+	// s3Server is nil unless the S3 API is enabled
+	s3Server *http.Server
 }
 type serverConfig struct {
 	Address string
@@ -52,48 +76,270 @@ func (srv *Server) Run() error {
 	cfgPath := os.Getenv("CONFIG_PATH")
 	cfg := config.ReadConfig(cfgPath)
 	if cfg == nil {
-		log.Fatal("The config wasn`t opened")
+		logger.Fatal("The config wasn`t opened")
+	}
+
+	logger.Setup(cfg.Log, "gateway")
+
+	ctx, stop := shutdown.NotifyContext(context.Background())
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, "gateway")
+	if err != nil {
+		logger.Fatal("Cannot set up tracing", "error", err)
 	}
+	defer shutdownTracing(context.Background())
 
 	credentials := handlers.AllowCredentials()
 	headersOk := handlers.AllowedHeaders(cfg.Server.Headers)
 	originsOk := handlers.AllowedOrigins(cfg.Server.Origins)
 	methodsOk := handlers.AllowedMethods(cfg.Server.Methods)
+	exposedOk := handlers.ExposedHeaders([]string{responses.RequestIDHeader})
 
+	if cfg.ServiceAuth.TokenSecret == "" {
+		logger.Fatal("Service token secret is not set")
+	}
+
+	clientCreds, err := serviceauth.ClientCredentials(cfg.Server.TLS)
+	if err != nil {
+		logger.Fatal("Cannot load TLS credentials for gRPC clients", "error", err)
+	}
+	opts := grpc.WithTransportCredentials(clientCreds)
+
+	// Calls to the internal services carry a token of the user the request is made for
+	tokenTTL := time.Second * time.Duration(cfg.ServiceAuth.TokenTTL)
+	authTokenSigner := serviceauth.NewTokenSigner(cfg.ServiceAuth.TokenSecret, serviceauth.GatewayName,
+		serviceauth.AuthServiceName, tokenTTL)
 	authServiceURL := fmt.Sprintf("%s:%s", cfg.Auth.ExternalHost, cfg.Auth.Port)
-	opts := grpc.WithTransportCredentials(insecure.NewCredentials())
-	authConn, err := grpc.NewClient(authServiceURL, opts)
+	authOpts := append(interceptors.ClientOptions(time.Second*time.Duration(cfg.Auth.Timeout)), opts,
+		grpc.WithChainUnaryInterceptor(serviceauth.UnaryClientInterceptor(authTokenSigner, cfg.Keys.User)))
+	authConn, err := grpc.NewClient(authServiceURL, authOpts...)
 	if err != nil {
-		log.Fatal("Cannot create client for auth service", err)
+		logger.Fatal("Cannot create client for auth service", "error", err)
 	}
 	defer authConn.Close()
 
+	fileTokenSigner := serviceauth.NewTokenSigner(cfg.ServiceAuth.TokenSecret, serviceauth.GatewayName,
+		serviceauth.FileServiceName, tokenTTL)
 	fileServiceURL := fmt.Sprintf("%s:%s", cfg.File.ExternalHost, cfg.File.Port)
-	fileConn, err := grpc.NewClient(fileServiceURL, opts)
+	fileOpts := append(interceptors.ClientOptions(time.Second*time.Duration(cfg.File.Timeout)), opts,
+		grpc.WithChainUnaryInterceptor(serviceauth.UnaryClientInterceptor(fileTokenSigner, cfg.Keys.User)),
+		grpc.WithChainStreamInterceptor(serviceauth.StreamClientInterceptor(fileTokenSigner, cfg.Keys.User)))
+	fileConn, err := grpc.NewClient(fileServiceURL, fileOpts...)
 	if err != nil {
-		log.Fatal("Cannot create client for file service", err)
+		logger.Fatal("Cannot create client for file service", "error", err)
 	}
 	defer fileConn.Close()
 
+	redisClient := dbinit.NewRedisClient(cfg.Auth.Redis.Host, cfg.Auth.Redis.Port, cfg.Auth.Redis.Password,
+		cfg.Auth.Redis.DB)
+	defer redisClient.Close()
+
 	authClient := authproto.NewAuthClient(authConn)
-	authUsecases := authuc.NewAuthUsecases(authClient)
+	authUsecases := authuc.NewAuthUsecases(authClient, cfg.Auth.Verification.AllowUnverifiedLogin)
 	authHandler := authdel.NewAuthHandler(authUsecases)
 
+	oidcProviders := make([]authuc.OIDCProviderOptions, len(cfg.Server.OIDC.Providers))
+	for i, provider := range cfg.Server.OIDC.Providers {
+		oidcProviders[i] = authuc.OIDCProviderOptions{
+			Name:         provider.Name,
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: os.Getenv(provider.ClientSecretEnv),
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		}
+	}
+	oidcStateTTL := time.Second * time.Duration(cfg.Server.OIDC.StateTTL)
+	oidcStateStorage := authrepo.NewOIDCStateStorage(redisClient, oidcStateTTL)
+	oidcUsecases := authuc.NewOIDCUsecases(authClient, oidcStateStorage, oidcProviders,
+		cfg.Auth.Verification.AllowUnverifiedLogin)
+	oidcHandler := authdel.NewOIDCHandler(oidcUsecases, oidcStateTTL, cfg.Server.OIDC.SuccessRedirect)
+
 	fileClient := fileproto.NewFileClient(fileConn)
 	fileUsecases := fileuc.NewFileUsecases(fileClient)
 	fileHandler := filedel.NewFileHandler(fileUsecases, cfg.Keys.User)
 
+	accountHandler := authdel.NewAccountHandler(authUsecases, fileUsecases, cfg.Keys.User)
+	adminHandler := authdel.NewAdminHandler(authUsecases, fileUsecases, cfg.Keys.User)
+
+	orgUsecases := authuc.NewOrgUsecases(authClient)
+	orgHandler := authdel.NewOrgHandler(orgUsecases, fileUsecases, cfg.Keys.User)
+
 	loginRequiredMiddleware := auth.LoginRequiredMiddleware(authUsecases, cfg.Keys.User)
 
-	router := routers.NewRouter(authHandler, fileHandler, loginRequiredMiddleware)
-	muxWithCORS := handlers.CORS(credentials, originsOk, headersOk, methodsOk)(router)
+	uploadMiddleware := func(next http.Handler) http.Handler { return next }
+	if !cfg.Auth.Verification.AllowUnverifiedUpload {
+		uploadMiddleware = auth.VerifiedRequiredMiddleware(cfg.Keys.User)
+	}
+
+	rateLimitCfg := cfg.Server.RateLimit
+	limiter := ratelimit.NewRedisLimiter(redisClient, time.Second*time.Duration(rateLimitCfg.Window),
+		rateLimitCfg.MaxFailures, time.Second*time.Duration(rateLimitCfg.LockoutBase),
+		time.Second*time.Duration(rateLimitCfg.LockoutMax))
+	loginRateLimitMiddleware := ratelimit.RateLimitMiddleware(limiter, ratelimit.Options{
+		Scope:      "login",
+		IPLimit:    rateLimitCfg.IPLimit,
+		EmailLimit: rateLimitCfg.EmailLimit,
+		TrustProxy: rateLimitCfg.TrustProxy,
+	})
+	signupRateLimitMiddleware := ratelimit.RateLimitMiddleware(limiter, ratelimit.Options{
+		Scope:      "signup",
+		IPLimit:    rateLimitCfg.IPLimit,
+		EmailLimit: rateLimitCfg.EmailLimit,
+		TrustProxy: rateLimitCfg.TrustProxy,
+	})
+	verificationRateLimitMiddleware := ratelimit.RateLimitMiddleware(limiter, ratelimit.Options{
+		Scope:      "verification",
+		IPLimit:    rateLimitCfg.IPLimit,
+		EmailLimit: rateLimitCfg.EmailLimit,
+		TrustProxy: rateLimitCfg.TrustProxy,
+	})
+
+	adminRequiredMiddleware := auth.AdminRequiredMiddleware(cfg.Keys.User)
+
+	router := routers.NewRouter(routers.RouterOptions{
+		AuthHandler:                     authHandler,
+		OIDCHandler:                     oidcHandler,
+		AccountHandler:                  accountHandler,
+		AdminHandler:                    adminHandler,
+		OrgHandler:                      orgHandler,
+		FileHandler:                     fileHandler,
+		LoginRequiredMiddleware:         loginRequiredMiddleware,
+		AdminRequiredMiddleware:         adminRequiredMiddleware,
+		UploadMiddleware:                uploadMiddleware,
+		LoginRateLimitMiddleware:        loginRateLimitMiddleware,
+		SignupRateLimitMiddleware:       signupRateLimitMiddleware,
+		VerificationRateLimitMiddleware: verificationRateLimitMiddleware,
+	})
+	router.Use(requestid.RequestIDMiddleware(), tracing.HTTPMiddleware(), metrics.HTTPMiddleware())
+
+	spec, err := openapi.Load()
+	if err != nil {
+		logger.Fatal("Cannot load OpenAPI specification", "error", err)
+	}
+	if cfg.Server.OpenAPI.Validate {
+		router.Use(openapi.ValidationMiddleware(spec))
+	}
+	if cfg.Server.OpenAPI.Docs {
+		router.Handle("/api/openapi.json", spec).Methods("GET")
+		router.PathPrefix("/api/docs").Handler(openapi.DocsHandler("/api/docs", "/api/openapi.json")).Methods("GET")
+	}
+
+	// Readiness aggregates the downstream services, each of them is asked with its own timeout
+	checker := health.NewChecker(time.Second * time.Duration(cfg.Health.Timeout))
+	checker.Add("auth", health.GRPCCheck(authConn))
+	checker.Add("file", health.GRPCCheck(fileConn))
+	checker.Add("redis", health.RedisCheck(redisClient))
+	router.Handle("/healthz", health.LivenessHandler()).Methods("GET")
+	router.Handle("/readyz", health.ReadinessHandler(checker)).Methods("GET")
+
+	muxWithCORS := handlers.CORS(credentials, originsOk, headersOk, methodsOk, exposedOk)(router)
+
+	rootMux := http.NewServeMux()
+	rootMux.Handle("/", muxWithCORS)
+
+	if cfg.Server.DAV.Enabled {
+		davHandler := davdel.NewHandler(fileUsecases, davPrefix, cfg.Keys.User)
+		basicAuthMiddleware := auth.BasicAuthMiddleware(authUsecases, limiter, auth.BasicAuthOptions{
+			Realm:      davRealm,
+			CtxUserKey: cfg.Keys.User,
+			IPLimit:    rateLimitCfg.IPLimit,
+			TrustProxy: rateLimitCfg.TrustProxy,
+			CacheTTL:   time.Second * time.Duration(cfg.Server.DAV.CredentialsTTL),
+		})
+
+		davRouter := routers.NewDAVRouter(davPrefix, davHandler, basicAuthMiddleware, uploadMiddleware)
+		davRouter.Use(requestid.RequestIDMiddleware(), tracing.HTTPMiddleware(), metrics.HTTPMiddleware())
+		rootMux.Handle(davPrefix+"/", davRouter)
+	}
+
+	var handler http.Handler = rootMux
+
+	// Metrics reveal traffic and storage usage, so they are served on an internal port only
+	if cfg.Server.MetricsPort != "" {
+		metricsServer := metrics.Serve(fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.MetricsPort), nil)
+		defer metricsServer.Shutdown(context.Background())
+	}
 
 	serverURL := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
 
-	serverCfg := createServerConfig(serverURL, cfg.Server.Timeout, &muxWithCORS)
+	serverCfg := createServerConfig(serverURL, cfg.Server.Timeout, &handler)
 	srv.server = createServer(serverCfg)
 
-	log.Printf("Server is listening on %s\n", serverURL)
+	slog.Info("Server is listening", "address", serverURL)
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- srv.server.ListenAndServe()
+	}()
+
+	if cfg.Server.S3.Enabled {
+		s3Cfg := cfg.Server.S3
+		multipartStorage := s3repo.NewMultipartStorage(redisClient, time.Second*time.Duration(s3Cfg.MultipartTTL))
+		s3Usecases := s3uc.NewS3Usecases(fileUsecases, orgUsecases, multipartStorage, s3Cfg.MaxObjectSize,
+			s3Cfg.MaxOpenUploads)
+		s3Handler := s3del.NewS3Handler(authUsecases, s3Usecases, s3Cfg.Region, s3Cfg.MaxObjectSize,
+			cfg.Auth.Verification.AllowUnverifiedUpload)
+
+		s3Router := routers.NewS3Router(s3Handler)
+		s3Router.Use(requestid.RequestIDMiddleware(), tracing.HTTPMiddleware(), metrics.HTTPMiddleware())
+
+		var s3RootHandler http.Handler = s3Router
+		s3URL := fmt.Sprintf("%s:%s", cfg.Server.Host, s3Cfg.Port)
+		srv.s3Server = createServer(createServerConfig(s3URL, cfg.Server.Timeout, &s3RootHandler))
+
+		slog.Info("S3 API is listening", "address", s3URL)
+
+		go func() {
+			serveErr <- srv.s3Server.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	return srv.shutdown(checker, cfg.Shutdown)
+}
+
+// shutdown fails readiness first and then waits for running requests of both servers, uploads included, until
+// the drain timeout expires. Client connections are closed by Run after it returns.
+func (srv *Server) shutdown(checker *health.Checker, cfg config.ShutdownConfig) error {
+	slog.Info("Shutting down server")
+
+	checker.Drain()
+	time.Sleep(time.Second * time.Duration(cfg.ReadinessDelay))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(cfg.DrainTimeout))
+	defer cancel()
+
+	// Both servers are drained at once within the same timeout
+	s3Done := make(chan error, 1)
+	if srv.s3Server != nil {
+		go func() {
+			s3Done <- srv.s3Server.Shutdown(ctx)
+		}()
+	} else {
+		s3Done <- nil
+	}
+
+	err := srv.server.Shutdown(ctx)
+	if s3Err := <-s3Done; err == nil {
+		err = s3Err
+	}
+
+	if err != nil {
+		slog.Warn("Drain timeout expired, closing running requests", "error", err)
+		if srv.s3Server != nil {
+			srv.s3Server.Close()
+		}
+		return srv.server.Close()
+	}
+
+	slog.Info("Server stopped")
 
-	return srv.server.ListenAndServe()
+	return nil
 }
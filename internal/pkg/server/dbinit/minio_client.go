@@ -3,7 +3,7 @@ package dbinit
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -32,10 +32,20 @@ func NewMinioClient(endpoint, accessKey, secretKey, bucketName string) (*minio.C
 		if err != nil {
 			return nil, err
 		}
-		log.Printf("Created bucket: %s\n", bucketName)
+		slog.Info("Created bucket", "bucket", bucketName)
 	} else {
-		log.Printf("Bucket %s already exists\n", bucketName)
+		slog.Info("Bucket already exists", "bucket", bucketName)
 	}
 
 	return client, nil
 }
+
+// NewMinioPresignClient creates a client used only for signing URLs with the endpoint reachable by users.
+// Region is set explicitly, so the client never has to reach the public endpoint itself
+func NewMinioPresignClient(endpoint, accessKey, secretKey, region string, secure bool) (*minio.Client, error) {
+	return minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: secure,
+		Region: region,
+	})
+}
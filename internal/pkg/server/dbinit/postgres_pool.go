@@ -3,11 +3,14 @@ package dbinit
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/IlyaChgn/voblako/internal/pkg/logger"
+	"github.com/IlyaChgn/voblako/internal/pkg/metrics"
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -35,9 +38,10 @@ func NewConnectionString(user, password, host, port, dbname string) string {
 func postgresPoolConfig(dbURL string) *pgxpool.Config {
 	dbConfig, err := pgxpool.ParseConfig(dbURL)
 	if err != nil {
-		log.Fatal("Failed to create postgres config ", err)
+		logger.Fatal("Failed to create postgres config", "error", err)
 	}
 
+	dbConfig.ConnConfig.Tracer = multitracer.New(&metrics.PostgresTracer{}, &tracing.PostgresTracer{})
 	dbConfig.MaxConns = defaultMaxConns
 	dbConfig.MinConns = defaultMinConns
 	dbConfig.MaxConnLifetime = defaultMaxConnLifetime
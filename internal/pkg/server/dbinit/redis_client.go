@@ -3,15 +3,20 @@ package dbinit
 import (
 	"fmt"
 
+	"github.com/IlyaChgn/voblako/internal/pkg/tracing"
+
 	"github.com/redis/go-redis/v9"
 )
 
 func NewRedisClient(host, port, password string, db int) *redis.Client {
-	return redis.NewClient(
+	client := redis.NewClient(
 		&redis.Options{
 			Addr:     fmt.Sprintf("%s:%s", host, port),
 			Password: password,
 			DB:       db,
 		},
 	)
+	client.AddHook(tracing.NewRedisHook())
+
+	return client
 }
@@ -2,7 +2,7 @@ package responses
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
@@ -12,8 +12,15 @@ const (
 	StatusBadRequest   = 400
 	StatusUnauthorized = 401
 	StatusForbidden    = 403
+	StatusNotFound     = 404
+	StatusConflict     = 409
+
+	StatusRequestEntityTooLarge = 413
+
+	StatusTooManyRequests = 429
 
 	StatusInternalServerError = 500
+	StatusNotImplemented      = 501
 )
 
 const (
@@ -23,12 +30,43 @@ const (
 	ErrNotAuthorized = "User not authorized"
 	ErrForbidden     = "User have no access to this content"
 
+	ErrTooManyRequests = "Too many requests, try again later"
+
 	ErrWrongPasswordFormat = "Password must have length between 8 and 32 symbols"
 	ErrDoNotMatch          = "Passwords do not match"
 	ErrWrongCredentials    = "Wrong credentials"
 	ErrAlreadyExists       = "User with this email already exists"
+	ErrWrongEmailFormat    = "Invalid email format"
+	ErrNotVerified         = "User email is not verified"
+	ErrInvalidToken        = "Invalid or expired verification token"
+	ErrUserDisabled        = "User account is disabled"
+	ErrUserNotFound        = "User not found"
+	ErrInvalidRole         = "Unknown role"
+	ErrSelfModification    = "Administrators cannot disable or demote themselves"
+	ErrReauthRequired      = "Log in again to confirm this action"
+
+	ErrAccessKeyNotFound = "Access key not found"
+	ErrAccessKeyLimit    = "Maximum number of access keys is reached"
+
+	ErrUnknownProvider  = "Unknown identity provider"
+	ErrOIDCFailed       = "Authentication with identity provider failed"
+	ErrIdentityConflict = "User with this email already exists, log in with password first"
 
 	ErrWrongFilename = "Filename must have length between 1 and 50"
+	ErrFileNotFound  = "File not found"
+	ErrQuotaExceeded = "Storage quota exceeded"
+	ErrInvalidQuota  = "Quota must not be negative"
+
+	ErrPresignDisabled   = "Direct uploads and downloads are not available"
+	ErrUploadNotFinished = "File content is not uploaded yet"
+	ErrUploadMismatch    = "Uploaded file does not match declared size or checksum"
+
+	ErrJobNotFound = "Job not found"
+
+	ErrOrgNotFound       = "Organization not found"
+	ErrInvalidOrgName    = "Organization name must have length between 1 and 50"
+	ErrInvalidOrgRole    = "Member role must be admin or member"
+	ErrOrgOwnerImmutable = "Organization owner cannot be changed or removed"
 
 	ErrBadJSON          = "Wrong JSON format"
 	ErrBadForm          = "Wrong form format"
@@ -36,20 +74,25 @@ const (
 	ErrInvalidURLParams = "Invalid URL params"
 )
 
+// RequestIDHeader is set by the request ID middleware before handlers are called
+const RequestIDHeader = "X-Request-ID"
+
 type ErrResponse struct {
-	Status string `json:"status"`
+	Status    string `json:"status"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
-func newErrResponse(status string) *ErrResponse {
+func newErrResponse(status, requestID string) *ErrResponse {
 	return &ErrResponse{
-		Status: status,
+		Status:    status,
+		RequestID: requestID,
 	}
 }
 
 func sendResponse(writer http.ResponseWriter, response any) {
 	serverResponse, err := json.Marshal(response)
 	if err != nil {
-		log.Println("Something went wrong while marshalling JSON", err)
+		slog.Error("Something went wrong while marshalling JSON", "error", err)
 		http.Error(writer, ErrInternalServer, StatusInternalServerError)
 
 		return
@@ -57,7 +100,7 @@ func sendResponse(writer http.ResponseWriter, response any) {
 
 	_, err = writer.Write(serverResponse)
 	if err != nil {
-		log.Println("Something went wrong while senddng response", err)
+		slog.Error("Something went wrong while sending response", "error", err)
 		http.Error(writer, ErrInternalServer, StatusInternalServerError)
 
 		return
@@ -75,7 +118,7 @@ func SendErrResponse(writer http.ResponseWriter, code int, status string) {
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(code)
 
-	response := newErrResponse(status)
+	response := newErrResponse(status, writer.Header().Get(RequestIDHeader))
 
 	sendResponse(writer, response)
 }
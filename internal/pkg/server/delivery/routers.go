@@ -1,38 +1,132 @@
 package delivery
 
 import (
+	"net/http"
+
 	authdel "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/rest"
 	filedel "github.com/IlyaChgn/voblako/internal/pkg/file/delivery/rest"
 
 	"github.com/gorilla/mux"
 )
 
-func NewRouter(
-	authHandler *authdel.AuthHandler,
-	fileHandler *filedel.FileHandler,
-	loginRequiredMiddleware mux.MiddlewareFunc,
-) *mux.Router {
+// RouterOptions holds the handlers and the middlewares of the REST API
+type RouterOptions struct {
+	AuthHandler    *authdel.AuthHandler
+	OIDCHandler    *authdel.OIDCHandler
+	AccountHandler *authdel.AccountHandler
+	AdminHandler   *authdel.AdminHandler
+	OrgHandler     *authdel.OrgHandler
+	FileHandler    *filedel.FileHandler
+
+	LoginRequiredMiddleware         mux.MiddlewareFunc
+	AdminRequiredMiddleware         mux.MiddlewareFunc
+	UploadMiddleware                mux.MiddlewareFunc
+	LoginRateLimitMiddleware        mux.MiddlewareFunc
+	SignupRateLimitMiddleware       mux.MiddlewareFunc
+	VerificationRateLimitMiddleware mux.MiddlewareFunc
+}
+
+func NewRouter(options RouterOptions) *mux.Router {
 	router := mux.NewRouter()
 	rootRouter := router.PathPrefix("/api").Subrouter()
 
 	subrouterAuth := rootRouter.PathPrefix("/auth").Subrouter()
-	subrouterAuth.HandleFunc("/signup", authHandler.Signup).Methods("POST")
-	subrouterAuth.HandleFunc("/login", authHandler.Login).Methods("POST")
-	subrouterAuth.HandleFunc("/check", authHandler.CheckAuth).Methods("GET")
+	subrouterAuth.Handle("/signup",
+		options.SignupRateLimitMiddleware(http.HandlerFunc(options.AuthHandler.Signup))).Methods("POST")
+	subrouterAuth.Handle("/login",
+		options.LoginRateLimitMiddleware(http.HandlerFunc(options.AuthHandler.Login))).Methods("POST")
+	subrouterAuth.HandleFunc("/check", options.AuthHandler.CheckAuth).Methods("GET")
+	subrouterAuth.HandleFunc("/verify", options.AuthHandler.VerifyEmail).Methods("GET")
+	subrouterAuth.Handle("/verify/resend",
+		options.VerificationRateLimitMiddleware(http.HandlerFunc(options.AuthHandler.ResendVerification)),
+	).Methods("POST")
+
+	subrouterOIDC := subrouterAuth.PathPrefix("/oidc/{provider}").Subrouter()
+	subrouterOIDC.Handle("/login",
+		options.LoginRateLimitMiddleware(http.HandlerFunc(options.OIDCHandler.Login))).Methods("GET")
+	subrouterOIDC.HandleFunc("/callback", options.OIDCHandler.Callback).Methods("GET")
 
 	subrouterLogout := subrouterAuth.PathPrefix("/logout").Subrouter()
-	subrouterLogout.Use(loginRequiredMiddleware)
-	subrouterLogout.HandleFunc("", authHandler.Logout).Methods("POST")
+	subrouterLogout.Use(options.LoginRequiredMiddleware)
+	subrouterLogout.HandleFunc("", options.AuthHandler.Logout).Methods("POST")
+
+	// The status is available without a session, because the session is revoked by the deletion itself.
+	// Job IDs are random UUIDs, so they cannot be guessed.
+	subrouterAuth.HandleFunc("/account/deletion/{id}", options.AccountHandler.GetDeletionStatus).Methods("GET")
+
+	subrouterAccount := subrouterAuth.PathPrefix("/account").Subrouter()
+	subrouterAccount.Use(options.LoginRequiredMiddleware)
+	subrouterAccount.HandleFunc("", options.AccountHandler.DeleteAccount).Methods("DELETE")
+	subrouterAccount.HandleFunc("/keys", options.AccountHandler.CreateAccessKey).Methods("POST")
+	subrouterAccount.HandleFunc("/keys", options.AccountHandler.ListAccessKeys).Methods("GET")
+	subrouterAccount.HandleFunc("/keys/{id}", options.AccountHandler.DeleteAccessKey).Methods("DELETE")
 
 	subrouterFiles := rootRouter.PathPrefix("/files").Subrouter()
-	subrouterFiles.Use(loginRequiredMiddleware)
-	subrouterFiles.HandleFunc("", fileHandler.UploadFile).Methods("POST")
-	subrouterFiles.HandleFunc("/list", fileHandler.GetFilesList).Methods("POST")
-	subrouterFiles.HandleFunc("/{id}", fileHandler.GetFile).Methods("GET")
-	subrouterFiles.HandleFunc("/{id}/meta", fileHandler.GetMetadata).Methods("GET")
-	subrouterFiles.HandleFunc("/{id}", fileHandler.UpdateFile).Methods("POST")
-	subrouterFiles.HandleFunc("/{id}/name", fileHandler.UpdateFilename).Methods("POST")
-	subrouterFiles.HandleFunc("/{id}", fileHandler.DeleteFile).Methods("DELETE")
+	subrouterFiles.Use(options.LoginRequiredMiddleware)
+	subrouterFiles.Handle("",
+		options.UploadMiddleware(http.HandlerFunc(options.FileHandler.UploadFile))).Methods("POST")
+	subrouterFiles.HandleFunc("/list", options.FileHandler.GetFilesList).Methods("POST")
+	subrouterFiles.HandleFunc("/export", options.FileHandler.StartExport).Methods("POST")
+	subrouterFiles.HandleFunc("/export/{id}", options.FileHandler.GetExport).Methods("GET")
+	subrouterFiles.Handle("/uploads",
+		options.UploadMiddleware(http.HandlerFunc(options.FileHandler.StartUpload))).Methods("POST")
+	subrouterFiles.HandleFunc("/uploads/{id}", options.FileHandler.FinishUpload).Methods("POST")
+	subrouterFiles.HandleFunc("/{id}", options.FileHandler.GetFile).Methods("GET")
+	subrouterFiles.HandleFunc("/{id}/meta", options.FileHandler.GetMetadata).Methods("GET")
+	subrouterFiles.HandleFunc("/{id}/url", options.FileHandler.GetDownloadURL).Methods("GET")
+	subrouterFiles.Handle("/{id}",
+		options.UploadMiddleware(http.HandlerFunc(options.FileHandler.UpdateFile))).Methods("POST")
+	subrouterFiles.HandleFunc("/{id}/name", options.FileHandler.UpdateFilename).Methods("POST")
+	subrouterFiles.HandleFunc("/{id}", options.FileHandler.DeleteFile).Methods("DELETE")
+
+	subrouterOrgs := rootRouter.PathPrefix("/orgs").Subrouter()
+	subrouterOrgs.Use(options.LoginRequiredMiddleware)
+	subrouterOrgs.HandleFunc("", options.OrgHandler.CreateOrganization).Methods("POST")
+	subrouterOrgs.HandleFunc("", options.OrgHandler.ListOrganizations).Methods("GET")
+	subrouterOrgs.HandleFunc("/{id:[0-9]+}/members", options.OrgHandler.ListMembers).Methods("GET")
+	subrouterOrgs.HandleFunc("/{id:[0-9]+}/members", options.OrgHandler.SetMember).Methods("POST")
+	subrouterOrgs.HandleFunc("/{id:[0-9]+}/members/{userID:[0-9]+}",
+		options.OrgHandler.RemoveMember).Methods("DELETE")
+	subrouterOrgs.HandleFunc("/{id:[0-9]+}/storage", options.OrgHandler.GetStorageUsage).Methods("GET")
+
+	subrouterAdmin := rootRouter.PathPrefix("/admin").Subrouter()
+	subrouterAdmin.Use(options.LoginRequiredMiddleware, options.AdminRequiredMiddleware)
+	subrouterAdmin.HandleFunc("/users/list", options.AdminHandler.ListUsers).Methods("POST")
+	subrouterAdmin.HandleFunc("/users/{id:[0-9]+}", options.AdminHandler.GetUser).Methods("GET")
+	subrouterAdmin.HandleFunc("/users/{id:[0-9]+}/disable", options.AdminHandler.DisableUser).Methods("POST")
+	subrouterAdmin.HandleFunc("/users/{id:[0-9]+}/enable", options.AdminHandler.EnableUser).Methods("POST")
+	subrouterAdmin.HandleFunc("/users/{id:[0-9]+}/role", options.AdminHandler.SetUserRole).Methods("POST")
+	subrouterAdmin.HandleFunc("/users/{id:[0-9]+}/storage", options.AdminHandler.GetStorageUsage).Methods("GET")
+	subrouterAdmin.HandleFunc("/users/{id:[0-9]+}/quota", options.AdminHandler.SetQuota).Methods("PUT")
+	subrouterAdmin.HandleFunc("/orgs/{id:[0-9]+}/storage", options.AdminHandler.GetOrgStorageUsage).Methods("GET")
+	subrouterAdmin.HandleFunc("/orgs/{id:[0-9]+}/quota", options.AdminHandler.SetOrgQuota).Methods("PUT")
+	subrouterAdmin.HandleFunc("/files/{id}", options.AdminHandler.ForceDeleteFile).Methods("DELETE")
+
+	return router
+}
+
+// NewDAVRouter is served outside of the CORS handler, because WebDAV clients send OPTIONS requests
+// without Origin and expect them to be answered by the WebDAV handler
+func NewDAVRouter(
+	prefix string,
+	davHandler http.Handler,
+	basicAuthMiddleware mux.MiddlewareFunc,
+	uploadMiddleware mux.MiddlewareFunc,
+) *mux.Router {
+	router := mux.NewRouter()
+
+	subrouterDAV := router.PathPrefix(prefix).Subrouter()
+	subrouterDAV.Use(basicAuthMiddleware)
+	subrouterDAV.PathPrefix("/").Handler(uploadMiddleware(davHandler)).Methods("PUT", "MKCOL", "COPY")
+	subrouterDAV.PathPrefix("/").Handler(davHandler)
+
+	return router
+}
+
+// NewS3Router keeps paths as they are, because object keys may contain repeated or trailing slashes
+func NewS3Router(s3Handler http.Handler) *mux.Router {
+	router := mux.NewRouter().SkipClean(true)
+	router.PathPrefix("/").Handler(s3Handler)
 
 	return router
 }
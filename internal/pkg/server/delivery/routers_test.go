@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/openapi"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatewayPaths are served by the gateway itself, they are registered next to the metrics and health checks
+var gatewayPaths = []string{"/api/openapi.json", "/api/docs"}
+
+func TestRouterMatchesSpec(t *testing.T) {
+	spec, err := openapi.Load()
+	require.NoError(t, err)
+
+	passthrough := func(next http.Handler) http.Handler { return next }
+	router := NewRouter(RouterOptions{
+		LoginRequiredMiddleware:         passthrough,
+		AdminRequiredMiddleware:         passthrough,
+		UploadMiddleware:                passthrough,
+		LoginRateLimitMiddleware:        passthrough,
+		SignupRateLimitMiddleware:       passthrough,
+		VerificationRateLimitMiddleware: passthrough,
+	})
+
+	registered := make(map[string]bool)
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Subrouters have no methods
+			return nil
+		}
+
+		template, err := route.GetPathTemplate()
+		require.NoError(t, err)
+		path := openapi.PathFromTemplate(template)
+
+		for _, method := range methods {
+			assert.NotNil(t, spec.Operation(method, path), "route %s %s is not described", method, path)
+			registered[method+" "+path] = true
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	for _, key := range spec.Operations() {
+		_, path, _ := strings.Cut(key, " ")
+		if slices.Contains(gatewayPaths, path) {
+			continue
+		}
+		assert.True(t, registered[key], "operation %s is not registered", key)
+	}
+}
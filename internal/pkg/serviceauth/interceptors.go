@@ -0,0 +1,229 @@
+package serviceauth
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Names of the services, they are used as token issuers and audiences
+const (
+	GatewayName     = "gateway"
+	AuthServiceName = "auth"
+	FileServiceName = "file"
+)
+
+const (
+	tokenMetadataKey = "authorization"
+	tokenPrefix      = "Bearer "
+)
+
+type identityKey struct{}
+
+// Policy describes who may call every RPC of a service. Methods missing from the policy are denied.
+type Policy struct {
+	// Public methods may be called without a user
+	Public []string
+	// Internal methods may be called without a user only by the services behind the gateway
+	Internal []string
+	// Admin methods may be called only by administrators
+	Admin []string
+	// Subjects return the user a request is made for, it must be the calling user unless the caller
+	// is an administrator
+	Subjects map[string]Subject
+}
+
+// Subject returns the user a request is made for. Zero means the request is not made for a particular
+// user, e.g. storage usage of an organization, which is checked by the handler. False means the request
+// has an unexpected type.
+type Subject func(req any) (uint32, bool)
+
+// SubjectOf builds a Subject from a getter of the request, e.g. (*protobuf.UserIDData).GetID
+func SubjectOf[T any](field func(T) uint32) Subject {
+	return func(req any) (uint32, bool) {
+		r, ok := req.(T)
+		if !ok {
+			return 0, false
+		}
+
+		return field(r), true
+	}
+}
+
+// UnaryClientInterceptor attaches a token for the user stored in the context under ctxUserKey.
+// Calls without a user get an anonymous token.
+func UnaryClientInterceptor(signer *TokenSigner, ctxUserKey string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := contextWithToken(ctx, signer, ctxUserKey)
+		if err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor attaches a token to every stream the same way as UnaryClientInterceptor
+func StreamClientInterceptor(signer *TokenSigner, ctxUserKey string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := contextWithToken(ctx, signer, ctxUserKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+func contextWithToken(ctx context.Context, signer *TokenSigner, ctxUserKey string) (context.Context, error) {
+	var token string
+	var err error
+
+	if user, ok := ctx.Value(ctxUserKey).(*models.User); ok && user != nil {
+		token, err = signer.Sign(user.ID, user.Role)
+	} else {
+		token, err = signer.Sign(0, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, tokenMetadataKey, tokenPrefix+token), nil
+}
+
+// UnaryServerInterceptor verifies the token of every call. The subject of a request must match the token,
+// unless it is zero or the caller is an administrator.
+func UnaryServerInterceptor(verifier *TokenVerifier, policy Policy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		identity, err := identityFromMetadata(ctx, verifier)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "%s", err.Error())
+		}
+
+		isAdmin := identity.Role == models.RoleAdmin
+
+		switch {
+		case slices.Contains(policy.Admin, info.FullMethod):
+			if !isAdmin {
+				return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+			}
+		case slices.Contains(policy.Public, info.FullMethod):
+		case identity.UserID == 0:
+			if identity.Issuer == GatewayName || !slices.Contains(policy.Internal, info.FullMethod) {
+				return nil, status.Errorf(codes.Unauthenticated, "%s", models.InvalidServiceTokenError.Error())
+			}
+		case !subjectMatches(policy.Subjects[info.FullMethod], req, identity, isAdmin):
+			return nil, status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+		}
+
+		return handler(ContextWithIdentity(ctx, identity), req)
+	}
+}
+
+// StreamServerInterceptor verifies the token of every stream. Requests of a stream are received after the
+// call is accepted, so their subjects are checked as they are received.
+func StreamServerInterceptor(verifier *TokenVerifier, policy Policy) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		identity, err := identityFromMetadata(ss.Context(), verifier)
+		if err != nil {
+			return status.Errorf(codes.Unauthenticated, "%s", err.Error())
+		}
+
+		isAdmin := identity.Role == models.RoleAdmin
+
+		var subject Subject
+		switch {
+		case slices.Contains(policy.Admin, info.FullMethod):
+			if !isAdmin {
+				return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+			}
+		case slices.Contains(policy.Public, info.FullMethod):
+		case identity.UserID == 0:
+			if identity.Issuer == GatewayName || !slices.Contains(policy.Internal, info.FullMethod) {
+				return status.Errorf(codes.Unauthenticated, "%s", models.InvalidServiceTokenError.Error())
+			}
+		default:
+			subject = policy.Subjects[info.FullMethod]
+			if subject == nil {
+				return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+			}
+		}
+
+		return handler(srv, &identityStream{
+			ServerStream: ss,
+			ctx:          ContextWithIdentity(ss.Context(), identity),
+			identity:     identity,
+			isAdmin:      isAdmin,
+			subject:      subject,
+		})
+	}
+}
+
+// identityStream checks the subject of every received request if it is set
+type identityStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	identity *Identity
+	isAdmin  bool
+	subject  Subject
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *identityStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	if s.subject != nil && !subjectMatches(s.subject, m, s.identity, s.isAdmin) {
+		return status.Errorf(codes.PermissionDenied, "%s", models.PermissionDeniedError.Error())
+	}
+
+	return nil
+}
+
+func ContextWithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity verified by the server interceptors
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+func identityFromMetadata(ctx context.Context, verifier *TokenVerifier) (*Identity, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, models.InvalidServiceTokenError
+	}
+
+	values := md.Get(tokenMetadataKey)
+	if len(values) != 1 || !strings.HasPrefix(values[0], tokenPrefix) {
+		return nil, models.InvalidServiceTokenError
+	}
+
+	return verifier.Verify(strings.TrimPrefix(values[0], tokenPrefix))
+}
+
+func subjectMatches(subject Subject, req any, identity *Identity, isAdmin bool) bool {
+	if subject == nil {
+		return false
+	}
+
+	id, ok := subject(req)
+
+	return ok && (isAdmin || id == 0 || uint(id) == identity.UserID)
+}
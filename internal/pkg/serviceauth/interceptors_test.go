@@ -0,0 +1,217 @@
+package serviceauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	authproto "github.com/IlyaChgn/voblako/internal/pkg/auth/delivery/grpc/protobuf"
+	"github.com/IlyaChgn/voblako/internal/pkg/file/delivery/grpc/protobuf"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var testPolicy = Policy{
+	Public:   []string{protobuf.File_GetPurgeJob_FullMethodName},
+	Internal: []string{authproto.Auth_GetUserByID_FullMethodName},
+	Admin:    []string{protobuf.File_SetQuota_FullMethodName},
+	Subjects: map[string]Subject{
+		protobuf.File_GetFile_FullMethodName:          SubjectOf((*protobuf.GetFileRequest).GetUserID),
+		authproto.Auth_GetUserByID_FullMethodName:     SubjectOf((*authproto.UserIDData).GetID),
+		authproto.Auth_DeleteUser_FullMethodName:      SubjectOf((*authproto.UserIDData).GetID),
+		authproto.Auth_CreateAccessKey_FullMethodName: SubjectOf((*authproto.UserIDData).GetID),
+		authproto.Auth_RemoveMember_FullMethodName:    SubjectOf((*authproto.RemoveMemberRequest).GetActorID),
+	},
+}
+
+func callWithToken(t *testing.T, user *models.User, method string, req any) error {
+	return callWithIssuer(t, GatewayName, user, method, req)
+}
+
+func callWithIssuer(t *testing.T, issuer string, user *models.User, method string, req any) error {
+	signer := NewTokenSigner("secret", issuer, FileServiceName, time.Minute)
+	verifier := NewTokenVerifier("secret", FileServiceName, GatewayName, AuthServiceName)
+
+	ctx := context.Background()
+	if user != nil {
+		ctx = context.WithValue(ctx, "user", user)
+	}
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	err := UnaryClientInterceptor(signer, "user")(ctx, method, req, nil, nil, invoker)
+	assert.NoError(t, err)
+
+	serverCtx := metadata.NewIncomingContext(context.Background(), outgoing)
+	handler := func(ctx context.Context, req any) (any, error) {
+		_, ok := IdentityFromContext(ctx)
+		assert.True(t, ok)
+		return nil, nil
+	}
+
+	_, err = UnaryServerInterceptor(verifier, testPolicy)(serverCtx, req,
+		&grpc.UnaryServerInfo{FullMethod: method}, handler)
+
+	return err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	user := &models.User{ID: 1, Role: models.RoleUser}
+	admin := &models.User{ID: 2, Role: models.RoleAdmin}
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		method string
+		req    any
+		code   codes.Code
+	}{
+		{"own file", user, protobuf.File_GetFile_FullMethodName,
+			&protobuf.GetFileRequest{UserID: 1}, codes.OK},
+		{"other user", user, protobuf.File_GetFile_FullMethodName,
+			&protobuf.GetFileRequest{UserID: 3}, codes.PermissionDenied},
+		{"anonymous", nil, protobuf.File_GetFile_FullMethodName,
+			&protobuf.GetFileRequest{UserID: 1}, codes.Unauthenticated},
+		{"anonymous public", nil, protobuf.File_GetPurgeJob_FullMethodName,
+			&protobuf.GetPurgeJobRequest{ID: "id"}, codes.OK},
+		{"admin method", user, protobuf.File_SetQuota_FullMethodName,
+			&protobuf.SetQuotaRequest{OwnerID: 1}, codes.PermissionDenied},
+		{"admin", admin, protobuf.File_SetQuota_FullMethodName,
+			&protobuf.SetQuotaRequest{OwnerID: 1}, codes.OK},
+		{"admin other user", admin, protobuf.File_GetFile_FullMethodName,
+			&protobuf.GetFileRequest{UserID: 3}, codes.OK},
+		{"not in policy", admin, protobuf.File_DeleteFile_FullMethodName,
+			&protobuf.DeleteFileRequest{UserID: 2}, codes.PermissionDenied},
+		{"unexpected request", user, protobuf.File_GetFile_FullMethodName,
+			&protobuf.DeleteFileRequest{UserID: 1}, codes.PermissionDenied},
+		{"remove member by owner", user, authproto.Auth_RemoveMember_FullMethodName,
+			&authproto.RemoveMemberRequest{ActorID: 1, OrgID: 1, UserID: 3}, codes.OK},
+		{"remove member for other actor", user, authproto.Auth_RemoveMember_FullMethodName,
+			&authproto.RemoveMemberRequest{ActorID: 3, OrgID: 1, UserID: 3}, codes.PermissionDenied},
+		{"delete other user", user, authproto.Auth_DeleteUser_FullMethodName,
+			&authproto.UserIDData{ID: 3}, codes.PermissionDenied},
+		{"access key of other user", user, authproto.Auth_CreateAccessKey_FullMethodName,
+			&authproto.UserIDData{ID: 3}, codes.PermissionDenied},
+		{"own access key", user, authproto.Auth_CreateAccessKey_FullMethodName,
+			&authproto.UserIDData{ID: 1}, codes.OK},
+		{"other user by ID", user, authproto.Auth_GetUserByID_FullMethodName,
+			&authproto.UserIDData{ID: 3}, codes.PermissionDenied},
+		{"anonymous internal", nil, authproto.Auth_GetUserByID_FullMethodName,
+			&authproto.UserIDData{ID: 3}, codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := callWithToken(t, tt.user, tt.method, tt.req)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_Internal(t *testing.T) {
+	req := &authproto.UserIDData{ID: 3}
+
+	err := callWithIssuer(t, AuthServiceName, nil, authproto.Auth_GetUserByID_FullMethodName, req)
+	assert.Equal(t, codes.OK, status.Code(err))
+
+	err = callWithIssuer(t, AuthServiceName, nil, authproto.Auth_DeleteUser_FullMethodName, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestUnaryServerInterceptor_NoToken(t *testing.T) {
+	verifier := NewTokenVerifier("secret", FileServiceName, GatewayName)
+	handler := func(ctx context.Context, req any) (any, error) {
+		t.Fatal("handler must not be called")
+		return nil, nil
+	}
+
+	_, err := UnaryServerInterceptor(verifier, testPolicy)(context.Background(), &protobuf.GetPurgeJobRequest{},
+		&grpc.UnaryServerInfo{FullMethod: protobuf.File_GetPurgeJob_FullMethodName}, handler)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) RecvMsg(m any) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	signer := NewTokenSigner("secret", GatewayName, FileServiceName, time.Minute)
+	verifier := NewTokenVerifier("secret", FileServiceName, GatewayName)
+
+	tests := []struct {
+		name   string
+		user   *models.User
+		token  bool
+		method string
+		req    *protobuf.GetFileRequest
+		code   codes.Code
+	}{
+		{"public", nil, true, protobuf.File_GetPurgeJob_FullMethodName, nil, codes.OK},
+		{"no token", nil, false, protobuf.File_GetPurgeJob_FullMethodName, nil, codes.Unauthenticated},
+		{"own file", &models.User{ID: 1, Role: models.RoleUser}, true, protobuf.File_GetFile_FullMethodName,
+			&protobuf.GetFileRequest{UserID: 1}, codes.OK},
+		{"other user", &models.User{ID: 1, Role: models.RoleUser}, true, protobuf.File_GetFile_FullMethodName,
+			&protobuf.GetFileRequest{UserID: 2}, codes.PermissionDenied},
+		{"admin other user", &models.User{ID: 2, Role: models.RoleAdmin}, true,
+			protobuf.File_GetFile_FullMethodName, &protobuf.GetFileRequest{UserID: 1}, codes.OK},
+		{"anonymous", nil, true, protobuf.File_GetFile_FullMethodName, nil, codes.Unauthenticated},
+		{"not in policy", &models.User{ID: 1, Role: models.RoleUser}, true, protobuf.File_ReadFile_FullMethodName,
+			nil, codes.PermissionDenied},
+		{"admin method", &models.User{ID: 1, Role: models.RoleUser}, true, protobuf.File_SetQuota_FullMethodName,
+			nil, codes.PermissionDenied},
+		{"admin", &models.User{ID: 2, Role: models.RoleAdmin}, true, protobuf.File_SetQuota_FullMethodName,
+			nil, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverCtx := context.Background()
+			if tt.token {
+				clientCtx := context.WithValue(context.Background(), "user", tt.user)
+				streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+					opts ...grpc.CallOption) (grpc.ClientStream, error) {
+					outgoing, _ := metadata.FromOutgoingContext(ctx)
+					serverCtx = metadata.NewIncomingContext(serverCtx, outgoing)
+					return nil, nil
+				}
+				_, err := StreamClientInterceptor(signer, "user")(clientCtx, nil, nil, tt.method, streamer)
+				assert.NoError(t, err)
+			}
+
+			handler := func(srv any, stream grpc.ServerStream) error {
+				_, ok := IdentityFromContext(stream.Context())
+				assert.True(t, ok)
+				if tt.req == nil {
+					return nil
+				}
+
+				return stream.RecvMsg(&protobuf.GetFileRequest{})
+			}
+
+			err := StreamServerInterceptor(verifier, testPolicy)(nil, &testServerStream{ctx: serverCtx, req: tt.req},
+				&grpc.StreamServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package serviceauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ServerCredentials returns mTLS credentials for a gRPC server. Client certificates must be signed by the
+// configured CA and, if AllowedClients is set, have one of the listed common names.
+func ServerCredentials(cfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	cert, pool, err := loadKeyPair(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(cfg.AllowedClients) == 0 {
+				return nil
+			}
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("client certificate is missing")
+			}
+
+			name := state.PeerCertificates[0].Subject.CommonName
+			if !slices.Contains(cfg.AllowedClients, name) {
+				return fmt.Errorf("client %q is not allowed", name)
+			}
+
+			return nil
+		},
+	}), nil
+}
+
+// ClientCredentials returns mTLS credentials for a gRPC client. The server name is taken from the dial target.
+func ClientCredentials(cfg config.TLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	cert, pool, err := loadKeyPair(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS13,
+	}), nil
+}
+
+func loadKeyPair(cfg config.TLSConfig) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("loading certificate: %w", err)
+	}
+
+	ca, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return tls.Certificate{}, nil, errors.New("CA file contains no certificates")
+	}
+
+	return cert, pool, nil
+}
@@ -0,0 +1,70 @@
+package serviceauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	signer := NewTokenSigner("secret", GatewayName, FileServiceName, time.Minute)
+	verifier := NewTokenVerifier("secret", FileServiceName, GatewayName)
+
+	token, err := signer.Sign(1, models.RoleAdmin)
+	assert.NoError(t, err)
+
+	identity, err := verifier.Verify(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), identity.UserID)
+	assert.Equal(t, models.RoleAdmin, identity.Role)
+	assert.Equal(t, GatewayName, identity.Issuer)
+}
+
+func TestTokenVerifier_Verify_Invalid(t *testing.T) {
+	verifier := NewTokenVerifier("secret", FileServiceName, GatewayName)
+
+	tests := []struct {
+		name   string
+		signer *TokenSigner
+	}{
+		{"wrong secret", NewTokenSigner("other", GatewayName, FileServiceName, time.Minute)},
+		{"wrong audience", NewTokenSigner("secret", GatewayName, "auth", time.Minute)},
+		{"wrong issuer", NewTokenSigner("secret", FileServiceName, FileServiceName, time.Minute)},
+		{"expired", NewTokenSigner("secret", GatewayName, FileServiceName, -time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.signer.Sign(1, models.RoleUser)
+			assert.NoError(t, err)
+
+			identity, err := verifier.Verify(token)
+
+			assert.Nil(t, identity)
+			assert.Equal(t, models.InvalidServiceTokenError, err)
+		})
+	}
+}
+
+func TestTokenVerifier_Verify_Tampered(t *testing.T) {
+	signer := NewTokenSigner("secret", GatewayName, FileServiceName, time.Minute)
+	verifier := NewTokenVerifier("secret", FileServiceName, GatewayName)
+
+	token, err := signer.Sign(1, models.RoleUser)
+	assert.NoError(t, err)
+
+	admin, err := signer.Sign(1, models.RoleAdmin)
+	assert.NoError(t, err)
+
+	adminPayload, _, _ := strings.Cut(admin, ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	identity, err := verifier.Verify(adminPayload + "." + signature)
+
+	assert.Nil(t, identity)
+	assert.Equal(t, models.InvalidServiceTokenError, err)
+}
@@ -0,0 +1,76 @@
+// Package shutdown contains the helpers used by the binaries to stop without dropping in-flight requests
+package shutdown
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/config"
+
+	"google.golang.org/grpc"
+)
+
+// NotifyContext returns a context cancelled on SIGINT or SIGTERM. A second signal kills the process as
+// usual, because the handler is removed after the first one.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return ctx, stop
+}
+
+// StopGRPC waits for running calls to finish and cancels them once the timeout expires
+func StopGRPC(srv *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		slog.Warn("Drain timeout expired, cancelling running calls", "timeout", timeout)
+		srv.Stop()
+		<-done
+	}
+}
+
+// ServeGRPC serves until ctx is cancelled. Then drain is called to fail readiness, and running calls are given
+// the drain timeout to finish after the readiness delay.
+func ServeGRPC(ctx context.Context, srv *grpc.Server, listener net.Listener, cfg config.ShutdownConfig,
+	drain func()) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down gRPC server")
+
+	drain()
+	time.Sleep(time.Second * time.Duration(cfg.ReadinessDelay))
+	StopGRPC(srv, time.Second*time.Duration(cfg.DrainTimeout))
+
+	slog.Info("gRPC server stopped")
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook starts a client span for every command or pipeline. Only command names are recorded, as keys
+// contain session IDs and tokens.
+type RedisHook struct{}
+
+func NewRedisHook() redis.Hook {
+	return RedisHook{}
+}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := startRedisSpan(ctx, "redis.dial")
+		defer span.End()
+
+		conn, err := next(ctx, network, addr)
+		endRedisSpan(span, err)
+
+		return conn, err
+	}
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startRedisSpan(ctx, "redis."+cmd.Name(),
+			semconv.DBOperationName(cmd.Name()))
+		defer span.End()
+
+		err := next(ctx, cmd)
+		endRedisSpan(span, err)
+
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startRedisSpan(ctx, "redis.pipeline",
+			attribute.Int(string(semconv.DBOperationBatchSizeKey), len(cmds)))
+		defer span.End()
+
+		err := next(ctx, cmds)
+		endRedisSpan(span, err)
+
+		return err
+	}
+}
+
+func startRedisSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, semconv.DBSystemNameRedis)...))
+}
+
+// redis.Nil is a regular "not found" answer and is not treated as an error
+func endRedisSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartStorage starts a client span for an object storage call, minio-go has no instrumentation of its own
+func StartStorage(ctx context.Context, storage, operation string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, storage+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.system", storage),
+			attribute.String("storage.operation", operation),
+		))
+}
+
+// End finishes the span started by StartStorage, it is meant to be deferred with a pointer to the named error
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewProvider(exporter, "test", 1)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+
+	return exporter
+}
+
+func spans(t *testing.T, exporter *tracetest.InMemoryExporter) tracetest.SpanStubs {
+	provider, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error })
+	require.True(t, ok)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	return exporter.GetSpans()
+}
+
+func TestHTTPMiddleware_SpanPerRoute(t *testing.T) {
+	exporter := setupExporter(t)
+
+	var handlerSpan trace.SpanContext
+	router := mux.NewRouter()
+	router.Use(HTTPMiddleware())
+	router.HandleFunc("/api/files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	}).Methods("GET")
+
+	parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodGet, "/api/files/42", nil)
+	req.Header.Set("traceparent", parent)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	got := spans(t, exporter)
+	require.Len(t, got, 1)
+	assert.Equal(t, "GET /api/files/{id}", got[0].Name)
+	assert.Equal(t, trace.SpanKindServer, got[0].SpanKind)
+	assert.Equal(t, codes.Error, got[0].Status.Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got[0].SpanContext.TraceID().String())
+	assert.Equal(t, got[0].SpanContext.SpanID(), handlerSpan.SpanID())
+}
+
+func TestRedisHook_CommandSpans(t *testing.T) {
+	exporter := setupExporter(t)
+
+	// The mock client answers from its own hook, so the hook is called directly
+	process := NewRedisHook().ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		cmd.SetErr(redis.Nil)
+		if cmd.Name() == "set" {
+			cmd.SetErr(assert.AnError)
+		}
+
+		return cmd.Err()
+	})
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	assert.ErrorIs(t, process(ctx, redis.NewStringCmd(ctx, "get", "session")), redis.Nil)
+	assert.ErrorIs(t, process(ctx, redis.NewStatusCmd(ctx, "set", "session", "1")), assert.AnError)
+	parent.End()
+
+	got := spans(t, exporter)
+	require.Len(t, got, 3)
+	assert.Equal(t, "redis.get", got[0].Name)
+	assert.Equal(t, codes.Unset, got[0].Status.Code)
+	assert.Equal(t, parent.SpanContext().SpanID(), got[0].Parent.SpanID())
+	assert.Equal(t, "redis.set", got[1].Name)
+	assert.Equal(t, codes.Error, got[1].Status.Code)
+}
+
+func TestEnd_RecordsError(t *testing.T) {
+	exporter := setupExporter(t)
+
+	_, span := StartStorage(context.Background(), "minio", "get_object")
+	err := assert.AnError
+	End(span, &err)
+
+	got := spans(t, exporter)
+	require.Len(t, got, 1)
+	assert.Equal(t, "minio.get_object", got[0].Name)
+	assert.Equal(t, codes.Error, got[0].Status.Code)
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+)
+
+// Login starts the session used by the following calls
+func (c *Client) Login(ctx context.Context, email, password string) (*User, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/auth/login", map[string]string{
+		"email":    email,
+		"password": password,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data authData
+	if err := c.doJSON(ctx, req, &data); err != nil {
+		return nil, err
+	}
+
+	return &data.User, nil
+}
+
+// Signup logs the user in if the server allows unverified users to log in, see Client.SessionID
+func (c *Client) Signup(ctx context.Context, email, password string) (*User, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/auth/signup", map[string]string{
+		"email":           email,
+		"password":        password,
+		"password_repeat": password,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data authData
+	if err := c.doJSON(ctx, req, &data); err != nil {
+		return nil, err
+	}
+
+	return &data.User, nil
+}
+
+func (c *Client) Logout(ctx context.Context) error {
+	req, err := jsonRequest(http.MethodPost, "/api/auth/logout", nil, false)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(ctx, req, nil)
+}
+
+// CheckAuth returns the user of the session or ErrNotAuthorized
+func (c *Client) CheckAuth(ctx context.Context) (*User, error) {
+	req, err := jsonRequest(http.MethodGet, "/api/auth/check", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var data authData
+	if err := c.doJSON(ctx, req, &data); err != nil {
+		return nil, err
+	}
+	if !data.IsAuth {
+		return nil, &Error{StatusCode: http.StatusUnauthorized, Message: responses.ErrNotAuthorized}
+	}
+
+	return &data.User, nil
+}
+
+func (c *Client) ResendVerification(ctx context.Context, email string) error {
+	req, err := jsonRequest(http.MethodPost, "/api/auth/verify/resend", map[string]string{"email": email}, false)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(ctx, req, nil)
+}
+
+// CreateAccessKey returns the key with its secret, which cannot be read later
+func (c *Client) CreateAccessKey(ctx context.Context) (*AccessKey, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/auth/account/keys", nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var key AccessKey
+	if err := c.doJSON(ctx, req, &key); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (c *Client) ListAccessKeys(ctx context.Context) ([]*AccessKey, error) {
+	req, err := jsonRequest(http.MethodGet, "/api/auth/account/keys", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*AccessKey
+	if err := c.doJSON(ctx, req, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (c *Client) DeleteAccessKey(ctx context.Context, id string) error {
+	req, err := jsonRequest(http.MethodDelete, "/api/auth/account/keys/"+url.PathEscape(id), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(ctx, req, nil)
+}
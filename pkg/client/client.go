@@ -0,0 +1,277 @@
+// Package client is the Go client of the voblako REST API. The client keeps the session cookie of the logged in
+// user, retries requests that failed with transient errors and returns typed errors for error responses.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+)
+
+const sessionCookie = "session_id"
+
+// RetryPolicy.MaxAttempts counts the first attempt, so 1 disables retries. Zero values are replaced with
+// the values of DefaultRetryPolicy.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// Options.HTTPClient is used as is except for its cookie jar, which is replaced to keep the session.
+// SessionID restores a session saved with Client.SessionID.
+type Options struct {
+	HTTPClient *http.Client
+	Retry      RetryPolicy
+	SessionID  string
+	UserAgent  string
+}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+	userAgent  string
+}
+
+// New creates the client of the gateway at baseURL, e.g. "https://voblako.example.com"
+func New(baseURL string, options Options) (*Client, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL scheme %q", parsedURL.Scheme)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{}
+	if options.HTTPClient != nil {
+		*httpClient = *options.HTTPClient
+	}
+	httpClient.Jar = jar
+
+	retry := options.Retry
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if retry.BaseDelay <= 0 {
+		retry.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if retry.MaxDelay <= 0 {
+		retry.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+
+	c := &Client{
+		baseURL:    parsedURL,
+		httpClient: httpClient,
+		retry:      retry,
+		userAgent:  options.UserAgent,
+	}
+	if options.SessionID != "" {
+		c.SetSessionID(options.SessionID)
+	}
+
+	return c, nil
+}
+
+// SessionID returns the session of the logged in user, it is empty before Login
+func (c *Client) SessionID() string {
+	for _, cookie := range c.httpClient.Jar.Cookies(c.baseURL) {
+		if cookie.Name == sessionCookie {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
+
+func (c *Client) SetSessionID(sessionID string) {
+	c.httpClient.Jar.SetCookies(c.baseURL, []*http.Cookie{{Name: sessionCookie, Value: sessionID, Path: "/"}})
+}
+
+// request describes a call of the API. Body is called before every attempt, requests with a body that cannot
+// be read again are not retryable.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        func() (io.Reader, error)
+	contentType string
+	// idempotent requests are also retried after server errors and lost connections
+	idempotent bool
+	retryable  bool
+}
+
+func jsonRequest(method, path string, data any, idempotent bool) (*request, error) {
+	req := &request{
+		method:     method,
+		path:       path,
+		idempotent: idempotent,
+		retryable:  true,
+	}
+	if data == nil {
+		return req, nil
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	req.body = func() (io.Reader, error) {
+		return bytes.NewReader(body), nil
+	}
+	req.contentType = "application/json"
+
+	return req, nil
+}
+
+// doJSON sends the request and decodes the JSON body of a successful response into result, if it is not nil
+func (c *Client) doJSON(ctx context.Context, req *request, result any) error {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("cannot decode response: %w", err)
+	}
+
+	return nil
+}
+
+// do returns the response with a successful status, the caller closes its body
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			return resp, nil
+		}
+
+		var delay time.Duration
+		retry := req.retryable && attempt < c.retry.MaxAttempts
+		if err != nil {
+			retry = retry && req.idempotent && ctx.Err() == nil
+			delay = c.backoff(attempt)
+		} else {
+			err = readError(resp)
+			delay, retry = c.retryDelay(req, resp, attempt, retry)
+		}
+
+		if !retry {
+			return nil, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	target := c.baseURL.JoinPath(req.path)
+	if len(req.query) > 0 {
+		target.RawQuery = req.query.Encode()
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		var err error
+		if body, err = req.body(); err != nil {
+			return nil, err
+		}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if c.userAgent != "" {
+		httpReq.Header.Set("User-Agent", c.userAgent)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+
+	return c.httpClient.Do(httpReq)
+}
+
+// retryDelay retries rate limited requests after the delay asked by the server, unless it is longer than
+// the maximum delay. Gateway errors are retried only for idempotent requests.
+func (c *Client) retryDelay(req *request, resp *http.Response, attempt int, retry bool) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err != nil {
+			return c.backoff(attempt), retry
+		}
+
+		delay := time.Duration(seconds) * time.Second
+		return delay, retry && delay <= c.retry.MaxDelay
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return c.backoff(attempt), retry && req.idempotent
+	}
+
+	return 0, false
+}
+
+// backoff doubles the delay after every attempt, the returned delay is randomized between its half and itself
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > c.retry.MaxDelay {
+		delay = c.retry.MaxDelay
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func readError(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+
+	var body responses.ErrResponse
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err := json.Unmarshal(data, &body); err == nil {
+		apiErr.Message = body.Status
+		apiErr.RequestID = body.RequestID
+	} else {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get(responses.RequestIDHeader)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, Options{Retry: testRetry})
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("ftp://example.com", Options{})
+	assert.Error(t, err)
+
+	c, err := New("http://example.com/", Options{SessionID: "session"})
+	require.NoError(t, err)
+	assert.Equal(t, "session", c.SessionID())
+}
+
+func TestLoginKeepsSession(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["password"] != "password" {
+			responses.SendErrResponse(w, responses.StatusUnauthorized, responses.ErrWrongCredentials)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "session", Path: "/"})
+		responses.SendOkResponse(w, authData{User: User{ID: 1, Email: body["email"]}, IsAuth: true})
+	})
+	mux.HandleFunc("GET /api/auth/check", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil || cookie.Value != "session" {
+			responses.SendOkResponse(w, authData{})
+			return
+		}
+
+		responses.SendOkResponse(w, authData{User: User{ID: 1}, IsAuth: true})
+	})
+	c := newTestClient(t, mux)
+
+	_, err := c.CheckAuth(context.Background())
+	assert.ErrorIs(t, err, ErrNotAuthorized)
+
+	_, err = c.Login(context.Background(), "user@example.com", "wrong")
+	assert.ErrorIs(t, err, ErrWrongCredentials)
+
+	user, err := c.Login(context.Background(), "user@example.com", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", user.Email)
+	assert.Equal(t, "session", c.SessionID())
+
+	user, err = c.CheckAuth(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+}
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   int
+		status string
+		want   error
+	}{
+		{"known status", http.StatusNotFound, responses.ErrFileNotFound, ErrFileNotFound},
+		{"unknown status", http.StatusForbidden, "Something new", ErrForbidden},
+		{"server error", http.StatusInternalServerError, responses.ErrInternalServer, ErrServer},
+		{"quota", http.StatusRequestEntityTooLarge, responses.ErrQuotaExceeded, ErrQuotaExceeded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(responses.RequestIDHeader, "request")
+				responses.SendErrResponse(w, test.code, test.status)
+			}))
+
+			err := c.DeleteFile(context.Background(), "id")
+			assert.ErrorIs(t, err, test.want)
+
+			var apiErr *Error
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, test.code, apiErr.StatusCode)
+			assert.Equal(t, test.status, apiErr.Message)
+			assert.Equal(t, "request", apiErr.RequestID)
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	t.Run("idempotent request after server error", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			responses.SendOkResponse(w, File{UUID: "id"})
+		}))
+
+		file, err := c.GetMetadata(context.Background(), "id")
+		require.NoError(t, err)
+		assert.Equal(t, "id", file.UUID)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("rate limited request", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				responses.SendErrResponse(w, http.StatusTooManyRequests, responses.ErrTooManyRequests)
+				return
+			}
+			responses.SendOkResponse(w, authData{User: User{ID: 1}, IsAuth: true})
+		}))
+
+		_, err := c.Login(context.Background(), "user@example.com", "password")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("long Retry-After", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "60")
+			responses.SendErrResponse(w, http.StatusTooManyRequests, responses.ErrTooManyRequests)
+		}))
+
+		_, err := c.Login(context.Background(), "user@example.com", "password")
+		assert.ErrorIs(t, err, ErrTooManyRequests)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("non-idempotent request", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		_, err := c.Login(context.Background(), "user@example.com", "password")
+		assert.ErrorIs(t, err, ErrServer)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("attempts are limited", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+		_, err := c.GetMetadata(context.Background(), "id")
+		assert.ErrorIs(t, err, ErrServer)
+		assert.Equal(t, int32(testRetry.MaxAttempts), calls.Load())
+	})
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+)
+
+var (
+	ErrServer          = errors.New("server error")
+	ErrNotAuthorized   = errors.New("not authorized")
+	ErrForbidden       = errors.New("access denied")
+	ErrTooManyRequests = errors.New("too many requests")
+
+	ErrAlreadyAuthorized = errors.New("already authorized")
+	ErrWrongCredentials  = errors.New("wrong credentials")
+	ErrNotVerified       = errors.New("email is not verified")
+	ErrUserDisabled      = errors.New("account is disabled")
+	ErrUserExists        = errors.New("user already exists")
+	ErrInvalidEmail      = errors.New("invalid email")
+	ErrInvalidPassword   = errors.New("invalid password")
+
+	ErrAccessKeyNotFound = errors.New("access key not found")
+	ErrAccessKeyLimit    = errors.New("too many access keys")
+
+	ErrInvalidFilename = errors.New("invalid filename")
+	ErrInvalidID       = errors.New("invalid ID")
+	ErrFileNotFound    = errors.New("file not found")
+	ErrQuotaExceeded   = errors.New("storage quota exceeded")
+	ErrPresignDisabled = errors.New("direct downloads are not available")
+	ErrBadRequest      = errors.New("bad request")
+)
+
+// statusErrors maps messages of the gateway to errors, the messages are part of the API
+var statusErrors = map[string]error{
+	responses.ErrInternalServer:      ErrServer,
+	responses.ErrNotAuthorized:       ErrNotAuthorized,
+	responses.ErrForbidden:           ErrForbidden,
+	responses.ErrTooManyRequests:     ErrTooManyRequests,
+	responses.ErrAuthorized:          ErrAlreadyAuthorized,
+	responses.ErrWrongCredentials:    ErrWrongCredentials,
+	responses.ErrNotVerified:         ErrNotVerified,
+	responses.ErrUserDisabled:        ErrUserDisabled,
+	responses.ErrAlreadyExists:       ErrUserExists,
+	responses.ErrWrongEmailFormat:    ErrInvalidEmail,
+	responses.ErrWrongPasswordFormat: ErrInvalidPassword,
+	responses.ErrDoNotMatch:          ErrInvalidPassword,
+	responses.ErrAccessKeyNotFound:   ErrAccessKeyNotFound,
+	responses.ErrAccessKeyLimit:      ErrAccessKeyLimit,
+	responses.ErrWrongFilename:       ErrInvalidFilename,
+	responses.ErrInvalidID:           ErrInvalidID,
+	responses.ErrFileNotFound:        ErrFileNotFound,
+	responses.ErrQuotaExceeded:       ErrQuotaExceeded,
+	responses.ErrPresignDisabled:     ErrPresignDisabled,
+}
+
+// statusCodeErrors are used for messages that are not known to the client
+var statusCodeErrors = map[int]error{
+	http.StatusBadRequest:      ErrBadRequest,
+	http.StatusUnauthorized:    ErrNotAuthorized,
+	http.StatusForbidden:       ErrForbidden,
+	http.StatusTooManyRequests: ErrTooManyRequests,
+}
+
+// Error is returned for responses with an error status. It wraps one of the Err* errors, so it can be checked
+// with errors.Is, e.g. errors.Is(err, client.ErrFileNotFound).
+type Error struct {
+	StatusCode int
+	// Message is the status of the error response
+	Message   string
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	if e.RequestID == "" {
+		return fmt.Sprintf("voblako: %d %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("voblako: %d %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+}
+
+func (e *Error) Unwrap() error {
+	if err, ok := statusErrors[e.Message]; ok {
+		return err
+	}
+	if err, ok := statusCodeErrors[e.StatusCode]; ok {
+		return err
+	}
+	if e.StatusCode >= http.StatusInternalServerError {
+		return ErrServer
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageSize = 100
+	sniffLen        = 512
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// UploadFile streams the content as a multipart form. The upload is retried only if the content is
+// an io.Seeker, e.g. an *os.File, because it has to be read again.
+//
+// The server keeps only the last element of the name of an uploaded file, so a name with slashes is set
+// by renaming the file after the upload. If the rename fails, the uploaded file is returned with the error.
+func (c *Client) UploadFile(ctx context.Context, filename string, content io.Reader,
+	options UploadOptions) (*File, error) {
+	uploadName := filename
+	if i := strings.LastIndexByte(filename, '/'); i >= 0 && i < len(filename)-1 {
+		uploadName = filename[i+1:]
+	}
+
+	req, err := uploadRequest(http.MethodPost, "/api/files", uploadName, content, options)
+	if err != nil {
+		return nil, err
+	}
+	if options.OrgID != 0 {
+		req.query = url.Values{"org_id": {strconv.FormatUint(uint64(options.OrgID), 10)}}
+	}
+
+	var file File
+	if err := c.doJSON(ctx, req, &file); err != nil {
+		return nil, err
+	}
+
+	if uploadName != filename {
+		if err := c.RenameFile(ctx, file.UUID, filename); err != nil {
+			return &file, fmt.Errorf("cannot rename uploaded file: %w", err)
+		}
+		file.Filename = filename
+	}
+
+	return &file, nil
+}
+
+// UpdateFile replaces the content of the file, its name and content type are kept
+func (c *Client) UpdateFile(ctx context.Context, id string, content io.Reader, options UploadOptions) error {
+	req, err := uploadRequest(http.MethodPost, "/api/files/"+url.PathEscape(id), "file", content, options)
+	if err != nil {
+		return err
+	}
+	req.idempotent = true
+
+	return c.doJSON(ctx, req, nil)
+}
+
+// DownloadFile writes the content of the file to w and returns the number of written bytes. Failed requests
+// are retried only until the content starts.
+func (c *Client) DownloadFile(ctx context.Context, id string, w io.Writer, progress ProgressFunc) (int64, error) {
+	req, err := jsonRequest(http.MethodGet, "/api/files/"+url.PathEscape(id), nil, true)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	return io.Copy(w, &progressReader{reader: resp.Body, progress: progress})
+}
+
+func (c *Client) GetMetadata(ctx context.Context, id string) (*File, error) {
+	req, err := jsonRequest(http.MethodGet, "/api/files/"+url.PathEscape(id)+"/meta", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := c.doJSON(ctx, req, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// GetDownloadURL returns ErrPresignDisabled if the storage of the deployment cannot presign URLs
+func (c *Client) GetDownloadURL(ctx context.Context, id string) (*DownloadURL, error) {
+	req, err := jsonRequest(http.MethodGet, "/api/files/"+url.PathEscape(id)+"/url", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var downloadURL DownloadURL
+	if err := c.doJSON(ctx, req, &downloadURL); err != nil {
+		return nil, err
+	}
+
+	return &downloadURL, nil
+}
+
+// ListFiles returns one page of files, see Files for iterating over all of them
+func (c *Client) ListFiles(ctx context.Context, options ListOptions) ([]*File, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/files/list", options, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*File
+	if err := c.doJSON(ctx, req, &files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Files iterates over the files starting from options.Offset, pages of options.Limit files are requested
+// as the iteration goes. The iteration stops after the first error.
+func (c *Client) Files(ctx context.Context, options ListOptions) iter.Seq2[*File, error] {
+	if options.Limit == 0 {
+		options.Limit = defaultPageSize
+	}
+
+	return func(yield func(*File, error) bool) {
+		for {
+			files, err := c.ListFiles(ctx, options)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, file := range files {
+				if !yield(file, nil) {
+					return
+				}
+			}
+
+			if uint(len(files)) < options.Limit {
+				return
+			}
+			options.Offset += options.Limit
+		}
+	}
+}
+
+func (c *Client) RenameFile(ctx context.Context, id, filename string) error {
+	req, err := jsonRequest(http.MethodPost, "/api/files/"+url.PathEscape(id)+"/name",
+		map[string]string{"filename": filename}, true)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(ctx, req, nil)
+}
+
+func (c *Client) DeleteFile(ctx context.Context, id string) error {
+	req, err := jsonRequest(http.MethodDelete, "/api/files/"+url.PathEscape(id), nil, true)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(ctx, req, nil)
+}
+
+// uploadRequest writes the form through a pipe, so the content is not buffered in memory
+func uploadRequest(method, path, filename string, content io.Reader, options UploadOptions) (*request, error) {
+	contentType := options.ContentType
+	if contentType == "" {
+		var err error
+		if content, contentType, err = detectContentType(content); err != nil {
+			return nil, err
+		}
+	}
+
+	seeker, seekable := content.(io.Seeker)
+	var start int64
+	if seekable {
+		var err error
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			seekable = false
+		}
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	attempts := 0
+
+	req := &request{
+		method:      method,
+		path:        path,
+		contentType: "multipart/form-data; boundary=" + boundary,
+		retryable:   seekable,
+	}
+	req.body = func() (io.Reader, error) {
+		if attempts > 0 {
+			if !seekable {
+				return nil, errors.New("upload content cannot be read again")
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		attempts++
+
+		pipeReader, pipeWriter := io.Pipe()
+		go func() {
+			pipeWriter.CloseWithError(writeForm(pipeWriter, boundary, filename, contentType,
+				&progressReader{reader: content, progress: options.Progress}))
+		}()
+
+		return pipeReader, nil
+	}
+
+	return req, nil
+}
+
+func writeForm(w io.Writer, boundary, filename, contentType string, content io.Reader) error {
+	form := multipart.NewWriter(w)
+	if err := form.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(filename)))
+	header.Set("Content-Type", contentType)
+
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return err
+	}
+
+	return form.Close()
+}
+
+// detectContentType sniffs the beginning of the content. Seekable content is rewound, other content is
+// returned wrapped in a buffered reader.
+func detectContentType(content io.Reader) (io.Reader, string, error) {
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			buf := make([]byte, sniffLen)
+			n, err := io.ReadFull(seeker, buf)
+			if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, "", err
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, "", err
+			}
+
+			return content, http.DetectContentType(buf[:n]), nil
+		}
+	}
+
+	buffered := bufio.NewReaderSize(content, sniffLen)
+	head, err := buffered.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", err
+	}
+
+	return buffered, http.DetectContentType(head), nil
+}
+
+// progressReader reports the number of bytes read so far
+type progressReader struct {
+	reader      io.Reader
+	progress    ProgressFunc
+	transferred int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 && r.progress != nil {
+		r.transferred += int64(n)
+		r.progress(r.transferred)
+	}
+
+	return n, err
+}
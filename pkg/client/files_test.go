@@ -0,0 +1,198 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IlyaChgn/voblako/internal/pkg/server/delivery/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadHandler stores the uploaded file and answers with its metadata
+func uploadHandler(t *testing.T, uploaded *File, content *[]byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+
+		*content, err = io.ReadAll(file)
+		require.NoError(t, err)
+
+		orgID, _ := strconv.Atoi(r.URL.Query().Get("org_id"))
+		*uploaded = File{
+			UUID:        "id",
+			OrgID:       uint(orgID),
+			Filename:    header.Filename,
+			ContentType: header.Header.Get("Content-Type"),
+			Size:        int64(len(*content)),
+		}
+		responses.SendOkResponse(w, uploaded)
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	var uploaded File
+	var content []byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/files", uploadHandler(t, &uploaded, &content))
+	c := newTestClient(t, mux)
+
+	data := strings.Repeat("plain text ", 1000)
+	var progress int64
+	file, err := c.UploadFile(context.Background(), `notes "1".txt`, io.LimitReader(strings.NewReader(data), 1<<20),
+		UploadOptions{OrgID: 2, Progress: func(transferred int64) { progress = transferred }})
+	require.NoError(t, err)
+
+	assert.Equal(t, data, string(content))
+	assert.Equal(t, `notes "1".txt`, file.Filename)
+	assert.Equal(t, "text/plain; charset=utf-8", file.ContentType)
+	assert.Equal(t, uint(2), file.OrgID)
+	assert.Equal(t, int64(len(data)), progress)
+}
+
+func TestUploadFileToFolder(t *testing.T) {
+	var uploaded File
+	var content []byte
+	var renamed string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/files", uploadHandler(t, &uploaded, &content))
+	mux.HandleFunc("POST /api/files/{id}/name", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		renamed = body["filename"]
+		responses.SendOkResponse(w, nil)
+	})
+	c := newTestClient(t, mux)
+
+	file, err := c.UploadFile(context.Background(), "docs/notes.txt", strings.NewReader("notes"), UploadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "notes.txt", uploaded.Filename)
+	assert.Equal(t, "docs/notes.txt", renamed)
+	assert.Equal(t, "docs/notes.txt", file.Filename)
+}
+
+func TestUploadFileRetry(t *testing.T) {
+	var calls atomic.Int32
+	var uploaded File
+	var content []byte
+	upload := uploadHandler(t, &uploaded, &content)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.Header().Set("Retry-After", "0")
+			responses.SendErrResponse(w, http.StatusTooManyRequests, responses.ErrTooManyRequests)
+			return
+		}
+		upload(w, r)
+	}))
+
+	_, err := c.UploadFile(context.Background(), "data.bin", bytes.NewReader([]byte{1, 2, 3}),
+		UploadOptions{ContentType: "application/x-custom"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []byte{1, 2, 3}, content)
+	assert.Equal(t, "application/x-custom", uploaded.ContentType)
+
+	calls.Store(0)
+	_, err = c.UploadFile(context.Background(), "data.bin", io.LimitReader(bytes.NewReader([]byte{1}), 1),
+		UploadOptions{})
+	assert.ErrorIs(t, err, ErrTooManyRequests)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDownloadFile(t *testing.T) {
+	data := strings.Repeat("x", 100000)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "id" {
+			responses.SendErrResponse(w, http.StatusNotFound, responses.ErrFileNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, data)
+	})
+	c := newTestClient(t, mux)
+
+	var buf bytes.Buffer
+	var progress int64
+	n, err := c.DownloadFile(context.Background(), "id", &buf, func(transferred int64) { progress = transferred })
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.Equal(t, int64(len(data)), progress)
+	assert.Equal(t, data, buf.String())
+
+	_, err = c.DownloadFile(context.Background(), "other", &buf, nil)
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestFiles(t *testing.T) {
+	const total = 25
+
+	var requests []ListOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/files/list", func(w http.ResponseWriter, r *http.Request) {
+		var options ListOptions
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&options))
+		requests = append(requests, options)
+
+		files := []*File{}
+		for i := options.Offset; i < min(options.Offset+options.Limit, total); i++ {
+			files = append(files, &File{UUID: strconv.Itoa(int(i))})
+		}
+		responses.SendOkResponse(w, files)
+	})
+	c := newTestClient(t, mux)
+
+	var ids []string
+	for file, err := range c.Files(context.Background(), ListOptions{Limit: 10, WithDeleted: true}) {
+		require.NoError(t, err)
+		ids = append(ids, file.UUID)
+	}
+	assert.Len(t, ids, total)
+	assert.Equal(t, "24", ids[total-1])
+	require.Len(t, requests, 3)
+	assert.Equal(t, uint(20), requests[2].Offset)
+	assert.True(t, requests[2].WithDeleted)
+
+	requests = nil
+	for file := range c.Files(context.Background(), ListOptions{Limit: 10}) {
+		if file.UUID == "12" {
+			break
+		}
+	}
+	assert.Len(t, requests, 2)
+}
+
+func TestRenameAndDelete(t *testing.T) {
+	var renamed, deleted string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/files/{id}/name", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["filename"] == "" {
+			responses.SendErrResponse(w, http.StatusBadRequest, responses.ErrWrongFilename)
+			return
+		}
+		renamed = r.PathValue("id") + ":" + body["filename"]
+		responses.SendOkResponse(w, nil)
+	})
+	mux.HandleFunc("DELETE /api/files/{id}", func(w http.ResponseWriter, r *http.Request) {
+		deleted = r.PathValue("id")
+		responses.SendOkResponse(w, nil)
+	})
+	c := newTestClient(t, mux)
+
+	require.NoError(t, c.RenameFile(context.Background(), "a/b", "new.txt"))
+	assert.Equal(t, "a/b:new.txt", renamed)
+	assert.ErrorIs(t, c.RenameFile(context.Background(), "id", ""), ErrInvalidFilename)
+
+	require.NoError(t, c.DeleteFile(context.Background(), "id"))
+	assert.Equal(t, "id", deleted)
+}
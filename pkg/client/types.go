@@ -0,0 +1,62 @@
+package client
+
+import "time"
+
+type User struct {
+	ID       uint   `json:"id"`
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+	Role     string `json:"role"`
+	Disabled bool   `json:"disabled"`
+}
+
+// File.OrgID is zero for files in the personal space of the user
+type File struct {
+	UUID        string     `json:"uuid"`
+	OwnerID     uint       `json:"owner_id"`
+	OrgID       uint       `json:"org_id,omitempty"`
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Size        int64      `json:"size"`
+	IsDeleted   bool       `json:"is_deleted"`
+	UploadTime  time.Time  `json:"upload_time"`
+	UpdateTime  time.Time  `json:"update_time"`
+	DeletedTime *time.Time `json:"deleted_time"`
+}
+
+// ListOptions.Limit is the size of a page, Filename lists only files with exactly this name
+type ListOptions struct {
+	Limit       uint   `json:"limit"`
+	Offset      uint   `json:"offset"`
+	WithDeleted bool   `json:"with_deleted"`
+	OrgID       uint   `json:"org_id"`
+	Filename    string `json:"filename,omitempty"`
+}
+
+// ProgressFunc is called with the number of bytes transferred so far
+type ProgressFunc func(transferred int64)
+
+// UploadOptions.ContentType is detected from the content if it is empty
+type UploadOptions struct {
+	OrgID       uint
+	ContentType string
+	Progress    ProgressFunc
+}
+
+// DownloadURL.URL gives access to the file without a session until ExpireTime
+type DownloadURL struct {
+	URL        string    `json:"url"`
+	ExpireTime time.Time `json:"expire_time"`
+}
+
+// AccessKey.Secret is returned only when the key is created
+type AccessKey struct {
+	ID         string    `json:"access_key_id"`
+	Secret     string    `json:"secret_access_key,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type authData struct {
+	User   User `json:"user"`
+	IsAuth bool `json:"is_auth"`
+}